@@ -0,0 +1,111 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNoAmbientScope is the cause reported by ScopedAccessor when the
+// context passed to Get carries no scope of the accessor's provider.
+var ErrNoAmbientScope = errors.New("no scope found in context")
+
+// ScopedAccessor gives singletons access to a scoped service of the scope
+// handling the current operation, such as the user of an HTTP request,
+// without capturing the root provider. Constructors receive it by taking
+// godi.ScopedAccessor[T] as a parameter; it cannot be registered.
+//
+// Get resolves T from the scope stored in the context it is given, as
+// godi.FromContext finds it, so it must be called with a context derived
+// from a scope's, e.g. a request context set up by the HTTP middleware.
+//
+// Example:
+//
+//	type AuditLog struct {
+//	    user godi.ScopedAccessor[*CurrentUser]
+//	}
+//
+//	func NewAuditLog(user godi.ScopedAccessor[*CurrentUser]) *AuditLog {
+//	    return &AuditLog{user: user}
+//	}
+//
+//	func (l *AuditLog) Record(ctx context.Context, action string) error {
+//	    user, err := l.user.Get(ctx)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+type ScopedAccessor[T any] struct {
+	provider *provider
+}
+
+// Get resolves T from the scope found in ctx. It fails with a
+// ResolutionError wrapping ErrNoAmbientScope when ctx carries no scope,
+// and when the scope belongs to another provider.
+func (a ScopedAccessor[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+	serviceType := reflect.TypeFor[T]()
+	if a.provider == nil {
+		return zero, &ValidationError{
+			ServiceType: serviceType,
+			Cause:       errors.New("ScopedAccessor was not injected by a provider"),
+		}
+	}
+	if ctx == nil {
+		return zero, &ResolutionError{ServiceType: serviceType, Cause: ErrNoAmbientScope}
+	}
+
+	ambient, ok := ctx.Value(scopeContextKey{}).(Scope)
+	if !ok {
+		return zero, &ResolutionError{ServiceType: serviceType, Cause: ErrNoAmbientScope}
+	}
+	if composed, ok := ambient.(*composedScope); ok {
+		if member := composed.memberOf(a.provider); member != nil {
+			ambient = member
+		}
+	}
+	if owner := ownerOf(ambient); owner != a.provider {
+		return zero, &ResolutionError{
+			ServiceType: serviceType,
+			Cause:       fmt.Errorf("%w for this provider: scope %s belongs to another provider", ErrNoAmbientScope, ambient.ID()),
+		}
+	}
+	return Resolve[T](ambient)
+}
+
+// bind returns the accessor resolving through p; it marks the
+// ScopedAccessor types for the resolver.
+func (ScopedAccessor[T]) bind(p *provider) any {
+	return ScopedAccessor[T]{provider: p}
+}
+
+// scopedAccessor is implemented by every ScopedAccessor type.
+type scopedAccessor interface {
+	bind(p *provider) any
+}
+
+var scopedAccessorType = reflect.TypeFor[scopedAccessor]()
+
+// isScopedAccessor reports whether t is a ScopedAccessor type.
+func isScopedAccessor(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Struct && t.Implements(scopedAccessorType)
+}
+
+// newScopedAccessor returns the ScopedAccessor of type t bound to p.
+func newScopedAccessor(t reflect.Type, p *provider) any {
+	return reflect.Zero(t).Interface().(scopedAccessor).bind(p)
+}
+
+// ownerOf returns the provider a scope, sealed or not, belongs to.
+func ownerOf(s Scope) *provider {
+	switch v := s.(type) {
+	case *scope:
+		return v.rootProvider
+	case *sealedScope:
+		return v.scope.rootProvider
+	default:
+		return nil
+	}
+}
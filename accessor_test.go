@@ -0,0 +1,79 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accessorConsumer struct {
+	deps ScopedAccessor[*TDependency]
+}
+
+func TestScopedAccessor(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T) Provider {
+		t.Helper()
+		return BuildProvider(t,
+			AddScoped(NewTDependency),
+			AddSingleton(func(deps ScopedAccessor[*TDependency]) *accessorConsumer {
+				return &accessorConsumer{deps: deps}
+			}),
+		)
+	}
+
+	t.Run("resolves_from_the_ambient_scope", func(t *testing.T) {
+		t.Parallel()
+		p := newProvider(t)
+		consumer := RequireResolve[*accessorConsumer](t, p)
+
+		first, second := NewTestScope(t, p), NewTestScope(t, p)
+		fromFirst, err := consumer.deps.Get(first.Context())
+		require.NoError(t, err)
+		assert.Same(t, RequireResolve[*TDependency](t, first), fromFirst)
+
+		fromSecond, err := consumer.deps.Get(second.Context())
+		require.NoError(t, err)
+		assert.NotSame(t, fromFirst, fromSecond)
+	})
+
+	t.Run("fails_outside_a_scope", func(t *testing.T) {
+		t.Parallel()
+		consumer := RequireResolve[*accessorConsumer](t, newProvider(t))
+
+		_, err := consumer.deps.Get(context.Background())
+		require.ErrorIs(t, err, ErrNoAmbientScope)
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[TDependency](), resErr.ServiceType)
+	})
+
+	t.Run("fails_for_another_providers_scope", func(t *testing.T) {
+		t.Parallel()
+		consumer := RequireResolve[*accessorConsumer](t, newProvider(t))
+		other := NewTestScope(t, newProvider(t))
+
+		_, err := consumer.deps.Get(other.Context())
+		require.ErrorIs(t, err, ErrNoAmbientScope)
+		assert.ErrorContains(t, err, "belongs to another provider")
+	})
+
+	t.Run("zero_value_fails", func(t *testing.T) {
+		t.Parallel()
+		var accessor ScopedAccessor[*TDependency]
+		_, err := accessor.Get(context.Background())
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("cannot_be_registered", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() ScopedAccessor[*TDependency] { return ScopedAccessor[*TDependency]{} })
+		assert.ErrorContains(t, c.Err(), "reserved")
+	})
+}
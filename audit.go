@@ -0,0 +1,104 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// Audited is an AddOption for security-sensitive services, such as signing
+// keys or admin clients, that need an access trail. Every resolution of the
+// service, direct or as a dependency and including cache hits, is reported
+// to ProviderOptions.OnAuditedResolution along with the reason, so the
+// callback is mandatory: Build fails when an audited service is registered
+// without it.
+//
+// Example:
+//
+//	services.AddSingleton(NewSigningKey, godi.Audited("signs customer invoices"))
+//
+//	provider, err := services.BuildWithOptions(&godi.ProviderOptions{
+//	    OnAuditedResolution: func(e godi.AuditEvent) {
+//	        auditLog.Info("service accessed", "service", e.ServiceType, "reason", e.Reason, "scope", e.ScopeID)
+//	    },
+//	})
+func Audited(reason string) AddOption {
+	return addAuditedOption(reason)
+}
+
+type addAuditedOption string
+
+func (o addAuditedOption) String() string {
+	return fmt.Sprintf("Audited(%q)", string(o))
+}
+
+func (o addAuditedOption) applyAddOption(opt *addOptions) {
+	opt.audited = true
+	opt.auditReason = string(o)
+}
+
+// AuditEvent describes one resolution of a service registered with
+// godi.Audited.
+type AuditEvent struct {
+	// ServiceType, ServiceKey and Group identify the resolved service.
+	ServiceType reflect.Type
+	ServiceKey  any
+	Group       string
+
+	// Reason is the reason given to godi.Audited.
+	Reason string
+
+	// ScopeID is the ID of the scope the service was resolved in.
+	ScopeID string
+
+	// Path is the chain of services being constructed that led to the
+	// resolution, from the service originally requested down to the
+	// audited one. It has a single frame when the audited service was
+	// resolved directly.
+	Path []ResolutionFrame
+
+	// Stack holds the program counters of the resolving goroutine's call
+	// stack, innermost first. Pass it to runtime.CallersFrames to find the
+	// code that asked for the service.
+	Stack []uintptr
+}
+
+// validateAudited fails for every audited registration, for builds without
+// an OnAuditedResolution callback.
+func validateAudited(all []*descriptor) error {
+	var errs []error
+	for _, d := range all {
+		if d == nil || d.auditReason == "" {
+			continue
+		}
+		errs = append(errs, &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("registered with godi.Audited(%q) but ProviderOptions.OnAuditedResolution is not set", d.auditReason),
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// audit reports the resolution of an audited service as a dependency of
+// r's constructor (r is nil for direct resolutions).
+func (s *scope) audit(r *resolution, key instanceKey, descriptor *descriptor) {
+	onAudited := s.rootProvider.onAudited
+	if onAudited == nil {
+		return
+	}
+
+	frame := newResolutionFrame(key, descriptor)
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip runtime.Callers, audit, and resolve
+
+	onAudited(AuditEvent{
+		ServiceType: frame.ServiceType,
+		ServiceKey:  frame.ServiceKey,
+		Group:       frame.Group,
+		Reason:      descriptor.auditReason,
+		ScopeID:     s.id,
+		Path:        append(r.path(), frame),
+		Stack:       append([]uintptr(nil), pcs[:n]...),
+	})
+}
@@ -0,0 +1,140 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditLog collects AuditEvents for assertions.
+type auditLog struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (l *auditLog) record(e AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+func (l *auditLog) snapshot() []AuditEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEvent(nil), l.events...)
+}
+
+func buildAudited(t *testing.T, log *auditLog, register func(Collection)) Provider {
+	t.Helper()
+	c := NewCollection()
+	register(c)
+	p, err := c.BuildWithOptions(&ProviderOptions{OnAuditedResolution: log.record})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestAudited(t *testing.T) {
+	t.Parallel()
+
+	t.Run("every_resolution_is_reported", func(t *testing.T) {
+		t.Parallel()
+		log := &auditLog{}
+		p := buildAudited(t, log, func(c Collection) {
+			c.AddSingleton(NewTDependency, Audited("signs invoices"))
+			c.AddScoped(NewTService)
+			c.AddScoped(NewTServiceWithDeps)
+		})
+		assert.Empty(t, log.snapshot(), "nothing depends on the audited singleton during build")
+
+		s := NewTestScope(t, p)
+		RequireResolveFrom[*TDependency](t, s)
+		RequireResolveFrom[*TDependency](t, s)
+		RequireResolveFrom[*TServiceWithDeps](t, s)
+		_, err := ResolveMany(s, PtrTypeOf[TDependency]())
+		require.NoError(t, err)
+
+		events := log.snapshot()
+		require.Len(t, events, 4, "cache hits and ResolveMany are audited too")
+		direct := events[0]
+		assert.Equal(t, PtrTypeOf[TDependency](), direct.ServiceType)
+		assert.Equal(t, "signs invoices", direct.Reason)
+		assert.Equal(t, s.ID(), direct.ScopeID)
+		require.Len(t, direct.Path, 1)
+
+		dependency := events[2]
+		require.Len(t, dependency.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), dependency.Path[0].ServiceType)
+		assert.Equal(t, PtrTypeOf[TDependency](), dependency.Path[1].ServiceType)
+	})
+
+	t.Run("stack_points_at_the_caller", func(t *testing.T) {
+		t.Parallel()
+		log := &auditLog{}
+		p := buildAudited(t, log, func(c Collection) {
+			c.AddScoped(NewTService, Name("admin"), Audited("admin client"))
+		})
+		_, err := ResolveKeyed[*TService](NewTestScope(t, p), "admin")
+		require.NoError(t, err)
+
+		events := log.snapshot()
+		require.Len(t, events, 1)
+		assert.Equal(t, "admin", events[0].ServiceKey)
+
+		var functions []string
+		frames := runtime.CallersFrames(events[0].Stack)
+		for {
+			frame, more := frames.Next()
+			functions = append(functions, frame.Function)
+			if !more {
+				break
+			}
+		}
+		assert.True(t, strings.HasSuffix(functions[0], "godi/v5.(*scope).getKeyed"), functions[0])
+		assert.True(t, slices.ContainsFunc(functions, func(fn string) bool {
+			return strings.HasPrefix(fn, "github.com/junioryono/godi/v5.TestAudited.")
+		}), "the stack includes the test")
+	})
+
+	t.Run("group_members", func(t *testing.T) {
+		t.Parallel()
+		log := &auditLog{}
+		p := buildAudited(t, log, func(c Collection) {
+			c.AddSingleton(NewTService, Group("all"), Audited("plugin"))
+			c.AddSingleton(NewTService, Group("all"))
+		})
+		_, err := ResolveGroup[*TService](p, "all")
+		require.NoError(t, err)
+
+		events := log.snapshot()
+		require.Len(t, events, 1)
+		assert.Equal(t, "all", events[0].Group)
+		assert.Nil(t, events[0].ServiceKey)
+	})
+
+	t.Run("callback_is_required", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Audited("signing key"))
+		_, err := c.Build()
+		buildErr, ok := errors.AsType[*BuildError](err)
+		require.True(t, ok)
+		assert.Equal(t, "validation", buildErr.Phase)
+		assert.ErrorContains(t, err, `godi.Audited("signing key")`)
+	})
+
+	t.Run("reason_is_required", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Audited(" "))
+		_, err := c.BuildWithContext(context.Background())
+		assert.ErrorContains(t, err, "godi.Audited requires a reason")
+	})
+}
@@ -0,0 +1,117 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// collectable reports whether a dependency of type t is satisfied by
+// ProviderOptions.AutoCollectSlices when nothing is registered as t: a
+// slice of a non-empty interface.
+func collectable(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface && t.Elem().NumMethod() > 0
+}
+
+// collectedMembers returns the registrations collected into a slice of
+// elem, in registration order. Flagged variants are left to their base
+// registration, private services to their modules, and each value produced
+// under several godi.As types is collected once.
+func collectedMembers(all []*descriptor, elem reflect.Type) []*descriptor {
+	var members []*descriptor
+	aliased := make(map[*descriptor]bool)
+	for _, d := range all {
+		if d == nil || d.VoidReturn || d.private || d.flag != "" || !d.Type.Implements(elem) {
+			continue
+		}
+		if d.isAlias && len(d.siblings) > 0 {
+			if aliased[d.siblings[0]] {
+				continue
+			}
+			aliased[d.siblings[0]] = true
+		}
+		members = append(members, d)
+	}
+	return members
+}
+
+// collected returns the members collected into serviceType, computed once
+// per slice type.
+func (p *provider) collected(serviceType reflect.Type) []*descriptor {
+	if members, ok := p.collections.Load(serviceType); ok {
+		return members.([]*descriptor)
+	}
+	members, _ := p.collections.LoadOrStore(serviceType, collectedMembers(p.descriptors, serviceType.Elem()))
+	return members.([]*descriptor)
+}
+
+// collect resolves every registration assignable to the element type of
+// serviceType on behalf of r, leaving out those r is constructing, so a
+// composite implementation can collect the others.
+func (s *scope) collect(r *resolution, serviceType reflect.Type) (any, error) {
+	members := s.rootProvider.collected(serviceType)
+	slice := reflect.MakeSlice(serviceType, 0, len(members))
+	for _, member := range members {
+		if r.constructing(member) {
+			continue
+		}
+		key := instanceKey{Type: member.Type, Key: member.Key, Group: member.Group}
+		descriptor := member
+		if member.flagged != nil {
+			descriptor = nil // let resolve select the variant
+		}
+		instance, err := s.resolve(r, key, descriptor)
+		if err != nil {
+			if isNotProvided(err, member) {
+				continue
+			}
+			return nil, &ResolutionError{
+				ServiceType: serviceType,
+				Cause:       fmt.Errorf("failed to resolve collected service: %w", r.dependencyError(key, member, err)),
+			}
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(instance))
+	}
+	return slice.Interface(), nil
+}
+
+// constructing reports whether r or one of its parents is constructing d,
+// or a sibling output of d's constructor.
+func (r *resolution) constructing(d *descriptor) bool {
+	for frame := r; frame != nil; frame = frame.parent {
+		if frame.descriptor != nil && flightKey(frame.descriptor) == flightKey(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCollectedLifetimes reports singletons and transients that would
+// collect scoped services into a slice, like validateLifetimes does for
+// their other dependencies.
+func validateCollectedLifetimes(all []*descriptor, services map[TypeKey]*descriptor) error {
+	var errs []error
+	for _, d := range all {
+		if d == nil || (d.Lifetime == Scoped && d.refresh == nil) {
+			continue
+		}
+		for _, dep := range d.Dependencies {
+			if dep == nil || dep.Key != nil || dep.Group != "" || !collectable(dep.Type) ||
+				services[TypeKey{Type: dep.Type}] != nil {
+				continue
+			}
+			for _, member := range collectedMembers(all, dep.Type.Elem()) {
+				if member.Lifetime == Scoped && flightKey(member) != flightKey(d) {
+					errs = append(errs, &LifetimeConflictError{
+						ServiceType:        d.Type,
+						ServiceLifetime:    d.Lifetime,
+						DependencyType:     member.Type,
+						DependencyLifetime: member.Lifetime,
+					})
+					break
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,116 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TOtherID is another TInterface implementation.
+type TOtherID struct{ id string }
+
+func (o *TOtherID) GetID() string { return o.id }
+
+// TCompositeID implements TInterface by joining the IDs of the others.
+type TCompositeID struct{ parts []TInterface }
+
+func (c *TCompositeID) GetID() string {
+	ids := make([]string, len(c.parts))
+	for i, part := range c.parts {
+		ids[i] = part.GetID()
+	}
+	return strings.Join(ids, ",")
+}
+
+func buildCollecting(t *testing.T, opts ...ModuleOption) Provider {
+	t.Helper()
+	c := NewCollection()
+	c.AddModules(opts...)
+	p, err := c.BuildWithOptions(&ProviderOptions{AutoCollectSlices: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestAutoCollectSlices(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collects_every_implementation_in_registration_order", func(t *testing.T) {
+		t.Parallel()
+		p := buildCollecting(t,
+			AddSingleton(NewTServiceWithID("service")),
+			AddSingleton(func() *TOtherID { return &TOtherID{id: "other"} }, As[TInterface]()),
+			AddSingleton(func() *TOtherID { return &TOtherID{id: "keyed"} }, Name("keyed")),
+			AddSingleton(NewTDependency),
+			AddSingleton(func(all []TInterface) *TCompositeID { return &TCompositeID{parts: all} }),
+		)
+
+		composite := RequireResolve[*TCompositeID](t, p)
+		assert.Equal(t, "service,other,keyed", composite.GetID())
+	})
+
+	t.Run("composites_leave_themselves_out", func(t *testing.T) {
+		t.Parallel()
+		p := buildCollecting(t,
+			AddScoped(NewTServiceWithID("a")),
+			AddScoped(func(all []TInterface) TInterface { return &TCompositeID{parts: all} }),
+		)
+
+		s := NewTestScope(t, p)
+		assert.Equal(t, "a", RequireResolveFrom[TInterface](t, s).GetID())
+		all := RequireResolveFrom[[]TInterface](t, s)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("registered_slices_and_groups_take_precedence", func(t *testing.T) {
+		t.Parallel()
+		p := buildCollecting(t,
+			AddSingleton(NewTServiceWithID("member"), Group("ids"), As[TInterface]()),
+			AddSingleton(NewTServiceWithID("loose")),
+			AddSingleton(func() []TInterface { return []TInterface{&TOtherID{id: "registered"}} }),
+		)
+
+		registered := RequireResolve[[]TInterface](t, p)
+		require.Len(t, registered, 1)
+		assert.Equal(t, "registered", registered[0].GetID())
+		grouped, err := ResolveGroup[TInterface](p, "ids")
+		require.NoError(t, err)
+		assert.Len(t, grouped, 1)
+	})
+
+	t.Run("off_by_default", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTService))
+		_, err := Resolve[[]TInterface](p)
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("singletons_cannot_collect_scoped_services", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTService)
+		c.AddSingleton(func(all []TInterface) *TCompositeID { return &TCompositeID{parts: all} })
+		_, err := c.BuildWithOptions(&ProviderOptions{AutoCollectSlices: true})
+		var conflict *LifetimeConflictError
+		require.True(t, errors.As(err, &conflict), "got %v", err)
+		assert.Equal(t, PtrTypeOf[TService](), conflict.DependencyType)
+	})
+
+	t.Run("scoped_consumers_collect_per_scope", func(t *testing.T) {
+		t.Parallel()
+		p := buildCollecting(t, AddScoped(NewTService))
+		first, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = first.Close() })
+		second := NewTestScope(t, p)
+
+		a := RequireResolveFrom[[]TInterface](t, first)
+		b := RequireResolveFrom[[]TInterface](t, second)
+		assert.NotSame(t, a[0], b[0])
+		assert.Same(t, a[0], RequireResolveFrom[[]TInterface](t, first)[0])
+	})
+}
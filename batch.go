@@ -0,0 +1,182 @@
+package godi
+
+import (
+	"reflect"
+)
+
+// ResolveMany resolves a service of each of the given types from p and
+// returns them in the same order. Cached singletons and scoped instances
+// are read under a single acquisition of the scope's lock, so handler setup
+// code that needs several services pays for one round-trip instead of one
+// per service. Services not cached yet are resolved as by Get, as are all
+// of them for Providers implemented outside this package.
+//
+// The first failure aborts the batch; its error is returned as Get would
+// return it.
+//
+// Example:
+//
+//	instances, err := godi.ResolveMany(scope, reflect.TypeFor[*UserService](), reflect.TypeFor[*Logger]())
+func ResolveMany(p Provider, serviceTypes ...reflect.Type) ([]any, error) {
+	switch v := p.(type) {
+	case nil:
+		return nil, ErrProviderNil
+	case batchResolver:
+		return v.ResolveMany(serviceTypes...)
+	}
+
+	instances := make([]any, len(serviceTypes))
+	for i, serviceType := range serviceTypes {
+		instance, err := p.Get(serviceType)
+		if err != nil {
+			return nil, err
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+// batchResolver is implemented by the providers and scopes of this package.
+type batchResolver interface {
+	ResolveMany(serviceTypes ...reflect.Type) ([]any, error)
+}
+
+// ResolveMany resolves several services in this scope; see godi.ResolveMany.
+func (s *scope) ResolveMany(serviceTypes ...reflect.Type) ([]any, error) {
+	instances, failed, err := s.resolveMany(serviceTypes, func(serviceType reflect.Type) (any, error) {
+		return s.get(nil, serviceType)
+	})
+	if err != nil {
+		return nil, s.translateError(err, ResolutionSite{Operation: "ResolveMany", ServiceType: failed})
+	}
+	return instances, nil
+}
+
+// resolveMany reads the cached services among serviceTypes in one pass and
+// resolves the rest with get. On error it also returns the type that failed.
+func (s *scope) resolveMany(
+	serviceTypes []reflect.Type,
+	get func(reflect.Type) (any, error),
+) ([]any, reflect.Type, error) {
+	if s.disposed.Load() != 0 {
+		return nil, nil, ErrScopeDisposed
+	}
+
+	instances := make([]any, len(serviceTypes))
+	var missed []int
+
+	s.instancesMu.RLock()
+	for i, serviceType := range serviceTypes {
+		if instance, ok := s.cachedLocked(serviceType); ok {
+			instances[i] = instance
+			continue
+		}
+		missed = append(missed, i)
+	}
+	s.instancesMu.RUnlock()
+	s.rootProvider.cacheHits.Add(uint64(len(serviceTypes) - len(missed)))
+
+	for _, i := range missed {
+		instance, err := get(serviceTypes[i])
+		if err != nil {
+			return nil, serviceTypes[i], err
+		}
+		instances[i] = instance
+	}
+
+	if s.disposed.Load() != 0 {
+		return nil, nil, ErrScopeDisposed
+	}
+	return instances, nil, nil
+}
+
+// cachedLocked returns the cached instance of serviceType's unkeyed
+// registration, if resolving it needs no further checks such as Private,
+// Audited or Deprecated. The caller holds instancesMu for reading.
+func (s *scope) cachedLocked(serviceType reflect.Type) (instance any, ok bool) {
+	descriptor := s.rootProvider.findDescriptor(serviceType, nil)
+	if descriptor == nil || descriptor.private || descriptor.auditReason != "" || descriptor.deprecation != "" {
+		return nil, false
+	}
+
+	key := instanceKey{Type: serviceType}
+	switch descriptor.Lifetime {
+	case Singleton:
+		instance, ok = s.rootProvider.getSingleton(key)
+	case Scoped:
+		if s.inheritScoped {
+			return nil, false
+		}
+		instance, ok = s.instances[key]
+	default:
+		return nil, false
+	}
+	if _, absent := instance.(notProvided); absent {
+		return nil, false // resolve it to report the error
+	}
+	return instance, ok
+}
+
+// Resolve2 resolves services of types A and B from the provider with a
+// single ResolveMany call.
+//
+// Example:
+//
+//	users, orders, err := godi.Resolve2[*UserService, *OrderService](scope)
+func Resolve2[A, B any](provider Provider) (A, B, error) {
+	var (
+		a A
+		b B
+	)
+	instances, err := ResolveMany(provider, reflect.TypeFor[A](), reflect.TypeFor[B]())
+	if err != nil {
+		return a, b, err
+	}
+	if a, err = assertResolved[A](instances[0]); err != nil {
+		return a, b, err
+	}
+	if b, err = assertResolved[B](instances[1]); err != nil {
+		return a, b, err
+	}
+	return a, b, nil
+}
+
+// Resolve3 resolves services of types A, B and C from the provider with a
+// single ResolveMany call.
+//
+// Example:
+//
+//	users, orders, logger, err := godi.Resolve3[*UserService, *OrderService, *Logger](scope)
+func Resolve3[A, B, C any](provider Provider) (A, B, C, error) {
+	var (
+		a A
+		b B
+		c C
+	)
+	instances, err := ResolveMany(provider, reflect.TypeFor[A](), reflect.TypeFor[B](), reflect.TypeFor[C]())
+	if err != nil {
+		return a, b, c, err
+	}
+	if a, err = assertResolved[A](instances[0]); err != nil {
+		return a, b, c, err
+	}
+	if b, err = assertResolved[B](instances[1]); err != nil {
+		return a, b, c, err
+	}
+	if c, err = assertResolved[C](instances[2]); err != nil {
+		return a, b, c, err
+	}
+	return a, b, c, nil
+}
+
+func assertResolved[T any](service any) (T, error) {
+	result, ok := service.(T)
+	if !ok {
+		return result, &TypeMismatchError{
+			Expected: reflect.TypeFor[T](),
+			Actual:   reflect.TypeOf(service),
+			Context:  "type assertion",
+		}
+	}
+	return result, nil
+}
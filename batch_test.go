@@ -0,0 +1,129 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves_in_order", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTDependency),
+			AddScoped(NewTService),
+			AddScoped(NewTServiceWithDeps),
+		)
+		s := NewTestScope(t, p)
+
+		warm := RequireResolveFrom[*TService](t, s)
+		instances, err := ResolveMany(s,
+			PtrTypeOf[TServiceWithDeps](),
+			PtrTypeOf[TService](),
+			PtrTypeOf[TDependency](),
+			reflect.TypeFor[context.Context](),
+		)
+		require.NoError(t, err)
+		require.Len(t, instances, 4)
+		assert.IsType(t, &TServiceWithDeps{}, instances[0])
+		assert.Same(t, warm, instances[1])
+		assert.Same(t, RequireResolveFrom[*TDependency](t, s), instances[2])
+		assert.Equal(t, s.Context(), instances[3])
+
+		fromProvider, err := ResolveMany(p, PtrTypeOf[TDependency]())
+		require.NoError(t, err)
+		assert.Same(t, instances[2], fromProvider[0])
+
+		empty, err := ResolveMany(s)
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("first_failure_aborts", func(t *testing.T) {
+		t.Parallel()
+		var site ResolutionSite
+		c := NewCollection()
+		c.AddScoped(NewTService)
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			TranslateError: func(err error, s ResolutionSite) error {
+				site = s
+				return err
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		s := NewTestScope(t, p)
+
+		instances, err := ResolveMany(s, PtrTypeOf[TService](), PtrTypeOf[TDependency]())
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.Nil(t, instances)
+		assert.Equal(t, "ResolveMany", site.Operation)
+		assert.Equal(t, PtrTypeOf[TDependency](), site.ServiceType)
+
+		_, err = ResolveMany(s, nil)
+		require.ErrorIs(t, err, ErrServiceTypeNil)
+	})
+
+	t.Run("private_services_are_rejected", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("internal", AddSingleton(NewTService, Private())))
+		_, err := ResolveMany(p, PtrTypeOf[TService]())
+		_, ok := errors.AsType[*PrivateServiceError](err)
+		assert.True(t, ok)
+	})
+
+	t.Run("closed_scope", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTService))
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+		_, err = ResolveMany(s, PtrTypeOf[TService]())
+		require.ErrorIs(t, err, ErrScopeDisposed)
+	})
+
+	t.Run("sealed_views_check_every_type", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTService), AddSingleton(NewTDependency))
+		sealed, err := Seal(p, AllowServices(PtrTypeOf[TService]()))
+		require.NoError(t, err)
+
+		instances, err := ResolveMany(sealed, PtrTypeOf[TService]())
+		require.NoError(t, err)
+		assert.Same(t, RequireResolve[*TService](t, p), instances[0])
+
+		_, err = ResolveMany(sealed, PtrTypeOf[TService](), PtrTypeOf[TDependency]())
+		capErr, ok := errors.AsType[*CapabilityError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[TDependency](), capErr.ServiceType)
+	})
+}
+
+func TestResolveTuples(t *testing.T) {
+	t.Parallel()
+	p := BuildProvider(t, AddSingleton(NewTDependency), AddScoped(NewTService), AddScoped(NewTServiceWithDeps))
+	s := NewTestScope(t, p)
+
+	svc, dep, err := Resolve2[*TService, *TDependency](s)
+	require.NoError(t, err)
+	assert.Same(t, RequireResolveFrom[*TService](t, s), svc)
+	assert.Same(t, RequireResolveFrom[*TDependency](t, s), dep)
+
+	svc, dep, withDeps, err := Resolve3[*TService, *TDependency, *TServiceWithDeps](s)
+	require.NoError(t, err)
+	assert.NotNil(t, svc)
+	assert.NotNil(t, dep)
+	assert.Same(t, svc, withDeps.Svc)
+
+	_, _, err = Resolve2[*TService, *TDisposable](s)
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	_, _, err = Resolve2[*TService, *TDependency](nil)
+	require.ErrorIs(t, err, ErrProviderNil)
+}
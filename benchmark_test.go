@@ -317,6 +317,59 @@ func BenchmarkGenericResolve(b *testing.B) {
 	}
 }
 
+// BenchmarkResolveMany compares batch resolution of warm scoped services
+// with resolving them one at a time
+func BenchmarkResolveMany(b *testing.B) {
+	p := setupBenchProvider(b, Scoped, 5)
+	scope, _ := p.CreateScope(context.Background())
+	defer scope.Close()
+
+	types := []reflect.Type{
+		reflect.TypeFor[*BenchDep1](),
+		reflect.TypeFor[*BenchDep2](),
+		reflect.TypeFor[*BenchDep3](),
+		reflect.TypeFor[*BenchDep4](),
+		reflect.TypeFor[*BenchDep5](),
+	}
+	_, _ = ResolveMany(scope, types...)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, t := range types {
+				_, _ = scope.Get(t)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = ResolveMany(scope, types...)
+		}
+	})
+
+	b.Run("batch_parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = ResolveMany(scope, types...)
+			}
+		})
+	})
+
+	b.Run("sequential_parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, t := range types {
+					_, _ = scope.Get(t)
+				}
+			}
+		})
+	})
+}
+
 // BenchmarkProviderBuild tests provider build performance
 func BenchmarkProviderBuild(b *testing.B) {
 	cases := []struct {
@@ -454,3 +507,44 @@ func BenchmarkMapVsSyncMap(b *testing.B) {
 		})
 	})
 }
+
+// BenchmarkKeyedAndGroupResolution measures warm resolution of keyed
+// services and groups
+func BenchmarkKeyedAndGroupResolution(b *testing.B) {
+	c := NewCollection()
+	c.AddSingleton(NewBenchService, Name("primary"))
+	for range 4 {
+		c.AddSingleton(NewBenchService, Group("handlers"))
+	}
+	c.AddScoped(NewBenchDep1, Name("primary"))
+	p, err := c.Build()
+	if err != nil {
+		b.Fatalf("failed to build provider: %v", err)
+	}
+	b.Cleanup(func() { p.Close() })
+	scope, err := p.CreateScope(context.Background())
+	if err != nil {
+		b.Fatalf("failed to create scope: %v", err)
+	}
+	b.Cleanup(func() { scope.Close() })
+
+	b.Run("Keyed/Singleton", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = ResolveKeyed[*BenchService](scope, "primary")
+		}
+	})
+	b.Run("Keyed/Scoped", func(b *testing.B) {
+		_, _ = ResolveKeyed[*BenchDep1](scope, "primary")
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = ResolveKeyed[*BenchDep1](scope, "primary")
+		}
+	})
+	b.Run("Group/4members", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = ResolveGroup[*BenchService](scope, "handlers")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package godi
+
+import (
+	"maps"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BuildInfo describes the running binary and the provider that resolved
+// it, for health endpoints and startup logs. Constructors receive it by
+// taking godi.BuildInfo as a parameter, and it can be resolved from any
+// provider or scope; it cannot be registered.
+//
+// Example:
+//
+//	func NewHealthHandler(info godi.BuildInfo) *HealthHandler {
+//	    return &HealthHandler{version: info.Version, built: info.Built}
+//	}
+type BuildInfo struct {
+	// Path and Version identify the main module, as reported by
+	// runtime/debug.ReadBuildInfo. Version is "(devel)" for binaries built
+	// from a working tree, and both are empty when the binary carries no
+	// build information.
+	Path    string
+	Version string
+
+	// GoVersion is the version of the Go toolchain that built the binary.
+	GoVersion string
+
+	// GodiVersion is the version of godi the binary was built with.
+	GodiVersion string
+
+	// Revision, RevisionTime and Modified describe the version control
+	// commit the binary was built from, when the toolchain recorded it.
+	Revision     string
+	RevisionTime time.Time
+	Modified     bool
+
+	// ProviderID is the ID of the provider, as returned by Provider.ID.
+	ProviderID string
+
+	// Built is when the provider was built.
+	Built time.Time
+
+	// Profiles lists the active build profiles (see ProviderOptions.Profiles).
+	Profiles []string
+
+	// Registrations counts the provider's registrations by lifetime,
+	// after profiles and pruning were applied.
+	Registrations map[Lifetime]int
+}
+
+// godiModulePath is the path of this module, to find its version among the
+// binary's dependencies.
+const godiModulePath = "github.com/junioryono/godi/v5"
+
+// readBuildInfo returns the build information that does not depend on the
+// provider, read once per process.
+var readBuildInfo = sync.OnceValue(func() BuildInfo {
+	var info BuildInfo
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Path = bi.Main.Path
+	info.Version = bi.Main.Version
+	info.GoVersion = bi.GoVersion
+	if bi.Main.Path == godiModulePath {
+		info.GodiVersion = bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == godiModulePath {
+			info.GodiVersion = dep.Version
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				info.GodiVersion = dep.Replace.Version
+			}
+		}
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.RevisionTime, _ = time.Parse(time.RFC3339, setting.Value)
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+})
+
+// newBuildInfo returns the BuildInfo of a provider built at built from all
+// with the given profiles.
+func newBuildInfo(id string, built time.Time, profiles []string, all []*descriptor) BuildInfo {
+	info := readBuildInfo()
+	info.ProviderID = id
+	info.Built = built
+	info.Profiles = slices.Clone(profiles)
+	info.Registrations = make(map[Lifetime]int)
+	for _, d := range all {
+		if d != nil {
+			info.Registrations[d.Lifetime]++
+		}
+	}
+	return info
+}
+
+// clone returns a copy of info that shares no slices or maps with it, so
+// consumers cannot change what the next one receives.
+func (info BuildInfo) clone() BuildInfo {
+	info.Profiles = slices.Clone(info.Profiles)
+	info.Registrations = maps.Clone(info.Registrations)
+	return info
+}
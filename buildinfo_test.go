@@ -0,0 +1,66 @@
+package godi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("describes_the_provider", func(t *testing.T) {
+		t.Parallel()
+		before := time.Now()
+		c := NewCollection()
+		c.AddSingleton(NewTDependency)
+		c.AddSingleton(NewTService)
+		c.AddScoped(NewTServiceWithDeps)
+		p, err := c.BuildWithOptions(&ProviderOptions{Profiles: []string{"test"}})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		info := RequireResolve[BuildInfo](t, p)
+		assert.Equal(t, p.ID(), info.ProviderID)
+		assert.False(t, info.Built.Before(before))
+		assert.Equal(t, []string{"test"}, info.Profiles)
+		assert.Equal(t, map[Lifetime]int{Singleton: 2, Scoped: 1}, info.Registrations)
+		assert.NotEmpty(t, info.GoVersion)
+
+		encoded, err := json.Marshal(info.Registrations)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"Singleton": 2, "Scoped": 1}`, string(encoded))
+	})
+
+	t.Run("injected_into_constructors", func(t *testing.T) {
+		t.Parallel()
+		type health struct{ info BuildInfo }
+		p := BuildProvider(t, AddScoped(func(info BuildInfo) *health { return &health{info: info} }))
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		h := RequireResolveFrom[*health](t, s)
+		assert.Equal(t, p.ID(), h.info.ProviderID)
+	})
+
+	t.Run("consumers_get_their_own_copy", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDependency))
+		info := RequireResolve[BuildInfo](t, p)
+		info.Registrations[Singleton] = 10
+
+		assert.Equal(t, 1, RequireResolve[BuildInfo](t, p).Registrations[Singleton])
+	})
+
+	t.Run("cannot_be_registered", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() BuildInfo { return BuildInfo{} })
+		assert.Error(t, c.Err())
+	})
+}
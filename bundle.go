@@ -0,0 +1,162 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// Bundle registers the struct T as a service whose exported fields are
+// injected like the fields of a parameter object. Constructors that take the
+// same handful of services can take the bundle instead, while every field
+// stays a declared, validated dependency.
+//
+// Example:
+//
+//	type Common struct {
+//	    Logger Logger
+//	    Clock  Clock
+//	    Tracer Tracer `optional:"true"`
+//	    Audit  AuditLog `name:"primary"`
+//	}
+//
+//	services.AddModules(godi.Bundle[Common]())
+//	services.AddScoped(func(c Common, repo *UserRepository) *UserService { ... })
+//
+// Fields accept the tags of In fields (name, group, optional, soft) and are
+// skipped when unexported or tagged inject:"-". T must be a struct that does
+// not embed In or Out; consumers take it by value.
+//
+// A bundle has no lifetime of its own: Build gives it the shortest lifetime
+// among its fields, so it is scoped if any field is scoped, a singleton if
+// every field is a singleton, and transient otherwise. A singleton that takes
+// a bundle with a scoped field is therefore rejected like one that takes the
+// scoped service directly. opts may name the bundle or add it to a group.
+func Bundle[T any](opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		constructor, err := bundleConstructor(reflect.TypeFor[T]())
+		if err != nil {
+			return err
+		}
+		s.AddTransient(constructor, append(slices.Clip(opts), bundleOption{})...)
+		return nil
+	}
+}
+
+type bundleOption struct{}
+
+func (bundleOption) String() string {
+	return "Bundle()"
+}
+
+func (bundleOption) applyAddOption(opts *addOptions) {
+	opts.bundle = true
+}
+
+// bundleConstructor synthesizes func(struct{ In; fields of t }) t, so the
+// analyzer treats the bundle's fields as parameter-object fields.
+func bundleConstructor(t reflect.Type) (any, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, &ValidationError{
+			ServiceType: t,
+			Cause:       fmt.Errorf("godi.Bundle needs a struct type, not %s", t.Kind()),
+		}
+	}
+	if reflection.HasEmbeddedIn(t) || reflection.HasEmbeddedOut(t) {
+		return nil, &ValidationError{
+			ServiceType: t,
+			Cause:       fmt.Errorf("godi.Bundle types cannot embed godi.In or godi.Out"),
+		}
+	}
+
+	fields := []reflect.StructField{{Name: "In", Type: reflect.TypeFor[In](), Anonymous: true}}
+	var indexes []int
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("inject") == "-" {
+			continue
+		}
+		fields = append(fields, reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag})
+		indexes = append(indexes, i)
+	}
+	if len(indexes) == 0 {
+		return nil, &ValidationError{
+			ServiceType: t,
+			Cause:       fmt.Errorf("godi.Bundle types need at least one exported field"),
+		}
+	}
+
+	params := reflect.StructOf(fields)
+	fnType := reflect.FuncOf([]reflect.Type{params}, []reflect.Type{t}, false)
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		bundle := reflect.New(t).Elem()
+		for i, index := range indexes {
+			bundle.Field(index).Set(args[0].Field(i + 1))
+		}
+		return []reflect.Value{bundle}
+	})
+	return fn.Interface(), nil
+}
+
+// applyBundleLifetimes gives every bundle the shortest lifetime among its
+// fields. Dependencies without a registration are ignored, except built-in
+// services of the resolving scope, which make a bundle at least transient.
+// It runs on a snapshot, before the lifetimes are validated.
+func applyBundleLifetimes(all []*descriptor, services map[TypeKey]*descriptor, groups map[GroupKey][]*descriptor) {
+	lifetimes := make(map[*descriptor]Lifetime)
+
+	var lifetimeOf func(d *descriptor) Lifetime
+	lifetimeOf = func(d *descriptor) Lifetime {
+		if !d.bundle {
+			return d.Lifetime
+		}
+		if lifetime, ok := lifetimes[d]; ok {
+			return lifetime
+		}
+		// A cycle through bundles is reported by the graph; assume the
+		// longest lifetime until this bundle is decided.
+		lifetimes[d] = Singleton
+
+		lifetime := Singleton
+		shorten := func(l Lifetime) {
+			switch {
+			case l == Scoped:
+				lifetime = Scoped
+			case l != Singleton && lifetime == Singleton:
+				lifetime = Transient
+			}
+		}
+		for _, dep := range d.Dependencies {
+			if dep == nil {
+				continue
+			}
+			if dep.Group != "" && dep.Key == nil {
+				for _, member := range groups[GroupKey{Type: dep.Type, Group: dep.Group}] {
+					if member != nil {
+						shorten(lifetimeOf(member))
+					}
+				}
+				continue
+			}
+			if target := services[TypeKey{Type: dep.Type, Key: dep.Key}]; target != nil {
+				shorten(lifetimeOf(target))
+				continue
+			}
+			switch dep.Type {
+			case contextType, scopeType, resolveInfoType, scopeInfoType, scopeWaitGroupType:
+				shorten(Transient)
+			}
+		}
+
+		lifetimes[d] = lifetime
+		return lifetime
+	}
+
+	for _, d := range all {
+		if d != nil && d.bundle {
+			d.Lifetime = lifetimeOf(d)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TBundle struct {
+	Service *TService
+	Dep     *TDependency `name:"primary"`
+	Multi   *TMultiA     `optional:"true"`
+	Skipped *TMultiB     `inject:"-"`
+	note    string
+}
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("injects_the_exported_fields", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("svc")),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			AddSingleton(func() *TMultiB { return &TMultiB{N: 1} }),
+			Bundle[TBundle](),
+		)
+
+		bundle := RequireResolve[TBundle](t, p)
+		assert.Equal(t, "svc", bundle.Service.ID)
+		assert.Equal(t, "primary", bundle.Dep.Name)
+		assert.Nil(t, bundle.Multi)
+		assert.Nil(t, bundle.Skipped)
+		assert.Empty(t, bundle.note)
+	})
+
+	t.Run("consumers_take_the_bundle", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("svc")),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			Bundle[TBundle](),
+			AddSingleton(func(b TBundle) *TServiceWithDeps {
+				return &TServiceWithDeps{Svc: b.Service, Dep: b.Dep}
+			}),
+		)
+
+		svc := RequireResolve[*TServiceWithDeps](t, p)
+		assert.Same(t, RequireResolve[*TService](t, p), svc.Svc)
+		assert.Equal(t, "primary", svc.Dep.Name)
+	})
+
+	t.Run("lifetime_follows_the_fields", func(t *testing.T) {
+		t.Parallel()
+		lifetimeOf := func(t *testing.T, opts ...ModuleOption) Lifetime {
+			t.Helper()
+			p := BuildProvider(t, append(opts,
+				AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+				Bundle[TBundle](),
+			)...)
+			for _, d := range p.(*provider).descriptors {
+				if d.bundle {
+					return d.Lifetime
+				}
+			}
+			t.Fatal("bundle not registered")
+			return 0
+		}
+
+		assert.Equal(t, Singleton, lifetimeOf(t, AddSingleton(NewTService)))
+		assert.Equal(t, Transient, lifetimeOf(t, AddTransient(NewTService)))
+		assert.Equal(t, Scoped, lifetimeOf(t, AddScoped(NewTService)))
+		assert.Equal(t, Scoped, lifetimeOf(t, AddScoped(NewTService), AddTransient(func() *TMultiA { return &TMultiA{} })))
+	})
+
+	t.Run("scoped_bundles_are_shared_within_a_scope", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddScoped(NewTService),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			Bundle[TBundle](),
+		)
+
+		first, second := NewTestScope(t, p), NewTestScope(t, p)
+		assert.Same(t, RequireResolveFrom[TBundle](t, first).Service, RequireResolveFrom[TBundle](t, first).Service)
+		assert.NotSame(t, RequireResolveFrom[TBundle](t, first).Service, RequireResolveFrom[TBundle](t, second).Service)
+	})
+
+	t.Run("singletons_cannot_hide_scoped_fields_behind_a_bundle", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(
+			AddScoped(NewTService),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			Bundle[TBundle](),
+			AddSingleton(func(b TBundle) *TServiceWithDeps { return &TServiceWithDeps{Svc: b.Service} }),
+		)
+
+		_, err := c.Build()
+		var conflict *LifetimeConflictError
+		require.True(t, errors.As(err, &conflict), "got %v", err)
+		assert.Equal(t, TypeOf[TBundle](), conflict.DependencyType)
+		assert.Equal(t, Scoped, conflict.DependencyLifetime)
+	})
+
+	t.Run("bundles_of_bundles", func(t *testing.T) {
+		t.Parallel()
+		type Outer struct {
+			Inner TBundle
+		}
+		c := NewCollection()
+		c.AddModules(
+			AddScoped(NewTService),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			Bundle[TBundle](),
+			Bundle[Outer](),
+			AddSingleton(func(o Outer) *TServiceWithDeps { return &TServiceWithDeps{Svc: o.Inner.Service} }),
+		)
+
+		_, err := c.Build()
+		var conflict *LifetimeConflictError
+		require.True(t, errors.As(err, &conflict), "got %v", err)
+		assert.Equal(t, TypeOf[Outer](), conflict.DependencyType)
+	})
+
+	t.Run("missing_fields_fail_the_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(AddSingleton(NewTService), Bundle[TBundle]())
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TDependency")
+	})
+
+	t.Run("rejects_invalid_types", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Service *TService
+		}
+		type unexported struct {
+			service *TService
+		}
+		for name, option := range map[string]ModuleOption{
+			"pointer":           Bundle[*TBundle](),
+			"param_object":      Bundle[params](),
+			"no_exported_field": Bundle[unexported](),
+		} {
+			c := NewCollection()
+			c.AddModules(AddSingleton(NewTService), option)
+			_, err := c.Build()
+			var validation *ValidationError
+			assert.True(t, errors.As(err, &validation), "%s: got %v", name, err)
+		}
+	})
+
+	t.Run("rejects_memoize", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(
+			AddSingleton(NewTService),
+			AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+			Bundle[TBundle](Memoize[string](nil)),
+		)
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "godi.Bundle cannot be refreshed, unloaded or memoized")
+	})
+}
@@ -0,0 +1,32 @@
+package godi
+
+import "reflect"
+
+// InstanceCache stores the instances a scope memoizes for transients
+// registered with godi.Memoize. By default each scope keeps them in its own
+// instance map until it is closed; set ProviderOptions.NewInstanceCache to
+// supply another implementation, e.g. one that records hit rates or bounds
+// the number of entries.
+//
+// Implementations must be safe for concurrent use. They may drop entries at
+// any time: a dropped instance is constructed again on its next resolution,
+// and both instances are still disposed with the scope.
+type InstanceCache interface {
+	// Get returns the instance cached under key.
+	Get(key CacheKey) (any, bool)
+
+	// Add caches instance under key, replacing any previous entry.
+	Add(key CacheKey, instance any)
+}
+
+// CacheKey identifies a memoized instance in an InstanceCache.
+type CacheKey struct {
+	// ServiceType and ServiceKey identify the registration; ServiceKey is
+	// nil for unkeyed services.
+	ServiceType reflect.Type
+	ServiceKey  any
+
+	// MemoKey is the value returned by the registration's Memoize key
+	// function.
+	MemoKey any
+}
@@ -0,0 +1,118 @@
+package godi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCache is an InstanceCache that records its keys and, when
+// dropAll is set, never keeps an entry.
+type recordingCache struct {
+	mu      sync.Mutex
+	entries map[CacheKey]any
+	added   []CacheKey
+	hits    int
+	dropAll bool
+}
+
+func (c *recordingCache) Get(key CacheKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	instance, ok := c.entries[key]
+	if ok {
+		c.hits++
+	}
+	return instance, ok
+}
+
+func (c *recordingCache) Add(key CacheKey, instance any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.added = append(c.added, key)
+	if !c.dropAll {
+		c.entries[key] = instance
+	}
+}
+
+func TestInstanceCache(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, newCache func() *recordingCache, builds *atomic.Int64) (Provider, *[]*recordingCache) {
+		t.Helper()
+		var (
+			mu     sync.Mutex
+			caches []*recordingCache
+		)
+		c := NewCollection()
+		c.AddTransient(func(ctx context.Context) *memoPermissions {
+			builds.Add(1)
+			return &memoPermissions{User: memoUser(ctx)}
+		}, Memoize(memoUser), Name("perms"))
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			NewInstanceCache: func() InstanceCache {
+				cache := newCache()
+				mu.Lock()
+				caches = append(caches, cache)
+				mu.Unlock()
+				return cache
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p, &caches
+	}
+
+	t.Run("custom_cache_serves_memoized_instances", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p, caches := build(t, func() *recordingCache {
+			return &recordingCache{entries: map[CacheKey]any{}}
+		}, &builds)
+
+		scope, err := p.CreateScope(context.WithValue(t.Context(), memoUserKey{}, "alice"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = scope.Close() })
+
+		first, err := ResolveKeyed[*memoPermissions](scope, "perms")
+		require.NoError(t, err)
+		again, err := ResolveKeyed[*memoPermissions](scope, "perms")
+		require.NoError(t, err)
+		assert.Same(t, first, again)
+		assert.Equal(t, int64(1), builds.Load())
+
+		require.Len(t, *caches, 2, "one cache for the root scope and one for the request scope")
+		cache := (*caches)[1]
+		assert.Equal(t, []CacheKey{{
+			ServiceType: PtrTypeOf[memoPermissions](),
+			ServiceKey:  "perms",
+			MemoKey:     "alice",
+		}}, cache.added)
+		assert.Equal(t, 1, cache.hits)
+	})
+
+	t.Run("dropped_entries_are_rebuilt_and_disposed", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p, _ := build(t, func() *recordingCache {
+			return &recordingCache{entries: map[CacheKey]any{}, dropAll: true}
+		}, &builds)
+
+		scope, err := p.CreateScope(t.Context())
+		require.NoError(t, err)
+		first, err := ResolveKeyed[*memoPermissions](scope, "perms")
+		require.NoError(t, err)
+		second, err := ResolveKeyed[*memoPermissions](scope, "perms")
+		require.NoError(t, err)
+		assert.NotSame(t, first, second)
+		assert.Equal(t, int64(2), builds.Load())
+
+		require.NoError(t, scope.Close())
+		assert.True(t, first.IsClosed())
+		assert.True(t, second.IsClosed())
+	})
+}
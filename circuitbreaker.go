@@ -0,0 +1,127 @@
+package godi
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// CircuitBreaker is an AddOption that stops calling the registration's
+// constructor after it has failed failures times in a row. For the next
+// coolDown, resolutions fail fast with a CircuitOpenError instead of
+// waiting on a downstream that is known to be down. After the cool-down one
+// resolution is let through to try again: if it succeeds the circuit
+// closes, and if it fails the circuit opens for another cool-down.
+//
+// Only errors returned by the constructor and its panics count as
+// failures. A dependency that cannot be resolved or a cancelled context
+// says nothing about the constructor and leaves the count unchanged.
+//
+// Example:
+//
+//	services.AddScoped(NewPaymentsClient, godi.CircuitBreaker(5, 30*time.Second))
+//
+// Each provider built from the collection keeps its own circuit, timed by
+// ProviderOptions.Clock.
+func CircuitBreaker(failures int, coolDown time.Duration) AddOption {
+	return &circuitBreakerOption{failures: failures, coolDown: coolDown}
+}
+
+type circuitBreakerOption struct {
+	failures int
+	coolDown time.Duration
+}
+
+func (o *circuitBreakerOption) String() string {
+	return "CircuitBreaker(" + strconv.Itoa(o.failures) + ", " + o.coolDown.String() + ")"
+}
+
+func (o *circuitBreakerOption) applyAddOption(opts *addOptions) {
+	opts.circuitBreaker = o
+}
+
+// circuitState is the circuit of one registration in one provider.
+type circuitState struct {
+	policy *circuitBreakerOption
+	clock  Clock
+
+	mu        sync.Mutex
+	failures  int       // consecutive constructor failures
+	lastErr   error     // the most recent of them
+	openUntil time.Time // set when failures reaches policy.failures
+	probing   bool      // a resolution is trying the constructor after a cool-down
+}
+
+// acquire reports whether the constructor may be called. Once the circuit
+// is open, it lets a single caller through after the cool-down, reported
+// as the probe, and fails everyone else with the returned error until that
+// caller releases it.
+func (c *circuitState) acquire() (probe bool, open *CircuitOpenError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures < c.policy.failures {
+		return false, nil
+	}
+	if c.probing || c.clock.Now().Before(c.openUntil) {
+		return false, &CircuitOpenError{
+			Failures: c.failures,
+			RetryAt:  c.openUntil,
+			Cause:    c.lastErr,
+		}
+	}
+	c.probing = true
+	return true, nil
+}
+
+// release records the outcome of a constructor call allowed by acquire.
+// err is nil after a success; errors that are not the constructor's own are
+// ignored. Only the probe's release lets another caller probe.
+func (c *circuitState) release(probe bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if probe {
+		c.probing = false
+	}
+	switch {
+	case err == nil:
+		c.failures = 0
+		c.lastErr = nil
+	case constructorFailed(err):
+		c.failures++
+		c.lastErr = err
+		if returned, ok := errors.AsType[*reflection.ReturnError](err); ok {
+			c.lastErr = returned.Err
+		}
+		if c.failures >= c.policy.failures {
+			c.openUntil = c.clock.Now().Add(c.policy.coolDown)
+		}
+	}
+}
+
+// constructorFailed reports whether err, returned by the invoker, is the
+// constructor's own failure rather than one building its arguments.
+func constructorFailed(err error) bool {
+	if _, ok := errors.AsType[*reflection.ArgumentError](err); ok {
+		return false
+	}
+	_, returned := errors.AsType[*reflection.ReturnError](err)
+	_, panicked := errors.AsType[*reflection.PanicError](err)
+	return returned || panicked
+}
+
+// circuit returns the circuit guarding d's constructor in p, or nil. The
+// members of one registration share a constructor and so a circuit.
+func (p *provider) circuit(d *descriptor) *circuitState {
+	if p.circuits == nil || d.circuitBreaker == nil {
+		return nil
+	}
+	if len(d.siblings) > 0 {
+		d = d.siblings[0]
+	}
+	return p.circuits[d]
+}
@@ -0,0 +1,202 @@
+package godi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a Clock that only moves when advanced.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
+
+// flakyDependency constructs a TDependency, failing with its err while set.
+type flakyDependency struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (f *flakyDependency) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *flakyDependency) constructor() (*TDependency, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return NewTDependency(), nil
+}
+
+func (f *flakyDependency) called() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, clock Clock, opts ...ModuleOption) Provider {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(opts...)
+		require.NoError(t, c.Err())
+		p, err := c.BuildWithOptions(&ProviderOptions{Clock: clock})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("opens_after_consecutive_failures", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("connection refused")
+		flaky := &flakyDependency{err: boom}
+		clock := &manualClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+		p := build(t, clock, AddTransient(flaky.constructor, CircuitBreaker(3, time.Minute)))
+
+		for range 3 {
+			_, err := Resolve[*TDependency](p)
+			require.ErrorIs(t, err, boom)
+			_, open := errors.AsType[*CircuitOpenError](err)
+			require.False(t, open)
+		}
+
+		_, err := Resolve[*TDependency](p)
+		open, ok := errors.AsType[*CircuitOpenError](err)
+		require.True(t, ok, "expected CircuitOpenError, got %v", err)
+		assert.Equal(t, 3, flaky.called(), "the constructor is not called while the circuit is open")
+		assert.Equal(t, 3, open.Failures)
+		assert.Equal(t, clock.Now().Add(time.Minute), open.RetryAt)
+		assert.Equal(t, PtrTypeOf[TDependency](), open.ServiceType)
+		assert.ErrorIs(t, err, boom, "the last failure is the cause")
+
+		clock.Advance(time.Minute)
+		_, err = Resolve[*TDependency](p)
+		require.ErrorIs(t, err, boom)
+		assert.Equal(t, 4, flaky.called(), "one resolution tries again after the cool-down")
+		_, err = Resolve[*TDependency](p)
+		require.ErrorAs(t, err, new(*CircuitOpenError), "a failed retry opens the circuit again")
+
+		flaky.fail(nil)
+		clock.Advance(time.Minute)
+		RequireResolve[*TDependency](t, p)
+		RequireResolve[*TDependency](t, p)
+		assert.Equal(t, 6, flaky.called())
+	})
+
+	t.Run("success_resets_the_count", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		flaky := &flakyDependency{}
+		p := build(t, &manualClock{}, AddTransient(flaky.constructor, CircuitBreaker(2, time.Minute)))
+
+		for range 3 {
+			flaky.fail(boom)
+			_, err := Resolve[*TDependency](p)
+			require.ErrorIs(t, err, boom)
+			flaky.fail(nil)
+			RequireResolve[*TDependency](t, p)
+		}
+	})
+
+	t.Run("one_probe_at_a_time", func(t *testing.T) {
+		t.Parallel()
+		clock := &manualClock{}
+		c := &circuitState{policy: &circuitBreakerOption{failures: 1, coolDown: time.Minute}, clock: clock}
+
+		early, open := c.acquire()
+		require.Nil(t, open)
+		assert.False(t, early)
+		first, _ := c.acquire()
+		c.release(first, &reflection.ReturnError{Err: errors.New("boom")})
+
+		clock.Advance(time.Minute)
+		probe, open := c.acquire()
+		require.Nil(t, open)
+		assert.True(t, probe)
+
+		// The caller admitted before the circuit opened finishes during
+		// the probe, failing on a dependency.
+		c.release(early, &reflection.ArgumentError{Err: errors.New("dependency")})
+		_, open = c.acquire()
+		require.NotNil(t, open, "a second caller must not probe")
+
+		c.release(probe, nil)
+		_, open = c.acquire()
+		assert.Nil(t, open)
+	})
+
+	t.Run("dependents_fail_fast", func(t *testing.T) {
+		t.Parallel()
+		flaky := &flakyDependency{err: errors.New("boom")}
+		p := build(t, &manualClock{},
+			AddTransient(flaky.constructor, CircuitBreaker(1, time.Minute)),
+			AddSingleton(NewTService),
+			AddTransient(NewTServiceWithDeps),
+		)
+
+		_, err := Resolve[*TServiceWithDeps](p)
+		require.Error(t, err)
+		_, err = Resolve[*TServiceWithDeps](p)
+		open, ok := errors.AsType[*CircuitOpenError](err)
+		require.True(t, ok, "expected CircuitOpenError, got %v", err)
+		require.Len(t, open.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), open.Path[0].ServiceType)
+		assert.Equal(t, 1, flaky.called())
+	})
+
+	t.Run("dependency_failures_do_not_count", func(t *testing.T) {
+		t.Parallel()
+		flaky := &flakyDependency{err: errors.New("boom")}
+		p := build(t, &manualClock{},
+			AddTransient(flaky.constructor),
+			AddSingleton(NewTService),
+			AddTransient(NewTServiceWithDeps, CircuitBreaker(1, time.Minute)),
+		)
+
+		for range 3 {
+			_, err := Resolve[*TServiceWithDeps](p)
+			require.Error(t, err)
+			require.False(t, errors.As(err, new(*CircuitOpenError)), "got %v", err)
+		}
+		assert.Equal(t, 3, flaky.called())
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		t.Parallel()
+		for _, opt := range []AddOption{CircuitBreaker(0, time.Minute), CircuitBreaker(3, 0)} {
+			c := NewCollection()
+			c.AddTransient(NewTDependency, opt)
+			require.ErrorContains(t, c.Err(), "must be positive")
+		}
+	})
+}
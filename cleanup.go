@@ -0,0 +1,141 @@
+package godi
+
+import (
+	"context"
+	"sync"
+)
+
+// Cleanup registers functions that release what a constructor creates
+// besides the instance it returns, such as temporary directories or
+// tickers, which would otherwise need a wrapper type only to be disposed.
+// Constructors receive one by taking Cleanup as a parameter; it cannot be
+// registered.
+//
+// The functions share the lifetime of the instance: a singleton's run when
+// the provider closes, a refreshing or unloadable service's when its
+// instance is disposed after Invalidate or Unload, and any other service's
+// when the scope that constructed it closes. They run in reverse order of
+// Add, after the instance itself is closed. They receive the context given
+// to StopAll, or context.Background() when closed by Close. If the
+// constructor fails, the functions it added run right away and their errors
+// are discarded; the resolution reports the constructor's error.
+//
+// Resolved outside a constructor, for example by a function given to
+// Invoke, a Cleanup adds functions to the scope it was resolved from.
+//
+// Example:
+//
+//	func NewIndexer(cleanup godi.Cleanup) (*Indexer, error) {
+//	    dir, err := os.MkdirTemp("", "index")
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    cleanup.Add(func(context.Context) error { return os.RemoveAll(dir) })
+//
+//	    ticker := time.NewTicker(time.Minute)
+//	    cleanup.Add(func(context.Context) error { ticker.Stop(); return nil })
+//	    return &Indexer{dir: dir, ticks: ticker.C}, nil
+//	}
+type Cleanup struct {
+	list *cleanupList
+}
+
+// Add registers fn to run when the instance being constructed is disposed.
+// A nil fn is ignored. Add panics on a Cleanup the container did not
+// provide.
+func (c Cleanup) Add(fn func(ctx context.Context) error) {
+	if c.list == nil {
+		panic("godi: Cleanup used without being injected")
+	}
+	if fn != nil {
+		c.list.add(fn)
+	}
+}
+
+// cleanupList collects the functions added through the Cleanup of one
+// constructor invocation. Until the constructor returns they are pending,
+// so a failed construction can run them; afterwards they are tracked for
+// disposal like the instance, including those added later by a constructor
+// that kept its Cleanup.
+type cleanupList struct {
+	mu         sync.Mutex
+	scope      *scope
+	descriptor *descriptor        // nil when resolved outside a constructor
+	generation *refreshGeneration // set for refreshing and unloadable services
+	pending    []func(ctx context.Context) error
+	settled    bool
+}
+
+// cleanup returns the Cleanup of the constructor invocation r, resolved in s.
+func (r *resolution) cleanup(s *scope) Cleanup {
+	if r.top() {
+		return Cleanup{list: &cleanupList{scope: s, settled: true}}
+	}
+	if r.cleanups == nil {
+		r.cleanups = &cleanupList{scope: s, descriptor: r.descriptor}
+		if state := s.rootProvider.refreshing[r.descriptor]; state != nil {
+			// The constructor runs under state.mu, building this generation.
+			r.cleanups.generation = state.building
+		}
+	}
+	return Cleanup{list: r.cleanups}
+}
+
+func (l *cleanupList) add(fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	if !l.settled {
+		l.pending = append(l.pending, fn)
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+	l.track(fn)
+}
+
+// settle ends the constructor invocation: the pending functions are tracked
+// for disposal if it succeeded, and run now in reverse order if it failed.
+func (l *cleanupList) settle(failed bool) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.settled = true
+	l.mu.Unlock()
+
+	if failed {
+		for i := len(pending) - 1; i >= 0; i-- {
+			closeOrphan(&cleanupFunc{fn: pending[i]})
+		}
+		return
+	}
+	for _, fn := range pending {
+		l.track(fn)
+	}
+}
+
+// track hands fn to whoever disposes the instance being constructed.
+func (l *cleanupList) track(fn func(ctx context.Context) error) {
+	d := &cleanupFunc{fn: fn}
+	if l.generation != nil {
+		l.generation.addCleanup(d)
+		return
+	}
+	if l.descriptor != nil && l.descriptor.Lifetime == Singleton {
+		l.scope.rootProvider.trackDisposable(l.descriptor, d)
+		return
+	}
+	l.scope.appendDisposable(l.descriptor, d)
+}
+
+// cleanupFunc adapts a function added through Cleanup to
+// DisposableWithContext.
+type cleanupFunc struct {
+	fn func(ctx context.Context) error
+}
+
+func (c *cleanupFunc) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+func (c *cleanupFunc) CloseContext(ctx context.Context) error {
+	return c.fn(ctx)
+}
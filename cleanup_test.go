@@ -0,0 +1,165 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logCleanup returns a cleanup function adding name to log.
+func logCleanup(log *closeLog, name string) func(context.Context) error {
+	return func(context.Context) error {
+		log.add(name)
+		return nil
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs_after_the_instance_when_the_scope_closes", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		p := BuildProvider(t, AddScoped(func(cleanup Cleanup) *TLoggedCloser {
+			cleanup.Add(logCleanup(log, "first"))
+			cleanup.Add(logCleanup(log, "second"))
+			return &TLoggedCloser{name: "instance", log: log}
+		}))
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		RequireResolveFrom[*TLoggedCloser](t, s)
+		assert.Empty(t, log.names())
+		require.NoError(t, s.Close())
+		assert.Equal(t, []string{"instance", "second", "first"}, log.names())
+	})
+
+	t.Run("singletons_are_cleaned_up_with_the_provider", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		c := NewCollection()
+		c.AddSingleton(func(cleanup Cleanup) *TService {
+			cleanup.Add(logCleanup(log, "singleton"))
+			return &TService{}
+		})
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		RequireResolveFrom[*TService](t, s)
+		require.NoError(t, s.Close())
+		assert.Empty(t, log.names())
+
+		require.NoError(t, p.Close())
+		assert.Equal(t, []string{"singleton"}, log.names())
+	})
+
+	t.Run("retired_generations_are_cleaned_up", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		c := NewCollection()
+		c.AddSingleton(func(cleanup Cleanup) *TService {
+			cleanup.Add(logCleanup(log, "unloadable"))
+			return &TService{}
+		}, Unloadable())
+		c.AddModules(AddRefreshing(func(cleanup Cleanup) *TLoggedCloser {
+			cleanup.Add(logCleanup(log, "cleanup"))
+			return &TLoggedCloser{name: "refreshing", log: log}
+		}))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		for range 3 {
+			s := NewTestScope(t, p)
+			RequireResolveFrom[*TService](t, s)
+			require.NoError(t, s.Close())
+			require.NoError(t, Unload[*TService](p))
+		}
+		assert.Equal(t, []string{"unloadable", "unloadable", "unloadable"}, log.names())
+
+		s := NewTestScope(t, p)
+		RequireResolveFrom[*TLoggedCloser](t, s)
+		require.NoError(t, Invalidate[*TLoggedCloser](p))
+		assert.Len(t, log.names(), 3, "held by an open scope")
+		require.NoError(t, s.Close())
+		assert.Equal(t, []string{"refreshing", "cleanup"}, log.names()[3:])
+
+		require.NoError(t, p.Close())
+		assert.Len(t, log.names(), 5)
+	})
+
+	t.Run("failed_constructors_clean_up_immediately", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		boom := errors.New("boom")
+		p := BuildProvider(t, AddScoped(func(cleanup Cleanup) (*TService, error) {
+			cleanup.Add(logCleanup(log, "temp dir"))
+			return nil, boom
+		}))
+		s := NewTestScope(t, p)
+
+		_, err := Resolve[*TService](s)
+		require.ErrorIs(t, err, boom)
+		assert.Equal(t, []string{"temp dir"}, log.names())
+	})
+
+	t.Run("errors_are_reported_on_close", func(t *testing.T) {
+		t.Parallel()
+		failed := errors.New("remove failed")
+		p := BuildProvider(t, AddScoped(func(cleanup Cleanup) *TService {
+			cleanup.Add(func(context.Context) error { return failed })
+			return &TService{}
+		}))
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		RequireResolveFrom[*TService](t, s)
+		assert.ErrorIs(t, s.Close(), failed)
+	})
+
+	t.Run("added_after_construction", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		type holder struct{ cleanup Cleanup }
+		p := BuildProvider(t, AddScoped(func(cleanup Cleanup) *holder { return &holder{cleanup: cleanup} }))
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		RequireResolveFrom[*holder](t, s).cleanup.Add(logCleanup(log, "late"))
+		require.NoError(t, s.Close())
+		assert.Equal(t, []string{"late"}, log.names())
+	})
+
+	t.Run("invoke_adds_to_the_scope", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		p := BuildProvider(t)
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, Invoke(s, func(cleanup Cleanup) {
+			cleanup.Add(logCleanup(log, "invoke"))
+		}))
+		assert.Empty(t, log.names())
+		require.NoError(t, s.Close())
+		assert.Equal(t, []string{"invoke"}, log.names())
+	})
+
+	t.Run("cannot_be_registered", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() Cleanup { return Cleanup{} })
+		assert.Error(t, c.Err())
+	})
+
+	t.Run("zero_value_panics", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, "godi: Cleanup used without being injected", func() {
+			Cleanup{}.Add(func(context.Context) error { return nil })
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package godi
+
+import "time"
+
+// Clock is the time source the container uses for its own timing: the
+// intervals of AddRefreshing services, ProviderOptions.ScopeWaitTimeout, and
+// the creation times of scopes. Set it with ProviderOptions.Clock, typically
+// to a ditest.Clock so that tests of refreshing services and close timeouts
+// advance time explicitly instead of sleeping.
+//
+// Constructor durations reported to observers, and deadlines of contexts
+// passed to godi, are measured with the system clock regardless.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine once d has elapsed, unless
+	// the returned timer is stopped first.
+	AfterFunc(d time.Duration, f func()) ClockTimer
+}
+
+// ClockTimer is a timer started by Clock.AfterFunc.
+type ClockTimer interface {
+	// Stop prevents the timer from firing. It returns false if the timer
+	// already fired or was stopped.
+	Stop() bool
+}
+
+// systemClock is the Clock used when ProviderOptions.Clock is nil.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
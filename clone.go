@@ -0,0 +1,112 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Clone is an AddOption for singleton and scoped services that hands every
+// consumer its own copy of the shared instance, made by clone. The cached
+// instance stays untouched, so a consumer that mutates its copy, such as a
+// config struct it adjusts for its own use, can't corrupt the value other
+// consumers see.
+//
+// Example:
+//
+//	services.AddSingleton(LoadConfig, godi.Clone(func(c *Config) *Config {
+//	    copied := *c
+//	    copied.Features = maps.Clone(c.Features)
+//	    return &copied
+//	}))
+//
+// T must be the type the service is registered as, and the constructor must
+// produce a single service value. With ProviderOptions.OnMutation set,
+// consumers receive the shared instance instead, and mutations to it are
+// reported; see MutationError.
+func Clone[T any](clone func(T) T) AddOption {
+	policy := &clonePolicy{serviceType: reflect.TypeFor[T]()}
+	if clone != nil {
+		policy.clone = func(instance any) any { return clone(instance.(T)) }
+	}
+	return addCloneOption{policy: policy}
+}
+
+type addCloneOption struct {
+	policy *clonePolicy
+}
+
+func (o addCloneOption) String() string {
+	t := formatType(o.policy.serviceType)
+	return fmt.Sprintf("Clone(func(%s) %s)", t, t)
+}
+
+func (o addCloneOption) applyAddOption(opt *addOptions) {
+	opt.clone = o.policy
+}
+
+// clonePolicy configures a Clone registration.
+type clonePolicy struct {
+	clone       func(any) any
+	serviceType reflect.Type
+}
+
+// validateClone checks that a Clone registration can be copied.
+func validateClone(d *descriptor) error {
+	if d.Lifetime != Singleton && d.Lifetime != Scoped {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("godi.Clone can only be used with AddSingleton or AddScoped, not %s", d.Lifetime),
+		}
+	}
+	if d.VoidReturn || d.isResultObject || d.serviceOutputs() > 1 {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("cloned services need a constructor returning a single service value"),
+		}
+	}
+	if d.Type != d.cloner.serviceType {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("godi.Clone copies %s, but the service is registered as %s", formatType(d.cloner.serviceType), formatType(d.Type)),
+		}
+	}
+	return nil
+}
+
+// cloneShared returns the copy of a Clone registration's shared instance
+// that a consumer receives. Under ProviderOptions.OnMutation it returns the
+// instance itself, after comparing it with a copy taken when it was first
+// handed out.
+func (s *scope) cloneShared(key instanceKey, descriptor *descriptor, instance any) any {
+	onMutation := s.rootProvider.onMutation
+	if onMutation == nil {
+		return descriptor.cloner.clone(instance)
+	}
+	if descriptor.refresh != nil {
+		return instance // each generation is a new instance
+	}
+
+	owner := s.rootProvider.rootScope
+	if descriptor.Lifetime == Scoped {
+		owner = s
+		for owner.inheritScoped {
+			owner = owner.parentScope
+		}
+	}
+
+	pristine, ok := owner.snapshots.Load(descriptor)
+	if !ok {
+		owner.snapshots.LoadOrStore(descriptor, descriptor.cloner.clone(instance))
+		return instance
+	}
+	if !reflect.DeepEqual(pristine, instance) {
+		owner.snapshots.Store(descriptor, descriptor.cloner.clone(instance))
+		onMutation(&MutationError{
+			ServiceType: key.Type,
+			ServiceKey:  key.Key,
+			ScopeID:     owner.id,
+			Source:      descriptor.source(),
+		})
+	}
+	return instance
+}
@@ -0,0 +1,93 @@
+package godi
+
+import (
+	"maps"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Name     string
+		Features map[string]bool
+	}
+	newConfig := func() *config {
+		return &config{Name: "app", Features: map[string]bool{"beta": false}}
+	}
+	copyConfig := func(c *config) *config {
+		copied := *c
+		copied.Features = maps.Clone(c.Features)
+		return &copied
+	}
+
+	t.Run("consumers_get_copies", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(newConfig, Clone(copyConfig)))
+
+		first := RequireResolve[*config](t, p)
+		first.Features["beta"] = true
+		first.Name = "changed"
+
+		second := RequireResolve[*config](t, p)
+		assert.NotSame(t, first, second)
+		assert.Equal(t, "app", second.Name)
+		assert.False(t, second.Features["beta"])
+	})
+
+	t.Run("dependencies_get_copies", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddScoped(newConfig, Clone(copyConfig)),
+			AddScoped(func(c *config) *TService { c.Name = "mutated"; return NewTService() }),
+		)
+		scope := NewTestScope(t, p)
+
+		RequireResolveFrom[*TService](t, scope)
+		assert.Equal(t, "app", RequireResolveFrom[*config](t, scope).Name)
+	})
+
+	t.Run("detection_reports_mutations", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var reported []*MutationError
+		c := NewCollection()
+		c.AddSingleton(newConfig, Clone(copyConfig))
+		p, err := c.BuildWithOptions(&ProviderOptions{OnMutation: func(err *MutationError) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		}})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		first := RequireResolve[*config](t, p)
+		assert.Same(t, first, RequireResolve[*config](t, p))
+		assert.Empty(t, reported)
+
+		first.Features["beta"] = true
+		RequireResolve[*config](t, p)
+		RequireResolve[*config](t, p)
+		require.Len(t, reported, 1, "a mutation is reported once")
+		assert.Equal(t, PtrTypeOf[config](), reported[0].ServiceType)
+		assert.Contains(t, reported[0].Error(), "was mutated after it was handed out")
+	})
+
+	t.Run("rejects_invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddTransient(newConfig, Clone(copyConfig))
+		c.AddSingleton(NewTService, Clone(func(s TInterface) TInterface { return s }))
+		c.AddSingleton(NewTDependency, Clone[*TDependency](nil))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "godi.Clone can only be used with AddSingleton or AddScoped, not Transient")
+		assert.Contains(t, err.Error(), "godi.Clone copies TInterface, but the service is registered as *TService")
+		assert.Contains(t, err.Error(), "godi.Clone function cannot be nil")
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -22,8 +25,10 @@ var providerIDCounter atomic.Uint64
 // Collection follows a builder pattern where services are registered
 // with their lifetimes and dependencies, then built into a Provider.
 //
-// Collection is NOT thread-safe. It should be configured in a single
-// goroutine before building the Provider.
+// Registering, removing, and inspecting services is safe for concurrent use,
+// but modules applied from several goroutines at once would be attributed to
+// each other's names. To build registration fragments in parallel, give each
+// goroutine its own collection and Merge them.
 //
 // Example:
 //
@@ -50,6 +55,12 @@ type Collection interface {
 	// for validation and behavior configuration.
 	BuildWithOptions(options *ProviderOptions) (Provider, error)
 
+	// Merge copies every registration of other into this collection, along
+	// with the registration errors recorded on it. If any of them conflicts
+	// with an existing registration, nothing is merged and the conflicts are
+	// recorded instead. other is left unchanged.
+	Merge(other Collection)
+
 	// AddModules applies one or more module configurations to the service collection.
 	// Modules provide a way to group related service registrations.
 	// Registration errors are recorded and reported by Build (or Err).
@@ -72,6 +83,13 @@ type Collection interface {
 	// Registration errors are recorded and reported by Build (or Err).
 	AddTransient(service any, opts ...AddOption)
 
+	// AddValue registers an existing value as a singleton, never treating
+	// it as a constructor. Values of built-in types and of slices, arrays,
+	// and maps of them, such as []string, must be given a godi.Name or
+	// godi.Group since their type alone does not say what they are.
+	// Registration errors are recorded and reported by Build (or Err).
+	AddValue(value any, opts ...AddOption)
+
 	// Err returns all registration errors recorded so far, joined into a
 	// single error, or nil if every registration succeeded. Build returns
 	// the same errors, so checking Err is only needed when inspecting the
@@ -96,6 +114,11 @@ type Collection interface {
 
 	// Count returns the number of registered services.
 	Count() int
+
+	// Graph returns a read-only view of the dependency graph of the
+	// registrations made so far. Unlike a provider's graph (see GraphOf) it
+	// has not been validated, so dependencies may be missing or cyclic.
+	Graph() GraphView
 }
 
 // Collection is the core service registry that manages services.
@@ -121,6 +144,10 @@ type collection struct {
 	// moduleStack tracks the modules currently being applied so that
 	// registration errors recorded inside a module carry the module's name.
 	moduleStack []string
+
+	// decorators holds the ModuleDecorate decorators of the modules being
+	// applied; they are attached to registrations made inside those modules.
+	decorators []*decorator
 }
 
 // TypeKey uniquely identifies a keyed service
@@ -145,7 +172,7 @@ type ServiceInfo struct {
 	Key any
 	// Group is the value-group name for grouped services, or "".
 	Group string
-	// Lifetime is the service's lifetime (Singleton, Scoped, or Transient).
+	// Lifetime is the service's lifetime.
 	Lifetime Lifetime
 }
 
@@ -177,7 +204,7 @@ func (sc *collection) BuildWithContext(ctx context.Context) (Provider, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return sc.doBuild(ctx)
+	return sc.doBuild(ctx, nil)
 }
 
 // BuildWithOptions creates a Provider with custom options for validation and behavior configuration.
@@ -191,10 +218,14 @@ func (sc *collection) BuildWithOptions(options *ProviderOptions) (Provider, erro
 		defer cancel()
 	}
 
-	return sc.doBuild(ctx)
+	return sc.doBuild(ctx, options)
 }
 
-func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
+func (sc *collection) doBuild(ctx context.Context, options *ProviderOptions) (Provider, error) {
+	if options == nil {
+		options = &ProviderOptions{}
+	}
+
 	// Check context before starting
 	select {
 	case <-ctx.Done():
@@ -206,17 +237,32 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 	default:
 	}
 
+	if err := options.validate(); err != nil {
+		return nil, &BuildError{
+			Phase:   "initialization",
+			Details: "invalid provider options",
+			Cause:   err,
+		}
+	}
+
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
 	// Surface every recorded registration error before doing any work:
 	// the Add* methods defer their errors to Build so callers can register
 	// services without per-call error checks.
-	if len(sc.errs) > 0 {
+	errs := sc.errs
+	if options.IgnoreDuplicateRegistrations {
+		errs = slices.DeleteFunc(slices.Clone(errs), func(err error) bool {
+			_, duplicate := errors.AsType[*DuplicateRegistrationError](err)
+			return duplicate
+		})
+	}
+	if len(errs) > 0 {
 		return nil, &BuildError{
 			Phase:   "registration",
 			Details: "one or more service registrations failed",
-			Cause:   errors.Join(sc.errs...),
+			Cause:   errors.Join(errs...),
 		}
 	}
 
@@ -228,6 +274,44 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 		sc.services,
 		sc.groups,
 	)
+	allDescriptors, services, groups = applyProfiles(options.Profiles, allDescriptors, services, groups)
+	applyKeyedFallbacks(allDescriptors, services)
+	applyBundleLifetimes(allDescriptors, services, groups)
+	if err := applyFeatureFlags(allDescriptors, services); err != nil {
+		return nil, &BuildError{
+			Phase:   "validation",
+			Details: "flagged registrations have no fallback",
+			Cause:   err,
+		}
+	}
+
+	if options.PruneUnreachable {
+		var pruned []*descriptor
+		var err error
+		allDescriptors, services, groups, pruned, err = pruneUnreachable(options.Roots, allDescriptors, services, groups)
+		if err != nil {
+			return nil, &BuildError{
+				Phase:   "pruning",
+				Details: "invalid root services",
+				Cause:   err,
+			}
+		}
+		if options.OnPrune != nil {
+			infos := make([]ServiceInfo, len(pruned))
+			for i, d := range pruned {
+				infos[i] = d.serviceInfo()
+			}
+			options.OnPrune(infos)
+		}
+	}
+
+	// Validation runs on the snapshot so pruned registrations are skipped.
+	registry := &collection{
+		services:       services,
+		groups:         groups,
+		allDescriptors: allDescriptors,
+		analyzer:       sc.analyzer,
+	}
 
 	// Phase 1: Build dependency graph (validates cycles as part of build)
 	select {
@@ -283,7 +367,7 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 	default:
 	}
 
-	if err := sc.validateLifetimes(); err != nil {
+	if err := registry.validateLifetimes(); err != nil {
 		return nil, &BuildError{
 			Phase:   "validation",
 			Details: "lifetime validation failed",
@@ -291,6 +375,92 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 		}
 	}
 
+	if options.AutoCollectSlices {
+		if err := validateCollectedLifetimes(allDescriptors, services); err != nil {
+			return nil, &BuildError{
+				Phase:   "validation",
+				Details: "lifetime validation failed",
+				Cause:   err,
+			}
+		}
+	}
+
+	if err := registry.validateGroupMembers(); err != nil {
+		return nil, &BuildError{
+			Phase:   "validation",
+			Details: "group member validation failed",
+			Cause:   err,
+		}
+	}
+
+	if err := registry.validatePrivateServices(); err != nil {
+		return nil, &BuildError{
+			Phase:   "validation",
+			Details: "private services are used outside their modules",
+			Cause:   err,
+		}
+	}
+
+	if options.OnAuditedResolution == nil {
+		if err := validateAudited(allDescriptors); err != nil {
+			return nil, &BuildError{
+				Phase:   "validation",
+				Details: "audited services need ProviderOptions.OnAuditedResolution",
+				Cause:   err,
+			}
+		}
+	}
+
+	if options.DisallowServiceLocator {
+		if err := registry.validateServiceLocators(options.ServiceLocatorAllowlist); err != nil {
+			return nil, &BuildError{
+				Phase:   "validation",
+				Details: "service locator usage is disallowed",
+				Cause:   err,
+			}
+		}
+	}
+
+	if options.StrictDisposal || options.OnDisposalMismatch != nil {
+		mismatches := disposalMismatches(allDescriptors)
+		if options.StrictDisposal && len(mismatches) > 0 {
+			errs := make([]error, len(mismatches))
+			for i, m := range mismatches {
+				errs[i] = m
+			}
+			return nil, &BuildError{
+				Phase:   "validation",
+				Details: "services have cleanup methods godi does not call",
+				Cause:   errors.Join(errs...),
+			}
+		}
+		if options.OnDisposalMismatch != nil {
+			for _, m := range mismatches {
+				options.OnDisposalMismatch(m)
+			}
+		}
+	}
+
+	exports, exportErr := exportedNames(allDescriptors)
+	if exportErr != nil {
+		return nil, &BuildError{
+			Phase:   "validation",
+			Details: "exported service names are not unique",
+			Cause:   exportErr,
+		}
+	}
+
+	if len(options.Validators) > 0 {
+		view := newGraphView(allDescriptors, services, groups)
+		if err := runGraphValidators(options.Validators, view); err != nil {
+			return nil, &BuildError{
+				Phase:   "validation",
+				Details: "custom graph validation failed",
+				Cause:   err,
+			}
+		}
+	}
+
 	// Phase 4: Create provider with fast ID generation
 	// Count void-return scoped descriptors for pre-allocation
 	voidCount := 0
@@ -304,20 +474,73 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 		id:                          "p" + strconv.FormatUint(providerIDCounter.Add(1), 36),
 		services:                    services,
 		groups:                      groups,
+		descriptors:                 allDescriptors,
 		graph:                       g,
 		analyzer:                    sc.analyzer, // Share analyzer from collection
 		singletonKeys:               make([]instanceKey, 0, len(allDescriptors)),
 		voidReturnScopedDescriptors: make([]*descriptor, 0, voidCount),
-		disposables:                 make([]Disposable, 0, 4),
+		disposables:                 make([]trackedDisposable, 0, 4),
 		disposableSet:               make(map[disposableIdentity]struct{}, 4),
 		scopes:                      make(map[*scope]struct{}, 4),
 		closeDone:                   make(chan struct{}),
-	}
+		fallback:                    options.Fallback,
+		exports:                     exports,
+		scopeWaitTimeout:            options.ScopeWaitTimeout,
+		translate:                   options.TranslateError,
+		onAudited:                   options.OnAuditedResolution,
+		onDeprecated:                options.OnDeprecatedResolution,
+		onConstructed:               options.OnServiceConstructed,
+		onResolved:                  options.OnServiceResolved,
+		onResolveError:              options.OnServiceError,
+		sampleRate:                  options.CallbackSampleRate,
+		onMutation:                  options.OnMutation,
+		maxConcurrentConstructions:  options.MaxConcurrentConstructions,
+		newInstanceCache:            options.NewInstanceCache,
+		allowScopedFromRoot:         options.AllowScopedFromRoot,
+		validateScopes:              options.ValidateScopes,
+		idGenerator:                 options.IDGenerator,
+		clock:                       options.Clock,
+		onOptionalFailure:           options.OnOptionalFailure,
+		beforeClose:                 options.BeforeClose,
+		requireScopesClosed:         options.RequireScopesClosed,
+		maxInstancesPerScope:        options.MaxInstancesPerScope,
+		scopePoolSize:               options.ScopePoolSize,
+		featureGate:                 options.FeatureGate,
+		autoCollect:                 options.AutoCollectSlices,
+		profileLabels:               options.ProfileLabels,
+	}
+	if p.clock == nil {
+		p.clock = systemClock{}
+	}
+	if p.scopePoolSize == 0 {
+		p.scopePoolSize = defaultScopePoolSize
+	}
+	p.buildInfo = newBuildInfo(p.id, p.clock.Now(), options.Profiles, allDescriptors)
 
 	for _, descriptor := range allDescriptors {
 		if descriptor != nil && descriptor.Lifetime == Scoped && descriptor.VoidReturn {
 			p.voidReturnScopedDescriptors = append(p.voidReturnScopedDescriptors, descriptor)
 		}
+		if descriptor != nil && descriptor.private {
+			p.hasPrivate = true
+		}
+		if descriptor != nil && options.CallbackFilter != nil {
+			descriptor.unobserved = !options.CallbackFilter(descriptor.serviceInfo())
+		}
+	}
+	for _, d := range allDescriptors {
+		if d != nil && d.refresh != nil {
+			if p.refreshing == nil {
+				p.refreshing = make(map[*descriptor]*refreshState)
+			}
+			p.refreshing[d] = &refreshState{descriptor: d, every: d.refresh.every, clock: p.clock}
+		}
+		if d != nil && d.circuitBreaker != nil && (len(d.siblings) == 0 || d.siblings[0] == d) {
+			if p.circuits == nil {
+				p.circuits = make(map[*descriptor]*circuitState)
+			}
+			p.circuits[d] = &circuitState{policy: d.circuitBreaker, clock: p.clock}
+		}
 	}
 
 	// Phase 5: Create root scope
@@ -333,7 +556,17 @@ func (sc *collection) doBuild(ctx context.Context) (Provider, error) {
 
 	var err error
 	rootCtx := context.Background()
-	p.rootScope, err = newUninitializedScope(p, nil, rootCtx, nil)
+	var rootID string
+	if p.idGenerator != nil {
+		if rootID, err = p.generateScopeID(); err != nil {
+			return nil, &BuildError{
+				Phase:   "scope-creation",
+				Details: "failed to generate root scope ID",
+				Cause:   err,
+			}
+		}
+	}
+	p.rootScope, err = newUninitializedScope(p, nil, rootCtx, nil, rootID, false)
 	if err != nil {
 		return nil, &BuildError{
 			Phase:   "scope-creation",
@@ -416,6 +649,78 @@ func (sc *collection) AddSingleton(service any, opts ...AddOption) {
 	sc.recordErr(sc.addService(service, Singleton, opts...))
 }
 
+// AddValue adds an existing value to the collection as a singleton.
+// Registration errors are recorded and reported by Build (or Err).
+func (sc *collection) AddValue(value any, opts ...AddOption) {
+	sc.recordErr(sc.addValue(value, opts...))
+}
+
+// Merge copies the registrations and recorded errors of other into the
+// collection.
+func (sc *collection) Merge(other Collection) {
+	sc.recordErr(sc.merge(other))
+}
+
+func (sc *collection) merge(other Collection) error {
+	fragment, ok := other.(*collection)
+	switch {
+	case other == nil || (ok && fragment == nil):
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("cannot merge a nil collection"),
+		}
+	case !ok:
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("cannot merge collection implementation %T", other),
+		}
+	case fragment == sc:
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("cannot merge a collection into itself"),
+		}
+	}
+
+	// Snapshot the fragment before locking the receiver so that two
+	// collections merging into each other cannot deadlock.
+	fragment.mu.RLock()
+	all, _, _ := snapshotRegistrations(fragment.allDescriptors, fragment.services, fragment.groups)
+	errs := slices.Clone(fragment.errs)
+	fragment.mu.RUnlock()
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var conflicts []error
+	for _, d := range all {
+		if d.Group != "" {
+			continue
+		}
+		if _, exists := sc.services[TypeKey{Type: d.Type, Key: d.Key}]; exists {
+			conflicts = append(conflicts, &RegistrationError{
+				ServiceType: d.Type,
+				Operation:   "merge",
+				Cause:       &AlreadyRegisteredError{ServiceType: d.Type},
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return errors.Join(conflicts...)
+	}
+
+	for _, d := range all {
+		if d.Group != "" {
+			// Group members are numbered again in this collection's groups.
+			d.Key = nil
+		}
+		if err := sc.registerDescriptor(d); err != nil {
+			return err
+		}
+	}
+	sc.errs = append(sc.errs, errs...)
+	return nil
+}
+
 // AddScoped adds a scoped service to the collection.
 // Registration errors are recorded and reported by Build (or Err).
 func (sc *collection) AddScoped(service any, opts ...AddOption) {
@@ -474,6 +779,10 @@ func (sc *collection) popModule() {
 	if len(sc.moduleStack) > 0 {
 		sc.moduleStack = sc.moduleStack[:len(sc.moduleStack)-1]
 	}
+	// Decorators stop applying once their module has been applied.
+	sc.decorators = slices.DeleteFunc(sc.decorators, func(dec *decorator) bool {
+		return len(dec.modules) > len(sc.moduleStack)
+	})
 	sc.mu.Unlock()
 }
 
@@ -638,16 +947,20 @@ func (r *collection) ToSlice() []ServiceInfo {
 		if d == nil {
 			continue
 		}
-		result = append(result, ServiceInfo{
-			ServiceType: d.Type,
-			Key:         d.Key,
-			Group:       d.Group,
-			Lifetime:    d.Lifetime,
-		})
+		result = append(result, d.serviceInfo())
 	}
 	return result
 }
 
+// Graph returns a view of a snapshot of the current registrations.
+func (r *collection) Graph() GraphView {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all, services, groups := snapshotRegistrations(r.allDescriptors, r.services, r.groups)
+	return newGraphView(all, services, groups)
+}
+
 // Count returns the number of registered services in the collection.
 func (r *collection) Count() int {
 	r.mu.RLock()
@@ -662,9 +975,21 @@ var (
 		reflect.TypeFor[context.Context](): {},
 		reflect.TypeFor[Provider]():        {},
 		reflect.TypeFor[Scope]():           {},
+		reflect.TypeFor[ResolveInfo]():     {},
+		reflect.TypeFor[ScopeInfo]():       {},
+		reflect.TypeFor[*ScopeWaitGroup](): {},
+		reflect.TypeFor[Cleanup]():         {},
+		reflect.TypeFor[BuildInfo]():       {},
 	}
 )
 
+// isReserved reports whether t is a reserved type, including every
+// ScopedAccessor type.
+func isReserved(t reflect.Type) bool {
+	_, reserved := reservedTypes[t]
+	return reserved || isScopedAccessor(t)
+}
+
 // addService registers a new service with the specified lifetime and options.
 // It performs validation, creates descriptors, handles multi-return constructors,
 // and manages interface registrations when using the As option.
@@ -697,7 +1022,7 @@ func (r *collection) addService(service any, lifetime Lifetime, opts ...AddOptio
 	}
 
 	// Check if the service type is reserved
-	if _, isReserved := reservedTypes[descriptor.Type]; isReserved {
+	if isReserved(descriptor.Type) {
 		return &ValidationError{
 			ServiceType: descriptor.Type,
 			Cause:       fmt.Errorf("service type %s is reserved and cannot be registered", formatType(descriptor.Type)),
@@ -707,6 +1032,15 @@ func (r *collection) addService(service any, lifetime Lifetime, opts ...AddOptio
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if len(r.moduleStack) > 0 {
+		descriptor.modules = append([]string(nil), r.moduleStack...)
+	} else if descriptor.private {
+		return &ValidationError{
+			ServiceType: descriptor.Type,
+			Cause:       fmt.Errorf("godi.Private can only be used for registrations inside a module"),
+		}
+	}
+
 	// newDescriptorWithAnalyzer already parsed options and validated them,
 	// and Analyze() was called on the way through. Re-parse the options
 	// locally so we can inspect them (Name/Group/As), but skip the second
@@ -734,6 +1068,16 @@ func (r *collection) addService(service any, lifetime Lifetime, opts ...AddOptio
 		}
 	}
 
+	if first := r.findIdenticalRegistration(descriptor, info, options); first != nil {
+		return &DuplicateRegistrationError{
+			ServiceType:   first.Type,
+			ServiceKey:    first.Key,
+			Constructor:   constructorName(first),
+			FirstModules:  first.modules,
+			SecondModules: descriptor.modules,
+		}
+	}
+
 	// Handle result objects (Out structs)
 	if info.IsResultObject {
 		if options.Name != "" || options.Group != "" {
@@ -771,6 +1115,61 @@ func (r *collection) addService(service any, lifetime Lifetime, opts ...AddOptio
 	return r.registerDescriptor(descriptor)
 }
 
+// addValue validates a value registered with AddValue and registers it as a
+// singleton instance.
+func (r *collection) addValue(value any, opts ...AddOption) error {
+	if value == nil {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.AddValue: value cannot be nil"),
+		}
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Func {
+		return &ValidationError{
+			ServiceType: valueType,
+			Cause:       fmt.Errorf("godi.AddValue registers values, not constructors; use AddSingleton for %s", formatType(valueType)),
+		}
+	}
+
+	if isPrimitiveType(valueType) {
+		options := &addOptions{}
+		for _, opt := range opts {
+			if opt != nil {
+				opt.applyAddOption(options)
+			}
+		}
+		if options.Name == "" && options.Group == "" {
+			return &ValidationError{
+				ServiceType: valueType,
+				Cause: fmt.Errorf("an unnamed %s value is ambiguous; register it with godi.Name or godi.Group, or use a named type",
+					formatType(valueType)),
+			}
+		}
+	}
+
+	return r.addService(value, Singleton, opts...)
+}
+
+// isPrimitiveType reports whether t is a predeclared type such as string or
+// int, or an unnamed slice, array, pointer, or map built only from them.
+func isPrimitiveType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return t.PkgPath() == ""
+	case reflect.Slice, reflect.Array, reflect.Pointer:
+		return t.Name() == "" && isPrimitiveType(t.Elem())
+	case reflect.Map:
+		return t.Name() == "" && isPrimitiveType(t.Key()) && isPrimitiveType(t.Elem())
+	default:
+		return false
+	}
+}
+
 // registerAliases registers a descriptor under each interface type in
 // options.As instead of its concrete type. The aliases are linked as siblings
 // so one constructor invocation caches every interface entry. Caller must hold
@@ -805,7 +1204,7 @@ func (r *collection) registerAliases(d *descriptor, options *addOptions) error {
 
 		// Reserved types are special-cased by the resolver and cannot be
 		// registered, not even via As.
-		if _, isReserved := reservedTypes[interfaceType]; isReserved {
+		if isReserved(interfaceType) {
 			return &ValidationError{
 				ServiceType: interfaceType,
 				Cause:       fmt.Errorf("service type %s is reserved and cannot be registered", formatType(interfaceType)),
@@ -875,6 +1274,7 @@ func snapshotRegistrations(
 		clone.Dependencies = append([]*reflection.Dependency(nil), original.Dependencies...)
 		clone.resultFields = append([]reflection.ResultField(nil), original.resultFields...)
 		clone.paramFields = append([]reflection.ParamField(nil), original.paramFields...)
+		clone.decorators = slices.Clone(original.decorators)
 		clones[original] = &clone
 		snapshotAll = append(snapshotAll, &clone)
 	}
@@ -941,6 +1341,8 @@ func (r *collection) registerResultObjectFields(d *descriptor) error {
 		fieldDescriptor.Key = field.Key
 		fieldDescriptor.Group = field.Group
 		fieldDescriptor.resultFieldIndex = field.Index
+		fieldDescriptor.optionalResult = field.Optional
+		fieldDescriptor.profiles = field.Profiles
 		fieldDescriptors = append(fieldDescriptors, fieldDescriptor)
 	}
 
@@ -1009,7 +1411,9 @@ func (r *collection) registerMultiReturn(d *descriptor, info *reflection.Constru
 
 		// Apply name/key only to the first return if specified
 		typeDescriptor.Key = nil
-		if options.Name != "" && i == 0 {
+		if i > 0 {
+			typeDescriptor.memberName = ""
+		} else if options.Name != "" && options.Group == "" {
 			typeDescriptor.Key = options.Name
 		}
 
@@ -1084,12 +1488,58 @@ func (r *collection) unregisterDescriptors(batch []*descriptor) {
 	r.pruneDescriptors(removed)
 }
 
+// findIdenticalRegistration returns a registration of the same constructor
+// function with the same lifetime, name, and service types as the one being
+// added, typically from a module applied twice. Group members are exempt,
+// since a group may list the same constructor more than once on purpose, and
+// so are registered values and result objects, whose fields carry their own
+// names and groups. Caller must hold r.mu.
+func (r *collection) findIdenticalRegistration(d *descriptor, info *reflection.ConstructorInfo, options *addOptions) *descriptor {
+	if d.IsInstance || info.IsResultObject || options.Group != "" {
+		return nil
+	}
+
+	var types []reflect.Type
+	if len(options.As) > 0 {
+		for _, iface := range options.As {
+			types = append(types, reflect.TypeOf(iface).Elem())
+		}
+	} else {
+		for _, ret := range info.Returns {
+			if !ret.IsError {
+				types = append(types, ret.Type)
+			}
+		}
+		if len(types) == 0 {
+			types = append(types, d.Type)
+		}
+	}
+
+	var key any
+	if options.Name != "" {
+		key = options.Name
+	}
+	for _, existing := range r.allDescriptors {
+		if existing.IsInstance || existing.Group != "" || existing.Key != key ||
+			existing.Constructor != d.Constructor || existing.Lifetime != d.Lifetime {
+			continue
+		}
+		if slices.Contains(types, existing.Type) {
+			return existing
+		}
+	}
+	return nil
+}
+
 // registerDescriptor registers a descriptor in the appropriate collections based on its type.
 // Regular services are registered by type and key,
 // and grouped services are registered in their respective groups.
 func (r *collection) registerDescriptor(descriptor *descriptor) error {
 	// Register based on type of service
 	if descriptor.Key != nil || descriptor.Group == "" {
+		if descriptor.flag != "" {
+			descriptor.Key = flagKey{flag: descriptor.flag, key: descriptor.Key}
+		}
 		key := TypeKey{Type: descriptor.Type, Key: descriptor.Key}
 		if _, exists := r.services[key]; exists {
 			if descriptor.Key == nil {
@@ -1104,7 +1554,24 @@ func (r *collection) registerDescriptor(descriptor *descriptor) error {
 
 		r.services[key] = descriptor
 	} else {
+		if descriptor.flag != "" {
+			return &ValidationError{
+				ServiceType: descriptor.Type,
+				Cause:       fmt.Errorf("godi.Flagged cannot register group members"),
+			}
+		}
 		groupKey := GroupKey{Type: descriptor.Type, Group: descriptor.Group}
+		if descriptor.memberName != "" {
+			for _, member := range r.groups[groupKey] {
+				if member.memberName == descriptor.memberName {
+					return &RegistrationError{
+						ServiceType: descriptor.Type,
+						Operation:   "register",
+						Cause:       fmt.Errorf("group %q already has a member named %q", descriptor.Group, descriptor.memberName),
+					}
+				}
+			}
+		}
 		r.groups[groupKey] = append(r.groups[groupKey], descriptor)
 
 		// Set a numeric key for group members
@@ -1114,6 +1581,10 @@ func (r *collection) registerDescriptor(descriptor *descriptor) error {
 	// Track in allDescriptors for efficient iteration
 	r.allDescriptors = append(r.allDescriptors, descriptor)
 
+	for _, dec := range r.decorators {
+		attachDecorator(dec, descriptor)
+	}
+
 	return nil
 }
 
@@ -1147,11 +1618,29 @@ func (c *collection) validateLifetimes() error {
 			return nil
 		}
 
-		// Skip scoped services - they can depend on anything
-		if descriptor.Lifetime == Scoped {
+		// Skip scoped services - they can depend on anything. Refreshing
+		// services are Scoped for their consumers, but one instance is shared
+		// by every scope, so their own dependencies are checked below.
+		if descriptor.Lifetime == Scoped && descriptor.refresh == nil {
 			return nil
 		}
 
+		conflict := func(dep *reflection.Dependency, depLifetime Lifetime) error {
+			if descriptor.Lifetime == Scoped && descriptor.refresh != nil {
+				return &ValidationError{
+					ServiceType: descriptor.Type,
+					Cause: fmt.Errorf("refreshing service cannot depend on %s %s: one instance is shared by every scope",
+						depLifetime, formatType(dep.Type)),
+				}
+			}
+			return &LifetimeConflictError{
+				ServiceType:        descriptor.Type,
+				ServiceLifetime:    descriptor.Lifetime,
+				DependencyType:     dep.Type,
+				DependencyLifetime: depLifetime,
+			}
+		}
+
 		// Both Singleton and Transient cannot depend on Scoped
 		for _, dep := range descriptor.Dependencies {
 			if dep == nil {
@@ -1164,12 +1653,7 @@ func (c *collection) validateLifetimes() error {
 				groupKey := GroupKey{Type: dep.Type, Group: dep.Group}
 				for _, memberDesc := range c.groups[groupKey] {
 					if memberDesc != nil && memberDesc.Lifetime == Scoped {
-						return &LifetimeConflictError{
-							ServiceType:        descriptor.Type,
-							ServiceLifetime:    descriptor.Lifetime,
-							DependencyType:     dep.Type,
-							DependencyLifetime: Scoped,
-						}
+						return conflict(dep, Scoped)
 					}
 				}
 				continue
@@ -1182,12 +1666,7 @@ func (c *collection) validateLifetimes() error {
 			}
 
 			if depLifetime == Scoped {
-				return &LifetimeConflictError{
-					ServiceType:        descriptor.Type,
-					ServiceLifetime:    descriptor.Lifetime,
-					DependencyType:     dep.Type,
-					DependencyLifetime: depLifetime,
-				}
+				return conflict(dep, depLifetime)
 			}
 		}
 
@@ -1211,3 +1690,178 @@ func (c *collection) validateLifetimes() error {
 
 	return nil
 }
+
+// validateGroupMembers ensures every member of a consumed value group is
+// registered under an element type its consumers request. Groups are looked
+// up by element type and group name, so a member registered under a type no
+// consumer of its group asks for would be silently missing from every slice.
+// Each such member is reported, in registration order, with its registration
+// source.
+func (c *collection) validateGroupMembers() error {
+	// Element types requested per group name, in first-seen order.
+	consumed := make(map[string][]reflect.Type)
+	for _, descriptor := range c.allDescriptors {
+		if descriptor == nil {
+			continue
+		}
+		for _, dep := range descriptor.Dependencies {
+			if dep == nil || dep.Group == "" || slices.Contains(consumed[dep.Group], dep.Type) {
+				continue
+			}
+			consumed[dep.Group] = append(consumed[dep.Group], dep.Type)
+		}
+	}
+	if len(consumed) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, member := range c.allDescriptors {
+		if member == nil || member.Group == "" {
+			continue
+		}
+		elemTypes, ok := consumed[member.Group]
+		if !ok || slices.Contains(elemTypes, member.Type) {
+			continue
+		}
+		errs = append(errs, &GroupMemberError{
+			Group:        member.Group,
+			ElementTypes: elemTypes,
+			MemberType:   member.Type,
+			Source:       member.source(),
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePrivateServices reports every dependency on a godi.Private
+// service from outside the module that registered it.
+func (c *collection) validatePrivateServices() error {
+	var errs []error
+	for _, consumer := range c.allDescriptors {
+		if consumer == nil {
+			continue
+		}
+		if len(consumer.siblings) > 0 && consumer.siblings[0] != consumer {
+			continue
+		}
+		for _, dep := range consumer.Dependencies {
+			if dep == nil || dep.Group != "" {
+				continue
+			}
+			target := c.services[TypeKey{Type: dep.Type, Key: dep.Key}]
+			if target == nil || !target.private || withinModule(consumer.modules, target.modules) {
+				continue
+			}
+			errs = append(errs, &PrivateServiceError{
+				ServiceType:    target.Type,
+				ServiceKey:     target.Key,
+				Module:         strings.Join(target.modules, "/"),
+				Consumer:       consumer.Type,
+				ConsumerSource: consumer.source(),
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// withinModule reports whether a registration made in the module path
+// modules belongs to the module path owner or one nested within it.
+func withinModule(modules, owner []string) bool {
+	return len(modules) >= len(owner) && slices.Equal(modules[:len(owner)], owner)
+}
+
+// validateServiceLocators reports every registration whose constructor takes
+// Provider or Scope, unless one of the types it provides is allowlisted.
+// Registrations producing several types are reported once.
+func (c *collection) validateServiceLocators(allowlist []reflect.Type) error {
+	var errs []error
+	for _, descriptor := range c.allDescriptors {
+		if descriptor == nil || descriptor.IsInstance {
+			continue
+		}
+		if len(descriptor.siblings) > 0 && descriptor.siblings[0] != descriptor {
+			continue
+		}
+		if serviceLocatorAllowed(descriptor, allowlist) {
+			continue
+		}
+		for _, dep := range descriptor.Dependencies {
+			if dep == nil || dep.Key != nil || dep.Group != "" {
+				continue
+			}
+			if dep.Type != providerType && dep.Type != scopeType {
+				continue
+			}
+			errs = append(errs, &ServiceLocatorError{
+				ServiceType: descriptor.Type,
+				ServiceKey:  descriptor.Key,
+				Parameter:   dep.Type,
+				Source:      descriptor.source(),
+			})
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// exportedNames maps every godi.ExportName to the descriptor it resolves and
+// reports names given to more than one registration. Interface aliases of
+// one registration share its name and are counted once.
+func exportedNames(descriptors []*descriptor) (map[string]*descriptor, error) {
+	var exports map[string]*descriptor
+	var conflicts map[string][]*descriptor
+	for _, d := range descriptors {
+		if d == nil || d.exportName == "" {
+			continue
+		}
+		if len(d.siblings) > 0 && d.siblings[0] != d {
+			continue
+		}
+		name := d.exportName
+		existing, taken := exports[name]
+		if !taken {
+			if exports == nil {
+				exports = make(map[string]*descriptor)
+			}
+			exports[name] = d
+			continue
+		}
+		if conflicts == nil {
+			conflicts = make(map[string][]*descriptor)
+		}
+		if conflicts[name] == nil {
+			conflicts[name] = []*descriptor{existing}
+		}
+		conflicts[name] = append(conflicts[name], d)
+	}
+
+	if len(conflicts) == 0 {
+		return exports, nil
+	}
+	errs := make([]error, 0, len(conflicts))
+	for _, name := range slices.Sorted(maps.Keys(conflicts)) {
+		conflict := &ExportNameConflictError{Name: name}
+		for _, d := range conflicts[name] {
+			conflict.ServiceTypes = append(conflict.ServiceTypes, d.Type)
+			conflict.Sources = append(conflict.Sources, d.source())
+		}
+		errs = append(errs, conflict)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func serviceLocatorAllowed(d *descriptor, allowlist []reflect.Type) bool {
+	if slices.Contains(allowlist, d.Type) {
+		return true
+	}
+	for _, sibling := range d.siblings {
+		if slices.Contains(allowlist, sibling.Type) {
+			return true
+		}
+	}
+	return false
+}
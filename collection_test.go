@@ -142,13 +142,15 @@ func TestCollectionRegistrationErrors(t *testing.T) {
 		assert.ErrorIs(t, err, ErrConstructorNil)
 	})
 
-	t.Run("rejects_name_and_group_together", func(t *testing.T) {
+	t.Run("rejects_duplicate_names_within_a_group", func(t *testing.T) {
 		t.Parallel()
 		c := NewCollection()
 		c.AddSingleton(NewTService, Name("n"), Group("g"))
+		require.NoError(t, c.Err())
+		c.AddSingleton(NewTService, Name("n"), Group("g"))
 		err := c.Err()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "cannot use both")
+		assert.Contains(t, err.Error(), `group "g" already has a member named "n"`)
 	})
 
 	t.Run("rejects_invalid_interface_binding", func(t *testing.T) {
@@ -525,6 +527,45 @@ func TestCollectionParameterObjects(t *testing.T) {
 	assert.Nil(t, svc.Dep) // Optional and not registered
 }
 
+func TestCollectionNestedParameterObjects(t *testing.T) {
+	t.Parallel()
+
+	type CommonDeps struct {
+		In
+		Service *TService
+		Dep     *TDependency `optional:"true"`
+	}
+	type Params struct {
+		In
+		CommonDeps
+		Shared *CommonDeps
+		Named  *TService `name:"named"`
+	}
+
+	p := BuildProvider(t,
+		AddSingleton(NewTService),
+		AddSingleton(NewTServiceWithID("named"), Name("named")),
+		AddSingleton(func(p Params) *TServiceWithDeps {
+			assert.Same(t, p.Service, p.Shared.Service)
+			assert.Equal(t, "named", p.Named.ID)
+			return &TServiceWithDeps{Svc: p.Service, Dep: p.Shared.Dep}
+		}),
+	)
+
+	svc := RequireResolve[*TServiceWithDeps](t, p)
+	assert.NotNil(t, svc.Svc)
+	assert.Nil(t, svc.Dep)
+
+	t.Run("missing_nested_dependency_fails_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(p Params) *TServiceWithDeps { return nil })
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "*TService")
+	})
+}
+
 func TestCollectionResultObjects(t *testing.T) {
 	t.Parallel()
 
@@ -825,7 +866,7 @@ func TestOptionalDependencyErrors(t *testing.T) {
 		require.NoError(t, err)
 		t.Cleanup(func() { _ = p.Close() })
 
-		consumer, err := Resolve[*TOptionalConsumer](p)
+		consumer, err := Resolve[*TOptionalConsumer](NewTestScope(t, p))
 		require.NoError(t, err)
 		assert.Nil(t, consumer.Failing)
 	})
@@ -847,7 +888,7 @@ func TestOptionalDependencyErrors(t *testing.T) {
 		require.NoError(t, err)
 		t.Cleanup(func() { _ = p.Close() })
 
-		_, err = Resolve[*TOptionalConsumer](p)
+		_, err = Resolve[*TOptionalConsumer](NewTestScope(t, p))
 		require.Error(t, err, "missing transitive dependency of an optional service must propagate")
 	})
 
@@ -865,10 +906,54 @@ func TestOptionalDependencyErrors(t *testing.T) {
 		require.NoError(t, err)
 		t.Cleanup(func() { _ = p.Close() })
 
-		_, err = Resolve[*TOptionalConsumer](p)
+		_, err = Resolve[*TOptionalConsumer](NewTestScope(t, p))
 		require.Error(t, err, "a registered optional dependency whose constructor fails must propagate the error")
 		assert.Contains(t, err.Error(), "constructor exploded")
 	})
+
+	t.Run("on_optional_failure_leaves_the_field_nil_and_reports", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("constructor exploded")
+		c := NewCollection()
+		c.AddScoped(func() (*TFailing, error) { return nil, boom })
+		c.AddScoped(func(p TOptionalParams) *TOptionalConsumer {
+			return &TOptionalConsumer{Failing: p.Failing}
+		})
+		c.AddScoped(func(f *TFailing) *TService { return &TService{} })
+
+		var failures []*OptionalFailureError
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			OnOptionalFailure: func(err *OptionalFailureError) { failures = append(failures, err) },
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		s := NewTestScope(t, p)
+		consumer, err := Resolve[*TOptionalConsumer](s)
+		require.NoError(t, err)
+		assert.Nil(t, consumer.Failing)
+
+		require.Len(t, failures, 1)
+		assert.ErrorIs(t, failures[0], boom)
+		assert.Equal(t, PtrTypeOf[TOptionalConsumer](), failures[0].ServiceType)
+		assert.Equal(t, "Failing", failures[0].Field)
+		assert.Equal(t, PtrTypeOf[TFailing](), failures[0].DependencyType)
+		assert.Equal(t, s.ID(), failures[0].ScopeID)
+
+		_, err = Resolve[*TService](s)
+		require.ErrorIs(t, err, boom, "required dependencies still fail")
+
+		d, err := DiagnosticsOf(p)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), d.OptionalFailures)
+
+		reported, err := OptionalFailuresOf(s)
+		require.NoError(t, err)
+		assert.Equal(t, failures, reported)
+		reported, err = OptionalFailuresOf(NewTestScope(t, p))
+		require.NoError(t, err)
+		assert.Empty(t, reported, "each scope reports its own")
+	})
 }
 
 // Registering the same multi-return constructor twice (legal via groups,
@@ -1060,10 +1145,11 @@ func TestDeferredRegistrationErrors(t *testing.T) {
 	t.Run("build_reports_all_recorded_errors", func(t *testing.T) {
 		t.Parallel()
 		c := NewCollection()
-		c.AddSingleton(nil)                             // error 1: nil constructor
-		c.AddSingleton(NewTService)                     // fine
-		c.AddSingleton(NewTService)                     // error 2: duplicate
-		c.AddScoped(NewTService, Name("n"), Group("g")) // error 3: name+group
+		c.AddSingleton(nil)         // error 1: nil constructor
+		c.AddSingleton(NewTService) // fine
+		c.AddSingleton(NewTService) // error 2: duplicate
+		c.AddScoped(NewTService, Name("n"), Group("g"))
+		c.AddScoped(NewTService, Name("n"), Group("g")) // error 3: duplicate member name
 
 		_, err := c.Build()
 		require.Error(t, err)
@@ -1075,7 +1161,7 @@ func TestDeferredRegistrationErrors(t *testing.T) {
 		msg := err.Error()
 		assert.Contains(t, msg, "constructor cannot be nil")
 		assert.Contains(t, msg, "already registered")
-		assert.Contains(t, msg, "cannot use both")
+		assert.Contains(t, msg, "already has a member named")
 	})
 
 	t.Run("err_is_nil_when_all_registrations_succeed", func(t *testing.T) {
@@ -1685,3 +1771,587 @@ func TestTypedNilConstructorResult(t *testing.T) {
 		assert.Nil(t, service)
 	})
 }
+
+func TestOptionalResultFields(t *testing.T) {
+	t.Parallel()
+
+	type metricsResult struct {
+		Out
+		Service *TService
+		Metrics *TDependency `optional:"true"`
+	}
+
+	t.Run("nil_field_is_not_registered", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		p := BuildProvider(t, AddSingleton(func() metricsResult {
+			calls.Add(1)
+			return metricsResult{Service: NewTService()}
+		}))
+
+		RequireResolve[*TService](t, p)
+		_, err := Resolve[*TDependency](p)
+		require.ErrorIs(t, err, ErrServiceNotProvided)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.Equal(t, int32(1), calls.Load(), "the constructor runs once for all its fields")
+	})
+
+	t.Run("optional_consumer_skips_it", func(t *testing.T) {
+		t.Parallel()
+		type consumerParams struct {
+			In
+			Metrics *TDependency `optional:"true"`
+		}
+		type consumer struct{ metrics *TDependency }
+
+		scope := BuildScope(t,
+			AddScoped(func() metricsResult { return metricsResult{Service: NewTService()} }),
+			AddScoped(func(p consumerParams) *consumer { return &consumer{metrics: p.Metrics} }),
+		)
+
+		got := RequireResolve[*consumer](t, scope)
+		assert.Nil(t, got.metrics)
+
+		_, err := Resolve[*TDependency](scope)
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.True(t, resErr.ServiceNotFound())
+	})
+
+	t.Run("provided_field_resolves", func(t *testing.T) {
+		t.Parallel()
+		dep := NewTDependency()
+		p := BuildProvider(t, AddSingleton(func() metricsResult {
+			return metricsResult{Service: NewTService(), Metrics: dep}
+		}))
+
+		assert.Same(t, dep, RequireResolve[*TDependency](t, p))
+	})
+
+	t.Run("transient_constructor_decides_per_call", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		p := BuildProvider(t, AddTransient(func() metricsResult {
+			if calls.Add(1)%2 == 0 {
+				return metricsResult{Service: NewTService(), Metrics: NewTDependency()}
+			}
+			return metricsResult{Service: NewTService()}
+		}))
+
+		_, err := Resolve[*TDependency](p)
+		require.ErrorIs(t, err, ErrServiceNotProvided)
+		RequireResolve[*TDependency](t, p)
+	})
+
+	t.Run("nil_group_member_is_skipped", func(t *testing.T) {
+		t.Parallel()
+		type handlerResult struct {
+			Out
+			Handler TInterface `group:"handlers" optional:"true"`
+		}
+		p := BuildProvider(t,
+			AddSingleton(func() handlerResult { return handlerResult{} }),
+			AddSingleton(func() handlerResult { return handlerResult{Handler: NewTService()} }),
+		)
+
+		handlers, err := ResolveGroup[TInterface](p, "handlers")
+		require.NoError(t, err)
+		assert.Len(t, handlers, 1)
+	})
+
+	t.Run("untagged_nil_field_still_fails", func(t *testing.T) {
+		t.Parallel()
+		type result struct {
+			Out
+			Service *TService
+			Metrics *TDependency
+		}
+		scope := BuildScope(t, AddScoped(func() result { return result{Service: NewTService()} }))
+
+		_, err := Resolve[*TDependency](scope)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrServiceNotProvided)
+	})
+}
+
+func TestGroupMemberValidation(t *testing.T) {
+	t.Parallel()
+
+	type consumer struct{ members []TInterface }
+	newConsumer := func(members []TInterface) *consumer { return &consumer{members: members} }
+
+	type consumerParams struct {
+		In
+		Members []TInterface `group:"members"`
+	}
+
+	t.Run("member_registered_as_concrete_type", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("members"))
+		c.AddSingleton(func(p consumerParams) *consumer { return newConsumer(p.Members) })
+
+		_, err := c.Build()
+		require.Error(t, err)
+
+		groupErr, ok := errors.AsType[*GroupMemberError](err)
+		require.True(t, ok, "expected GroupMemberError, got %v", err)
+		assert.Equal(t, "members", groupErr.Group)
+		assert.Equal(t, []reflect.Type{TypeOf[TInterface]()}, groupErr.ElementTypes)
+		assert.Equal(t, PtrTypeOf[TService](), groupErr.MemberType)
+		assert.Contains(t, groupErr.Source, "testutil_test.go")
+		assert.Contains(t, err.Error(), "godi.As[TInterface]()")
+	})
+
+	t.Run("member_not_assignable_to_element_type", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(NewModule("plugins", AddSingleton(NewTDependency, Group("members"))))
+		c.AddSingleton(func(p consumerParams) *consumer { return newConsumer(p.Members) })
+
+		_, err := c.Build()
+		require.Error(t, err)
+		groupErr, ok := errors.AsType[*GroupMemberError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[TDependency](), groupErr.MemberType)
+		assert.Contains(t, groupErr.Source, `module "plugins"`)
+		assert.NotContains(t, err.Error(), "godi.As[")
+	})
+
+	t.Run("reports_every_offending_member", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("members"))
+		c.AddSingleton(NewTDependency, Group("members"))
+		c.AddSingleton(func(p consumerParams) *consumer { return newConsumer(p.Members) })
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "member *TService")
+		assert.Contains(t, err.Error(), "member *TDependency")
+	})
+
+	t.Run("matching_members_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("members"), As[TInterface]())
+		c.AddSingleton(func(p consumerParams) *consumer { return newConsumer(p.Members) })
+
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		got, err := Resolve[*consumer](p)
+		require.NoError(t, err)
+		assert.Len(t, got.members, 1)
+	})
+
+	t.Run("members_of_each_consumed_type_build", func(t *testing.T) {
+		t.Parallel()
+		type dependencyParams struct {
+			In
+			Members []*TDependency `group:"members"`
+		}
+
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("members"), As[TInterface]())
+		c.AddSingleton(NewTDependency, Group("members"))
+		c.AddSingleton(func(p consumerParams) *consumer { return newConsumer(p.Members) })
+		c.AddSingleton(func(p dependencyParams) []*TDependency { return p.Members })
+
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		got, err := Resolve[*consumer](p)
+		require.NoError(t, err)
+		assert.Len(t, got.members, 1)
+		deps, err := Resolve[[]*TDependency](p)
+		require.NoError(t, err)
+		assert.Len(t, deps, 1)
+	})
+
+	t.Run("unconsumed_group_is_not_validated", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("members"))
+		c.AddSingleton(NewTDependency, Group("members"))
+
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+	})
+}
+
+func TestDisallowServiceLocator(t *testing.T) {
+	t.Parallel()
+
+	options := func(allow ...reflect.Type) *ProviderOptions {
+		return &ProviderOptions{DisallowServiceLocator: true, ServiceLocatorAllowlist: allow}
+	}
+
+	t.Run("rejects_provider_and_scope_parameters", func(t *testing.T) {
+		t.Parallel()
+		type locatorParams struct {
+			In
+			Scope Scope
+		}
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) *TService { return &TService{} })
+		c.AddScoped(func(p locatorParams) *TDependency { return &TDependency{} }, Name("scoped"))
+		c.AddSingleton(NewTDependency)
+
+		_, err := c.BuildWithOptions(options())
+		require.Error(t, err)
+
+		var locatorErrs []*ServiceLocatorError
+		var walk func(error)
+		walk = func(err error) {
+			if locatorErr, ok := err.(*ServiceLocatorError); ok {
+				locatorErrs = append(locatorErrs, locatorErr)
+				return
+			}
+			if multi, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, child := range multi.Unwrap() {
+					walk(child)
+				}
+				return
+			}
+			if next := errors.Unwrap(err); next != nil {
+				walk(next)
+			}
+		}
+		walk(err)
+
+		require.Len(t, locatorErrs, 2)
+		assert.Equal(t, PtrTypeOf[TService](), locatorErrs[0].ServiceType)
+		assert.Equal(t, TypeOf[Provider](), locatorErrs[0].Parameter)
+		assert.Contains(t, locatorErrs[0].Source, "collection_test.go")
+		assert.Equal(t, PtrTypeOf[TDependency](), locatorErrs[1].ServiceType)
+		assert.Equal(t, "scoped", locatorErrs[1].ServiceKey)
+		assert.Equal(t, TypeOf[Scope](), locatorErrs[1].Parameter)
+	})
+
+	t.Run("allowlisted_types_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) *TService { return &TService{} }, As[TInterface]())
+
+		p, err := c.BuildWithOptions(options(TypeOf[TInterface]()))
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+	})
+
+	t.Run("context_parameters_are_allowed", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(ctx context.Context) *TService { return &TService{} })
+
+		p, err := c.BuildWithOptions(options())
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+	})
+
+	t.Run("off_by_default", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) *TService { return &TService{} })
+
+		p, err := c.Build()
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+	})
+}
+
+func TestAddValue(t *testing.T) {
+	t.Parallel()
+
+	type port int
+
+	t.Run("named_primitive_containers", func(t *testing.T) {
+		t.Parallel()
+		type serverParams struct {
+			In
+			Origins []string       `name:"allowedOrigins"`
+			Limits  map[string]int `name:"rateLimits"`
+		}
+		var got serverParams
+		p := BuildProvider(t,
+			AddValue([]string{"a", "b"}, Name("allowedOrigins")),
+			AddValue(map[string]int{"free": 60}, Name("rateLimits")),
+			AddSingleton(func(params serverParams) *TService {
+				got = params
+				return &TService{}
+			}),
+		)
+
+		RequireResolve[*TService](t, p)
+		assert.Equal(t, []string{"a", "b"}, got.Origins)
+		assert.Equal(t, map[string]int{"free": 60}, got.Limits)
+
+		origins, err := ResolveKeyed[[]string](p, "allowedOrigins")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, origins)
+	})
+
+	t.Run("named_types_need_no_name", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddValue(port(8080)), AddValue([]port{1, 2}), AddValue(&TService{ID: "value"}))
+		assert.Equal(t, port(8080), RequireResolve[port](t, p))
+		assert.Equal(t, []port{1, 2}, RequireResolve[[]port](t, p))
+		assert.Equal(t, "value", RequireResolve[*TService](t, p).ID)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddValue("a", Group("hosts"))
+		c.AddValue("b", Group("hosts"))
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		hosts, err := ResolveGroup[string](p, "hosts")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, hosts)
+	})
+
+	t.Run("invalid_values", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name  string
+			value any
+			want  string
+		}{
+			{"nil", nil, "value cannot be nil"},
+			{"constructor", NewTService, "registers values, not constructors"},
+			{"unnamed_string", "localhost", "an unnamed string value is ambiguous"},
+			{"unnamed_slice", []string{"a"}, "an unnamed []string value is ambiguous"},
+			{"unnamed_map", map[string][]int{}, "an unnamed map[string][]int value is ambiguous"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddValue(tt.value)
+				require.Error(t, c.Err())
+				assert.Contains(t, c.Err().Error(), tt.want)
+			})
+		}
+	})
+}
+
+func TestCollectionMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fragments_built_in_parallel", func(t *testing.T) {
+		t.Parallel()
+		fragments := []ModuleOption{
+			NewModule("services",
+				AddSingleton(NewTService),
+				AddSingleton(NewTServiceWithID("a"), Group("all")),
+			),
+			NewModule("dependencies",
+				AddSingleton(NewTDependency, Private()),
+				AddSingleton(NewTServiceWithDeps),
+				AddSingleton(NewTServiceWithID("b"), Group("all")),
+			),
+			AddSingleton(func() (*TMultiA, *TMultiB) { return &TMultiA{N: 1}, &TMultiB{N: 2} }),
+		}
+
+		collections := make([]Collection, len(fragments))
+		var wg sync.WaitGroup
+		for i, fragment := range fragments {
+			wg.Go(func() {
+				collections[i] = NewCollection()
+				collections[i].AddModules(fragment)
+			})
+		}
+		wg.Wait()
+
+		c := NewCollection()
+		for _, fragment := range collections {
+			c.Merge(fragment)
+		}
+		require.NoError(t, c.Err())
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.NotNil(t, RequireResolve[*TServiceWithDeps](t, p).Dep)
+		_, err = Resolve[*TDependency](p)
+		require.ErrorIs(t, err, ErrServiceNotFound, "module attribution survives the merge")
+
+		all, err := ResolveGroup[*TService](p, "all")
+		require.NoError(t, err)
+		require.Len(t, all, 2, "group members from both fragments are kept")
+		assert.ElementsMatch(t, []string{"a", "b"}, []string{all[0].ID, all[1].ID})
+
+		assert.Equal(t, 2, RequireResolve[*TMultiB](t, p).N)
+		assert.Same(t, RequireResolve[*TMultiA](t, p), RequireResolve[*TMultiA](t, p))
+	})
+
+	t.Run("conflicts_merge_nothing", func(t *testing.T) {
+		t.Parallel()
+		fragment := NewCollection()
+		fragment.AddSingleton(NewTDependency)
+		fragment.AddSingleton(NewTService)
+
+		c := NewCollection()
+		c.AddSingleton(NewTService)
+		c.Merge(fragment)
+
+		var alreadyRegistered *AlreadyRegisteredError
+		require.ErrorAs(t, c.Err(), &alreadyRegistered)
+		assert.False(t, c.Contains(PtrTypeOf[TDependency]()))
+		assert.Equal(t, 2, fragment.Count(), "the fragment is unchanged")
+	})
+
+	t.Run("fragment_errors_are_kept", func(t *testing.T) {
+		t.Parallel()
+		fragment := NewCollection()
+		fragment.AddSingleton(nil)
+
+		c := NewCollection()
+		c.Merge(fragment)
+		require.ErrorIs(t, c.Err(), ErrConstructorNil)
+	})
+
+	t.Run("fragments_stay_usable", func(t *testing.T) {
+		t.Parallel()
+		fragment := NewCollection()
+		fragment.AddSingleton(NewTService, Group("all"))
+
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("all"))
+		c.Merge(fragment)
+		c.Merge(fragment)
+		require.NoError(t, c.Err())
+
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		all, err := ResolveGroup[*TService](p, "all")
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+
+		fp, err := fragment.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = fp.Close() })
+		all, err = ResolveGroup[*TService](fp, "all")
+		require.NoError(t, err)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("invalid_collections", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.Merge(nil)
+		c.Merge(c)
+		require.Error(t, c.Err())
+		assert.Contains(t, c.Err().Error(), "cannot merge a nil collection")
+		assert.Contains(t, c.Err().Error(), "cannot merge a collection into itself")
+	})
+
+	t.Run("concurrent_registration", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Go(func() {
+				c.AddSingleton(NewTServiceWithID(strconv.Itoa(i)), Name(strconv.Itoa(i)))
+				_ = c.Contains(PtrTypeOf[TService]())
+				_ = c.ToSlice()
+			})
+		}
+		wg.Wait()
+		require.NoError(t, c.Err())
+		assert.Equal(t, 16, c.Count())
+	})
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	t.Parallel()
+
+	shared := NewModule("storage",
+		AddSingleton(NewTService),
+		AddScoped(NewTDependency, Name("primary")),
+	)
+
+	t.Run("module_included_twice", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(shared, NewModule("api", shared))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		dupErr, ok := errors.AsType[*DuplicateRegistrationError](err)
+		require.True(t, ok, "expected DuplicateRegistrationError, got %v", err)
+		assert.Equal(t, PtrTypeOf[TService](), dupErr.ServiceType)
+		assert.Equal(t, []string{"storage"}, dupErr.FirstModules)
+		assert.Equal(t, []string{"api", "storage"}, dupErr.SecondModules)
+		assert.Contains(t, err.Error(), `first in module "storage", again in module "api/storage"`)
+		assert.Contains(t, err.Error(), "(key: primary)")
+		assert.ErrorAs(t, err, new(*AlreadyRegisteredError))
+	})
+
+	t.Run("ignored_by_option", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(shared, NewModule("api", shared))
+
+		p, err := c.BuildWithOptions(&ProviderOptions{IgnoreDuplicateRegistrations: true})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		assert.Equal(t, 2, c.Count())
+		RequireResolve[*TService](t, p)
+	})
+
+	t.Run("option_keeps_other_errors", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService)
+		c.AddSingleton(NewTService)
+		c.AddSingleton(NewTServiceWithID("other"))
+
+		_, err := c.BuildWithOptions(&ProviderOptions{IgnoreDuplicateRegistrations: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already registered (use keyed services or groups)")
+		assert.NotContains(t, err.Error(), "same constructor")
+	})
+
+	t.Run("not_identical", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name     string
+			register func(Collection)
+		}{
+			{"different_closures", func(c Collection) {
+				c.AddSingleton(NewTServiceWithID("a"))
+				c.AddSingleton(NewTServiceWithID("b"))
+			}},
+			{"different_lifetimes", func(c Collection) {
+				c.AddSingleton(NewTService)
+				c.AddScoped(NewTService)
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				tt.register(c)
+				require.Error(t, c.Err())
+				_, duplicate := errors.AsType[*DuplicateRegistrationError](c.Err())
+				assert.False(t, duplicate)
+			})
+		}
+	})
+
+	t.Run("group_members_are_exempt", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("all"))
+		c.AddSingleton(NewTService, Group("all"))
+		require.NoError(t, c.Err())
+	})
+}
@@ -0,0 +1,520 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Compose returns a read-only facade over providers built independently,
+// such as the feature modules of a modular monolith, so the composition
+// root can resolve from all of them through one Provider.
+//
+// Precedence follows the order of providers: a service is resolved from
+// the first provider that registers its type, and key for keyed services;
+// a sealed provider only counts for the types it exposes. A group collects
+// the members registered in every provider, in that order. A type no
+// provider registers is resolved from the first one, which reports it
+// missing. Each service is still constructed by the provider registering
+// it, from its own registrations: composing never lets one provider's
+// services depend on another's.
+//
+// Scopes created from the facade hold one scope of each provider and
+// close them together. Resolving godi.Provider, godi.Scope or
+// context.Context from the facade returns the facade and its scopes, and
+// godi.FromContext finds the composed scope. Close and StopAll fail with
+// ErrProviderSealed, since each provider belongs to whoever built it.
+// CaptureTrace is not available on the facade; call it on the providers.
+//
+// Example:
+//
+//	app, err := godi.Compose(ordersProvider, billingProvider, sharedProvider)
+func Compose(providers ...Provider) (Provider, error) {
+	if len(providers) == 0 {
+		return nil, &ValidationError{Cause: fmt.Errorf("godi.Compose needs at least one provider")}
+	}
+
+	p := &composedProvider{}
+	var roots []Scope
+	for _, member := range providers {
+		switch v := member.(type) {
+		case nil:
+			return nil, ErrProviderNil
+		case *provider:
+			p.providers = append(p.providers, v)
+			roots = append(roots, v.rootScope)
+		case *singletonProvider:
+			p.providers = append(p.providers, v.provider)
+			roots = append(roots, v.rootScope)
+		case *sealedProvider:
+			p.providers = append(p.providers, v)
+			roots = append(roots, v.scopeView())
+		case *composedProvider:
+			p.providers = append(p.providers, v.providers...)
+			roots = append(roots, v.root.scopes()...)
+		default:
+			return nil, &ValidationError{Cause: fmt.Errorf("godi.Compose: unsupported provider implementation %T", member)}
+		}
+	}
+
+	ids := make([]string, len(p.providers))
+	for i, member := range p.providers {
+		if isClosedProvider(member) {
+			return nil, ErrProviderDisposed
+		}
+		ids[i] = member.ID()
+	}
+	p.id = strings.Join(ids, "+")
+	p.root = newComposedScope(p, roots, context.Background(), nil, false)
+	p.composedView = p.root.composedView
+	p.self = p
+	return p, nil
+}
+
+// isClosedProvider reports whether the provider behind p has been closed.
+func isClosedProvider(p Provider) bool {
+	switch v := p.(type) {
+	case *provider:
+		return v.isDisposed()
+	case *singletonProvider:
+		return v.isDisposed()
+	case *sealedProvider:
+		return v.root.isDisposed()
+	}
+	return false
+}
+
+// composeMember is one of the scopes a composed view resolves from.
+type composeMember struct {
+	scope  Scope
+	owner  *provider
+	sealed *sealedView // set when scope is sealed
+}
+
+func newComposeMember(s Scope) composeMember {
+	member := composeMember{scope: s, owner: ownerOf(s)}
+	if sealed, ok := s.(*sealedScope); ok {
+		member.sealed = &sealed.sealedView
+	}
+	return member
+}
+
+// registers reports whether the member's provider has a registration for
+// serviceType and key that the member exposes.
+func (m composeMember) registers(serviceType reflect.Type, key any) bool {
+	if m.sealed != nil && !m.sealed.permits(serviceType) {
+		return false
+	}
+	resolved := m.owner.keyedFallback(instanceKey{Type: serviceType, Key: key})
+	return m.owner.findDescriptor(resolved.Type, resolved.Key) != nil
+}
+
+// composedView implements the resolution methods shared by composed
+// providers and composed scopes.
+type composedView struct {
+	members []composeMember
+	self    Provider // the composedProvider or composedScope embedding this view
+}
+
+// memberFor returns the member to resolve serviceType and key from.
+func (v *composedView) memberFor(serviceType reflect.Type, key any) Scope {
+	return v.members[v.memberIndex(serviceType, key)].scope
+}
+
+// memberIndex returns the index of the member to resolve serviceType and
+// key from.
+func (v *composedView) memberIndex(serviceType reflect.Type, key any) int {
+	for i, m := range v.members {
+		if m.registers(serviceType, key) {
+			return i
+		}
+	}
+	return 0
+}
+
+func (v *composedView) Get(serviceType reflect.Type) (any, error) {
+	switch serviceType {
+	case providerType:
+		return v.provider(), nil
+	case scopeType:
+		return v.scopeView(), nil
+	case contextType:
+		return v.scopeView().Context(), nil
+	}
+	return v.memberFor(serviceType, nil).Get(serviceType)
+}
+
+func (v *composedView) ResolveMany(serviceTypes ...reflect.Type) ([]any, error) {
+	instances := make([]any, len(serviceTypes))
+	for i, serviceType := range serviceTypes {
+		instance, err := v.Get(serviceType)
+		if err != nil {
+			return nil, err
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+func (v *composedView) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	return v.memberFor(serviceType, key).GetKeyed(serviceType, key)
+}
+
+func (v *composedView) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return v.collectGroup(serviceType, group, func(i int) ([]any, error) {
+		return v.members[i].scope.GetGroup(serviceType, group)
+	})
+}
+
+// collectGroup joins the members of a group registered in each member, got
+// with getGroup(i) for member i, or returns the first member's group if
+// none registers it.
+func (v *composedView) collectGroup(serviceType reflect.Type, group string, getGroup func(i int) ([]any, error)) ([]any, error) {
+	var instances []any
+	found := false
+	for i, m := range v.members {
+		if m.groupSize(serviceType, group) == 0 {
+			continue
+		}
+		members, err := getGroup(i)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, members...)
+		found = true
+	}
+	if !found {
+		return getGroup(0)
+	}
+	return instances, nil
+}
+
+// groupSize returns the number of members of a group the member registers
+// and exposes.
+func (m composeMember) groupSize(serviceType reflect.Type, group string) int {
+	if m.sealed != nil && !m.sealed.permits(serviceType) {
+		return 0
+	}
+	return len(m.owner.findGroupDescriptors(serviceType, group))
+}
+
+func (v *composedView) ResolveByName(name string) (any, error) {
+	for _, m := range v.members {
+		if d := m.owner.exports[name]; d != nil && (m.sealed == nil || m.sealed.permits(d.Type)) {
+			return ResolveByName(m.scope, name)
+		}
+	}
+	return ResolveByName(v.members[0].scope, name)
+}
+
+// Inject fills the fields of target like godi.Inject, resolving each
+// field from the provider that registers it.
+func (v *composedView) Inject(target any) error {
+	return v.members[0].owner.rootScope.inject(target, v)
+}
+
+// DumpTo writes the registrations of each provider in turn.
+func (v *composedView) DumpTo(w io.Writer) error {
+	for _, m := range v.members {
+		if err := DumpTo(m.scope, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *composedView) CreateScope(ctx context.Context) (Scope, error) {
+	return v.createScopeWith(ctx)
+}
+
+func (v *composedView) createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error) {
+	return v.newScope(ctx, func(ctx context.Context, parent Provider) (Scope, error) {
+		return CreateScope(ctx, parent, opts...)
+	})
+}
+
+func (v *composedView) GetPooledScope(ctx context.Context) (Scope, error) {
+	return v.newScope(ctx, func(ctx context.Context, parent Provider) (Scope, error) {
+		return GetPooledScope(ctx, parent)
+	})
+}
+
+// newScope creates a composed scope from one scope made by create from each
+// member: from the provider itself for a composed provider, so the scopes
+// have no parent. If one fails, those already created are closed.
+func (v *composedView) newScope(ctx context.Context, create func(context.Context, Provider) (Scope, error)) (Scope, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	var parents []Provider
+	if p, ok := v.self.(*composedProvider); ok {
+		parents = p.providers
+	} else {
+		for _, m := range v.members {
+			parents = append(parents, m.scope)
+		}
+	}
+
+	children := make([]Scope, 0, len(parents))
+	for _, parent := range parents {
+		child, err := create(ctx, parent)
+		if err != nil {
+			cancel()
+			for i := len(children) - 1; i >= 0; i-- {
+				_ = children[i].Close()
+			}
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return newComposedScope(v.provider(), children, ctx, cancel, true), nil
+}
+
+// invocation starts the call on every member, so each parameter is
+// resolved from the provider registering it within that provider's call.
+func (v *composedView) invocation(shareTransients bool) (*invocation, error) {
+	calls := make([]*invocation, len(v.members))
+	for i, m := range v.members {
+		target, err := invokerOf(m.scope)
+		if err != nil {
+			return nil, err
+		}
+		if calls[i], err = target.invocation(shareTransients); err != nil {
+			return nil, err
+		}
+	}
+	return &invocation{
+		analyzer: calls[0].analyzer,
+		resolver: composedResolver{v: v, calls: calls},
+		context:  v.scopeView().Context(),
+		// composedResolver translates each error in the member it comes from.
+		translate: func(err error, _ ResolutionSite) error { return err },
+	}, nil
+}
+
+func (v *composedView) groupSize(elemType reflect.Type, group string) int {
+	n := 0
+	for _, m := range v.members {
+		n += m.groupSize(elemType, group)
+	}
+	return n
+}
+
+func (v *composedView) groupMember(elemType reflect.Type, group string, i int) (any, error) {
+	for _, m := range v.members {
+		n := m.groupSize(elemType, group)
+		if i < n {
+			target, err := invokerOf(m.scope)
+			if err != nil {
+				return nil, err
+			}
+			return target.groupMember(elemType, group, i)
+		}
+		i -= n
+	}
+	return nil, &ResolutionError{ServiceType: elemType, Cause: ErrServiceNotFound}
+}
+
+// graph joins the graphs of the members like resolution does: a service
+// is provided by the first member registering it, and a group by all of
+// them.
+func (v *composedView) graph() (*graphView, error) {
+	var all []*descriptor
+	services := make(map[TypeKey]*descriptor)
+	groups := make(map[GroupKey][]*descriptor)
+	for _, m := range v.members {
+		member, err := graphOf(m.scope)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, member.all...)
+		for key, d := range member.byType {
+			if _, ok := services[key]; !ok {
+				services[key] = d
+			}
+		}
+		for key, members := range member.groups {
+			groups[key] = append(groups[key], members...)
+		}
+	}
+	return newGraphView(all, services, groups), nil
+}
+
+// composedResolver resolves the parameters of one call through a composed
+// view, each within the call started on the member registering it.
+type composedResolver struct {
+	v     *composedView
+	calls []*invocation // by member
+}
+
+func (r composedResolver) Get(serviceType reflect.Type) (any, error) {
+	switch serviceType {
+	case providerType, scopeType, contextType:
+		return r.v.Get(serviceType)
+	}
+	call := r.calls[r.v.memberIndex(serviceType, nil)]
+	instance, err := call.resolver.Get(serviceType)
+	if err != nil {
+		return nil, call.translate(err, ResolutionSite{Operation: "Get", ServiceType: serviceType})
+	}
+	return instance, nil
+}
+
+func (r composedResolver) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	call := r.calls[r.v.memberIndex(serviceType, key)]
+	instance, err := call.resolver.GetKeyed(serviceType, key)
+	if err != nil {
+		return nil, call.translate(err, ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: key})
+	}
+	return instance, nil
+}
+
+func (r composedResolver) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return r.v.collectGroup(serviceType, group, func(i int) ([]any, error) {
+		call := r.calls[i]
+		instances, err := call.resolver.GetGroup(serviceType, group)
+		if err != nil {
+			return nil, call.translate(err, ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+		}
+		return instances, nil
+	})
+}
+
+// provider returns the composed provider the view belongs to.
+func (v *composedView) provider() *composedProvider {
+	if s, ok := v.self.(*composedScope); ok {
+		return s.owner
+	}
+	return v.self.(*composedProvider)
+}
+
+// scopeView returns the composed scope this view resolves from.
+func (v *composedView) scopeView() *composedScope {
+	if s, ok := v.self.(*composedScope); ok {
+		return s
+	}
+	return v.self.(*composedProvider).root
+}
+
+// composedProvider is the facade returned by Compose.
+type composedProvider struct {
+	composedView
+	id        string
+	providers []Provider
+	root      *composedScope // the root scopes of providers
+}
+
+func (p *composedProvider) ID() string {
+	return p.id
+}
+
+// Close always fails: the composed providers belong to their builders.
+func (p *composedProvider) Close() error {
+	return ErrProviderSealed
+}
+
+// StopAll always fails, like Close.
+func (p *composedProvider) StopAll(context.Context) error {
+	return ErrProviderSealed
+}
+
+// composedScope is a scope of a composed provider: one scope of each of
+// its providers.
+type composedScope struct {
+	composedView
+	owner   *composedProvider
+	owned   bool // created through the facade, so its holder may close it
+	context context.Context
+	cancel  context.CancelFunc
+}
+
+func newComposedScope(owner *composedProvider, scopes []Scope, ctx context.Context, cancel context.CancelFunc, owned bool) *composedScope {
+	s := &composedScope{owner: owner, owned: owned, cancel: cancel}
+	s.members = make([]composeMember, len(scopes))
+	for i, member := range scopes {
+		s.members[i] = newComposeMember(member)
+	}
+	s.self = s
+	// FromContext must find the composed scope, not one of its members.
+	s.context = context.WithValue(ctx, scopeContextKey{}, Scope(s))
+	return s
+}
+
+// scopes returns the member scopes of s.
+func (s *composedScope) scopes() []Scope {
+	scopes := make([]Scope, len(s.members))
+	for i, m := range s.members {
+		scopes[i] = m.scope
+	}
+	return scopes
+}
+
+// memberOf returns the member scope of s belonging to p, or nil.
+func (s *composedScope) memberOf(p *provider) Scope {
+	for _, m := range s.members {
+		if m.owner == p {
+			return m.scope
+		}
+	}
+	return nil
+}
+
+// ID returns the IDs of the member scopes joined by "+".
+func (s *composedScope) ID() string {
+	ids := make([]string, len(s.members))
+	for i, m := range s.members {
+		ids[i] = m.scope.ID()
+	}
+	return strings.Join(ids, "+")
+}
+
+func (s *composedScope) Provider() Provider {
+	return s.owner
+}
+
+func (s *composedScope) Context() context.Context {
+	return s.context
+}
+
+// DumpState writes the state of each member scope in turn.
+func (s *composedScope) DumpState(w io.Writer) error {
+	for _, m := range s.members {
+		if err := DumpState(m.scope, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the member scopes, last first, if the scope was created
+// through the facade, and fails with ErrProviderSealed otherwise.
+func (s *composedScope) Close() error {
+	return s.closeMembers(func(m Scope) error { return m.Close() })
+}
+
+// StopAll stops the member scopes, last first, like Close.
+func (s *composedScope) StopAll(ctx context.Context) error {
+	return s.closeMembers(func(m Scope) error { return StopAll(ctx, m) })
+}
+
+func (s *composedScope) closeMembers(closeMember func(Scope) error) error {
+	if !s.owned {
+		return ErrProviderSealed
+	}
+
+	var errs []error
+	for i := len(s.members) - 1; i >= 0; i-- {
+		if err := closeMember(s.members[i].scope); err != nil {
+			errs = append(errs, fmt.Errorf("scope %s: %w", s.members[i].scope.ID(), err))
+		}
+	}
+	s.cancel()
+	if len(errs) > 0 {
+		return &DisposalError{Context: "composed scope", Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves_from_the_first_provider_registering_the_type", func(t *testing.T) {
+		t.Parallel()
+		orders := BuildProvider(t, AddSingleton(NewTServiceWithID("orders")))
+		billing := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("billing")),
+			AddSingleton(NewTDependencyWithName("billing")),
+		)
+
+		app, err := Compose(orders, billing)
+		require.NoError(t, err)
+		assert.Equal(t, "orders", RequireResolve[*TService](t, app).ID)
+		assert.Equal(t, "billing", RequireResolve[*TDependency](t, app).Name)
+		assert.Equal(t, orders.ID()+"+"+billing.ID(), app.ID())
+
+		_, err = Resolve[*TTransient](app)
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("keyed_services_and_groups", func(t *testing.T) {
+		t.Parallel()
+		first := BuildProvider(t,
+			AddSingleton(NewTDependencyWithName("first"), Name("a")),
+			AddSingleton(NewTServiceWithID("first"), Group("handlers")),
+		)
+		second := BuildProvider(t,
+			AddSingleton(NewTDependencyWithName("second"), Name("b")),
+			AddSingleton(NewTServiceWithID("second"), Group("handlers")),
+		)
+
+		app, err := Compose(first, second)
+		require.NoError(t, err)
+		b, err := ResolveKeyed[*TDependency](app, "b")
+		require.NoError(t, err)
+		assert.Equal(t, "second", b.Name)
+
+		handlers, err := ResolveGroup[*TService](app, "handlers")
+		require.NoError(t, err)
+		require.Len(t, handlers, 2)
+		assert.Equal(t, "first", handlers[0].ID)
+		assert.Equal(t, "second", handlers[1].ID)
+	})
+
+	t.Run("sealed_providers_count_only_for_exposed_types", func(t *testing.T) {
+		t.Parallel()
+		hidden := BuildProvider(t, AddSingleton(NewTServiceWithID("hidden")))
+		sealed, err := Seal(hidden, AllowServices(reflect.TypeFor[*TDependency]()))
+		require.NoError(t, err)
+		open := BuildProvider(t, AddSingleton(NewTServiceWithID("open")))
+
+		app, err := Compose(sealed, open)
+		require.NoError(t, err)
+		assert.Equal(t, "open", RequireResolve[*TService](t, app).ID)
+	})
+
+	t.Run("scopes_span_every_provider", func(t *testing.T) {
+		t.Parallel()
+		orders := BuildProvider(t, AddScoped(NewTDisposable))
+		billing := BuildProvider(t, AddScoped(NewTDependency))
+
+		app, err := Compose(orders, billing)
+		require.NoError(t, err)
+		s, err := app.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		disposable := RequireResolveFrom[*TDisposable](t, s)
+		dep := RequireResolveFrom[*TDependency](t, s)
+		assert.Same(t, dep, RequireResolveFrom[*TDependency](t, s))
+		assert.Same(t, app, RequireResolveFrom[Provider](t, s))
+
+		found, err := FromContext(s.Context())
+		require.NoError(t, err)
+		assert.Same(t, s, found)
+		assert.Same(t, s, RequireResolveFrom[Scope](t, s))
+
+		require.NoError(t, s.Close())
+		assert.True(t, disposable.IsClosed())
+		_, err = Resolve[*TDependency](s)
+		assert.ErrorIs(t, err, ErrScopeDisposed)
+	})
+
+	t.Run("scoped_accessors_find_their_member_scope", func(t *testing.T) {
+		t.Parallel()
+		type audit struct{ user ScopedAccessor[*TDependency] }
+		orders := BuildProvider(t,
+			AddScoped(NewTDependencyWithName("alice")),
+			AddSingleton(func(user ScopedAccessor[*TDependency]) *audit { return &audit{user: user} }),
+		)
+		other := BuildProvider(t, AddSingleton(NewTService))
+
+		app, err := Compose(other, orders)
+		require.NoError(t, err)
+		s, err := app.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		user, err := RequireResolve[*audit](t, app).user.Get(s.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user.Name)
+	})
+
+	t.Run("inject_and_resolve_by_name", func(t *testing.T) {
+		t.Parallel()
+		first := BuildProvider(t, AddSingleton(NewTService))
+		second := BuildProvider(t, AddSingleton(NewTDependencyWithName("named"), ExportName("dep")))
+
+		app, err := Compose(first, second)
+		require.NoError(t, err)
+
+		var target struct {
+			Svc *TService
+			Dep *TDependency
+		}
+		require.NoError(t, Inject(app, &target))
+		assert.NotNil(t, target.Svc)
+		assert.Equal(t, "named", target.Dep.Name)
+
+		named, err := ResolveByName(app, "dep")
+		require.NoError(t, err)
+		assert.Same(t, target.Dep, named)
+	})
+
+	t.Run("is_read_only", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDisposable))
+		app, err := Compose(p)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, app.Close(), ErrProviderSealed)
+		assert.ErrorIs(t, StopAll(context.Background(), app), ErrProviderSealed)
+		assert.False(t, RequireResolve[*TDisposable](t, p).IsClosed())
+
+		root := RequireResolve[Scope](t, app)
+		assert.ErrorIs(t, root.Close(), ErrProviderSealed)
+	})
+
+	t.Run("flattens_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		a := BuildProvider(t, AddSingleton(NewTServiceWithID("a")))
+		b := BuildProvider(t, AddSingleton(NewTDependencyWithName("b")))
+		inner, err := Compose(a)
+		require.NoError(t, err)
+
+		app, err := Compose(inner, b)
+		require.NoError(t, err)
+		assert.Equal(t, "a", RequireResolve[*TService](t, app).ID)
+		assert.Equal(t, "b", RequireResolve[*TDependency](t, app).Name)
+
+		var dump strings.Builder
+		require.NoError(t, DumpTo(app, &dump))
+		assert.Contains(t, dump.String(), a.ID())
+		assert.Contains(t, dump.String(), b.ID())
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		_, err := Compose()
+		var validation *ValidationError
+		assert.True(t, errors.As(err, &validation))
+
+		_, err = Compose(BuildProvider(t), nil)
+		assert.ErrorIs(t, err, ErrProviderNil)
+
+		s := NewTestScope(t, BuildProvider(t))
+		_, err = Compose(s)
+		assert.True(t, errors.As(err, &validation))
+
+		closed := BuildProvider(t)
+		require.NoError(t, closed.Close())
+		_, err = Compose(closed)
+		assert.ErrorIs(t, err, ErrProviderDisposed)
+	})
+}
@@ -0,0 +1,88 @@
+package godi
+
+import (
+	"context"
+
+	"github.com/junioryono/godi/v5/internal/graph"
+)
+
+// createSingletonsConcurrently creates the singletons of the dependency
+// graph with up to ProviderOptions.MaxConcurrentConstructions constructors
+// running at once. A node is scheduled once every node it depends on is
+// done, so singletons reached through transient or scoped dependencies are
+// built first; nodes that are not singletons complete immediately.
+func (p *provider) createSingletonsConcurrently(ctx context.Context, sorted []*graph.Node) error {
+	type result struct {
+		node *graph.Node
+		err  error
+	}
+
+	nodes := make(map[graph.NodeKey]*graph.Node, len(sorted))
+	pending := make(map[graph.NodeKey]int, len(sorted))
+	ready := make([]*graph.Node, 0, len(sorted))
+	for _, node := range sorted {
+		nodes[node.Key] = node
+		pending[node.Key] = len(node.Dependencies)
+		if len(node.Dependencies) == 0 {
+			ready = append(ready, node)
+		}
+	}
+	complete := func(node *graph.Node) {
+		for _, dependent := range node.Dependents {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, nodes[dependent])
+			}
+		}
+	}
+
+	done := make(chan result)
+	running := 0
+	var firstErr error
+
+	for len(ready) > 0 || running > 0 {
+		for firstErr == nil && ctx.Err() == nil && running < p.maxConcurrentConstructions && len(ready) > 0 {
+			node := ready[0]
+			ready = ready[1:]
+
+			descriptor, err := singletonDescriptor(node)
+			if err != nil {
+				firstErr = err
+				break
+			}
+			if descriptor == nil {
+				complete(node)
+				continue
+			}
+
+			// Siblings share one constructor call: createSingleton makes the
+			// first of them construct it while the others wait for it.
+			running++
+			go func() {
+				done <- result{node: node, err: p.createSingleton(node)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		res := <-done
+		running--
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+		complete(res.node)
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return &BuildError{
+			Phase:   "singleton-creation",
+			Details: "build cancelled during singleton creation",
+			Cause:   err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package godi
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrentConstructions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bounds_running_constructors", func(t *testing.T) {
+		t.Parallel()
+		var running, peak atomic.Int32
+		dial := func(name string) func() *TService {
+			return func() *TService {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				return &TService{ID: name}
+			}
+		}
+
+		c := NewCollection()
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			c.AddSingleton(dial(name), Name(name))
+		}
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 3})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.Equal(t, int32(3), peak.Load())
+		svc, err := ResolveKeyed[*TService](p, "h")
+		require.NoError(t, err)
+		assert.Equal(t, "h", svc.ID)
+	})
+
+	t.Run("waits_for_dependencies", func(t *testing.T) {
+		t.Parallel()
+		var built atomic.Bool
+		c := NewCollection()
+		c.AddSingleton(func() *TService {
+			time.Sleep(10 * time.Millisecond)
+			built.Store(true)
+			return NewTService()
+		})
+		c.AddTransient(func(*TService) *TDependency { return NewTDependency() })
+		c.AddSingleton(func(dep *TDependency) *TServiceWithDeps {
+			assert.True(t, built.Load())
+			return &TServiceWithDeps{Dep: dep}
+		})
+		c.AddSingleton(NewTDisposable)
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 4})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.NotNil(t, RequireResolve[*TServiceWithDeps](t, p).Dep)
+	})
+
+	t.Run("siblings_share_one_call", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		c := NewCollection()
+		c.AddSingleton(func() (*TService, *TDependency, *TDisposable) {
+			calls.Add(1)
+			return NewTTripleReturn()
+		})
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 3})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("constructs_different_singletons_in_parallel", func(t *testing.T) {
+		t.Parallel()
+		var started sync.WaitGroup
+		started.Add(2)
+		rendezvous := func(name string) func() *TService {
+			return func() *TService {
+				started.Done()
+				waited := make(chan struct{})
+				go func() { started.Wait(); close(waited) }()
+				select {
+				case <-waited:
+				case <-time.After(5 * time.Second):
+					t.Errorf("%s was constructed alone", name)
+				}
+				return &TService{ID: name}
+			}
+		}
+
+		c := NewCollection()
+		c.AddSingleton(rendezvous("a"), Name("a"))
+		c.AddSingleton(rendezvous("b"), Name("b"))
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 2})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+	})
+
+	t.Run("dynamic_resolutions_share_one_construction", func(t *testing.T) {
+		t.Parallel()
+		var shared, siblings atomic.Int32
+		c := NewCollection()
+		c.AddSingleton(func() *TDisposable {
+			shared.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return NewTDisposable()
+		})
+		c.AddSingleton(func() (*TService, *TDependency) {
+			siblings.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return NewTService(), NewTDependency()
+		})
+
+		// Consumers resolve through the provider, so Build does not know
+		// to construct the shared singletons first.
+		type consumer struct {
+			disposable *TDisposable
+			service    *TService
+			dependency *TDependency
+		}
+		const consumers = 32
+		for i := range consumers {
+			c.AddSingleton(func(p Provider) (*consumer, error) {
+				disposable, err := Resolve[*TDisposable](p)
+				if err != nil {
+					return nil, err
+				}
+				if i%2 == 0 {
+					dependency, err := Resolve[*TDependency](p)
+					return &consumer{disposable: disposable, dependency: dependency}, err
+				}
+				service, err := Resolve[*TService](p)
+				return &consumer{disposable: disposable, service: service}, err
+			}, Name(strconv.Itoa(i)))
+		}
+
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 8})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.Equal(t, int32(1), shared.Load())
+		assert.Equal(t, int32(1), siblings.Load())
+		disposable := RequireResolve[*TDisposable](t, p)
+		for i := range consumers {
+			got, err := ResolveKeyed[*consumer](p, strconv.Itoa(i))
+			require.NoError(t, err)
+			assert.Same(t, disposable, got.disposable)
+		}
+	})
+
+	t.Run("reports_constructor_errors", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTServiceError)
+		c.AddSingleton(NewTDependency)
+		_, err := c.BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: 2})
+		require.Error(t, err)
+	})
+
+	t.Run("rejects_negative_limits", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewCollection().BuildWithOptions(&ProviderOptions{MaxConcurrentConstructions: -1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MaxConcurrentConstructions")
+	})
+}
+
+func TestSingletonsResolvingEachOther(t *testing.T) {
+	t.Parallel()
+
+	type (
+		cycleA struct{}
+		cycleB struct{}
+	)
+
+	build := func(t *testing.T, c Collection, options *ProviderOptions) error {
+		t.Helper()
+		done := make(chan error, 1)
+		go func() {
+			p, err := c.BuildWithOptions(options)
+			if err == nil {
+				_ = p.Close()
+			}
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Build deadlocked")
+			return nil
+		}
+	}
+
+	t.Run("in_one_goroutine", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) (*cycleA, error) {
+			_, err := Resolve[*cycleB](p)
+			return &cycleA{}, err
+		})
+		c.AddSingleton(func(p Provider) (*cycleB, error) {
+			_, err := Resolve[*cycleA](p)
+			return &cycleB{}, err
+		})
+
+		err := build(t, c, nil)
+		cycle, ok := errors.AsType[*CircularDependencyError](err)
+		require.True(t, ok, "expected CircularDependencyError, got %v", err)
+		assert.Len(t, cycle.Path, 2)
+	})
+
+	t.Run("on_a_goroutine_the_constructor_starts", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) (*cycleA, error) {
+			errs := make(chan error)
+			go func() {
+				_, err := Resolve[*cycleB](p)
+				errs <- err
+			}()
+			return &cycleA{}, <-errs
+		})
+		c.AddSingleton(func(p Provider) (*cycleB, error) {
+			_, err := Resolve[*cycleA](p)
+			return &cycleB{}, err
+		})
+
+		err := build(t, c, nil)
+		cycle, ok := errors.AsType[*CircularDependencyError](err)
+		require.True(t, ok, "expected CircularDependencyError, got %v", err)
+		assert.Len(t, cycle.Path, 2)
+	})
+
+	t.Run("across_goroutines", func(t *testing.T) {
+		t.Parallel()
+		var started sync.WaitGroup
+		started.Add(2)
+		rendezvous := func() {
+			started.Done()
+			started.Wait()
+		}
+
+		c := NewCollection()
+		c.AddSingleton(func(p Provider) (*cycleA, error) {
+			rendezvous()
+			_, err := Resolve[*cycleB](p)
+			return &cycleA{}, err
+		})
+		c.AddSingleton(func(p Provider) (*cycleB, error) {
+			rendezvous()
+			_, err := Resolve[*cycleA](p)
+			return &cycleB{}, err
+		})
+
+		err := build(t, c, &ProviderOptions{MaxConcurrentConstructions: 2})
+		_, ok := errors.AsType[*CircularDependencyError](err)
+		require.True(t, ok, "expected CircularDependencyError, got %v", err)
+	})
+}
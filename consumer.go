@@ -0,0 +1,100 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// WrapConsumer adapts a message handler for use with a message consumer
+// such as a Kafka or NATS subscription. Each message is handled in a fresh
+// child scope of p, giving event processing the same scoped lifetimes as a
+// web request: deps is resolved from that scope and the handler receives
+// its context, so godi.FromContext works downstream.
+//
+// D is either a single service or a godi.In parameter object naming several
+// dependencies. The scope is closed after every message, including when the
+// handler fails or panics; a panic is returned as a *ConsumerPanicError.
+// Close errors are joined with the handler's error.
+//
+// Example:
+//
+//	type orderDeps struct {
+//	    godi.In
+//	    Orders *OrderService
+//	    Tx     *sql.Tx
+//	}
+//
+//	handle := godi.WrapConsumer(provider, func(ctx context.Context, msg *kafka.Message, deps orderDeps) error {
+//	    return deps.Orders.Apply(ctx, deps.Tx, msg.Value)
+//	})
+//
+//	for msg := range messages {
+//	    if err := handle(ctx, msg); err != nil {
+//	        log.Printf("message %s: %v", msg.Key, err)
+//	    }
+//	}
+func WrapConsumer[M, D any](p Provider, handler func(ctx context.Context, msg M, deps D) error) func(context.Context, M) error {
+	// Resolving D through an identity function lets the invoker fill in
+	// godi.In parameter objects exactly as it does for constructors.
+	analyze := sync.OnceValues(func() (*reflection.ConstructorInfo, error) {
+		target, err := invokerOf(p)
+		if err != nil {
+			return nil, err
+		}
+		call, err := target.invocation(false)
+		if err != nil {
+			return nil, err
+		}
+		identity := func(deps D) D { return deps }
+		info, err := call.analyzer.Analyze(identity)
+		if err != nil {
+			return nil, &ReflectionAnalysisError{Constructor: identity, Operation: "analyze", Cause: err}
+		}
+		return info, nil
+	})
+
+	return func(ctx context.Context, msg M) (err error) {
+		if handler == nil {
+			return &ValidationError{ServiceType: reflect.TypeFor[D](), Cause: ErrConstructorNil}
+		}
+		info, err := analyze()
+		if err != nil {
+			return err
+		}
+
+		s, err := p.CreateScope(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := s.Close(); closeErr != nil {
+				err = errors.Join(err, closeErr)
+			}
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				err = &ConsumerPanicError{Message: reflect.TypeFor[M](), Panic: r, Stack: debug.Stack()}
+			}
+		}()
+
+		target, err := invokerOf(s)
+		if err != nil {
+			return err
+		}
+		call, err := target.invocation(false)
+		if err != nil {
+			return err
+		}
+		results, err := call.analyzer.GetInvoker().Invoke(info, call.resolver)
+		if err != nil {
+			return call.translate(err, ResolutionSite{Operation: "WrapConsumer", ServiceType: reflect.TypeFor[D]()})
+		}
+		deps, _ := reflect.TypeAssert[D](results[0])
+		return handler(s.Context(), msg, deps)
+	}
+}
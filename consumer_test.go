@@ -0,0 +1,154 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type consumerMessage struct{ Body string }
+
+func TestWrapConsumer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves_deps_in_a_fresh_scope_per_message", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+
+		var seen []*TDisposable
+		handle := WrapConsumer(p, func(ctx context.Context, msg consumerMessage, d *TDisposable) error {
+			s, err := FromContext(ctx)
+			require.NoError(t, err)
+			assert.Same(t, d, RequireResolveFrom[*TDisposable](t, s), "handler runs in the scope deps came from")
+			assert.False(t, d.IsClosed())
+			seen = append(seen, d)
+			return nil
+		})
+
+		require.NoError(t, handle(t.Context(), consumerMessage{Body: "a"}))
+		require.NoError(t, handle(t.Context(), consumerMessage{Body: "b"}))
+		require.Len(t, seen, 2)
+		assert.NotSame(t, seen[0], seen[1])
+		assert.True(t, seen[0].IsClosed())
+		assert.True(t, seen[1].IsClosed())
+	})
+
+	t.Run("parameter_objects", func(t *testing.T) {
+		t.Parallel()
+		type deps struct {
+			In
+			Service *TService
+			Named   *TDependency      `name:"audit"`
+			Missing *TServiceWithDeps `optional:"true"`
+		}
+		p := BuildProvider(t,
+			AddScoped(NewTService),
+			AddSingleton(NewTDependencyWithName("audit"), Name("audit")),
+		)
+
+		handle := WrapConsumer(p, func(_ context.Context, _ consumerMessage, d deps) error {
+			assert.NotNil(t, d.Service)
+			assert.Equal(t, "audit", d.Named.Name)
+			assert.Nil(t, d.Missing)
+			return nil
+		})
+		require.NoError(t, handle(t.Context(), consumerMessage{}))
+	})
+
+	t.Run("sealed_and_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t, AddScoped(NewTDisposable)))
+		require.NoError(t, err)
+		app, err := Compose(sealed, BuildProvider(t, AddScoped(NewTService)))
+		require.NoError(t, err)
+
+		for _, p := range []Provider{sealed, app} {
+			var got *TDisposable
+			handle := WrapConsumer(p, func(_ context.Context, _ consumerMessage, d *TDisposable) error {
+				got = d
+				return nil
+			})
+			require.NoError(t, handle(t.Context(), consumerMessage{}))
+			require.NotNil(t, got)
+			assert.True(t, got.IsClosed())
+		}
+	})
+
+	t.Run("disposes_scope_on_failure_and_panic", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+		handlerErr := errors.New("bad message")
+
+		var failed, panicked *TDisposable
+		fail := WrapConsumer(p, func(_ context.Context, _ consumerMessage, d *TDisposable) error {
+			failed = d
+			return handlerErr
+		})
+		require.ErrorIs(t, fail(t.Context(), consumerMessage{}), handlerErr)
+		assert.True(t, failed.IsClosed())
+
+		explode := WrapConsumer(p, func(_ context.Context, msg consumerMessage, d *TDisposable) error {
+			panicked = d
+			panic("poison message " + msg.Body)
+		})
+		err := explode(t.Context(), consumerMessage{Body: "42"})
+		panicErr, ok := errors.AsType[*ConsumerPanicError](err)
+		require.True(t, ok, "expected ConsumerPanicError, got %v", err)
+		assert.Equal(t, "poison message 42", panicErr.Panic)
+		assert.Equal(t, TypeOf[consumerMessage](), panicErr.Message)
+		assert.True(t, panicked.IsClosed())
+	})
+
+	t.Run("close_errors_are_joined", func(t *testing.T) {
+		t.Parallel()
+		closeErr := errors.New("close failed")
+		p := BuildProvider(t, AddScoped(func() *TDisposable {
+			d := NewTDisposable()
+			d.SetCloseError(closeErr)
+			return d
+		}))
+
+		handle := WrapConsumer(p, func(context.Context, consumerMessage, *TDisposable) error { return nil })
+		require.ErrorIs(t, handle(t.Context(), consumerMessage{}), closeErr)
+	})
+
+	t.Run("missing_dependency_skips_handler", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+
+		called := false
+		handle := WrapConsumer(p, func(context.Context, consumerMessage, *TService) error {
+			called = true
+			return nil
+		})
+		require.ErrorIs(t, handle(t.Context(), consumerMessage{}), ErrServiceNotFound)
+		assert.False(t, called)
+	})
+
+	t.Run("context_is_derived_from_caller", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+		type ctxKey struct{}
+		ctx := context.WithValue(t.Context(), ctxKey{}, "trace-id")
+
+		handle := WrapConsumer(p, func(ctx context.Context, _ consumerMessage, _ Scope) error {
+			assert.Equal(t, "trace-id", ctx.Value(ctxKey{}))
+			return nil
+		})
+		require.NoError(t, handle(ctx, consumerMessage{}))
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+
+		err := WrapConsumer[consumerMessage, *TService](p, nil)(t.Context(), consumerMessage{})
+		require.ErrorIs(t, err, ErrConstructorNil)
+
+		err = WrapConsumer(nil, func(context.Context, consumerMessage, *TService) error { return nil })(t.Context(), consumerMessage{})
+		require.ErrorIs(t, err, ErrProviderNil)
+	})
+}
@@ -0,0 +1,271 @@
+// Package cron runs scheduled jobs in a godi scope per run.
+//
+// Jobs are scoped services registered with AddJob, which adds their
+// schedule to a group. A Scheduler runs each job in a fresh scope that is
+// closed when the run ends, so the job's scoped dependencies, such as
+// database transactions, are disposed after every run instead of living as
+// long as the process.
+//
+// Example usage:
+//
+//	services.AddScoped(NewCleanupJob) // implements cron.Job
+//	services.AddModules(godicron.AddJob[*CleanupJob]("cleanup", godicron.Every(time.Hour)))
+//
+//	provider, _ := services.Build()
+//	scheduler, _ := godicron.NewScheduler(provider,
+//	    godicron.WithOnRun(func(run godicron.Run) {
+//	        metrics.ObserveJob(run.Name, run.Duration, run.Err)
+//	    }),
+//	)
+//	go scheduler.Start(ctx)
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// JobsGroup is the group AddJob adds job entries to, the group "jobs" in
+// the namespace "cron" (see godi.GroupNS).
+const JobsGroup = "cron/jobs"
+
+// ErrJobPanicked is wrapped by the error of a run whose job panicked.
+var ErrJobPanicked = errors.New("job panicked")
+
+// ErrJobNotFound is returned by Scheduler.RunNow for an unknown job name.
+var ErrJobNotFound = errors.New("job not found")
+
+// Schedule reports when a job runs next. Next returns the first activation
+// after t, or the zero time if there is none. Schedules parsed by
+// github.com/robfig/cron/v3 satisfy Schedule.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule that activates every d.
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// Job is a unit of scheduled work. It is resolved from a fresh scope for
+// every run, and ctx is the scope's context.
+type Job interface {
+	Run(ctx context.Context) error
+}
+
+// Entry is a job registered with AddJob.
+type Entry struct {
+	// Name identifies the job in Run reports and Scheduler.RunNow.
+	Name string
+
+	// Schedule decides when the job runs.
+	Schedule Schedule
+
+	resolve func(scope godi.Scope) (Job, error)
+}
+
+// AddJob registers an Entry that runs the job T, resolved from a fresh
+// scope, on schedule. T itself must be registered separately, usually as a
+// scoped service.
+//
+// Example:
+//
+//	services.AddScoped(NewReportJob)
+//	services.AddModules(godicron.AddJob[*ReportJob]("reports", godicron.Every(15*time.Minute)))
+func AddJob[T Job](name string, schedule Schedule) godi.ModuleOption {
+	entry := &Entry{
+		Name:     name,
+		Schedule: schedule,
+		resolve: func(scope godi.Scope) (Job, error) {
+			return godi.Resolve[T](scope)
+		},
+	}
+	return godi.AddSingleton(func() *Entry { return entry }, godi.Group(JobsGroup))
+}
+
+// Run describes one run of a job, reported to the OnRun callback.
+type Run struct {
+	// Name is the job's name.
+	Name string
+
+	// Start is when the run began, and Duration how long it took,
+	// including creating and closing its scope.
+	Start    time.Time
+	Duration time.Duration
+
+	// Err is the error the run failed with, or nil. Resolving the job,
+	// running it and closing its scope can each fail; a panic is recovered
+	// and reported as an error wrapping ErrJobPanicked.
+	Err error
+}
+
+// Config holds the configuration of a Scheduler.
+type Config struct {
+	// OnRun is called after every run, whether it succeeded or failed.
+	// If nil, failed runs are logged using slog.
+	OnRun func(run Run)
+}
+
+// Option configures a Scheduler.
+type Option func(*Config)
+
+// WithOnRun sets the callback that receives every run, for metrics and
+// logging.
+func WithOnRun(fn func(run Run)) Option {
+	return func(c *Config) {
+		if fn != nil {
+			c.OnRun = fn
+		}
+	}
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		OnRun: func(run Run) {
+			if run.Err != nil {
+				slog.Error("scheduled job failed", "job", run.Name, "error", run.Err)
+			}
+		},
+	}
+}
+
+// Scheduler runs the jobs registered with AddJob.
+type Scheduler struct {
+	provider godi.Provider
+	cfg      *Config
+	entries  []*Entry
+	byName   map[string]*Entry
+}
+
+// NewScheduler returns a Scheduler for the jobs registered in provider. It
+// fails if two jobs share a name or a job has no schedule.
+func NewScheduler(provider godi.Provider, opts ...Option) (*Scheduler, error) {
+	if provider == nil {
+		return nil, godi.ErrProviderNil
+	}
+
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+
+	entries, err := godi.ResolveGroup[*Entry](provider, JobsGroup)
+	if err != nil {
+		return nil, fmt.Errorf("resolve jobs: %w", err)
+	}
+	byName := make(map[string]*Entry, len(entries))
+	for _, entry := range entries {
+		if entry.Schedule == nil {
+			return nil, fmt.Errorf("job %q has no schedule", entry.Name)
+		}
+		if _, exists := byName[entry.Name]; exists {
+			return nil, fmt.Errorf("job %q is registered more than once", entry.Name)
+		}
+		byName[entry.Name] = entry
+	}
+
+	return &Scheduler{provider: provider, cfg: cfg, entries: entries, byName: byName}, nil
+}
+
+// Entries returns the scheduled jobs in registration order.
+func (s *Scheduler) Entries() []*Entry {
+	return append([]*Entry(nil), s.entries...)
+}
+
+// Start runs every job on its schedule until ctx is done, then waits for
+// the runs in progress, whose scopes' contexts are cancelled with ctx, and
+// returns. An activation that comes while the previous run of the same job
+// is still in progress is skipped.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, entry := range s.entries {
+		wg.Go(func() { s.schedule(ctx, entry, &wg) })
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) schedule(ctx context.Context, entry *Entry, wg *sync.WaitGroup) {
+	var running atomic.Bool
+	for {
+		now := time.Now()
+		next := entry.Schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if running.CompareAndSwap(false, true) {
+			wg.Go(func() {
+				defer running.Store(false)
+				_ = s.run(ctx, entry)
+			})
+		}
+	}
+}
+
+// RunNow runs the named job once, in a fresh scope created from ctx, and
+// returns the run's error after reporting it to OnRun.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	entry, ok := s.byName[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	return s.run(ctx, entry)
+}
+
+func (s *Scheduler) run(ctx context.Context, entry *Entry) error {
+	start := time.Now()
+	err := s.runInScope(ctx, entry)
+	s.cfg.OnRun(Run{
+		Name:     entry.Name,
+		Start:    start,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return err
+}
+
+func (s *Scheduler) runInScope(ctx context.Context, entry *Entry) (err error) {
+	scope, err := s.provider.CreateScope(ctx)
+	if err != nil {
+		return fmt.Errorf("create scope: %w", err)
+	}
+	defer func() {
+		if closeErr := scope.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("close scope: %w", closeErr))
+		}
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrJobPanicked, r)
+		}
+	}()
+
+	job, err := entry.resolve(scope)
+	if err != nil {
+		return fmt.Errorf("resolve job: %w", err)
+	}
+	return job.Run(scope.Context())
+}
@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resource is a scoped dependency that records its disposal.
+type resource struct {
+	closed *atomic.Int32
+}
+
+func (r *resource) Close() error {
+	r.closed.Add(1)
+	return nil
+}
+
+type countingJob struct {
+	res  *resource
+	runs *atomic.Int32
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return ctx.Err()
+}
+
+type failingJob struct{}
+
+func (failingJob) Run(context.Context) error { return errors.New("boom") }
+
+type panickingJob struct{}
+
+func (panickingJob) Run(context.Context) error { panic("boom") }
+
+func newTestScheduler(t *testing.T, modules []godi.ModuleOption, opts ...Option) *Scheduler {
+	t.Helper()
+	collection := godi.NewCollection()
+	collection.AddModules(modules...)
+	provider, err := collection.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	scheduler, err := NewScheduler(provider, opts...)
+	require.NoError(t, err)
+	return scheduler
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("runs jobs in a scope per run", func(t *testing.T) {
+		var closed, runs atomic.Int32
+		scheduler := newTestScheduler(t, []godi.ModuleOption{
+			godi.AddScoped(func() *resource { return &resource{closed: &closed} }),
+			godi.AddScoped(func(res *resource) *countingJob { return &countingJob{res: res, runs: &runs} }),
+			AddJob[*countingJob]("count", Every(time.Millisecond)),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			scheduler.Start(ctx)
+			close(done)
+		}()
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+		cancel()
+		<-done
+
+		assert.Equal(t, runs.Load(), closed.Load(), "every run's scope is closed")
+	})
+
+	t.Run("reports runs", func(t *testing.T) {
+		var mu sync.Mutex
+		var reported []Run
+		scheduler := newTestScheduler(t, []godi.ModuleOption{
+			godi.AddScoped(func() failingJob { return failingJob{} }),
+			godi.AddScoped(func() panickingJob { return panickingJob{} }),
+			AddJob[failingJob]("fail", Every(time.Hour)),
+			AddJob[panickingJob]("panic", Every(time.Hour)),
+		}, WithOnRun(func(run Run) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, run)
+		}))
+
+		require.EqualError(t, scheduler.RunNow(context.Background(), "fail"), "boom")
+		require.ErrorIs(t, scheduler.RunNow(context.Background(), "panic"), ErrJobPanicked)
+		require.ErrorIs(t, scheduler.RunNow(context.Background(), "missing"), ErrJobNotFound)
+
+		require.Len(t, reported, 2)
+		assert.Equal(t, "fail", reported[0].Name)
+		assert.Equal(t, "panic", reported[1].Name)
+		assert.ErrorIs(t, reported[1].Err, ErrJobPanicked)
+	})
+
+	t.Run("reports unregistered jobs", func(t *testing.T) {
+		scheduler := newTestScheduler(t, []godi.ModuleOption{
+			AddJob[*countingJob]("count", Every(time.Hour)),
+		})
+
+		err := scheduler.RunNow(context.Background(), "count")
+		require.ErrorIs(t, err, godi.ErrServiceNotFound)
+	})
+
+	t.Run("rejects duplicate names", func(t *testing.T) {
+		collection := godi.NewCollection()
+		collection.AddModules(
+			AddJob[failingJob]("job", Every(time.Hour)),
+			AddJob[panickingJob]("job", Every(time.Hour)),
+		)
+		provider, err := collection.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = provider.Close() })
+
+		_, err = NewScheduler(provider)
+		require.ErrorContains(t, err, `job "job" is registered more than once`)
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/junioryono/godi/cron/v5
+
+go 1.26.0
+
+require (
+	github.com/junioryono/godi/v5 v5.0.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/junioryono/godi/v5 => ../
@@ -0,0 +1,127 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// Curry is an AddOption for positional constructors that supplies their
+// trailing parameters from args instead of the container, the positional
+// counterpart of tagging a parameter-object field inject:"-". The leading
+// parameters are injected as usual. It saves writing an adapter closure for
+// constructors that mix services with plain values such as addresses and
+// timeouts.
+//
+// Example:
+//
+//	func NewServer(logger Logger, handler http.Handler, addr string, timeout time.Duration) *Server
+//
+//	services.AddSingleton(NewServer, godi.Curry(":8080", 30*time.Second))
+//
+// Each argument must be assignable to its parameter, and nil is accepted for
+// parameters of pointer, interface, slice, map, channel and function types.
+// The last argument of a variadic constructor supplies its variadic
+// parameter and must be a slice. Constructors taking a parameter object
+// cannot be curried.
+func Curry(args ...any) AddOption {
+	return curryOption{args: args}
+}
+
+type curryOption struct {
+	args []any
+}
+
+func (o curryOption) String() string {
+	args := make([]string, len(o.args))
+	for i, arg := range o.args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("Curry(%s)", strings.Join(args, ", "))
+}
+
+func (o curryOption) applyAddOption(opts *addOptions) {
+	opts.curried = o.args
+}
+
+// curryConstructor returns a constructor taking the leading parameters of
+// constructor and calling it with them followed by args.
+func curryConstructor(constructor any, args []any) (any, error) {
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() == reflect.Func && fn.IsNil() {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       ErrConstructorNil,
+		}
+	}
+	if fn.Kind() != reflect.Func {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Curry needs a constructor function, not %T", constructor),
+		}
+	}
+
+	fnType := fn.Type()
+	if len(args) > fnType.NumIn() {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Curry got %d arguments for a constructor with %d parameters", len(args), fnType.NumIn()),
+		}
+	}
+
+	injected := fnType.NumIn() - len(args)
+	in := make([]reflect.Type, injected)
+	for i := range fnType.NumIn() {
+		if reflection.HasEmbeddedIn(fnType.In(i)) {
+			return nil, &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.Curry cannot be used with parameter objects; tag their fields inject:\"-\" instead"),
+			}
+		}
+		if i < injected {
+			in[i] = fnType.In(i)
+		}
+	}
+
+	values := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		param := fnType.In(injected + i)
+		value := reflect.New(param).Elem()
+		switch {
+		case arg == nil:
+			switch param.Kind() {
+			case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+			default:
+				return nil, &ValidationError{
+					ServiceType: nil,
+					Cause:       fmt.Errorf("godi.Curry argument %d is nil, which cannot be assigned to parameter %d of type %s", i+1, injected+i+1, formatType(param)),
+				}
+			}
+		case reflect.TypeOf(arg).AssignableTo(param):
+			value.Set(reflect.ValueOf(arg))
+		default:
+			return nil, &ValidationError{
+				ServiceType: nil,
+				Cause: fmt.Errorf("godi.Curry argument %d has type %s, which cannot be assigned to parameter %d of type %s",
+					i+1, formatType(reflect.TypeOf(arg)), injected+i+1, formatType(param)),
+			}
+		}
+		values[i] = value
+	}
+
+	out := make([]reflect.Type, fnType.NumOut())
+	for i := range out {
+		out[i] = fnType.Out(i)
+	}
+
+	call := fn.Call
+	if fnType.IsVariadic() {
+		call = fn.CallSlice
+	}
+	curried := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(injected []reflect.Value) []reflect.Value {
+		return call(append(injected, values...))
+	})
+	return curried.Interface(), nil
+}
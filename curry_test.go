@@ -0,0 +1,114 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TServer struct {
+	Svc     *TService
+	Addr    string
+	Timeout time.Duration
+	Tags    []string
+}
+
+func NewTServer(svc *TService, addr string, timeout time.Duration) *TServer {
+	return &TServer{Svc: svc, Addr: addr, Timeout: timeout}
+}
+
+func TestCurry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("supplies_trailing_parameters", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("svc")),
+			AddSingleton(NewTServer, Curry(":8080", 30*time.Second)),
+		)
+
+		server := RequireResolve[*TServer](t, p)
+		assert.Same(t, RequireResolve[*TService](t, p), server.Svc)
+		assert.Equal(t, ":8080", server.Addr)
+		assert.Equal(t, 30*time.Second, server.Timeout)
+	})
+
+	t.Run("curried_parameters_are_not_dependencies", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			AddSingleton(NewTServer, Curry(":8080", time.Second)),
+		)
+
+		deps := p.(*provider).findDescriptor(PtrTypeOf[TServer](), nil).Dependencies
+		require.Len(t, deps, 1)
+		assert.Equal(t, PtrTypeOf[TService](), deps[0].Type)
+	})
+
+	t.Run("every_parameter_can_be_supplied", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddTransient(NewTServer, Curry(nil, "addr", time.Minute)))
+
+		server := RequireResolve[*TServer](t, p)
+		assert.Nil(t, server.Svc)
+		assert.Equal(t, "addr", server.Addr)
+	})
+
+	t.Run("variadic_parameters_take_a_slice", func(t *testing.T) {
+		t.Parallel()
+		newServer := func(svc *TService, tags ...string) *TServer {
+			return &TServer{Svc: svc, Tags: tags}
+		}
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			AddSingleton(newServer, Curry([]string{"a", "b"})),
+		)
+
+		assert.Equal(t, []string{"a", "b"}, RequireResolve[*TServer](t, p).Tags)
+	})
+
+	t.Run("errors_are_still_returned", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		newServer := func(svc *TService, addr string) (*TServer, error) {
+			return nil, boom
+		}
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			AddTransient(newServer, Curry(":8080")),
+		)
+
+		_, err := Resolve[*TServer](p)
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("rejects_invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Service *TService
+		}
+		for name, tc := range map[string]struct {
+			constructor any
+			args        []any
+			message     string
+		}{
+			"too_many":     {NewTServer, []any{1, 2, 3, 4}, "got 4 arguments for a constructor with 3 parameters"},
+			"wrong_type":   {NewTServer, []any{8080, time.Second}, "argument 1 has type int, which cannot be assigned to parameter 2 of type string"},
+			"nil_value":    {NewTServer, []any{":8080", nil}, "argument 2 is nil, which cannot be assigned to parameter 3 of type Duration"},
+			"not_a_func":   {&TService{}, []any{1}, "needs a constructor function"},
+			"param_object": {func(p params, addr string) *TServer { return nil }, []any{"x"}, "cannot be used with parameter objects"},
+		} {
+			c := NewCollection()
+			c.AddSingleton(tc.constructor, Curry(tc.args...))
+			_, err := c.Build()
+			var validation *ValidationError
+			if assert.True(t, errors.As(err, &validation), "%s: got %v", name, err) {
+				assert.Contains(t, err.Error(), tc.message, name)
+			}
+		}
+	})
+}
@@ -0,0 +1,341 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// ModuleDecorate creates a ModuleOption that wraps the services of the
+// enclosing module with decorator, leaving registrations made elsewhere
+// untouched. The decorator's first parameter is the service being
+// decorated and its result replaces it; any further parameters are
+// resolved from the container like constructor dependencies. A decorator
+// may also return an error, and may take the resolving scope's
+// context.Context and a DecoratorContext describing the registration
+// before the service, in that order:
+//
+//	func(inner T, deps...) T
+//	func(inner T, deps...) (T, error)
+//	func(ctx context.Context, inner T, deps...) (T, error)
+//	func(dc godi.DecoratorContext, inner T, deps...) T
+//
+// A failing decorator fails the resolution of the service with a
+// ResolutionError whose Cause is a *DecoratorError naming the decorator.
+//
+// It applies to every registration of T made in the module or its nested
+// modules, before or after the ModuleDecorate call, including keyed
+// services and group members. Decorators run once per constructed
+// instance, in the order they were registered, before the instance is
+// cached. ModuleDecorate must be used inside NewModule.
+//
+// Example:
+//
+//	var APIModule = godi.NewModule("api",
+//	    godi.AddScoped(NewUserHandler),
+//	    godi.ModuleDecorate(func(inner Handler, logger *Logger) Handler {
+//	        return &loggingHandler{next: inner, logger: logger}
+//	    }),
+//	)
+func ModuleDecorate(decorator any) ModuleOption {
+	return func(s Collection) error {
+		c, ok := s.(*collection)
+		if !ok {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.ModuleDecorate requires a collection created by godi.NewCollection"),
+			}
+		}
+		c.recordErr(c.addDecorator(decorator))
+		return nil
+	}
+}
+
+// DecoratorContext describes the registration a decorator is applied to.
+// A decorator receives it by taking a DecoratorContext parameter before the
+// service, so one decorator can tell the keyed services and group members
+// of a type apart, for example to label metrics with a cache's name:
+//
+//	godi.ModuleDecorate(func(dc godi.DecoratorContext, inner Cache, m *Metrics) Cache {
+//	    return &instrumentedCache{next: inner, hits: m.Counter("cache_hits", "cache", fmt.Sprint(dc.Key))}
+//	})
+type DecoratorContext struct {
+	// ServiceType is the decorated type.
+	ServiceType reflect.Type
+
+	// Key is the registration's key, or for a group member the name given
+	// with godi.Name. It is nil for unkeyed services and unnamed members.
+	Key any
+
+	// Group is the group the registration belongs to, if any.
+	Group string
+
+	// Lifetime is the registration's lifetime.
+	Lifetime Lifetime
+
+	// Module is the path of the module the service was registered in, such
+	// as "app/api".
+	Module string
+}
+
+// decoratorContextType is the reflect.Type of DecoratorContext.
+var decoratorContextType = reflect.TypeFor[DecoratorContext]()
+
+// decorator is a function registered with ModuleDecorate.
+type decorator struct {
+	// serviceType is the decorated type: the first parameter after an
+	// optional context.Context and DecoratorContext, and the first result.
+	serviceType reflect.Type
+
+	info *reflection.ConstructorInfo
+
+	// takesContext is set when the first parameter is a context.Context
+	// preceding the service.
+	takesContext bool
+
+	// takesDecoratorContext is set when a DecoratorContext precedes the
+	// service.
+	takesDecoratorContext bool
+
+	// dependencies are the parameters after the service, which are added to
+	// the decorated registrations so the graph validates them.
+	dependencies []*reflection.Dependency
+
+	// modules is the module stack the decorator was registered in.
+	modules []string
+}
+
+// addDecorator validates fn and attaches it to the matching registrations
+// of the module being applied, including those registered before it.
+func (c *collection) addDecorator(fn any) error {
+	if fn == nil {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("decorator cannot be nil"),
+		}
+	}
+
+	info, err := c.analyzer.Analyze(fn)
+	if err != nil {
+		return &ReflectionAnalysisError{
+			Constructor: fn,
+			Operation:   "analyze decorator",
+			Cause:       err,
+		}
+	}
+
+	fnType := info.Type
+	inner := 0
+	takesContext := false
+	takesDecoratorContext := false
+	if info.IsFunc && fnType.NumIn() > inner+1 && fnType.In(inner) == contextType {
+		takesContext = true
+		inner++
+	}
+	if info.IsFunc && fnType.NumIn() > inner+1 && fnType.In(inner) == decoratorContextType {
+		takesDecoratorContext = true
+		inner++
+	}
+	if !info.IsFunc || fnType.IsVariadic() || fnType.NumIn() == 0 || info.IsParamObject ||
+		(fnType.NumOut() != 1 && (fnType.NumOut() != 2 || !info.HasErrorReturn)) ||
+		fnType.Out(0) != fnType.In(inner) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause: fmt.Errorf("decorator must be a function of the form func(T, deps...) T or "+
+				"func(T, deps...) (T, error), optionally taking a context.Context and a godi.DecoratorContext first, got %s",
+				formatType(fnType)),
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.moduleStack) == 0 {
+		return &ValidationError{
+			ServiceType: fnType.In(inner),
+			Cause:       fmt.Errorf("godi.ModuleDecorate can only be used inside a module"),
+		}
+	}
+
+	dec := &decorator{
+		serviceType:           fnType.In(inner),
+		info:                  info,
+		takesContext:          takesContext,
+		takesDecoratorContext: takesDecoratorContext,
+		dependencies:          info.Dependencies()[inner+1:],
+		modules:               slices.Clone(c.moduleStack),
+	}
+	c.decorators = append(c.decorators, dec)
+
+	for _, d := range c.allDescriptors {
+		attachDecorator(dec, d)
+	}
+	return nil
+}
+
+// attachDecorator attaches dec to the registration d belongs to if it was
+// made inside dec's module and produces dec's type. All interfaces of a
+// godi.As registration share one instance, so the decorator applies to the
+// whole registration when it targets any of them. Caller must hold c.mu.
+func attachDecorator(dec *decorator, d *descriptor) {
+	if !withinModule(d.modules, dec.modules) {
+		return
+	}
+
+	registration := d.siblings
+	if len(registration) == 0 {
+		registration = []*descriptor{d}
+	}
+	if slices.ContainsFunc(registration, func(member *descriptor) bool {
+		return slices.Contains(member.decorators, dec)
+	}) {
+		return
+	}
+
+	var targets []*descriptor
+	for _, member := range registration {
+		if member.Type == dec.serviceType {
+			targets = append(targets, member)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	if d.isAlias {
+		targets = registration
+	}
+
+	for _, target := range targets {
+		target.decorators = append(slices.Clip(target.decorators), dec)
+	}
+	// Every member runs the same constructor, so each one depends on what
+	// the decorators need.
+	for _, member := range registration {
+		member.Dependencies = append(slices.Clip(member.Dependencies), dec.dependencies...)
+	}
+}
+
+// decorate passes instance through descriptor's decorators in order,
+// resolving their dependencies from s in a child frame of r. The
+// instance's Initialize method and the registration's ValidateWith checks
+// run first, on the undecorated instance.
+func (s *scope) decorate(r *resolution, requested instanceKey, descriptor *descriptor, instance any) (any, error) {
+	if err := s.initialize(r, requested, descriptor, instance); err != nil {
+		return nil, err
+	}
+	if len(descriptor.validators) > 0 {
+		if err := s.checkInstance(r, requested, descriptor, instance); err != nil {
+			return nil, err
+		}
+	}
+	if len(descriptor.decorators) == 0 {
+		return instance, nil
+	}
+
+	invoker := s.rootProvider.analyzer.GetInvoker()
+	value := reflect.ValueOf(instance)
+	for _, dec := range descriptor.decorators {
+		if !value.Type().AssignableTo(dec.serviceType) {
+			return nil, &TypeMismatchError{
+				Expected: dec.serviceType,
+				Actual:   value.Type(),
+				Context:  "decorator input",
+			}
+		}
+
+		leading := make([]reflect.Value, 0, 3)
+		if dec.takesContext {
+			leading = append(leading, reflect.ValueOf(s.resolvedContext()))
+		}
+		if dec.takesDecoratorContext {
+			leading = append(leading, reflect.ValueOf(newDecoratorContext(dec, descriptor)))
+		}
+		leading = append(leading, value)
+
+		frame := r.child(s, requested, descriptor)
+		results, err := invoker.InvokeWith(dec.info, frame, leading...)
+		if frame.cleanups != nil {
+			frame.cleanups.settle(err != nil)
+		}
+		frame.release()
+		if err == nil && isNilServiceResult(results[0]) {
+			err = fmt.Errorf("decorator returned nil")
+		}
+		if err != nil {
+			return nil, r.decoratorError(requested, descriptor, dec, err)
+		}
+		// Unwrap interface results so the next decorator, and every alias
+		// the instance is cached under, sees the dynamic type.
+		value = reflect.ValueOf(results[0].Interface())
+	}
+
+	for _, alias := range descriptor.siblings {
+		if alias.isAlias && !value.Type().AssignableTo(alias.Type) {
+			return nil, &TypeMismatchError{
+				Expected: alias.Type,
+				Actual:   value.Type(),
+				Context:  "decorated service",
+			}
+		}
+	}
+	return value.Interface(), nil
+}
+
+// newDecoratorContext describes the registration of descriptor to dec.
+func newDecoratorContext(dec *decorator, descriptor *descriptor) DecoratorContext {
+	// Group members are keyed by position and flagged registrations by a
+	// flagKey; neither is what the service was registered with.
+	key := descriptor.Key
+	if descriptor.Group != "" {
+		key = nil
+		if descriptor.memberName != "" {
+			key = descriptor.memberName
+		}
+	} else if flagged, ok := key.(flagKey); ok {
+		key = flagged.key
+	}
+
+	return DecoratorContext{
+		ServiceType: dec.serviceType,
+		Key:         key,
+		Group:       descriptor.Group,
+		Lifetime:    descriptor.Lifetime,
+		Module:      strings.Join(descriptor.modules, "/"),
+	}
+}
+
+// decoratorError reports the failure of dec while decorating descriptor's
+// service as a ResolutionError naming the decorator. Failures that already
+// carry a resolution path, such as a decorator dependency that could not be
+// resolved, keep the path to the step that failed.
+func (r *resolution) decoratorError(requested instanceKey, descriptor *descriptor, dec *decorator, err error) error {
+	if hasResolutionPath(err) {
+		return err
+	}
+
+	if panicErr, ok := errors.AsType[*reflection.PanicError](err); ok {
+		err = &ConstructorPanicError{
+			Constructor: dec.info.Type,
+			Panic:       panicErr.Panic,
+			Stack:       panicErr.Stack,
+		}
+	} else if returned, ok := errors.AsType[*reflection.ReturnError](err); ok {
+		err = returned.Err
+	}
+
+	return &ResolutionError{
+		ServiceType: requested.Type,
+		ServiceKey:  requested.Key,
+		Cause: &DecoratorError{
+			Decorator:   dec.info.Type,
+			ServiceType: dec.serviceType,
+			Module:      strings.Join(dec.modules, "/"),
+			Cause:       err,
+		},
+		Path: append(r.path(), newResolutionFrame(requested, descriptor)),
+	}
+}
@@ -0,0 +1,291 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decoratedID wraps a TInterface, appending suffix to its ID.
+type decoratedID struct {
+	inner  TInterface
+	suffix string
+}
+
+func (d *decoratedID) GetID() string { return d.inner.GetID() + d.suffix }
+
+func suffixDependency(suffix string) func(*TDependency) *TDependency {
+	return func(inner *TDependency) *TDependency {
+		return &TDependency{Name: inner.Name + suffix}
+	}
+}
+
+func TestModuleDecorate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies_only_within_module", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			NewModule("api",
+				AddSingleton(NewTDependencyWithName("api"), Name("api")),
+				ModuleDecorate(suffixDependency("+logged")),
+			),
+			AddSingleton(NewTDependencyWithName("root")),
+			NewModule("worker", AddSingleton(NewTDependencyWithName("worker"), Name("worker"))),
+		)
+
+		api, err := ResolveKeyed[*TDependency](p, "api")
+		require.NoError(t, err)
+		assert.Equal(t, "api+logged", api.Name)
+		assert.Equal(t, "root", RequireResolve[*TDependency](t, p).Name)
+		worker, err := ResolveKeyed[*TDependency](p, "worker")
+		require.NoError(t, err)
+		assert.Equal(t, "worker", worker.Name)
+
+		again, err := ResolveKeyed[*TDependency](p, "api")
+		require.NoError(t, err)
+		assert.Same(t, api, again, "the decorated instance is cached")
+	})
+
+	t.Run("runs_in_registration_order", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("api",
+			ModuleDecorate(suffixDependency("+a")),
+			AddSingleton(NewTDependency),
+			ModuleDecorate(suffixDependency("+b")),
+		))
+		assert.Equal(t, "dep+a+b", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("nested_modules", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("app",
+			NewModule("inner",
+				AddSingleton(NewTDependencyWithName("inner"), Name("inner")),
+				ModuleDecorate(suffixDependency("+inner")),
+			),
+			AddSingleton(NewTDependencyWithName("outer"), Name("outer")),
+			ModuleDecorate(suffixDependency("+app")),
+		))
+
+		inner, err := ResolveKeyed[*TDependency](p, "inner")
+		require.NoError(t, err)
+		assert.Equal(t, "inner+inner+app", inner.Name)
+		outer, err := ResolveKeyed[*TDependency](p, "outer")
+		require.NoError(t, err)
+		assert.Equal(t, "outer+app", outer.Name)
+	})
+
+	t.Run("resolves_decorator_dependencies", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			NewModule("api",
+				AddScoped(NewTService, As[TInterface]()),
+				ModuleDecorate(func(inner TInterface, svc *TService) TInterface {
+					return &decoratedID{inner: inner, suffix: "+" + svc.ID}
+				}),
+			),
+		)
+		s := NewTestScope(t, p)
+		assert.Equal(t, "test+test", RequireResolveFrom[TInterface](t, s).GetID())
+	})
+
+	t.Run("validates_decorator_dependencies", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(NewModule("api",
+			AddSingleton(NewTService),
+			ModuleDecorate(func(inner *TService, _ *TDependency) *TService { return inner }),
+		))
+		_, err := c.Build()
+		require.Error(t, err)
+
+		c = NewCollection()
+		c.AddModules(
+			AddScoped(NewTDependency),
+			NewModule("api",
+				AddSingleton(NewTService),
+				ModuleDecorate(func(inner *TService, _ *TDependency) *TService { return inner }),
+			),
+		)
+		_, err = c.Build()
+		_, ok := errors.AsType[*LifetimeConflictError](err)
+		assert.True(t, ok, "expected LifetimeConflictError, got %v", err)
+	})
+
+	t.Run("groups_and_multiple_returns", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("api",
+			AddSingleton(NewTDependencyWithName("a"), Group("deps")),
+			AddSingleton(NewTDependencyWithName("b"), Group("deps")),
+			AddSingleton(func() (*TService, *TDependency) {
+				return NewTService(), &TDependency{Name: "multi"}
+			}),
+			ModuleDecorate(suffixDependency("!")),
+		))
+
+		deps, err := ResolveGroup[*TDependency](p, "deps")
+		require.NoError(t, err)
+		require.Len(t, deps, 2)
+		assert.Equal(t, "a!", deps[0].Name)
+		assert.Equal(t, "b!", deps[1].Name)
+		assert.Equal(t, "multi!", RequireResolve[*TDependency](t, p).Name)
+		assert.Equal(t, "test", RequireResolve[*TService](t, p).ID)
+	})
+
+	t.Run("panicking_decorator", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("api",
+			AddScoped(NewTDependency),
+			ModuleDecorate(func(*TDependency) *TDependency { panic("boom") }),
+		))
+		_, err := Resolve[*TDependency](NewTestScope(t, p))
+		panicErr, ok := errors.AsType[*ConstructorPanicError](err)
+		require.True(t, ok, "expected ConstructorPanicError, got %v", err)
+		assert.Equal(t, "boom", panicErr.Panic)
+	})
+
+	t.Run("decorator_error", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		decorator := func(*TDependency) (*TDependency, error) { return nil, boom }
+		p := BuildProvider(t,
+			NewModule("api",
+				AddScoped(NewTDependency),
+				ModuleDecorate(decorator),
+			),
+			AddSingleton(NewTService),
+			AddScoped(NewTServiceWithDeps),
+		)
+
+		_, err := Resolve[*TServiceWithDeps](NewTestScope(t, p))
+		require.ErrorIs(t, err, boom)
+		decErr, ok := errors.AsType[*DecoratorError](err)
+		require.True(t, ok, "expected DecoratorError, got %v", err)
+		assert.Equal(t, reflect.TypeOf(decorator), decErr.Decorator)
+		assert.Equal(t, PtrTypeOf[TDependency](), decErr.ServiceType)
+		assert.Equal(t, "api", decErr.Module)
+
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		require.Len(t, resErr.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), resErr.Path[0].ServiceType)
+		assert.Equal(t, PtrTypeOf[TDependency](), resErr.Path[1].ServiceType)
+		assert.Contains(t, err.Error(), "decorator func(*godi.TDependency) (*godi.TDependency, error)")
+		assert.NotContains(t, err.Error(), "service not found")
+	})
+
+	t.Run("decorator_returning_error", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("api",
+			AddScoped(NewTDependency),
+			ModuleDecorate(func(inner *TDependency) (*TDependency, error) {
+				return &TDependency{Name: inner.Name + "+checked"}, nil
+			}),
+		))
+		assert.Equal(t, "dep+checked", RequireResolve[*TDependency](t, NewTestScope(t, p)).Name)
+	})
+
+	t.Run("decorator_taking_context", func(t *testing.T) {
+		t.Parallel()
+		type ctxKey struct{}
+		p := BuildProvider(t, NewModule("api",
+			AddScoped(NewTDependency),
+			ModuleDecorate(func(ctx context.Context, inner *TDependency, svc *TService) (*TDependency, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				return &TDependency{Name: inner.Name + "+" + ctx.Value(ctxKey{}).(string) + "+" + svc.ID}, nil
+			}),
+			AddSingleton(NewTService),
+		))
+
+		scope, err := p.CreateScope(context.WithValue(t.Context(), ctxKey{}, "req"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = scope.Close() })
+		assert.Equal(t, "dep+req+test", RequireResolve[*TDependency](t, scope).Name)
+
+		ctx, cancel := context.WithCancel(context.WithValue(t.Context(), ctxKey{}, "req"))
+		canceled, err := p.CreateScope(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = canceled.Close() })
+		cancel()
+		// Construction stops before the decorated constructor runs.
+		_, err = Resolve[*TDependency](canceled)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorAs(t, err, new(*ContextCancelledError))
+	})
+
+	t.Run("decorator_taking_decorator_context", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		seen := map[string]DecoratorContext{}
+		label := func(ctx context.Context, dc DecoratorContext, inner *TDependency) *TDependency {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[inner.Name] = dc
+			return &TDependency{Name: fmt.Sprintf("%s[%v]", inner.Name, dc.Key)}
+		}
+		p := BuildProvider(t, NewModule("app", NewModule("caches",
+			AddSingleton(NewTDependencyWithName("users"), Name("users")),
+			AddScoped(NewTDependencyWithName("plain")),
+			AddSingleton(NewTDependencyWithName("member"), Group("caches"), Name("sessions")),
+			AddSingleton(NewTDependencyWithName("unnamed"), Group("caches")),
+			ModuleDecorate(label),
+		)))
+
+		users, err := ResolveKeyed[*TDependency](p, "users")
+		require.NoError(t, err)
+		assert.Equal(t, "users[users]", users.Name)
+		assert.Equal(t, "plain[<nil>]", RequireResolve[*TDependency](t, NewTestScope(t, p)).Name)
+		members, err := ResolveGroup[*TDependency](p, "caches")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "member[sessions]", members[0].Name)
+		assert.Equal(t, "unnamed[<nil>]", members[1].Name)
+
+		assert.Equal(t, DecoratorContext{
+			ServiceType: PtrTypeOf[TDependency](),
+			Key:         "users",
+			Lifetime:    Singleton,
+			Module:      "app/caches",
+		}, seen["users"])
+		assert.Equal(t, Scoped, seen["plain"].Lifetime)
+		assert.Equal(t, "caches", seen["member"].Group)
+		assert.Nil(t, seen["unnamed"].Key)
+	})
+
+	t.Run("invalid_decorators", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name   string
+			module ModuleOption
+			want   string
+		}{
+			{"outside_module", ModuleDecorate(suffixDependency("")), "can only be used inside a module"},
+			{"nil", NewModule("m", ModuleDecorate(nil)), "decorator cannot be nil"},
+			{"not_a_function", NewModule("m", ModuleDecorate(&TDependency{})), "func(T, deps...) T"},
+			{"no_parameters", NewModule("m", ModuleDecorate(NewTDependency)), "func(T, deps...) T"},
+			{"different_result", NewModule("m", ModuleDecorate(func(*TDependency) *TService { return nil })), "func(T, deps...) T"},
+			{"second_result_not_error", NewModule("m", ModuleDecorate(func(*TDependency) (*TDependency, int) { return nil, 0 })), "func(T, deps...) (T, error)"},
+			{"context_only", NewModule("m", ModuleDecorate(func(context.Context, *TService) *TDependency { return nil })), "func(T, deps...) T"},
+			{"decorator_context_only", NewModule("m", ModuleDecorate(func(DecoratorContext, *TService) *TDependency { return nil })), "func(T, deps...) T"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddModules(tt.module)
+				require.Error(t, c.Err())
+				assert.Contains(t, c.Err().Error(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// Deprecated is an AddOption marking a registration as deprecated, with a
+// message telling its consumers what to use instead. The first resolution
+// of the service, direct or as a dependency, is reported to
+// ProviderOptions.OnDeprecatedResolution, so a migration between
+// implementations can be tracked down to its last consumers. Later
+// resolutions of the same registration are not reported again. Without the
+// callback, Deprecated has no effect.
+//
+// Example:
+//
+//	services.AddSingleton(NewClient, godi.Deprecated("use NewV2Client"))
+//
+//	provider, err := services.BuildWithOptions(&godi.ProviderOptions{
+//	    OnDeprecatedResolution: func(e godi.DeprecationEvent) {
+//	        logger.Warn("deprecated service resolved", "service", e.ServiceType, "message", e.Message, "path", e.Path)
+//	    },
+//	})
+func Deprecated(message string) AddOption {
+	return addDeprecatedOption(message)
+}
+
+type addDeprecatedOption string
+
+func (o addDeprecatedOption) String() string {
+	return fmt.Sprintf("Deprecated(%q)", string(o))
+}
+
+func (o addDeprecatedOption) applyAddOption(opt *addOptions) {
+	opt.deprecated = true
+	opt.deprecation = string(o)
+}
+
+// DeprecationEvent describes the first resolution of a service registered
+// with godi.Deprecated.
+type DeprecationEvent struct {
+	// ServiceType, ServiceKey and Group identify the resolved service.
+	ServiceType reflect.Type
+	ServiceKey  any
+	Group       string
+
+	// Message is the message given to godi.Deprecated.
+	Message string
+
+	// ScopeID is the ID of the scope the service was resolved in.
+	ScopeID string
+
+	// Path is the chain of services being constructed that led to the
+	// resolution, ending with the deprecated service. Its first frame names
+	// the consumer that still needs migrating.
+	Path []ResolutionFrame
+
+	// Stack holds the program counters of the resolving goroutine's call
+	// stack, innermost first, for deprecated services resolved directly
+	// rather than through a constructor.
+	Stack []uintptr
+}
+
+// warnDeprecated reports the first resolution of a deprecated service as a
+// dependency of r's constructor (r is nil for direct resolutions).
+func (s *scope) warnDeprecated(r *resolution, key instanceKey, descriptor *descriptor) {
+	onDeprecated := s.rootProvider.onDeprecated
+	if onDeprecated == nil {
+		return
+	}
+	if _, reported := s.rootProvider.deprecationsReported.LoadOrStore(descriptor, struct{}{}); reported {
+		return
+	}
+
+	frame := newResolutionFrame(key, descriptor)
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip runtime.Callers, warnDeprecated, and resolve
+
+	onDeprecated(DeprecationEvent{
+		ServiceType: frame.ServiceType,
+		ServiceKey:  frame.ServiceKey,
+		Group:       frame.Group,
+		Message:     descriptor.deprecation,
+		ScopeID:     s.id,
+		Path:        append(r.path(), frame),
+		Stack:       append([]uintptr(nil), pcs[:n]...),
+	})
+}
@@ -0,0 +1,80 @@
+package godi
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecated(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first_resolution_is_reported_once", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var events []DeprecationEvent
+		c := NewCollection()
+		c.AddScoped(NewTDependency, Deprecated("use NewV2Client"))
+		c.AddScoped(NewTService)
+		c.AddScoped(NewTServiceWithDeps)
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			OnDeprecatedResolution: func(e DeprecationEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+				events = append(events, e)
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		s := NewTestScope(t, p)
+		RequireResolveFrom[*TServiceWithDeps](t, s)
+		RequireResolveFrom[*TDependency](t, s)
+		RequireResolveFrom[*TDependency](t, NewTestScope(t, p))
+
+		require.Len(t, events, 1)
+		e := events[0]
+		assert.Equal(t, PtrTypeOf[TDependency](), e.ServiceType)
+		assert.Equal(t, "use NewV2Client", e.Message)
+		assert.Equal(t, s.ID(), e.ScopeID)
+		require.Len(t, e.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), e.Path[0].ServiceType)
+		assert.NotEmpty(t, e.Stack)
+	})
+
+	t.Run("keyed_registrations_are_reported_separately", func(t *testing.T) {
+		t.Parallel()
+		var keys []any
+		c := NewCollection()
+		c.AddSingleton(NewTDependencyWithName("a"), Name("a"), Deprecated("use b"))
+		c.AddSingleton(NewTDependencyWithName("b"), Name("b"))
+		c.AddSingleton(NewTDependencyWithName("c"), Name("c"), Deprecated("use b"))
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			OnDeprecatedResolution: func(e DeprecationEvent) { keys = append(keys, e.ServiceKey) },
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		for _, key := range []string{"a", "b", "c", "a"} {
+			_, err := ResolveKeyed[*TDependency](p, key)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []any{"a", "c"}, keys)
+	})
+
+	t.Run("without_callback_resolves_normally", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDependency, Deprecated("use NewV2Client")))
+		RequireResolve[*TDependency](t, p)
+	})
+
+	t.Run("requires_a_message", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTDependency, Deprecated(" "))
+		_, err := c.Build()
+		assert.ErrorContains(t, err, "godi.Deprecated requires a message")
+	})
+}
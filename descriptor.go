@@ -3,8 +3,12 @@ package godi
 import (
 	"fmt"
 	"reflect"
+	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/junioryono/godi/v5/internal/reflection"
 )
@@ -14,7 +18,8 @@ var voidKeyCounter atomic.Uint64
 
 // descriptor is the internal registration record for a service. It is not
 // exported: callers inspect registrations through the read-only ServiceInfo
-// view returned by Collection.ToSlice.
+// view returned by Collection.ToSlice, or through Descriptor values from a
+// GraphView.
 type descriptor struct {
 	// Type is the service type this descriptor produces
 	Type reflect.Type
@@ -74,6 +79,10 @@ type descriptor struct {
 	// collection.addService.
 	siblings []*descriptor
 
+	// memberName is the godi.Name of a group member, reported by
+	// ResolveGroupMap. Group members are keyed by their position instead.
+	memberName string
+
 	// isAlias marks descriptors registered through godi.As. Alias siblings
 	// advertise one produced value under several interface types and therefore
 	// share singleton/scoped construction and cache identity.
@@ -82,6 +91,94 @@ type descriptor struct {
 	// resultFieldIndex is the Out-struct field index this descriptor was
 	// created from. -1 when the descriptor is not a result-object field.
 	resultFieldIndex int
+
+	// optionalResult marks result-object fields tagged optional:"true". A
+	// nil field is not an error: the registration resolves as not found
+	// for that constructor invocation.
+	optionalResult bool
+
+	// cloner is set by godi.Clone: consumers receive copies of the cached
+	// instance.
+	cloner *clonePolicy
+
+	// unobserved is set at Build for registrations that
+	// ProviderOptions.CallbackFilter excludes from resolution callbacks.
+	unobserved bool
+
+	// keyedFallback is set by godi.KeyedFallback: this unkeyed registration
+	// serves keys of its type that have no registration of their own.
+	keyedFallback bool
+
+	// maxInstances is set by godi.MaxInstancesPerScope: the number of
+	// instances one scope may construct. Zero defers to the provider.
+	maxInstances int
+
+	// flag is set by godi.Flagged: this registration replaces the
+	// unflagged one of its type and key while the flag is on. Its Key is
+	// a flagKey.
+	flag string
+
+	// flagged are set at Build on an unflagged registration: the
+	// registrations that replace it while their flag is on.
+	flagged []*descriptor
+
+	// bundle is set by godi.Bundle: Build replaces the registered
+	// lifetime with the shortest lifetime among the dependencies.
+	bundle bool
+
+	// profiles are the build profiles a result-object field tagged
+	// profile:"..." is registered in. Empty means every profile.
+	profiles []string
+
+	// modules is the stack of module names (outermost first) that were being
+	// applied when this descriptor was registered. Empty for registrations
+	// made directly on the collection.
+	modules []string
+
+	// refresh is set for AddRefreshing registrations.
+	refresh *refreshPolicy
+
+	// exportName is the name given with godi.ExportName, if any.
+	exportName string
+
+	// memoize is set for transients registered with godi.Memoize.
+	memoize *memoizePolicy
+
+	// private marks registrations made with godi.Private, usable only by
+	// services of the module that registered them.
+	private bool
+
+	// decorators are the ModuleDecorate decorators applied to instances of
+	// this registration, in order.
+	decorators []*decorator
+
+	// auditReason is the reason given with godi.Audited; resolutions of
+	// audited services are reported to ProviderOptions.OnAuditedResolution.
+	auditReason string
+
+	// deprecation is the message given with godi.Deprecated; the first
+	// resolution is reported to ProviderOptions.OnDeprecatedResolution.
+	deprecation string
+
+	// excludeFromSnapshot leaves the singleton out of TakeSnapshot
+	// (see godi.ExcludeFromSnapshot).
+	excludeFromSnapshot bool
+
+	// fieldFallbacks are the WithFieldFallback constructors for
+	// unregistered dependencies, by service type.
+	fieldFallbacks map[reflect.Type]*fieldFallback
+
+	// disposeWith releases instances in place of their Close method; set
+	// by godi.DisposeWith.
+	disposeWith *disposeWithOption
+
+	// validators are the godi.ValidateWith checks run on constructed
+	// instances before they are decorated and cached.
+	validators []validateWithOption
+
+	// circuitBreaker is set by godi.CircuitBreaker; each provider keeps
+	// the circuit's state.
+	circuitBreaker *circuitBreakerOption
 }
 
 // newDescriptor creates a new descriptor from a service with the given lifetime and options
@@ -111,6 +208,14 @@ func newDescriptorWithAnalyzer(service any, lifetime Lifetime, analyzer *reflect
 		return nil, err
 	}
 
+	if len(options.curried) > 0 {
+		curried, err := curryConstructor(service, options.curried)
+		if err != nil {
+			return nil, err
+		}
+		service = curried
+	}
+
 	// Get constructor value and type
 	constructorValue := reflect.ValueOf(service)
 
@@ -192,9 +297,70 @@ func newDescriptorWithAnalyzer(service any, lifetime Lifetime, analyzer *reflect
 	}
 
 	// Apply options
-	if options.Name != "" {
+	if options.Name != "" && options.Group != "" {
+		descriptor.memberName = options.Name
+	} else if options.Name != "" {
 		descriptor.Key = options.Name
 	}
+	descriptor.exportName = options.ExportName
+	descriptor.private = options.private
+	descriptor.auditReason = options.auditReason
+	descriptor.deprecation = options.deprecation
+	descriptor.excludeFromSnapshot = options.excludeFromSnapshot
+	descriptor.keyedFallback = options.keyedFallback
+	descriptor.bundle = options.bundle
+	descriptor.flag = options.flag
+	if options.hasMaxInstances && lifetime == Singleton {
+		return nil, &ValidationError{
+			ServiceType: descriptor.Type,
+			Cause:       fmt.Errorf("godi.MaxInstancesPerScope cannot be used with AddSingleton: singletons are not constructed by scopes"),
+		}
+	}
+	descriptor.maxInstances = options.maxInstances
+	descriptor.circuitBreaker = options.circuitBreaker
+	if len(options.fieldFallbacks) > 0 {
+		fallbacks, err := newFieldFallbacks(descriptor, options.fieldFallbacks, analyzer)
+		if err != nil {
+			return nil, err
+		}
+		descriptor.fieldFallbacks = fallbacks
+	}
+	if options.disposeWith != nil {
+		if err := validateDisposeWith(descriptor, info, options.disposeWith); err != nil {
+			return nil, err
+		}
+		descriptor.disposeWith = options.disposeWith
+	}
+	for _, opt := range options.validators {
+		if err := validateValidateWith(descriptor, info, opt); err != nil {
+			return nil, err
+		}
+	}
+	descriptor.validators = options.validators
+	if options.memoize != nil {
+		if lifetime != Transient {
+			return nil, &ValidationError{
+				ServiceType: descriptor.Type,
+				Cause:       fmt.Errorf("godi.Memoize can only be used with AddTransient, not %s", lifetime),
+			}
+		}
+		descriptor.memoize = options.memoize
+	}
+	descriptor.cloner = options.clone
+	if options.refreshing {
+		descriptor.refresh = &refreshPolicy{every: options.RefreshEvery}
+	}
+	if options.unloadable {
+		if lifetime != Singleton {
+			return nil, &ValidationError{
+				ServiceType: descriptor.Type,
+				Cause:       fmt.Errorf("godi.Unloadable can only be used with AddSingleton, not %s", lifetime),
+			}
+		}
+		// Unloadable singletons are shared generations without a schedule,
+		// leased by each scope that resolves them.
+		descriptor.refresh = &refreshPolicy{}
+	}
 
 	// Cache analysis results for performance
 	descriptor.isFunc = info.IsFunc
@@ -223,11 +389,13 @@ func newDescriptorWithAnalyzer(service any, lifetime Lifetime, analyzer *reflect
 		for _, ret := range info.Returns {
 			if !ret.IsError {
 				descriptor.resultFields = append(descriptor.resultFields, reflection.ResultField{
-					Name:  ret.Name,
-					Type:  ret.Type,
-					Key:   ret.Key,
-					Group: ret.Group,
-					Index: ret.Index,
+					Name:     ret.Name,
+					Type:     ret.Type,
+					Key:      ret.Key,
+					Group:    ret.Group,
+					Index:    ret.Index,
+					Optional: ret.Optional,
+					Profiles: ret.Profiles,
 				})
 			}
 		}
@@ -260,6 +428,172 @@ func (d *descriptor) siblingForField(index int) *descriptor {
 	return nil
 }
 
+// serviceInfo returns the public identity of the registration.
+func (d *descriptor) serviceInfo() ServiceInfo {
+	return ServiceInfo{
+		ServiceType: d.Type,
+		Key:         d.Key,
+		Group:       d.Group,
+		Lifetime:    d.Lifetime,
+	}
+}
+
+// Descriptor is the public, read-only description of a registration,
+// returned by GraphView.Descriptors. Unlike the internal record it is
+// detached from the collection, safe to keep and compare, and has a stable
+// textual identity, so linters, visualizers, and diff tools can be built on
+// it.
+type Descriptor struct {
+	ServiceInfo
+
+	// Constructor identifies the constructor by its fully qualified function
+	// name, e.g. "example.com/app/db.NewPool", or "instance of T" for
+	// registered values. Function literals are named after their enclosing
+	// function with a numeric suffix, which shifts when literals are added
+	// before them.
+	Constructor string
+
+	// ConstructorLocation is the file:line where the constructor is
+	// defined, or "" for instances and when the location is unknown.
+	ConstructorLocation string
+
+	// Modules are the names of the modules the service was registered in,
+	// outermost first.
+	Modules []string
+
+	// Private reports whether the service was registered with godi.Private.
+	Private bool
+}
+
+// ID returns a textual identity for the registration that is stable across
+// processes and builds: the fully qualified service type, followed by
+// "[key]" for keyed services or "{group}@constructor" for group members,
+// whose constructor is the only thing telling them apart. Two registrations
+// of the same collection never share an ID unless they are group members
+// registered with the same constructor.
+//
+// Example IDs:
+//
+//	*example.com/app/db.Pool
+//	*example.com/app/db.Pool["replica"]
+//	example.com/app/http.Handler{routes}@example.com/app/users.NewHandler
+func (d Descriptor) ID() string {
+	id := qualifiedTypeName(d.ServiceType)
+	switch {
+	case d.Group != "":
+		id += "{" + d.Group + "}@" + d.Constructor
+	case d.Key != nil:
+		id += fmt.Sprintf("[%#v]", d.Key)
+	}
+	return id
+}
+
+// Equal reports whether d and other describe the same registration with the
+// same lifetime, constructor, modules, and visibility.
+func (d Descriptor) Equal(other Descriptor) bool {
+	return d.ServiceInfo == other.ServiceInfo &&
+		d.Constructor == other.Constructor &&
+		d.ConstructorLocation == other.ConstructorLocation &&
+		d.Private == other.Private &&
+		slices.Equal(d.Modules, other.Modules)
+}
+
+// String returns the descriptor's ID.
+func (d Descriptor) String() string {
+	return d.ID()
+}
+
+// public returns the exported view of the registration.
+func (d *descriptor) public() Descriptor {
+	public := Descriptor{
+		ServiceInfo: d.serviceInfo(),
+		Constructor: constructorName(d),
+		Modules:     slices.Clone(d.modules),
+		Private:     d.private,
+	}
+	if !d.IsInstance {
+		public.ConstructorLocation = functionLocation(d.Constructor)
+	}
+	return public
+}
+
+// constructorName identifies d's constructor for Descriptor.Constructor.
+func constructorName(d *descriptor) string {
+	if d.IsInstance || !d.Constructor.IsValid() || d.Constructor.Kind() != reflect.Func {
+		return "instance of " + formatType(d.ConstructorType)
+	}
+	if f := runtime.FuncForPC(d.Constructor.Pointer()); f != nil {
+		return f.Name()
+	}
+	return formatType(d.ConstructorType)
+}
+
+// qualifiedTypeName is like t.String but names packages by their import
+// path, which unlike the package name is unique.
+func qualifiedTypeName(t reflect.Type) string {
+	if t == nil {
+		return "<nil>"
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + qualifiedTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + qualifiedTypeName(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + qualifiedTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + qualifiedTypeName(t.Key()) + "]" + qualifiedTypeName(t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + qualifiedTypeName(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + qualifiedTypeName(t.Elem())
+		}
+		return "chan " + qualifiedTypeName(t.Elem())
+	default:
+		return t.String()
+	}
+}
+
+// source describes where the descriptor came from for diagnostics: the
+// constructor and its definition site, plus the registering module if any.
+func (d *descriptor) source() string {
+	var b strings.Builder
+	if d.IsInstance {
+		fmt.Fprintf(&b, "instance of %s", formatType(d.ConstructorType))
+	} else {
+		fmt.Fprintf(&b, "constructor %s", formatType(d.ConstructorType))
+		if location := functionLocation(d.Constructor); location != "" {
+			fmt.Fprintf(&b, " at %s", location)
+		}
+	}
+	if len(d.modules) > 0 {
+		fmt.Fprintf(&b, " (module %q)", strings.Join(d.modules, "/"))
+	}
+	return b.String()
+}
+
+// functionLocation returns the file:line where fn is defined, or "" when fn
+// is not a function value or its location is unknown.
+func functionLocation(fn reflect.Value) string {
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return ""
+	}
+	file, line := f.FileLine(f.Entry())
+	return file + ":" + strconv.Itoa(line)
+}
+
 // GetType returns the service type this descriptor produces.
 // This method implements the Provider interface from the graph package,
 // enabling the descriptor to participate in dependency resolution.
@@ -322,7 +656,7 @@ func (d *descriptor) Validate() error {
 
 	// Validate lifetime
 	switch d.Lifetime {
-	case Singleton, Scoped, Transient:
+	case Singleton, Scoped, Transient, PerResolution:
 		// Valid lifetimes
 	default:
 		return &LifetimeError{Value: d.Lifetime}
@@ -337,12 +671,41 @@ func (d *descriptor) Validate() error {
 			Cause:       fmt.Errorf("instance values can only be registered with singleton lifetime; use a constructor for %s", d.Lifetime),
 		}
 	}
+	if d.refresh != nil && (d.IsInstance || d.VoidReturn || d.isResultObject || d.serviceOutputs() > 1) {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("refreshing and unloadable services need a constructor returning a single service value"),
+		}
+	}
+	if d.memoize != nil && (d.IsInstance || d.VoidReturn || d.isResultObject || d.serviceOutputs() > 1) {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("memoized services need a constructor returning a single service value"),
+		}
+	}
+	if d.cloner != nil {
+		if err := validateClone(d); err != nil {
+			return err
+		}
+	}
+	if d.exportName != "" && (d.VoidReturn || d.isResultObject || d.serviceOutputs() > 1) {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("godi.ExportName(%q) needs a constructor returning a single service value", d.exportName),
+		}
+	}
 	if d.VoidReturn && d.Lifetime == Transient {
 		return &ValidationError{
 			ServiceType: d.Type,
 			Cause:       fmt.Errorf("transient constructors must return a service value"),
 		}
 	}
+	if d.VoidReturn && d.Lifetime == PerResolution {
+		return &ValidationError{
+			ServiceType: d.Type,
+			Cause:       fmt.Errorf("per-resolution constructors must return a service value"),
+		}
+	}
 	if d.isFunc && d.ConstructorType.IsVariadic() {
 		return &ValidationError{
 			ServiceType: d.Type,
@@ -497,3 +860,22 @@ func (d *descriptor) validateParameterTypes() error {
 
 	return nil
 }
+
+// refreshPolicy configures an AddRefreshing registration.
+type refreshPolicy struct {
+	every time.Duration // zero: refreshed only by Invalidate
+}
+
+// serviceOutputs counts the constructor's non-error return values.
+func (d *descriptor) serviceOutputs() int {
+	if d.ConstructorType == nil || d.ConstructorType.Kind() != reflect.Func {
+		return 0
+	}
+	n := 0
+	for i := range d.ConstructorType.NumOut() {
+		if !d.ConstructorType.Out(i).Implements(reflect.TypeFor[error]()) {
+			n++
+		}
+	}
+	return n
+}
@@ -152,9 +152,11 @@ func TestDescriptor(t *testing.T) {
 
 		t.Run("name_and_group", func(t *testing.T) {
 			t.Parallel()
-			_, err := newDescriptor(NewTService, Singleton, Name("n"), Group("g"))
-			require.Error(t, err)
-			assert.Contains(t, err.Error(), "cannot use both")
+			d, err := newDescriptor(NewTService, Singleton, Name("n"), Group("g"))
+			require.NoError(t, err)
+			assert.Nil(t, d.Key)
+			assert.Equal(t, "g", d.Group)
+			assert.Equal(t, "n", d.memberName)
 		})
 
 		t.Run("backtick_in_name", func(t *testing.T) {
@@ -345,3 +347,59 @@ func TestDescriptor(t *testing.T) {
 		})
 	})
 }
+
+func TestPublicDescriptor(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollection()
+	c.AddModules(NewModule("app",
+		AddSingleton(NewTService, Private()),
+		AddScoped(NewTDependency, Name("replica")),
+		AddSingleton(NewTService, Group("all")),
+		AddSingleton(&TDisposable{}),
+	))
+	descriptors := c.Graph().Descriptors()
+	require.Len(t, descriptors, 4)
+
+	t.Run("fields", func(t *testing.T) {
+		t.Parallel()
+		d := descriptors[0]
+		assert.Equal(t, ServiceInfo{ServiceType: PtrTypeOf[TService](), Lifetime: Singleton}, d.ServiceInfo)
+		assert.Equal(t, "github.com/junioryono/godi/v5.NewTService", d.Constructor)
+		assert.Contains(t, d.ConstructorLocation, "testutil_test.go:")
+		assert.Equal(t, []string{"app"}, d.Modules)
+		assert.True(t, d.Private)
+
+		instance := descriptors[3]
+		assert.Equal(t, "instance of *TDisposable", instance.Constructor)
+		assert.Empty(t, instance.ConstructorLocation)
+	})
+
+	t.Run("id", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "*github.com/junioryono/godi/v5.TService", descriptors[0].ID())
+		assert.Equal(t, `*github.com/junioryono/godi/v5.TDependency["replica"]`, descriptors[1].ID())
+		assert.Equal(t, "*github.com/junioryono/godi/v5.TService{all}@github.com/junioryono/godi/v5.NewTService", descriptors[2].ID())
+		assert.Equal(t, descriptors[1].ID(), descriptors[1].String())
+
+		assert.Equal(t, "map[string][]*github.com/junioryono/godi/v5.TService",
+			qualifiedTypeName(reflect.TypeFor[map[string][]*TService]()))
+		assert.Equal(t, "<-chan [2]int", qualifiedTypeName(reflect.TypeFor[<-chan [2]int]()))
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		t.Parallel()
+		again := c.Graph().Descriptors()
+		for i := range descriptors {
+			assert.True(t, descriptors[i].Equal(again[i]))
+		}
+		assert.False(t, descriptors[0].Equal(descriptors[2]))
+
+		moved := descriptors[1]
+		moved.Modules = []string{"app", "nested"}
+		assert.False(t, descriptors[1].Equal(moved))
+
+		again[0].Modules[0] = "changed"
+		assert.Equal(t, []string{"app"}, c.Graph().Descriptors()[0].Modules, "descriptors are detached from the collection")
+	})
+}
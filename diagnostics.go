@@ -0,0 +1,106 @@
+package godi
+
+import (
+	"errors"
+	"slices"
+)
+
+// Diagnostics are runtime counters of a provider, as reported by
+// DiagnosticsOf. Counters start at zero when the provider is built, include
+// the work done by Build itself, and are shared by all of its scopes.
+type Diagnostics struct {
+	// Constructions is the number of constructor invocations, including
+	// failed ones. Registered instances are never constructed.
+	Constructions uint64
+
+	// CacheHits is the number of resolutions of Singleton, Scoped, and
+	// memoized Transient services served from a cache instead of running
+	// the constructor.
+	CacheHits uint64
+
+	// OptionalFailures is the number of optional fields left nil because
+	// their service failed to construct (see
+	// ProviderOptions.OnOptionalFailure).
+	OptionalFailures uint64
+
+	// AnalysisCacheSize is the number of constructor and function analyses
+	// cached. The cache belongs to the collection the provider was built
+	// from, so it and the counters below include registration and are
+	// shared by every provider built from that collection.
+	AnalysisCacheSize int
+
+	// AnalysisCacheHits is the number of functions whose analysis was
+	// served from the cache, e.g. by Invoke after WarmAnalysis.
+	AnalysisCacheHits uint64
+
+	// AnalysisCacheMisses is the number of functions analyzed from scratch.
+	AnalysisCacheMisses uint64
+}
+
+// DiagnosticsOf returns the counters of the provider p was built as, or that
+// the scope p belongs to. Together with ProviderOptions.OnServiceConstructed
+// they tell first-time construction apart from cache hits, e.g. to spot a
+// Scoped service rebuilt on every request:
+//
+//	d, err := godi.DiagnosticsOf(provider)
+//	if err == nil {
+//	    log.Printf("constructed %d, cached %d", d.Constructions, d.CacheHits)
+//	}
+func DiagnosticsOf(p Provider) (Diagnostics, error) {
+	root, err := providerOf(p)
+	if err != nil {
+		return Diagnostics{}, err
+	}
+	hits, misses := root.analyzer.CacheStats()
+	return Diagnostics{
+		Constructions:       root.constructions.Load(),
+		CacheHits:           root.cacheHits.Load(),
+		OptionalFailures:    root.optionalFailures.Load(),
+		AnalysisCacheSize:   root.analyzer.CacheSize(),
+		AnalysisCacheHits:   hits,
+		AnalysisCacheMisses: misses,
+	}, nil
+}
+
+// OptionalFailuresOf returns the optional fields left nil while resolving
+// in the scope p, or in a provider's root scope, oldest first (see
+// ProviderOptions.OnOptionalFailure). Where DiagnosticsOf counts the
+// failures of the whole provider, this tells which request ran with a
+// missing dependency, e.g. to log it when the request's scope closes:
+//
+//	if failures, _ := godi.OptionalFailuresOf(scope); len(failures) > 0 {
+//	    log.Printf("request %s degraded: %v", scope.ID(), failures)
+//	}
+func OptionalFailuresOf(p Provider) ([]*OptionalFailureError, error) {
+	s, err := scopeOf(p)
+	if err != nil {
+		return nil, err
+	}
+	s.optionalFailuresMu.Lock()
+	defer s.optionalFailuresMu.Unlock()
+	return slices.Clone(s.optionalFailures), nil
+}
+
+// WarmAnalysis analyzes functions that will be passed to Invoke or
+// InvokeEach, so their first calls don't pay for reflection. Registered
+// constructors need no warming: they are analyzed when added. The cache is
+// keyed by function value, so closures created anew for each call are not
+// helped. It returns the analysis errors of the functions godi cannot call.
+//
+//	if err := godi.WarmAnalysis(provider, handleOrder, handleRefund); err != nil {
+//	    return err
+//	}
+func WarmAnalysis(p Provider, fns ...any) error {
+	root, err := providerOf(p)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, fn := range fns {
+		if _, err := root.analyzer.Analyze(fn); err != nil {
+			errs = append(errs, &ReflectionAnalysisError{Constructor: fn, Operation: "analyze", Cause: err})
+		}
+	}
+	return errors.Join(errs...)
+}
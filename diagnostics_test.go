@@ -0,0 +1,104 @@
+package godi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnServiceConstructed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu          sync.Mutex
+		constructed []ServiceInfo
+	)
+	c := NewCollection()
+	c.AddSingleton(NewTDependency)
+	c.AddTransient(NewTService)
+	c.AddScoped(NewTServiceWithDeps)
+	c.AddSingleton(&TDisposable{})
+	p, err := c.BuildWithOptions(&ProviderOptions{
+		OnServiceConstructed: func(service ServiceInfo, duration time.Duration) {
+			assert.GreaterOrEqual(t, duration, time.Duration(0))
+			mu.Lock()
+			constructed = append(constructed, service)
+			mu.Unlock()
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	require.Equal(t, []ServiceInfo{{ServiceType: PtrTypeOf[TDependency](), Lifetime: Singleton}}, constructed,
+		"singletons are constructed once by Build; instances are not constructed")
+
+	s := NewTestScope(t, p)
+	RequireResolveFrom[*TDependency](t, s)
+	RequireResolveFrom[*TServiceWithDeps](t, s)
+	RequireResolveFrom[*TServiceWithDeps](t, s)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []ServiceInfo{
+		{ServiceType: PtrTypeOf[TDependency](), Lifetime: Singleton},
+		{ServiceType: PtrTypeOf[TService](), Lifetime: Transient},
+		{ServiceType: PtrTypeOf[TServiceWithDeps](), Lifetime: Scoped},
+	}, constructed, "dependencies finish constructing first")
+}
+
+func TestDiagnosticsOf(t *testing.T) {
+	t.Parallel()
+
+	p := BuildProvider(t, AddSingleton(NewTDependency), AddTransient(NewTService), AddScoped(NewTServiceWithDeps))
+	before, err := DiagnosticsOf(p)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), before.Constructions)
+	assert.Zero(t, before.CacheHits)
+
+	s := NewTestScope(t, p)
+	RequireResolveFrom[*TServiceWithDeps](t, s)       // two constructions, singleton hit
+	RequireResolveFrom[*TServiceWithDeps](t, s)       // scoped hit
+	_, err = ResolveMany(s, PtrTypeOf[TDependency]()) // singleton hit
+	require.NoError(t, err)
+
+	after, err := DiagnosticsOf(s)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), after.Constructions)
+	assert.Equal(t, uint64(3), after.CacheHits)
+	assert.Equal(t, before.AnalysisCacheSize, after.AnalysisCacheSize, "registrations are analyzed when added")
+
+	_, err = DiagnosticsOf(nil)
+	require.ErrorIs(t, err, ErrProviderNil)
+}
+
+func TestWarmAnalysis(t *testing.T) {
+	t.Parallel()
+
+	p := BuildProvider(t, AddSingleton(NewTDependency), AddTransient(NewTService))
+	handle := func(*TService, *TDependency) {}
+
+	before, err := DiagnosticsOf(p)
+	require.NoError(t, err)
+	assert.Equal(t, 2, before.AnalysisCacheSize)
+	assert.Equal(t, uint64(2), before.AnalysisCacheMisses)
+
+	require.NoError(t, WarmAnalysis(p, handle))
+	require.NoError(t, Invoke(p, handle))
+	require.NoError(t, Invoke(p, handle))
+
+	after, err := DiagnosticsOf(p)
+	require.NoError(t, err)
+	assert.Equal(t, 3, after.AnalysisCacheSize)
+	assert.Equal(t, before.AnalysisCacheMisses+1, after.AnalysisCacheMisses, "only the warm-up analyzes")
+	assert.Equal(t, before.AnalysisCacheHits+2, after.AnalysisCacheHits)
+
+	err = WarmAnalysis(p, nil, handle)
+	_, ok := errors.AsType[*ReflectionAnalysisError](err)
+	assert.True(t, ok)
+
+	require.ErrorIs(t, WarmAnalysis(nil), ErrProviderNil)
+}
@@ -0,0 +1,175 @@
+package godi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegistrationChange is a registration present in both collections compared
+// by DiffCollections whose lifetime or constructor differs.
+type RegistrationChange struct {
+	Before Descriptor
+	After  Descriptor
+}
+
+// LifetimeChanged reports whether the registration's lifetime differs.
+func (c RegistrationChange) LifetimeChanged() bool {
+	return c.Before.Lifetime != c.After.Lifetime
+}
+
+// ConstructorChanged reports whether the registration's constructor differs.
+func (c RegistrationChange) ConstructorChanged() bool {
+	return c.Before.Constructor != c.After.Constructor
+}
+
+// CollectionDiff lists the differences between two collections, as reported
+// by DiffCollections.
+type CollectionDiff struct {
+	// Added are the registrations only the second collection has, in its
+	// registration order.
+	Added []Descriptor
+
+	// Removed are the registrations only the first collection has, in its
+	// registration order.
+	Removed []Descriptor
+
+	// Changed are the registrations whose lifetime or constructor differs,
+	// in the second collection's registration order.
+	Changed []RegistrationChange
+}
+
+// Empty reports whether the collections have the same registrations.
+func (d CollectionDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// LifetimeChanges returns the changes that alter a registration's lifetime,
+// which usually deserve review: a singleton that becomes scoped, for
+// example, is no longer shared between requests.
+func (d CollectionDiff) LifetimeChanges() []RegistrationChange {
+	var changes []RegistrationChange
+	for _, change := range d.Changed {
+		if change.LifetimeChanged() {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// String formats the diff for review, one registration per line: "+" for
+// added, "-" for removed, and "~" for changed registrations.
+func (d CollectionDiff) String() string {
+	var b strings.Builder
+	for _, r := range d.Added {
+		fmt.Fprintf(&b, "+ %s (%s) from %s\n", describeRegistration(r.ServiceInfo), r.Lifetime, r.Constructor)
+	}
+	for _, r := range d.Removed {
+		fmt.Fprintf(&b, "- %s (%s) from %s\n", describeRegistration(r.ServiceInfo), r.Lifetime, r.Constructor)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s:", describeRegistration(c.After.ServiceInfo))
+		if c.LifetimeChanged() {
+			fmt.Fprintf(&b, " lifetime %s -> %s", c.Before.Lifetime, c.After.Lifetime)
+		}
+		if c.ConstructorChanged() {
+			if c.LifetimeChanged() {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, " constructor %s -> %s", c.Before.Constructor, c.After.Constructor)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func describeRegistration(info ServiceInfo) string {
+	switch {
+	case info.Group != "":
+		return fmt.Sprintf("%s in group %q", formatType(info.ServiceType), info.Group)
+	case info.Key != nil:
+		return fmt.Sprintf("%s[%v]", formatType(info.ServiceType), info.Key)
+	default:
+		return formatType(info.ServiceType)
+	}
+}
+
+// DiffCollections compares the registrations of two collections, such as
+// the wiring of two releases or of two deployment configurations, so that
+// tooling can review DI changes and fail CI on unexpected ones.
+//
+// Registrations are matched by Descriptor.ID: service type and key, or for
+// group members, whose constructor is part of their ID, group and
+// constructor. A group member whose constructor changes therefore shows up
+// as removed and added. Only lifetime and constructor changes are reported;
+// a constructor moving within its file is not a change.
+//
+// Example:
+//
+//	diff := godi.DiffCollections(current, candidate)
+//	if changes := diff.LifetimeChanges(); len(changes) > 0 {
+//	    log.Fatalf("unexpected lifetime changes:\n%s", diff)
+//	}
+func DiffCollections(a, b Collection) CollectionDiff {
+	before := registrationsOf(a)
+	after := registrationsOf(b)
+
+	beforeByID := make(map[registrationID]Descriptor, len(before))
+	for _, r := range before {
+		beforeByID[r.id] = r.info
+	}
+	afterIDs := make(map[registrationID]struct{}, len(after))
+
+	var diff CollectionDiff
+	for _, r := range after {
+		afterIDs[r.id] = struct{}{}
+		previous, ok := beforeByID[r.id]
+		if !ok {
+			diff.Added = append(diff.Added, r.info)
+			continue
+		}
+		if previous.Lifetime != r.info.Lifetime || previous.Constructor != r.info.Constructor {
+			diff.Changed = append(diff.Changed, RegistrationChange{Before: previous, After: r.info})
+		}
+	}
+	for _, r := range before {
+		if _, ok := afterIDs[r.id]; !ok {
+			diff.Removed = append(diff.Removed, r.info)
+		}
+	}
+	return diff
+}
+
+// registrationID identifies a registration across collections: its
+// Descriptor.ID, numbered when group members share a constructor.
+type registrationID struct {
+	id         string
+	occurrence int
+}
+
+type registrationRecord struct {
+	id   registrationID
+	info Descriptor
+}
+
+// registrationsOf lists c's registrations in registration order.
+func registrationsOf(c Collection) []registrationRecord {
+	if c == nil {
+		return nil
+	}
+	if c, ok := c.(*collection); ok && c == nil {
+		return nil
+	}
+
+	descriptors := c.Graph().Descriptors()
+	records := make([]registrationRecord, len(descriptors))
+	occurrences := make(map[string]int)
+	for i, d := range descriptors {
+		id := registrationID{id: d.ID()}
+		if d.Group != "" {
+			id.occurrence = occurrences[id.id]
+			occurrences[id.id]++
+		}
+		records[i] = registrationRecord{id: id, info: d}
+	}
+	return records
+}
@@ -0,0 +1,95 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffDependency() *TDependency { return &TDependency{Name: "v2"} }
+
+func TestDiffCollections(t *testing.T) {
+	t.Parallel()
+
+	collectionOf := func(modules ...ModuleOption) Collection {
+		c := NewCollection()
+		c.AddModules(modules...)
+		return c
+	}
+
+	t.Run("identical_collections", func(t *testing.T) {
+		t.Parallel()
+		a := collectionOf(AddSingleton(NewTService), AddScoped(NewTDependency, Name("dep")), AddSingleton(NewTService, Group("all")))
+		b := collectionOf(AddSingleton(NewTService), AddScoped(NewTDependency, Name("dep")), AddSingleton(NewTService, Group("all")))
+		diff := DiffCollections(a, b)
+		assert.True(t, diff.Empty())
+		assert.Empty(t, diff.String())
+	})
+
+	t.Run("added_removed_and_changed", func(t *testing.T) {
+		t.Parallel()
+		a := collectionOf(
+			AddSingleton(NewTService),
+			AddSingleton(NewTDependency),
+			AddSingleton(NewTDisposable, Name("legacy")),
+		)
+		b := collectionOf(
+			AddScoped(NewTService),
+			AddSingleton(newDiffDependency),
+			AddTransient(NewTServiceWithDeps),
+		)
+
+		diff := DiffCollections(a, b)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), diff.Added[0].ServiceType)
+		assert.Equal(t, Transient, diff.Added[0].Lifetime)
+		assert.Equal(t, "github.com/junioryono/godi/v5.NewTServiceWithDeps", diff.Added[0].Constructor)
+
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, "legacy", diff.Removed[0].Key)
+
+		require.Len(t, diff.Changed, 2)
+		assert.True(t, diff.Changed[0].LifetimeChanged())
+		assert.False(t, diff.Changed[0].ConstructorChanged())
+		assert.False(t, diff.Changed[1].LifetimeChanged())
+		assert.True(t, diff.Changed[1].ConstructorChanged())
+
+		require.Len(t, diff.LifetimeChanges(), 1)
+		assert.Equal(t, PtrTypeOf[TService](), diff.LifetimeChanges()[0].After.ServiceType)
+
+		report := diff.String()
+		assert.Contains(t, report, "+ *TServiceWithDeps (Transient) from github.com/junioryono/godi/v5.NewTServiceWithDeps\n")
+		assert.Contains(t, report, "- *TDisposable[legacy] (Singleton)")
+		assert.Contains(t, report, "~ *TService: lifetime Singleton -> Scoped\n")
+		assert.Contains(t, report, "~ *TDependency: constructor github.com/junioryono/godi/v5.NewTDependency -> github.com/junioryono/godi/v5.newDiffDependency\n")
+	})
+
+	t.Run("group_members_match_by_constructor", func(t *testing.T) {
+		t.Parallel()
+		a := collectionOf(
+			AddSingleton(NewTService, Group("all")),
+			AddSingleton(NewTService, Group("all")),
+		)
+		b := collectionOf(
+			AddSingleton(NewTServiceWithDeps, Group("others")),
+			AddScoped(NewTService, Group("all")),
+		)
+
+		diff := DiffCollections(a, b)
+		require.Len(t, diff.Added, 1, "only the new group is added")
+		assert.Equal(t, "others", diff.Added[0].Group)
+		require.Len(t, diff.Removed, 1, "one of the two identical members was removed")
+		require.Len(t, diff.Changed, 1)
+		assert.Contains(t, diff.String(), "~ *TService in group \"all\": lifetime Singleton -> Scoped\n")
+	})
+
+	t.Run("instances_and_nil_collections", func(t *testing.T) {
+		t.Parallel()
+		b := collectionOf(AddSingleton(&TService{}))
+		diff := DiffCollections(nil, b)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, "instance of *TService", diff.Added[0].Constructor)
+		assert.True(t, DiffCollections(nil, nil).Empty())
+	})
+}
@@ -0,0 +1,103 @@
+package godi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// disposalMethodNames are the method names that suggest a type holds a
+// resource the container should release.
+var disposalMethodNames = []string{"Close", "CloseContext", "Shutdown", "Stop", "Dispose"}
+
+var (
+	disposableType            = reflect.TypeFor[Disposable]()
+	disposableWithContextType = reflect.TypeFor[DisposableWithContext]()
+)
+
+// disposalMismatches reports the registered types that look disposable but
+// implement neither Disposable nor DisposableWithContext, and have no
+// DisposeWith function, so the container never releases them. Each type is
+// reported once, for its first registration.
+func disposalMismatches(descriptors []*descriptor) []*DisposalMethodError {
+	var (
+		mismatches []*DisposalMethodError
+		seen       = make(map[reflect.Type]struct{})
+	)
+	for _, d := range descriptors {
+		if d == nil || d.VoidReturn || d.Type == nil {
+			continue
+		}
+		if d.disposeWith != nil && d.Type.AssignableTo(d.disposeWith.serviceType) {
+			continue // disposed with godi.DisposeWith
+		}
+		if _, ok := seen[d.Type]; ok {
+			continue
+		}
+		seen[d.Type] = struct{}{}
+		if method := mismatchedDisposalMethod(d.Type); method != "" {
+			mismatches = append(mismatches, &DisposalMethodError{
+				ServiceType: d.Type,
+				Method:      method,
+				Source:      d.source(),
+			})
+		}
+	}
+	return mismatches
+}
+
+// mismatchedDisposalMethod returns the signature of the first disposal-like
+// method of t that the container will not call, or "" if there is none.
+func mismatchedDisposalMethod(t reflect.Type) string {
+	if isDisposableType(t) {
+		return ""
+	}
+	if t.Kind() != reflect.Interface && t.Kind() != reflect.Pointer && isDisposableType(reflect.PointerTo(t)) {
+		// Registered by value, so the pointer method set is unreachable.
+		for _, name := range disposalMethodNames {
+			if m, ok := reflect.PointerTo(t).MethodByName(name); ok {
+				return methodSignature(m, true) + " (pointer receiver)"
+			}
+		}
+	}
+	for _, name := range disposalMethodNames {
+		if m, ok := t.MethodByName(name); ok {
+			return methodSignature(m, t.Kind() != reflect.Interface)
+		}
+	}
+	return ""
+}
+
+func isDisposableType(t reflect.Type) bool {
+	return t.Implements(disposableType) || t.Implements(disposableWithContextType)
+}
+
+// methodSignature renders m as "Name(params) results". hasReceiver reports
+// whether m.Type takes the receiver as its first parameter.
+func methodSignature(m reflect.Method, hasReceiver bool) string {
+	var b strings.Builder
+	b.WriteString(m.Name)
+	b.WriteByte('(')
+	first := 0
+	if hasReceiver {
+		first = 1
+	}
+	for i := first; i < m.Type.NumIn(); i++ {
+		if i > first {
+			b.WriteString(", ")
+		}
+		b.WriteString(m.Type.In(i).String())
+	}
+	b.WriteByte(')')
+	switch m.Type.NumOut() {
+	case 0:
+	case 1:
+		b.WriteString(" " + m.Type.Out(0).String())
+	default:
+		outs := make([]string, m.Type.NumOut())
+		for i := range outs {
+			outs[i] = m.Type.Out(i).String()
+		}
+		b.WriteString(" (" + strings.Join(outs, ", ") + ")")
+	}
+	return b.String()
+}
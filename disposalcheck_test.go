@@ -0,0 +1,108 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeWithoutError struct{}
+
+func (*closeWithoutError) Close() {}
+
+type shutdownOnly struct{}
+
+func (*shutdownOnly) Shutdown(context.Context) error { return nil }
+
+type valueWithPointerClose struct{}
+
+func (*valueWithPointerClose) Close() error { return nil }
+
+type properlyDisposable struct{}
+
+func (*properlyDisposable) Close() error { return nil }
+
+// Shutdown is not a problem: Close is called.
+func (*properlyDisposable) Shutdown(context.Context) error { return nil }
+
+type stoppable interface{ Stop() }
+
+type stopOnly struct{}
+
+func (stopOnly) Stop() {}
+
+func TestDisposalMismatches(t *testing.T) {
+	t.Parallel()
+
+	collect := func(t *testing.T, opts ...ModuleOption) []*DisposalMethodError {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(opts...)
+		require.NoError(t, c.Err())
+
+		var got []*DisposalMethodError
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			OnDisposalMismatch: func(err *DisposalMethodError) { got = append(got, err) },
+		})
+		require.NoError(t, err, "mismatches are warnings by default")
+		t.Cleanup(func() { _ = p.Close() })
+		return got
+	}
+
+	t.Run("reports_methods_that_are_not_called", func(t *testing.T) {
+		t.Parallel()
+		got := collect(t,
+			AddSingleton(func() *closeWithoutError { return &closeWithoutError{} }),
+			AddScoped(func() *shutdownOnly { return &shutdownOnly{} }),
+			AddValue(valueWithPointerClose{}),
+			AddSingleton(func() stoppable { return stopOnly{} }),
+		)
+
+		require.Len(t, got, 4)
+		assert.Equal(t, PtrTypeOf[closeWithoutError](), got[0].ServiceType)
+		assert.Equal(t, "Close()", got[0].Method)
+		assert.Equal(t, "Shutdown(context.Context) error", got[1].Method)
+		assert.Equal(t, "Close() error (pointer receiver)", got[2].Method)
+		assert.Equal(t, "Stop()", got[3].Method)
+		assert.Contains(t, got[0].Error(), "*closeWithoutError has Close(), which godi does not call on disposal")
+		assert.NotEmpty(t, got[0].Source)
+	})
+
+	t.Run("ignores_disposable_types", func(t *testing.T) {
+		t.Parallel()
+		got := collect(t,
+			AddSingleton(func() *properlyDisposable { return &properlyDisposable{} }),
+			AddScoped(func() *TDisposable { return &TDisposable{} }),
+			AddTransient(NewTService),
+		)
+		assert.Empty(t, got)
+	})
+
+	t.Run("reports_each_type_once", func(t *testing.T) {
+		t.Parallel()
+		got := collect(t,
+			AddSingleton(func() *closeWithoutError { return &closeWithoutError{} }, Name("a")),
+			AddSingleton(func() *closeWithoutError { return &closeWithoutError{} }, Name("b")),
+		)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("strict_mode_fails_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() *closeWithoutError { return &closeWithoutError{} })
+		c.AddScoped(func() *shutdownOnly { return &shutdownOnly{} })
+
+		_, err := c.BuildWithOptions(&ProviderOptions{StrictDisposal: true})
+		require.Error(t, err)
+		_, ok := errors.AsType[*BuildError](err)
+		assert.True(t, ok)
+		mismatch, ok := errors.AsType[*DisposalMethodError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[closeWithoutError](), mismatch.ServiceType)
+		assert.Contains(t, err.Error(), "*shutdownOnly has Shutdown(context.Context) error")
+	})
+}
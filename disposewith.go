@@ -0,0 +1,104 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// DisposeWith is an AddOption that releases the registration's instances
+// of type T by calling dispose, for third-party types whose cleanup method
+// is not Close() error, e.g. Flush(), Stop() or Shutdown(ctx). dispose runs
+// wherever a Disposable would be closed: when the owning scope or provider
+// closes, with context.Background(), or during StopAll, with its context.
+// It replaces the instance's own Close method, if it has one.
+//
+// Example:
+//
+//	services.AddSingleton(NewGRPCServer, godi.DisposeWith(func(_ context.Context, s *grpc.Server) error {
+//	    s.GracefulStop()
+//	    return nil
+//	}))
+func DisposeWith[T any](dispose func(ctx context.Context, instance T) error) AddOption {
+	o := disposeWithOption{serviceType: reflect.TypeFor[T]()}
+	if dispose != nil {
+		o.dispose = func(ctx context.Context, instance any) error {
+			return dispose(ctx, instance.(T))
+		}
+	}
+	return o
+}
+
+type disposeWithOption struct {
+	serviceType reflect.Type
+	dispose     func(ctx context.Context, instance any) error // instance is a T
+}
+
+func (o disposeWithOption) String() string {
+	return fmt.Sprintf("DisposeWith[%s]", formatType(o.serviceType))
+}
+
+func (o disposeWithOption) applyAddOption(opts *addOptions) {
+	opts.disposeWith = &o
+}
+
+// validateDisposeWith checks that d produces a value of the type a
+// DisposeWith option disposes.
+func validateDisposeWith(d *descriptor, info *reflection.ConstructorInfo, opt *disposeWithOption) error {
+	if opt.dispose == nil {
+		return &ValidationError{ServiceType: d.Type, Cause: fmt.Errorf("%s: dispose function cannot be nil", opt)}
+	}
+	if d.IsInstance {
+		if d.Type.AssignableTo(opt.serviceType) {
+			return nil
+		}
+	} else {
+		for _, ret := range info.Returns {
+			if !ret.IsError && ret.Type.AssignableTo(opt.serviceType) {
+				return nil
+			}
+		}
+	}
+	return &ValidationError{
+		ServiceType: d.Type,
+		Cause:       fmt.Errorf("%s: registration produces no value of type %s", opt, formatType(opt.serviceType)),
+	}
+}
+
+// disposableFor returns how instance, produced by descriptor, is released:
+// through the descriptor's DisposeWith function if the instance is of its
+// type, or else its Close method if it is Disposable.
+func disposableFor(descriptor *descriptor, instance any) (Disposable, bool) {
+	if cleanup, ok := instance.(*cleanupFunc); ok {
+		return cleanup, true
+	}
+	if descriptor != nil && descriptor.disposeWith != nil && instance != nil &&
+		reflect.TypeOf(instance).AssignableTo(descriptor.disposeWith.serviceType) {
+		return &customDisposable{instance: instance, dispose: descriptor.disposeWith.dispose}, true
+	}
+	d, ok := instance.(Disposable)
+	return d, ok
+}
+
+// customDisposable adapts an instance and its DisposeWith function to
+// DisposableWithContext.
+type customDisposable struct {
+	instance any
+	dispose  func(ctx context.Context, instance any) error
+}
+
+func (d *customDisposable) Close() error {
+	return d.dispose(context.Background(), d.instance)
+}
+
+func (d *customDisposable) CloseContext(ctx context.Context) error {
+	return d.dispose(ctx, d.instance)
+}
+
+// disposalIdentity identifies the instance rather than the adapter, so an
+// instance shared by sibling registrations is disposed once.
+func (d *customDisposable) disposalIdentity() any {
+	return d.instance
+}
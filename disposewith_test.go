@@ -0,0 +1,164 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flusher is a third-party-style resource whose cleanup is not Close.
+type flusher struct {
+	flushes atomic.Int32
+	closes  atomic.Int32
+}
+
+func (f *flusher) Flush() { f.flushes.Add(1) }
+
+func (f *flusher) Close() error {
+	f.closes.Add(1)
+	return nil
+}
+
+type flushable interface{ Flush() }
+
+func flushWith(_ context.Context, f *flusher) error {
+	f.Flush()
+	return nil
+}
+
+func TestDisposeWith(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disposes_singletons_with_the_provider", func(t *testing.T) {
+		t.Parallel()
+		f := &flusher{}
+		c := NewCollection()
+		c.AddSingleton(func() *flusher { return f }, DisposeWith(flushWith))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		assert.Equal(t, int32(1), f.flushes.Load())
+		assert.Zero(t, f.closes.Load(), "DisposeWith replaces Close")
+	})
+
+	t.Run("disposes_scoped_and_transient_instances_with_the_scope", func(t *testing.T) {
+		t.Parallel()
+		var created []*flusher
+		p := BuildProvider(t,
+			AddScoped(func() *flusher { f := &flusher{}; created = append(created, f); return f }, DisposeWith(flushWith)),
+			AddTransient(func() flushable { f := &flusher{}; created = append(created, f); return f },
+				DisposeWith(func(_ context.Context, f flushable) error { f.Flush(); return nil })),
+		)
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		RequireResolve[*flusher](t, s)
+		RequireResolve[flushable](t, s)
+		RequireResolve[flushable](t, s)
+
+		require.NoError(t, s.Close())
+		require.Len(t, created, 3)
+		for _, f := range created {
+			assert.Equal(t, int32(1), f.flushes.Load())
+		}
+	})
+
+	t.Run("aliases_dispose_once", func(t *testing.T) {
+		t.Parallel()
+		f := &flusher{}
+		c := NewCollection()
+		c.AddSingleton(func() *flusher { return f }, As[flushable](), As[Disposable](), DisposeWith(flushWith))
+		p, err := c.Build()
+		require.NoError(t, err)
+		_, err = Resolve[flushable](p)
+		require.NoError(t, err)
+		_, err = Resolve[Disposable](p)
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		assert.Equal(t, int32(1), f.flushes.Load())
+	})
+
+	t.Run("disposes_retired_generations", func(t *testing.T) {
+		t.Parallel()
+		var created []*flusher
+		newFlusher := func() *flusher { f := &flusher{}; created = append(created, f); return f }
+		c := NewCollection()
+		c.AddSingleton(newFlusher, Unloadable(), DisposeWith(flushWith))
+		c.AddModules(AddRefreshing(func() flushable { return newFlusher() },
+			DisposeWith(func(_ context.Context, f flushable) error { f.Flush(); return nil })))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		s := NewTestScope(t, p)
+		RequireResolveFrom[*flusher](t, s)
+		RequireResolveFrom[flushable](t, s)
+		require.NoError(t, s.Close())
+		require.NoError(t, Unload[*flusher](p))
+		require.NoError(t, Invalidate[flushable](p))
+		require.Len(t, created, 2)
+		for _, f := range created {
+			assert.Equal(t, int32(1), f.flushes.Load())
+		}
+
+		RequireResolveFrom[*flusher](t, NewTestScope(t, p))
+		require.NoError(t, p.Close())
+		require.Len(t, created, 3)
+		assert.Equal(t, int32(1), created[2].flushes.Load())
+		for _, f := range created {
+			assert.Zero(t, f.closes.Load(), "DisposeWith replaces Close")
+		}
+	})
+
+	t.Run("stop_all_passes_its_context", func(t *testing.T) {
+		t.Parallel()
+		type ctxKey struct{}
+		var got any
+		c := NewCollection()
+		c.AddSingleton(func() *flusher { return &flusher{} }, DisposeWith(func(ctx context.Context, _ *flusher) error {
+			got = ctx.Value(ctxKey{})
+			return nil
+		}))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		require.NoError(t, StopAll(context.WithValue(context.Background(), ctxKey{}, "shutdown"), p))
+		assert.Equal(t, "shutdown", got)
+	})
+
+	t.Run("errors_are_reported_on_close", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("flush failed")
+		c := NewCollection()
+		c.AddSingleton(func() *flusher { return &flusher{} }, DisposeWith(func(context.Context, *flusher) error { return boom }))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		require.ErrorIs(t, p.Close(), boom)
+	})
+
+	t.Run("suppresses_disposal_mismatch_reports", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() *closeWithoutError { return &closeWithoutError{} },
+			DisposeWith(func(_ context.Context, c *closeWithoutError) error { c.Close(); return nil }))
+		p, err := c.BuildWithOptions(&ProviderOptions{StrictDisposal: true})
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, DisposeWith(flushWith))
+		require.ErrorContains(t, c.Err(), "registration produces no value of type *flusher")
+
+		c = NewCollection()
+		c.AddSingleton(func() *flusher { return &flusher{} }, DisposeWith[*flusher](nil))
+		require.ErrorContains(t, c.Err(), "dispose function cannot be nil")
+	})
+}
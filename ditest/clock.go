@@ -0,0 +1,121 @@
+package ditest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// Clock is a godi.Clock whose time only moves when Advance is called, for
+// deterministic tests of refreshing services and scope close timeouts.
+// Install it with godi.ProviderOptions.Clock:
+//
+//	clock := ditest.NewClock(time.Now())
+//	provider, _ := services.BuildWithOptions(&godi.ProviderOptions{Clock: clock})
+//
+//	first := godi.MustResolve[*Rates](scope)
+//	clock.Advance(time.Hour) // the refresh interval elapses
+//
+// Unlike the system clock, timers run their functions on the goroutine that
+// calls Advance, before it returns. Clock is safe for concurrent use.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*clockTimer
+}
+
+var _ godi.Clock = (*Clock)(nil)
+
+// NewClock returns a Clock set to start.
+func NewClock(start time.Time) *Clock {
+	c := &Clock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules f to run once Advance moves the clock d or more past
+// the current time. A non-positive d runs f on the next Advance.
+func (c *Clock) AfterFunc(d time.Duration, f func()) godi.ClockTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &clockTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, running the functions of the
+// timers that come due in the order of their deadlines. While a timer's
+// function runs, Now returns the timer's deadline.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		next := -1
+		for i, t := range c.timers {
+			if !t.when.After(target) && (next < 0 || t.when.Before(c.timers[next].when)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		t := c.timers[next]
+		c.timers = append(c.timers[:next], c.timers[next+1:]...)
+		if t.when.After(c.now) {
+			c.now = t.when
+		}
+		c.mu.Unlock()
+		t.f()
+		c.mu.Lock()
+	}
+	c.now = target
+	c.mu.Unlock()
+}
+
+// Pending returns the number of timers that have neither fired nor been
+// stopped.
+func (c *Clock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are pending. Use it before
+// Advance when the timer is started by another goroutine, such as a scope
+// Close waiting for its ScopeWaitGroup.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+type clockTimer struct {
+	clock *Clock
+	when  time.Time
+	f     func()
+}
+
+func (t *clockTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
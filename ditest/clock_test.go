@@ -0,0 +1,107 @@
+package ditest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rates struct{ generation int64 }
+
+func buildWithClock(t *testing.T, clock *Clock, options *godi.ProviderOptions, modules ...godi.ModuleOption) godi.Provider {
+	t.Helper()
+	services := godi.NewCollection()
+	services.AddModules(modules...)
+	options.Clock = clock
+	provider, err := services.BuildWithOptions(options)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+	return provider
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fires_timers_in_deadline_order", func(t *testing.T) {
+		clock := NewClock(start)
+		var fired []string
+		clock.AfterFunc(2*time.Second, func() { fired = append(fired, "second") })
+		clock.AfterFunc(time.Second, func() { fired = append(fired, "first at "+clock.Now().Sub(start).String()) })
+		stopped := clock.AfterFunc(time.Second, func() { fired = append(fired, "stopped") })
+		assert.True(t, stopped.Stop())
+		assert.False(t, stopped.Stop())
+
+		clock.Advance(1500 * time.Millisecond)
+		assert.Equal(t, []string{"first at 1s"}, fired)
+		assert.Equal(t, start.Add(1500*time.Millisecond), clock.Now())
+		assert.Equal(t, 1, clock.Pending())
+
+		clock.Advance(time.Second)
+		assert.Equal(t, []string{"first at 1s", "second"}, fired)
+		assert.Zero(t, clock.Pending())
+	})
+
+	t.Run("drives_refresh_intervals", func(t *testing.T) {
+		clock := NewClock(start)
+		var builds atomic.Int64
+		provider := buildWithClock(t, clock, &godi.ProviderOptions{},
+			godi.AddRefreshing(func() *rates { return &rates{generation: builds.Add(1)} }, godi.RefreshEvery(time.Hour)),
+		)
+
+		resolve := func() *rates {
+			scope, err := provider.CreateScope(context.Background())
+			require.NoError(t, err)
+			defer scope.Close()
+			return godi.MustResolve[*rates](scope)
+		}
+
+		assert.Equal(t, int64(1), resolve().generation)
+		clock.Advance(59 * time.Minute)
+		assert.Equal(t, int64(1), resolve().generation)
+		clock.Advance(time.Minute)
+		assert.Equal(t, int64(2), resolve().generation)
+	})
+
+	t.Run("drives_scope_wait_timeouts", func(t *testing.T) {
+		clock := NewClock(start)
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		provider := buildWithClock(t, clock, &godi.ProviderOptions{ScopeWaitTimeout: time.Minute},
+			godi.AddScoped(func(wg *godi.ScopeWaitGroup) *rates {
+				wg.Go(func() { <-release })
+				return &rates{}
+			}),
+		)
+
+		scope, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+		godi.MustResolve[*rates](scope)
+
+		closed := make(chan error, 1)
+		go func() { closed <- scope.Close() }()
+		clock.BlockUntil(1)
+		select {
+		case <-closed:
+			t.Fatal("Close returned before the timeout elapsed")
+		default:
+		}
+		clock.Advance(time.Minute)
+		assert.ErrorIs(t, <-closed, context.DeadlineExceeded)
+	})
+
+	t.Run("timestamps_scopes", func(t *testing.T) {
+		clock := NewClock(start)
+		provider := buildWithClock(t, clock, &godi.ProviderOptions{})
+		clock.Advance(time.Minute)
+
+		scope, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+		defer scope.Close()
+		assert.Equal(t, start.Add(time.Minute), godi.MustResolve[godi.ScopeInfo](scope).Created)
+	})
+}
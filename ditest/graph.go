@@ -0,0 +1,155 @@
+// Package ditest provides test helpers for checking how a godi provider is
+// wired.
+//
+// The assertions read the dependency graph and never construct services.
+// They accept the wiring to inspect as a godi.Collection, a godi.Provider or
+// godi.Scope, or a godi.GraphView. Building a provider creates its
+// singletons, so wiring regression tests that must run without databases or
+// other live dependencies should pass the collection instead:
+//
+//	func TestWiring(t *testing.T) {
+//	    services := godi.NewCollection()
+//	    services.AddModules(app.Module)
+//
+//	    ditest.AssertDependency[*UserHandler, *UserService](t, services)
+//	    ditest.AssertLifetime[*UserRepository](t, services, godi.Scoped)
+//	}
+//
+// Like testify's assert package, every assertion reports a failure through
+// t.Errorf and returns whether it passed.
+package ditest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// AssertDependency asserts that the constructor of T's unkeyed registration
+// declares a dependency on U, either as a parameter or as a field of a
+// godi.In struct. A group dependency counts as a dependency on the group's
+// element type.
+//
+// Example:
+//
+//	ditest.AssertDependency[*UserHandler, *UserService](t, services)
+func AssertDependency[T, U any](t testing.TB, wiring any) bool {
+	t.Helper()
+	return assertDependency(t, wiring, reflect.TypeFor[T](), reflect.TypeFor[U](), true)
+}
+
+// AssertNoDependency asserts that the constructor of T's unkeyed
+// registration does not declare a dependency on U.
+//
+// Example:
+//
+//	ditest.AssertNoDependency[*UserHandler, *sql.DB](t, services)
+func AssertNoDependency[T, U any](t testing.TB, wiring any) bool {
+	t.Helper()
+	return assertDependency(t, wiring, reflect.TypeFor[T](), reflect.TypeFor[U](), false)
+}
+
+// AssertLifetime asserts that T's unkeyed registration has the given
+// lifetime.
+//
+// Example:
+//
+//	ditest.AssertLifetime[*UserRepository](t, services, godi.Scoped)
+func AssertLifetime[T any](t testing.TB, wiring any, want godi.Lifetime) bool {
+	t.Helper()
+	serviceType := reflect.TypeFor[T]()
+	graph, service, ok := registration(t, wiring, serviceType)
+	if !ok {
+		return false
+	}
+	if service.Lifetime != want {
+		t.Errorf("ditest: %v is %v, want %v\n\tregistered by %s",
+			serviceType, service.Lifetime, want, graph.Source(service))
+		return false
+	}
+	return true
+}
+
+func assertDependency(t testing.TB, wiring any, serviceType, dependencyType reflect.Type, want bool) bool {
+	t.Helper()
+	graph, service, ok := registration(t, wiring, serviceType)
+	if !ok {
+		return false
+	}
+
+	deps := graph.Dependencies(service)
+	found := false
+	for _, dep := range deps {
+		if dep.ServiceType == dependencyType {
+			found = true
+			break
+		}
+	}
+	if found == want {
+		return true
+	}
+
+	if want {
+		t.Errorf("ditest: %v does not depend on %v\n\tdependencies: %s\n\tregistered by %s",
+			serviceType, dependencyType, describeDependencies(deps), graph.Source(service))
+	} else {
+		t.Errorf("ditest: %v depends on %v\n\tregistered by %s",
+			serviceType, dependencyType, graph.Source(service))
+	}
+	return false
+}
+
+// graphOf returns the dependency graph of wiring.
+func graphOf(wiring any) (godi.GraphView, error) {
+	switch w := wiring.(type) {
+	case godi.GraphView:
+		return w, nil
+	case godi.Collection:
+		return w.Graph(), nil
+	case godi.Provider:
+		return godi.GraphOf(w)
+	case nil:
+		return nil, fmt.Errorf("wiring is nil")
+	default:
+		return nil, fmt.Errorf("%T is not a godi.Collection, godi.Provider, or godi.GraphView", wiring)
+	}
+}
+
+// registration finds the unkeyed registration of serviceType in wiring,
+// reporting a failure if there is none.
+func registration(t testing.TB, wiring any, serviceType reflect.Type) (godi.GraphView, godi.ServiceInfo, bool) {
+	t.Helper()
+	graph, err := graphOf(wiring)
+	if err != nil {
+		t.Errorf("ditest: cannot read the dependency graph: %v", err)
+		return nil, godi.ServiceInfo{}, false
+	}
+	for _, service := range graph.Services() {
+		if service.ServiceType == serviceType && service.Key == nil && service.Group == "" {
+			return graph, service, true
+		}
+	}
+	t.Errorf("ditest: %v is not registered", serviceType)
+	return nil, godi.ServiceInfo{}, false
+}
+
+func describeDependencies(deps []godi.DependencyInfo) string {
+	if len(deps) == 0 {
+		return "none"
+	}
+	names := make([]string, len(deps))
+	for i, dep := range deps {
+		switch {
+		case dep.Group != "":
+			names[i] = fmt.Sprintf("[]%v (group %q)", dep.ServiceType, dep.Group)
+		case dep.Key != nil:
+			names[i] = fmt.Sprintf("%v (name %v)", dep.ServiceType, dep.Key)
+		default:
+			names[i] = dep.ServiceType.String()
+		}
+	}
+	return strings.Join(names, ", ")
+}
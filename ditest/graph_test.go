@@ -0,0 +1,107 @@
+package ditest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	database   struct{}
+	repository struct{ db *database }
+	service    struct{ repo *repository }
+	plugin     struct{}
+	handler    struct{}
+)
+
+// recorder captures assertion failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func wiring() godi.Collection {
+	services := godi.NewCollection()
+	services.AddModules(godi.NewModule("app",
+		godi.AddSingleton(func() *database { panic("assertions must not construct services") }),
+		godi.AddScoped(func(db *database) *repository { return &repository{db: db} }),
+		godi.AddScoped(func(repo *repository) *service { return &service{repo: repo} }),
+		godi.AddSingleton(func() *plugin { return &plugin{} }, godi.Group("plugins")),
+		godi.AddTransient(func(params struct {
+			godi.In
+			Service *service
+			Plugins []*plugin `group:"plugins"`
+		}) *handler {
+			return &handler{}
+		}),
+	))
+	return services
+}
+
+func TestAssertDependency(t *testing.T) {
+	t.Parallel()
+	services := wiring()
+
+	assert.True(t, AssertDependency[*service, *repository](t, services))
+	assert.True(t, AssertDependency[*handler, *service](t, services), "In struct fields are dependencies")
+	assert.True(t, AssertDependency[*handler, *plugin](t, services), "groups count as their element type")
+	assert.True(t, AssertNoDependency[*handler, *repository](t, services), "only direct dependencies count")
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertDependency[*repository, *service](rec, services))
+	assert.False(t, AssertNoDependency[*service, *repository](rec, services))
+	require.Len(t, rec.errors, 2)
+	assert.Contains(t, rec.errors[0], "*ditest.repository does not depend on *ditest.service")
+	assert.Contains(t, rec.errors[0], "dependencies: *ditest.database")
+	assert.Contains(t, rec.errors[0], "graph_test.go")
+	assert.Contains(t, rec.errors[1], "*ditest.service depends on *ditest.repository")
+}
+
+func TestAssertLifetime(t *testing.T) {
+	t.Parallel()
+	services := wiring()
+
+	assert.True(t, AssertLifetime[*repository](t, services, godi.Scoped))
+	assert.True(t, AssertLifetime[*handler](t, services, godi.Transient))
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertLifetime[*database](rec, services, godi.Scoped))
+	assert.False(t, AssertLifetime[*plugin](rec, services, godi.Singleton), "group members have no unkeyed registration")
+	require.Len(t, rec.errors, 2)
+	assert.Contains(t, rec.errors[0], "*ditest.database is Singleton, want Scoped")
+	assert.Contains(t, rec.errors[1], "*ditest.plugin is not registered")
+}
+
+func TestWiringSources(t *testing.T) {
+	t.Parallel()
+	services := godi.NewCollection()
+	services.AddScoped(func() *repository { return &repository{} })
+	services.AddScoped(func(repo *repository) *service { return &service{repo: repo} })
+	provider, err := services.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+
+	scope, err := provider.CreateScope(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scope.Close() })
+
+	assert.True(t, AssertDependency[*service, *repository](t, provider))
+	assert.True(t, AssertDependency[*service, *repository](t, scope))
+	assert.True(t, AssertDependency[*service, *repository](t, services.Graph()))
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertLifetime[*service](rec, "not wiring", godi.Scoped))
+	assert.False(t, AssertLifetime[*service](rec, nil, godi.Scoped))
+	require.Len(t, rec.errors, 2)
+	assert.Contains(t, rec.errors[0], "string is not a godi.Collection")
+	assert.Contains(t, rec.errors[1], "wiring is nil")
+}
@@ -0,0 +1,302 @@
+package godi
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DumpTo writes a table of p's registrations to w: service type, key,
+// group, lifetime, registering module, number of module decorators, and the
+// number of cached instances. For a provider, scoped instances are counted
+// across all open scopes; for a scope, only those it caches. It is meant for
+// debugging, e.g. from a debug endpoint; the format may change between
+// releases.
+//
+// Example:
+//
+//	http.HandleFunc("/debug/godi", func(w http.ResponseWriter, r *http.Request) {
+//	    _ = godi.DumpTo(provider, w)
+//	})
+func DumpTo(p Provider, w io.Writer) error {
+	switch v := p.(type) {
+	case nil:
+		return ErrProviderNil
+	case dumper:
+		return v.DumpTo(w)
+	default:
+		return errUnsupportedProvider(p)
+	}
+}
+
+// dumper is implemented by the providers and scopes of this package.
+type dumper interface {
+	DumpTo(w io.Writer) error
+}
+
+// String returns the table written by DumpTo, for debuggers and logs.
+func (p *provider) String() string {
+	var b strings.Builder
+	_ = p.DumpTo(&b)
+	return b.String()
+}
+
+// DumpTo writes the provider's registrations to w; see godi.DumpTo.
+func (p *provider) DumpTo(w io.Writer) error {
+	state := "open"
+	if p.disposed.Load() != 0 {
+		state = "closed"
+	}
+
+	p.scopesMu.Lock()
+	scopes := make([]*scope, 0, len(p.scopes)+1)
+	scopes = append(scopes, p.rootScope)
+	for s := range p.scopes {
+		scopes = append(scopes, s)
+	}
+	p.scopesMu.Unlock()
+
+	header := fmt.Sprintf("provider %s (%s): %d registrations, %d scopes", p.id, state, len(p.descriptors), len(scopes)-1)
+	return dumpRegistrations(w, header, p.descriptors, func(d *descriptor) int {
+		key := instanceKey{Type: d.Type, Key: d.Key, Group: d.Group}
+		switch d.Lifetime {
+		case Singleton:
+			if _, ok := p.getSingleton(key); ok {
+				return 1
+			}
+			return 0
+		case Scoped:
+			n := 0
+			for _, s := range scopes {
+				if _, ok := s.getInstance(key); ok {
+					n++
+				}
+			}
+			return n
+		default:
+			return -1
+		}
+	})
+}
+
+// String returns the table written by DumpTo, for debuggers and logs.
+func (s *scope) String() string {
+	var b strings.Builder
+	_ = s.DumpTo(&b)
+	return b.String()
+}
+
+// DumpTo writes the registrations to w, counting only the scoped instances
+// cached by this scope; see godi.DumpTo.
+func (s *scope) DumpTo(w io.Writer) error {
+	state := "open"
+	if s.disposed.Load() != 0 {
+		state = "closed"
+	}
+	parent := ""
+	if s.parentScope != nil {
+		parent = ", child of " + s.parentScope.id
+	}
+
+	p := s.rootProvider
+	header := fmt.Sprintf("scope %s (%s) of provider %s%s: %d registrations", s.id, state, p.id, parent, len(p.descriptors))
+	return dumpRegistrations(w, header, p.descriptors, func(d *descriptor) int {
+		key := instanceKey{Type: d.Type, Key: d.Key, Group: d.Group}
+		var ok bool
+		switch d.Lifetime {
+		case Singleton:
+			_, ok = p.getSingleton(key)
+		case Scoped:
+			_, ok = s.getInstance(key)
+		default:
+			return -1
+		}
+		if ok {
+			return 1
+		}
+		return 0
+	})
+}
+
+// dumpRegistrations writes header and one row per registration. cached
+// returns the number of cached instances of a registration, or -1 when its
+// lifetime is not cached.
+func dumpRegistrations(w io.Writer, header string, all []*descriptor, cached func(*descriptor) int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	fmt.Fprintln(tw, "TYPE\tKEY\tGROUP\tLIFETIME\tMODULE\tDECORATORS\tCACHED")
+	for _, d := range all {
+		if d == nil {
+			continue
+		}
+		key, group, module, count := "-", "-", "-", "-"
+		if d.Group != "" {
+			group = d.Group
+		} else if d.Key != nil {
+			key = fmt.Sprint(d.Key)
+		}
+		if len(d.modules) > 0 {
+			module = strings.Join(d.modules, "/")
+		}
+		if n := cached(d); n >= 0 {
+			count = strconv.Itoa(n)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			formatType(d.Type), key, group, d.Lifetime, module, len(d.decorators), count)
+	}
+	return tw.Flush()
+}
+
+// Sizer is implemented by services that can estimate how many bytes they
+// hold, such as caches and buffers. DumpState reports the estimate.
+type Sizer interface {
+	Size() int64
+}
+
+// InstanceState describes an instance held by a scope, as reported by
+// ScopeStateOf and DumpState.
+type InstanceState struct {
+	ServiceType reflect.Type
+	ServiceKey  any    // nil for non-keyed services
+	Group       string // empty unless the instance is a group member
+	Lifetime    Lifetime
+	Created     time.Time // when the scope started holding the instance
+	Disposable  bool      // closed with the scope
+	Size        int64     // reported by Sizer, or -1
+}
+
+// instanceRecord is what a scope remembers about a cached instance for
+// ScopeStateOf.
+type instanceRecord struct {
+	descriptor *descriptor
+	created    time.Time
+}
+
+// ScopeStateOf returns the instances held by the scope p, or by the root
+// scope if p is a provider, oldest first: the scoped and memoized instances
+// it caches and the transients it will close. Transients without a cleanup
+// method are not held and not listed. Singletons belong to the provider and
+// are not listed either. Together with Sizer it helps find what grows in a
+// long-lived scope, such as the scope of a websocket session, and the
+// result can be serialized in whatever format a debug endpoint needs.
+func ScopeStateOf(p Provider) ([]InstanceState, error) {
+	s, err := scopeOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return s.state(), nil
+}
+
+func (s *scope) state() []InstanceState {
+	var states []InstanceState
+
+	s.instancesMu.RLock()
+	for key, instance := range s.instances {
+		record := s.records[key]
+		state := newInstanceState(key.Type, key.Key, key.Group, record.descriptor, instance, record.created)
+		if record.descriptor != nil {
+			_, state.Disposable = disposableFor(record.descriptor, instance)
+		}
+		states = append(states, state)
+	}
+	s.instancesMu.RUnlock()
+
+	s.disposablesMu.Lock()
+	for _, tracked := range s.disposables {
+		d := tracked.descriptor
+		if d == nil || (d.Lifetime != Transient && d.Lifetime != PerResolution) {
+			continue
+		}
+		if _, ok := tracked.Disposable.(*cleanupFunc); ok {
+			continue
+		}
+		var instance any = tracked.Disposable
+		if custom, ok := instance.(*customDisposable); ok {
+			instance = custom.disposalIdentity()
+		}
+		state := newInstanceState(d.Type, d.Key, d.Group, d, instance, tracked.created)
+		state.Disposable = true
+		states = append(states, state)
+	}
+	s.disposablesMu.Unlock()
+
+	slices.SortStableFunc(states, func(a, b InstanceState) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return cmp.Compare(formatType(a.ServiceType), formatType(b.ServiceType))
+	})
+	return states
+}
+
+func newInstanceState(t reflect.Type, key any, group string, d *descriptor, instance any, created time.Time) InstanceState {
+	state := InstanceState{ServiceType: t, Group: group, Created: created, Size: -1}
+	if group == "" {
+		state.ServiceKey = key
+	}
+	if d != nil {
+		state.Lifetime = d.Lifetime
+	}
+	if sizer, ok := instance.(Sizer); ok {
+		state.Size = sizer.Size()
+	}
+	return state
+}
+
+// DumpState writes a table of the instances s holds to w, as returned by
+// ScopeStateOf: type, key, group, lifetime, creation time, whether the
+// scope closes it, and its Sizer estimate. It is meant for debugging; the
+// format may change between releases.
+//
+// Example:
+//
+//	_ = godi.DumpState(session, os.Stderr)
+func DumpState(s Scope, w io.Writer) error {
+	switch v := s.(type) {
+	case nil:
+		return ErrProviderNil
+	case stateDumper:
+		return v.DumpState(w)
+	default:
+		return errUnsupportedProvider(s)
+	}
+}
+
+// stateDumper is implemented by the scopes of this package.
+type stateDumper interface {
+	DumpState(w io.Writer) error
+}
+
+// DumpState writes the instances the scope holds to w; see godi.DumpState.
+func (s *scope) DumpState(w io.Writer) error {
+	states := s.state()
+	var total int64
+	for _, state := range states {
+		total += max(state.Size, 0)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "scope %s of provider %s: %d instances, %d bytes reported\n", s.id, s.rootProvider.id, len(states), total)
+	fmt.Fprintln(tw, "TYPE\tKEY\tGROUP\tLIFETIME\tCREATED\tDISPOSABLE\tSIZE")
+	for _, state := range states {
+		key, group, size := "-", "-", "-"
+		if state.Group != "" {
+			group = state.Group
+		} else if state.ServiceKey != nil {
+			key = fmt.Sprint(state.ServiceKey)
+		}
+		if state.Size >= 0 {
+			size = strconv.FormatInt(state.Size, 10)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+			formatType(state.ServiceType), key, group, state.Lifetime,
+			state.Created.Format(time.RFC3339Nano), state.Disposable, size)
+	}
+	return tw.Flush()
+}
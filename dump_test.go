@@ -0,0 +1,168 @@
+package godi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dumpRows returns the whitespace-separated cells of each table row.
+func dumpRows(t *testing.T, dump string) [][]string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+	require.GreaterOrEqual(t, len(lines), 2)
+	assert.Equal(t, []string{"TYPE", "KEY", "GROUP", "LIFETIME", "MODULE", "DECORATORS", "CACHED"}, strings.Fields(lines[1]))
+	rows := make([][]string, 0, len(lines)-2)
+	for _, line := range lines[2:] {
+		rows = append(rows, strings.Fields(line))
+	}
+	return rows
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	p := BuildProvider(t,
+		AddSingleton(NewTDependency),
+		NewModule("app",
+			AddScoped(NewTService, Name("primary")),
+			AddTransient(NewTTransient, Group("multi")),
+			ModuleDecorate(func(svc *TService) *TService { return svc }),
+		),
+	)
+	first := NewTestScope(t, p)
+	second := NewTestScope(t, p)
+	_, err := ResolveKeyed[*TService](first, "primary")
+	require.NoError(t, err)
+	_, err = ResolveKeyed[*TService](second, "primary")
+	require.NoError(t, err)
+
+	t.Run("provider", func(t *testing.T) {
+		t.Parallel()
+		var b bytes.Buffer
+		require.NoError(t, DumpTo(p, &b))
+		assert.True(t, strings.HasPrefix(b.String(), fmt.Sprintf("provider %s (open): 3 registrations, ", p.ID())))
+		assert.Equal(t, [][]string{
+			{"*TDependency", "-", "-", "Singleton", "-", "0", "1"},
+			{"*TService", "primary", "-", "Scoped", "app", "1", "2"},
+			{"*TTransient", "-", "multi", "Transient", "app", "0", "-"},
+		}, dumpRows(t, b.String()))
+		assert.Equal(t, b.String(), fmt.Sprint(p), "String matches DumpTo")
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		t.Parallel()
+		child, err := first.CreateScope(first.Context())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = child.Close() })
+
+		dump := fmt.Sprint(child)
+		assert.True(t, strings.HasPrefix(dump, fmt.Sprintf("scope %s (open) of provider %s, child of %s:", child.ID(), p.ID(), first.ID())), dump)
+		rows := dumpRows(t, dump)
+		assert.Equal(t, "0", rows[1][6], "the child has not resolved the scoped service")
+
+		var b bytes.Buffer
+		require.NoError(t, DumpTo(first, &b))
+		assert.Equal(t, "1", dumpRows(t, b.String())[1][6])
+	})
+
+	t.Run("sealed", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(p)
+		require.NoError(t, err)
+		require.ErrorIs(t, DumpTo(sealed, &bytes.Buffer{}), ErrProviderSealed)
+	})
+
+	t.Run("nil_provider", func(t *testing.T) {
+		t.Parallel()
+		require.ErrorIs(t, DumpTo(nil, &bytes.Buffer{}), ErrProviderNil)
+	})
+}
+
+// stepClock is a Clock that moves forward a second every time it is read.
+type stepClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+func (c *stepClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
+
+type TSized struct{ bytes int64 }
+
+func (s *TSized) Size() int64 { return s.bytes }
+
+func TestScopeDumpState(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCollection()
+	c.AddSingleton(NewTDependency)
+	c.AddScoped(func() *TSized { return &TSized{bytes: 4096} })
+	c.AddScoped(NewTService, Name("primary"))
+	c.AddTransient(NewTDisposable)
+	c.AddTransient(NewTTransient)
+	p, err := c.BuildWithOptions(&ProviderOptions{Clock: &stepClock{now: start}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	s := NewTestScope(t, p)
+	RequireResolveFrom[*TSized](t, s)
+	_, err = ResolveKeyed[*TService](s, "primary")
+	require.NoError(t, err)
+	RequireResolveFrom[*TDisposable](t, s)
+	RequireResolveFrom[*TTransient](t, s)
+	RequireResolveFrom[*TDependency](t, s)
+
+	states, err := ScopeStateOf(s)
+	require.NoError(t, err)
+	require.Len(t, states, 3, "singletons and transients without cleanup are not held by the scope")
+
+	assert.Equal(t, PtrTypeOf[TSized](), states[0].ServiceType)
+	assert.Equal(t, Scoped, states[0].Lifetime)
+	assert.Equal(t, int64(4096), states[0].Size)
+	assert.False(t, states[0].Disposable)
+
+	assert.Equal(t, PtrTypeOf[TService](), states[1].ServiceType)
+	assert.Equal(t, "primary", states[1].ServiceKey)
+	assert.Equal(t, int64(-1), states[1].Size)
+	assert.True(t, states[1].Created.After(states[0].Created))
+
+	assert.Equal(t, PtrTypeOf[TDisposable](), states[2].ServiceType)
+	assert.Equal(t, Transient, states[2].Lifetime)
+	assert.True(t, states[2].Disposable)
+
+	var b bytes.Buffer
+	require.NoError(t, DumpState(s, &b))
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, fmt.Sprintf("scope %s of provider %s: 3 instances, 4096 bytes reported", s.ID(), p.ID()), strings.TrimSpace(lines[0]))
+	assert.Equal(t, []string{"TYPE", "KEY", "GROUP", "LIFETIME", "CREATED", "DISPOSABLE", "SIZE"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"*TSized", "-", "-", "Scoped", states[0].Created.Format(time.RFC3339Nano), "false", "4096"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"*TService", "primary", "-", "Scoped", states[1].Created.Format(time.RFC3339Nano), "false", "-"}, strings.Fields(lines[3]))
+
+	require.NoError(t, s.Close())
+	states, err = ScopeStateOf(s)
+	require.NoError(t, err)
+	assert.Empty(t, states, "a closed scope holds nothing")
+
+	sealed, err := Seal(p)
+	require.NoError(t, err)
+	_, err = ScopeStateOf(sealed)
+	assert.ErrorIs(t, err, ErrProviderSealed)
+	assert.ErrorIs(t, DumpState(nil, &b), ErrProviderNil)
+}
@@ -0,0 +1,275 @@
+package godi
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FromEnv returns a ModuleOption registering T, a struct or a pointer to
+// one, as a singleton whose fields are read from environment variables. It
+// replaces the constructor every service otherwise writes to load its
+// configuration and register it.
+//
+// Each exported field is read from prefix followed by its name in upper
+// snake case, so with the prefix "APP_" the field DatabaseURL is read from
+// APP_DATABASE_URL. Field tags change that:
+//
+//	env:"NAME"        read prefix+NAME instead
+//	env:",required"   fail if the variable is not set
+//	env:"-"           leave the field alone
+//	default:"value"   use value when the variable is not set
+//
+// Fields of nested structs are read with the nested field's name added to
+// the prefix, as in APP_DATABASE_HOST. Strings, booleans, numbers,
+// time.Duration, types implementing encoding.TextUnmarshaler, pointers to
+// those and comma-separated slices of them are supported.
+//
+// The variables are read when the singleton is constructed. A missing
+// required variable or a value that cannot be converted fails the
+// construction with an *EnvError for each problem.
+//
+// Example:
+//
+//	type ServerConfig struct {
+//	    Addr         string        `default:":8080"`
+//	    ReadTimeout  time.Duration `default:"5s"`
+//	    DatabaseURL  string        `env:"DB_URL,required"`
+//	    AllowedHosts []string
+//	}
+//
+//	var ConfigModule = godi.NewModule("config",
+//	    godi.FromEnv[*ServerConfig]("SERVER_"),
+//	)
+func FromEnv[T any](prefix string, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		serviceType := reflect.TypeFor[T]()
+		structType := serviceType
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return &ValidationError{
+				ServiceType: serviceType,
+				Cause:       fmt.Errorf("godi.FromEnv needs a struct or a pointer to a struct"),
+			}
+		}
+		if err := checkEnvFields(structType); err != nil {
+			return &ValidationError{
+				ServiceType: serviceType,
+				Cause:       err,
+			}
+		}
+
+		s.AddSingleton(func() (T, error) {
+			var result T
+			target := reflect.ValueOf(&result).Elem()
+			if serviceType.Kind() == reflect.Pointer {
+				target.Set(reflect.New(structType))
+				target = target.Elem()
+			}
+			if err := loadEnv(target, prefix); err != nil {
+				var zero T
+				return zero, err
+			}
+			return result, nil
+		}, opts...)
+		return nil
+	}
+}
+
+// envTag is a parsed env:"..." tag.
+type envTag struct {
+	name     string
+	required bool
+	skip     bool
+}
+
+func parseEnvTag(field reflect.StructField) envTag {
+	tag, ok := field.Tag.Lookup("env")
+	if !ok {
+		return envTag{name: envName(field.Name)}
+	}
+	if tag == "-" {
+		return envTag{skip: true}
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = envName(field.Name)
+	}
+	return envTag{name: name, required: options == "required"}
+}
+
+// envName converts a Go identifier to upper snake case, keeping
+// initialisms together: DatabaseURL becomes DATABASE_URL.
+func envName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// envStruct reports whether fields of type t are read as nested structs
+// rather than parsed from a single variable.
+func envStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// checkEnvFields reports a field of t, or of its nested structs, whose type
+// cannot be read from a variable, so the mistake surfaces at registration.
+func checkEnvFields(t reflect.Type) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || parseEnvTag(field).skip {
+			continue
+		}
+		if envStruct(field.Type) {
+			if err := checkEnvFields(field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		if !envParsable(field.Type) {
+			return fmt.Errorf("field %s.%s of type %s cannot be read from an environment variable",
+				t.Name(), field.Name, formatType(field.Type))
+		}
+	}
+	return nil
+}
+
+func envParsable(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Pointer:
+		return envParsable(t.Elem())
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && envParsable(t.Elem())
+	default:
+		return false
+	}
+}
+
+// loadEnv sets the fields of the struct v from the variables under prefix,
+// returning every problem found.
+func loadEnv(v reflect.Value, prefix string) error {
+	var errs []error
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := parseEnvTag(field)
+		if !field.IsExported() || tag.skip {
+			continue
+		}
+		if envStruct(field.Type) {
+			if err := loadEnv(v.Field(i), prefix+tag.name+"_"); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		name := prefix + tag.name
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value, ok = field.Tag.Lookup("default")
+		}
+		if !ok {
+			if tag.required {
+				errs = append(errs, &EnvError{Variable: name, Field: t.Name() + "." + field.Name, Cause: ErrEnvNotSet})
+			}
+			continue
+		}
+		if err := setEnvValue(v.Field(i), value); err != nil {
+			errs = append(errs, &EnvError{Variable: name, Field: t.Name() + "." + field.Name, Value: value, Cause: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// setEnvValue parses value into v.
+func setEnvValue(v reflect.Value, value string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := setEnvValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		var parts []string
+		if value != "" {
+			parts = strings.Split(value, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setEnvValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", formatType(v.Type()))
+	}
+	return nil
+}
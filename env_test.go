@@ -0,0 +1,125 @@
+package godi
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TEnvDatabase struct {
+	Host string
+	Port int `default:"5432"`
+}
+
+type TEnvConfig struct {
+	Addr         string        `default:":8080"`
+	ReadTimeout  time.Duration `default:"5s"`
+	DatabaseURL  string        `env:"DB_URL,required"`
+	Debug        bool
+	Ratio        float64
+	MaxConns     *uint16
+	AllowedHosts []string
+	Ports        []int
+	Listen       netip.Addr
+	Database     TEnvDatabase
+	Ignored      string `env:"-"`
+	internal     string
+}
+
+// TestFromEnv cannot run in parallel: it sets environment variables.
+func TestFromEnv(t *testing.T) {
+	t.Run("reads_fields", func(t *testing.T) {
+		t.Setenv("APP_DB_URL", "postgres://localhost/app")
+		t.Setenv("APP_DEBUG", "true")
+		t.Setenv("APP_RATIO", "0.5")
+		t.Setenv("APP_MAX_CONNS", "20")
+		t.Setenv("APP_ALLOWED_HOSTS", "a.example.com, b.example.com")
+		t.Setenv("APP_PORTS", "80,443")
+		t.Setenv("APP_LISTEN", "127.0.0.1")
+		t.Setenv("APP_DATABASE_HOST", "db")
+		t.Setenv("APP_IGNORED", "set")
+		t.Setenv("APP_INTERNAL", "set")
+
+		p := BuildProvider(t, FromEnv[*TEnvConfig]("APP_"))
+		cfg := RequireResolve[*TEnvConfig](t, p)
+		assert.Equal(t, ":8080", cfg.Addr)
+		assert.Equal(t, 5*time.Second, cfg.ReadTimeout)
+		assert.Equal(t, "postgres://localhost/app", cfg.DatabaseURL)
+		assert.True(t, cfg.Debug)
+		assert.Equal(t, 0.5, cfg.Ratio)
+		require.NotNil(t, cfg.MaxConns)
+		assert.Equal(t, uint16(20), *cfg.MaxConns)
+		assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.AllowedHosts)
+		assert.Equal(t, []int{80, 443}, cfg.Ports)
+		assert.Equal(t, netip.MustParseAddr("127.0.0.1"), cfg.Listen)
+		assert.Equal(t, TEnvDatabase{Host: "db", Port: 5432}, cfg.Database)
+		assert.Empty(t, cfg.Ignored)
+		assert.Empty(t, cfg.internal)
+		assert.Same(t, cfg, RequireResolve[*TEnvConfig](t, p), "registered as a singleton")
+	})
+
+	t.Run("struct_value_and_options", func(t *testing.T) {
+		t.Setenv("WORKER_HOST", "queue")
+		p := BuildProvider(t, FromEnv[TEnvDatabase]("WORKER_", Name("worker")))
+		db, err := ResolveKeyed[TEnvDatabase](p, "worker")
+		require.NoError(t, err)
+		assert.Equal(t, TEnvDatabase{Host: "queue", Port: 5432}, db)
+	})
+
+	t.Run("reports_every_problem", func(t *testing.T) {
+		t.Setenv("BAD_RATIO", "half")
+		t.Setenv("BAD_PORTS", "80,https")
+
+		c := NewCollection()
+		c.AddModules(FromEnv[*TEnvConfig]("BAD_"))
+		require.NoError(t, c.Err())
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrEnvNotSet)
+
+		for _, name := range []string{"BAD_DB_URL", "BAD_RATIO", "BAD_PORTS"} {
+			assert.Contains(t, err.Error(), name)
+		}
+		envErr, ok := errors.AsType[*EnvError](err)
+		require.True(t, ok, "expected EnvError, got %v", err)
+		assert.Equal(t, "TEnvConfig.DatabaseURL", envErr.Field)
+	})
+
+	t.Run("rejects_unsupported_types", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			module ModuleOption
+			want   string
+		}{
+			{"not_a_struct", FromEnv[string]("X_"), "needs a struct"},
+			{"unsupported_field", FromEnv[struct{ Handler func() }]("X_"), "field .Handler"},
+			{"nested_unsupported_field", FromEnv[struct{ Inner struct{ M map[string]int } }]("X_"), "field .M"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c := NewCollection()
+				c.AddModules(tt.module)
+				require.Error(t, c.Err())
+				assert.Contains(t, c.Err().Error(), tt.want)
+			})
+		}
+	})
+}
+
+func TestEnvName(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]string{
+		"Addr":        "ADDR",
+		"DatabaseURL": "DATABASE_URL",
+		"HTTPPort":    "HTTP_PORT",
+		"MaxConns":    "MAX_CONNS",
+		"Port2":       "PORT2",
+		"ID":          "ID",
+	} {
+		assert.Equal(t, want, envName(name), name)
+	}
+}
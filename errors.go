@@ -27,18 +27,44 @@ var (
 	ErrServiceKeyNil   = errors.New("service key cannot be nil")
 	ErrServiceTypeNil  = errors.New("service type cannot be nil")
 
+	// ErrServiceNotProvided is the cause when a result-object field tagged
+	// optional:"true" was left nil by its constructor. It matches
+	// ErrServiceNotFound, so optional dependencies on the field are skipped.
+	ErrServiceNotProvided error = notProvidedError{}
+
 	// Lifecycle errors.
 	ErrProviderNil      = errors.New("service provider cannot be nil")
 	ErrProviderDisposed = errors.New("service provider has been disposed")
 	ErrScopeDisposed    = errors.New("scope has been disposed")
+	ErrProviderSealed   = errors.New("service provider is sealed")
+
+	// ErrScopedResolvedFromRoot is the cause when a Scoped service is
+	// resolved from the provider itself rather than from a scope, unless
+	// ProviderOptions.AllowScopedFromRoot is set. Services registered with
+	// AddRefreshing are exempt.
+	ErrScopedResolvedFromRoot = errors.New("scoped service resolved from the root provider")
 
 	// Validation errors.
 	ErrConstructorNil          = errors.New("constructor cannot be nil")
 	ErrGroupNameEmpty          = errors.New("group name cannot be empty")
 	ErrSingletonNotInitialized = errors.New("singleton not initialized at build time")
 	ErrDescriptorNil           = errors.New("descriptor cannot be nil")
+
+	// ErrEnvNotSet is the cause of an EnvError for a required variable
+	// that is not set.
+	ErrEnvNotSet = errors.New("required but not set")
 )
 
+type notProvidedError struct{}
+
+func (notProvidedError) Error() string {
+	return "optional result was not provided by its constructor"
+}
+
+func (notProvidedError) Is(target error) bool {
+	return target == ErrServiceNotFound
+}
+
 // All typed errors are returned as pointers. Match them with
 // errors.AsType (Go 1.26+) or errors.As using a pointer target:
 //
@@ -49,8 +75,20 @@ var (
 	_ error = (*LifetimeError)(nil)
 	_ error = (*LifetimeConflictError)(nil)
 	_ error = (*AlreadyRegisteredError)(nil)
+	_ error = (*DuplicateRegistrationError)(nil)
+	_ error = (*GroupMemberError)(nil)
+	_ error = (*ServiceLocatorError)(nil)
+	_ error = (*DisposalMethodError)(nil)
+	_ error = (*LayerViolationError)(nil)
+	_ error = (*InvokeEachError)(nil)
+	_ error = (*ExportNameConflictError)(nil)
+	_ error = (*ExportNameNotFoundError)(nil)
 	_ error = (*ResolutionError)(nil)
+	_ error = (*InjectError)(nil)
+	_ error = (*CapabilityError)(nil)
+	_ error = (*PrivateServiceError)(nil)
 	_ error = (*TimeoutError)(nil)
+	_ error = (*ContextCancelledError)(nil)
 	_ error = (*RegistrationError)(nil)
 	_ error = (*ValidationError)(nil)
 	_ error = (*ModuleError)(nil)
@@ -59,8 +97,13 @@ var (
 	_ error = (*GraphOperationError)(nil)
 	_ error = (*ConstructorInvocationError)(nil)
 	_ error = (*ConstructorPanicError)(nil)
+	_ error = (*ConsumerPanicError)(nil)
+	_ error = (*ContextValueError)(nil)
 	_ error = (*BuildError)(nil)
 	_ error = (*DisposalError)(nil)
+	_ error = (*OptionalFailureError)(nil)
+	_ error = (*StopError)(nil)
+	_ error = (*DecoratorError)(nil)
 	_ error = (*CircularDependencyError)(nil)
 )
 
@@ -106,12 +149,21 @@ func (e LifetimeConflictError) Error() string {
 		b.WriteString("Scoped services are created per-scope and may have different values in different scopes.\n\n")
 		b.WriteString("A transient depending on a scoped service could outlive and hold a reference\n")
 		b.WriteString("to a disposed scoped service.\n\n")
+	case PerResolution:
+		b.WriteString("PerResolution services are created for each top-level resolution and handed off.\n")
+		b.WriteString("Scoped services are created per-scope and may have different values in different scopes.\n\n")
+		b.WriteString("A per-resolution service depending on a scoped service could outlive and hold\n")
+		b.WriteString("a reference to a disposed scoped service.\n\n")
 	}
 
 	b.WriteString("To resolve this:\n")
 	fmt.Fprintf(&b, "  • Change %s to Scoped lifetime\n", formatType(e.ServiceType))
 	fmt.Fprintf(&b, "  • Change %s to Singleton lifetime\n", formatType(e.DependencyType))
-	fmt.Fprintf(&b, "  • Use a factory function to resolve %s lazily\n", formatType(e.DependencyType))
+	if e.ServiceLifetime == Singleton {
+		fmt.Fprintf(&b, "  • Take godi.ScopedAccessor[%s] and call Get with the operation's context\n", formatType(e.DependencyType))
+	} else {
+		fmt.Fprintf(&b, "  • Use a factory function to resolve %s lazily\n", formatType(e.DependencyType))
+	}
 
 	return b.String()
 }
@@ -125,20 +177,425 @@ func (e AlreadyRegisteredError) Error() string {
 	return fmt.Sprintf("service %s already registered (use keyed services or groups)", formatType(e.ServiceType))
 }
 
+// DuplicateRegistrationError indicates that the same constructor function
+// was registered twice for the same service type and key, typically
+// because a module was included twice. Build reports it unless
+// ProviderOptions.IgnoreDuplicateRegistrations is set, in which case the
+// second registration is dropped.
+type DuplicateRegistrationError struct {
+	ServiceType reflect.Type
+	ServiceKey  any    // nil for unkeyed services
+	Constructor string // function name of the constructor
+
+	// FirstModules and SecondModules are the module stacks the two
+	// registrations were made in, outermost first; empty when registered
+	// directly on the collection.
+	FirstModules  []string
+	SecondModules []string
+}
+
+func (e DuplicateRegistrationError) Error() string {
+	service := formatType(e.ServiceType)
+	if e.ServiceKey != nil {
+		service += fmt.Sprintf(" (key: %v)", e.ServiceKey)
+	}
+	return fmt.Sprintf("service %s already registered by the same constructor %s: first %s, again %s "+
+		"(set ProviderOptions.IgnoreDuplicateRegistrations to keep only the first)",
+		service, e.Constructor, registrationSite(e.FirstModules), registrationSite(e.SecondModules))
+}
+
+func (e DuplicateRegistrationError) Unwrap() error {
+	return &AlreadyRegisteredError{ServiceType: e.ServiceType}
+}
+
+func registrationSite(modules []string) string {
+	if len(modules) == 0 {
+		return "outside any module"
+	}
+	return fmt.Sprintf("in module %q", strings.Join(modules, "/"))
+}
+
+// GroupMemberError indicates a value group member that the group's consumers
+// can never receive because it is registered under a type none of them
+// request as the slice element type.
+type GroupMemberError struct {
+	Group        string
+	ElementTypes []reflect.Type // element types requested by the group's consumers
+	MemberType   reflect.Type   // type the member is registered as
+	Source       string         // where the member was registered
+}
+
+func (e GroupMemberError) Error() string {
+	var b strings.Builder
+	consumers := make([]string, len(e.ElementTypes))
+	for i, elemType := range e.ElementTypes {
+		consumers[i] = "[]" + formatType(elemType)
+	}
+	fmt.Fprintf(&b, "group %q: member %s registered by %s is not consumed as any of %s\n\n",
+		e.Group, formatType(e.MemberType), e.Source, strings.Join(consumers, ", "))
+
+	b.WriteString("Group members are matched by their registered type, so this member\n")
+	b.WriteString("would be missing from every consumer of the group.\n\n")
+
+	b.WriteString("To resolve this:\n")
+	for _, elemType := range e.ElementTypes {
+		if e.MemberType != nil && elemType != nil && e.MemberType.AssignableTo(elemType) {
+			fmt.Fprintf(&b, "  • Register the member with godi.As[%s]()\n", formatType(elemType))
+		}
+	}
+	fmt.Fprintf(&b, "  • Consume the group as []%s\n", formatType(e.MemberType))
+	b.WriteString("  • Move the member to a group name that is consumed with its type\n")
+
+	return b.String()
+}
+
+// ServiceLocatorError indicates a constructor that takes Provider or Scope as
+// a parameter while ProviderOptions.DisallowServiceLocator is set.
+type ServiceLocatorError struct {
+	ServiceType reflect.Type
+	ServiceKey  any
+	Parameter   reflect.Type // godi.Provider or godi.Scope
+	Source      string       // where the constructor was registered
+}
+
+func (e ServiceLocatorError) Error() string {
+	var b strings.Builder
+	service := formatType(e.ServiceType)
+	if e.ServiceKey != nil {
+		service = fmt.Sprintf("%s[%v]", service, e.ServiceKey)
+	}
+	fmt.Fprintf(&b, "service locator: %s takes %s (%s)\n\n", service, formatType(e.Parameter), e.Source)
+
+	b.WriteString("Resolving services from inside a constructor hides its dependencies\n")
+	b.WriteString("from the graph, so they cannot be validated at build time.\n\n")
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Declare the services it resolves as constructor parameters\n")
+	fmt.Fprintf(&b, "  • Add %s to ProviderOptions.ServiceLocatorAllowlist if it must resolve dynamically\n", formatType(e.ServiceType))
+
+	return b.String()
+}
+
+// DisposalMethodError reports a registered type with a cleanup method, such
+// as Close() without an error result or Shutdown(ctx), that the container
+// never calls because the type implements neither Disposable nor
+// DisposableWithContext. It is passed to ProviderOptions.OnDisposalMismatch,
+// or returned by Build under ProviderOptions.StrictDisposal.
+type DisposalMethodError struct {
+	ServiceType reflect.Type
+	Method      string // the signature found, e.g. "Close()"
+	Source      string // where the service was registered
+}
+
+func (e DisposalMethodError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s has %s, which godi does not call on disposal (%s)\n\n", formatType(e.ServiceType), e.Method, e.Source)
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Implement Close() error (godi.Disposable) or CloseContext(context.Context) error (godi.DisposableWithContext)\n")
+	b.WriteString("  • Register a pointer if the method has a pointer receiver\n")
+	b.WriteString("  • Or register it with godi.DisposeWith to call the method it has\n")
+
+	return b.String()
+}
+
+// MutationError reports that the shared instance of a godi.Clone
+// registration changed after it was first handed out, which would corrupt
+// the value other consumers see. It is passed to ProviderOptions.OnMutation.
+type MutationError struct {
+	ServiceType reflect.Type
+	ServiceKey  any
+	ScopeID     string // of the scope caching the instance
+	Source      string // where the service was registered
+}
+
+func (e MutationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "shared instance of %s", formatType(e.ServiceType))
+	if e.ServiceKey != nil {
+		fmt.Fprintf(&b, " (key: %v)", e.ServiceKey)
+	}
+	fmt.Fprintf(&b, " in scope %s was mutated after it was handed out (%s)\n\n", e.ScopeID, e.Source)
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Copy the value before changing it\n")
+	b.WriteString("  • Or register a separate service for the consumer that needs its own value\n")
+
+	return b.String()
+}
+
+// OptionalFailureError reports an optional:"true" field that was left nil
+// because its service is registered but failed to construct. It is passed to
+// ProviderOptions.OnOptionalFailure.
+type OptionalFailureError struct {
+	ServiceType    reflect.Type // whose field was left nil; nil for Invoke and Inject
+	Field          string
+	DependencyType reflect.Type
+	ScopeID        string // of the scope resolving the field
+	Cause          error
+}
+
+func (e OptionalFailureError) Error() string {
+	consumer := "the invoked function"
+	if e.ServiceType != nil {
+		consumer = formatType(e.ServiceType)
+	}
+	return fmt.Sprintf("optional field %s of %s was left nil in scope %s: %s failed: %v",
+		e.Field, consumer, e.ScopeID, formatType(e.DependencyType), e.Cause)
+}
+
+func (e OptionalFailureError) Unwrap() error {
+	return e.Cause
+}
+
+// InstanceLimitError reports a resolution that would have made a scope
+// construct more instances than ProviderOptions.MaxInstancesPerScope, or the
+// godi.MaxInstancesPerScope of the registration, allows.
+type InstanceLimitError struct {
+	ServiceType  reflect.Type
+	ServiceKey   any    // nil for non-keyed services
+	ScopeID      string // of the scope at its limit
+	Limit        int
+	Registration bool // the limit is the registration's own
+	Path         []ResolutionFrame
+}
+
+func (e InstanceLimitError) Error() string {
+	what := "instances"
+	if e.Registration {
+		what = "instances of " + formatType(e.ServiceType)
+	}
+	return fmt.Sprintf("scope %s reached its limit of %d %s at %s",
+		e.ScopeID, e.Limit, what, formatResolutionPath(e.Path))
+}
+
+// CircuitOpenError reports a resolution failed fast because the
+// constructor of a registration with godi.CircuitBreaker failed too many
+// times in a row. Cause is the most recent failure.
+type CircuitOpenError struct {
+	ServiceType reflect.Type
+	ServiceKey  any       // nil for non-keyed services
+	Failures    int       // consecutive constructor failures
+	RetryAt     time.Time // when the constructor will be tried again
+	Path        []ResolutionFrame
+	Cause       error
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s after %d consecutive constructor failures, retrying at %s, at %s: %v",
+		formatType(e.ServiceType), e.Failures, e.RetryAt.Format(time.RFC3339), formatResolutionPath(e.Path), e.Cause)
+}
+
+func (e CircuitOpenError) Unwrap() error {
+	return e.Cause
+}
+
+// InitializeError reports an instance whose Initialize method failed (see
+// Initializer). Resolution returns it as the Cause of a ResolutionError
+// whose path ends at the service.
+type InitializeError struct {
+	ServiceType reflect.Type // the instance's type
+	Cause       error
+}
+
+func (e InitializeError) Error() string {
+	return fmt.Sprintf("failed to initialize %s: %v", formatType(e.ServiceType), e.Cause)
+}
+
+func (e InitializeError) Unwrap() error {
+	return e.Cause
+}
+
+// InstanceCheckError reports an instance rejected by a check registered
+// with godi.ValidateWith. Resolution returns it as the Cause of a
+// ResolutionError whose path ends at the rejected service.
+type InstanceCheckError struct {
+	ServiceType reflect.Type // the rejected instance's type
+	Check       reflect.Type // the type the check accepts
+	Cause       error
+}
+
+func (e InstanceCheckError) Error() string {
+	return fmt.Sprintf("%s rejected by godi.ValidateWith[%s]: %v",
+		formatType(e.ServiceType), formatType(e.Check), e.Cause)
+}
+
+func (e InstanceCheckError) Unwrap() error {
+	return e.Cause
+}
+
+// EnvError reports an environment variable godi.FromEnv could not read
+// into a configuration field.
+type EnvError struct {
+	Variable string // the environment variable
+	Field    string // the field, as Struct.Field
+	Value    string // the value that could not be converted; not part of Error, as it may be a secret
+	Cause    error
+}
+
+func (e EnvError) Error() string {
+	return fmt.Sprintf("environment variable %s for %s: %v", e.Variable, e.Field, e.Cause)
+}
+
+func (e EnvError) Unwrap() error {
+	return e.Cause
+}
+
+// OpenScopesError reports the scopes a provider still had open when it was
+// closed, under ProviderOptions.RequireScopesClosed. They were closed
+// before the singletons.
+type OpenScopesError struct {
+	Scopes []ScopeInfo // oldest first
+}
+
+func (e OpenScopesError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d scope(s) still open when the provider was closed:", len(e.Scopes))
+	for _, s := range e.Scopes {
+		fmt.Fprintf(&b, " %s", s.ID)
+		if s.Name != "" {
+			fmt.Fprintf(&b, " (%s)", s.Name)
+		}
+	}
+	return b.String()
+}
+
+// LayerViolationError indicates a dependency between layers that godi.Layers
+// does not allow.
+type LayerViolationError struct {
+	From    string         // layer of the dependent service
+	To      string         // layer of the service it reaches
+	Allowed []string       // layers From may depend on
+	Path    []reflect.Type // from the dependent service to the offending dependency
+	Source  string         // where the dependent service was registered
+}
+
+func (e LayerViolationError) Error() string {
+	var b strings.Builder
+	path := make([]string, len(e.Path))
+	for i, t := range e.Path {
+		path[i] = formatType(t)
+	}
+	fmt.Fprintf(&b, "layer violation: %q may not depend on %q: %s (%s)\n\n",
+		e.From, e.To, strings.Join(path, " -> "), e.Source)
+
+	if len(e.Allowed) == 0 {
+		fmt.Fprintf(&b, "Layer %q may not depend on other layers.\n\n", e.From)
+	} else {
+		fmt.Fprintf(&b, "Layer %q may only depend on: %s.\n\n", e.From, strings.Join(e.Allowed, ", "))
+	}
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Depend on a service from an allowed layer instead\n")
+	fmt.Fprintf(&b, "  • Add %q to the DependsOn of layer %q if the dependency is intended\n", e.To, e.From)
+
+	return b.String()
+}
+
+// InvokeEachError reports the failure of one group member's call in
+// InvokeEach.
+type InvokeEachError struct {
+	Group string
+	Index int // position of the member in the group
+	Cause error
+}
+
+func (e InvokeEachError) Error() string {
+	return fmt.Sprintf("group %q member %d: %v", e.Group, e.Index, e.Cause)
+}
+
+func (e InvokeEachError) Unwrap() error {
+	return e.Cause
+}
+
 // Type aliases for graph package types to maintain backward compatibility
 type CircularDependencyError = graph.CircularDependencyError
 
+// ExportNameConflictError indicates a godi.ExportName given to more than one
+// registration.
+type ExportNameConflictError struct {
+	Name         string
+	ServiceTypes []reflect.Type // registrations using the name
+	Sources      []string       // where each registration was made
+}
+
+func (e ExportNameConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "export name %q is used by %d registrations:\n", e.Name, len(e.ServiceTypes))
+	for i, serviceType := range e.ServiceTypes {
+		fmt.Fprintf(&b, "  • %s", formatType(serviceType))
+		if i < len(e.Sources) {
+			fmt.Fprintf(&b, " (%s)", e.Sources[i])
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nTo resolve this:\n")
+	b.WriteString("  • Give each registration a distinct godi.ExportName\n")
+
+	return b.String()
+}
+
+// ExportNameNotFoundError indicates that no service was registered with the
+// godi.ExportName passed to ResolveByName. It matches ErrServiceNotFound.
+type ExportNameNotFoundError struct {
+	Name      string
+	Available []string // exported names, sorted
+}
+
+func (e ExportNameNotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "no service exported as %q", e.Name)
+	if len(e.Available) > 0 {
+		fmt.Fprintf(&b, " (exported names: %s)", strings.Join(e.Available, ", "))
+	}
+	b.WriteString("\n\nTo resolve this:\n")
+	fmt.Fprintf(&b, "  • Register the service with godi.ExportName(%q)\n", e.Name)
+	return b.String()
+}
+
+func (e ExportNameNotFoundError) Unwrap() error {
+	return ErrServiceNotFound
+}
+
 // ResolutionError wraps errors that occur during service resolution.
 type ResolutionError struct {
 	ServiceType reflect.Type
 	ServiceKey  any // nil for non-keyed services
 	Cause       error
 	Available   []reflect.Type // Types that ARE registered (optional, for suggestions)
+
+	// Path lists the services being resolved when the failure occurred, from
+	// the service originally requested down to ServiceType. It is set when
+	// the failure happened while resolving a constructor's dependencies.
+	Path []ResolutionFrame
 }
 
 func (e ResolutionError) Error() string {
 	var b strings.Builder
 
+	if len(e.Path) > 0 {
+		cause := e.Cause
+		if cause == nil {
+			cause = ErrServiceNotFound
+		}
+		fmt.Fprintf(&b, "%s: %v", formatResolutionPath(e.Path), cause)
+		if !e.ServiceNotFound() {
+			return b.String()
+		}
+		e.writeSuggestions(&b)
+		return b.String()
+	}
+
+	if e.Cause == ErrScopedResolvedFromRoot {
+		fmt.Fprintf(&b, "cannot resolve scoped service %s from the root provider", formatType(e.ServiceType))
+		if e.ServiceKey != nil {
+			fmt.Fprintf(&b, " (key: %v)", e.ServiceKey)
+		}
+		e.writeSuggestions(&b)
+		return b.String()
+	}
+
 	if e.ServiceKey != nil {
 		fmt.Fprintf(&b, "service not found: %s (key: %v)", formatType(e.ServiceType), e.ServiceKey)
 	} else {
@@ -149,20 +606,31 @@ func (e ResolutionError) Error() string {
 		fmt.Fprintf(&b, ": %v", e.Cause)
 	}
 
+	e.writeSuggestions(&b)
+	return b.String()
+}
+
+func (e ResolutionError) writeSuggestions(b *strings.Builder) {
+	switch e.Cause {
+	case ErrServiceNotProvided:
+		return // registered; there is nothing to suggest
+	case ErrScopedResolvedFromRoot:
+		b.WriteString("\nResolve it from a scope created with CreateScope, or set ProviderOptions.AllowScopedFromRoot.")
+		return
+	}
+
 	// Suggest similar types if available
 	if len(e.Available) > 0 {
 		similar := findSimilarTypes(e.ServiceType, e.Available)
 		if len(similar) > 0 {
 			b.WriteString("\n\nDid you mean one of these?\n")
 			for _, t := range similar {
-				fmt.Fprintf(&b, "  • %s\n", formatType(t))
+				fmt.Fprintf(b, "  • %s\n", formatType(t))
 			}
 		}
 	}
 
 	b.WriteString("\nMake sure the service is registered with the correct lifetime and type.")
-
-	return b.String()
 }
 
 func (e ResolutionError) Unwrap() error {
@@ -174,7 +642,7 @@ func (e ResolutionError) Unwrap() error {
 // failed). Used by the parameter builder to decide whether an optional
 // dependency may be skipped.
 func (e ResolutionError) ServiceNotFound() bool {
-	return e.Cause == ErrServiceNotFound
+	return e.Cause == ErrServiceNotFound || e.Cause == ErrServiceNotProvided
 }
 
 // findSimilarTypes finds types with similar names using a simple substring/prefix match
@@ -220,6 +688,97 @@ func findSimilarTypes(target reflect.Type, available []reflect.Type) []reflect.T
 	return similar
 }
 
+// CapabilityError indicates a service that a view created with godi.Seal
+// and godi.AllowServices does not expose. It matches ErrProviderSealed.
+type CapabilityError struct {
+	ServiceType reflect.Type // nil for names not exported by the provider
+	ServiceKey  any
+	Group       string
+	Name        string // set when resolving with ResolveByName
+}
+
+func (e CapabilityError) Error() string {
+	var b strings.Builder
+	switch {
+	case e.ServiceType == nil:
+		fmt.Fprintf(&b, "service exported as %q is not exposed by this sealed provider\n\n", e.Name)
+	case e.ServiceKey != nil:
+		fmt.Fprintf(&b, "%s[%v] is not exposed by this sealed provider\n\n", formatType(e.ServiceType), e.ServiceKey)
+	case e.Group != "":
+		fmt.Fprintf(&b, "group %q of %s is not exposed by this sealed provider\n\n", e.Group, formatType(e.ServiceType))
+	default:
+		fmt.Fprintf(&b, "%s is not exposed by this sealed provider\n\n", formatType(e.ServiceType))
+	}
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Add the type to godi.AllowServices where the provider is sealed\n")
+	b.WriteString("  • Depend only on the services the plugin API exposes\n")
+
+	return b.String()
+}
+
+func (e CapabilityError) Unwrap() error {
+	return ErrProviderSealed
+}
+
+// PrivateServiceError indicates a godi.Private service used outside the
+// module that registered it: as a dependency of a service from another
+// module (reported by Build), or resolved directly. It matches
+// ErrServiceNotFound.
+type PrivateServiceError struct {
+	ServiceType    reflect.Type
+	ServiceKey     any
+	Module         string       // module path of the private registration
+	Consumer       reflect.Type // nil when resolved directly
+	ConsumerSource string
+}
+
+func (e PrivateServiceError) Error() string {
+	var b strings.Builder
+	service := formatType(e.ServiceType)
+	if e.ServiceKey != nil {
+		service = fmt.Sprintf("%s[%v]", service, e.ServiceKey)
+	}
+	if e.Consumer == nil {
+		fmt.Fprintf(&b, "%s is private to module %q and cannot be resolved directly\n\n", service, e.Module)
+	} else {
+		fmt.Fprintf(&b, "%s depends on %s, which is private to module %q (%s)\n\n",
+			formatType(e.Consumer), service, e.Module, e.ConsumerSource)
+	}
+
+	b.WriteString("To resolve this:\n")
+	fmt.Fprintf(&b, "  • Depend on a public service of module %q instead\n", e.Module)
+	b.WriteString("  • Remove godi.Private if the service is part of the module's API\n")
+
+	return b.String()
+}
+
+func (e PrivateServiceError) Unwrap() error {
+	return ErrServiceNotFound
+}
+
+// ServiceNotFound reports true so that optional dependencies treat a
+// private service like one that is not registered.
+func (e PrivateServiceError) ServiceNotFound() bool {
+	return true
+}
+
+// InjectError indicates a field of an Inject target that could not be
+// resolved.
+type InjectError struct {
+	Target reflect.Type // pointer to the target struct
+	Field  string
+	Cause  error
+}
+
+func (e InjectError) Error() string {
+	return fmt.Sprintf("failed to inject field %s of %s: %v", e.Field, formatType(e.Target), e.Cause)
+}
+
+func (e InjectError) Unwrap() error {
+	return e.Cause
+}
+
 // TimeoutError indicates a service resolution timed out.
 type TimeoutError struct {
 	ServiceType reflect.Type
@@ -234,6 +793,30 @@ func (e TimeoutError) Is(target error) bool {
 	return errors.Is(target, context.DeadlineExceeded)
 }
 
+// ContextCancelledError indicates that construction stopped because the
+// resolution context was cancelled or its deadline passed. Constructors
+// already running finish, but no further constructors are called. Path
+// lists the services being resolved, ending at the one that was not
+// constructed. It matches context.Canceled or context.DeadlineExceeded
+// through errors.Is.
+type ContextCancelledError struct {
+	ServiceType reflect.Type
+	ServiceKey  any // nil for non-keyed services
+	Path        []ResolutionFrame
+	Cause       error
+}
+
+func (e ContextCancelledError) Error() string {
+	if len(e.Path) > 0 {
+		return fmt.Sprintf("construction cancelled at %s: %v", formatResolutionPath(e.Path), e.Cause)
+	}
+	return fmt.Sprintf("construction of %s cancelled: %v", formatType(e.ServiceType), e.Cause)
+}
+
+func (e ContextCancelledError) Unwrap() error {
+	return e.Cause
+}
+
 // RegistrationError wraps errors during service registration.
 type RegistrationError struct {
 	ServiceType reflect.Type
@@ -373,6 +956,69 @@ func (e ConstructorPanicError) Error() string {
 	return b.String()
 }
 
+// DecoratorError indicates that a decorator registered with ModuleDecorate
+// failed: it returned an error, panicked, or its dependencies could not be
+// resolved. Resolution returns it as the Cause of a ResolutionError whose
+// path ends at the decorated service.
+type DecoratorError struct {
+	Decorator   reflect.Type // the decorator function's type
+	ServiceType reflect.Type // the decorated type
+	Module      string       // the module the decorator was registered in
+	Cause       error
+}
+
+func (e DecoratorError) Error() string {
+	return fmt.Sprintf("decorator %s of %s in module %q failed: %v",
+		formatType(e.Decorator), formatType(e.ServiceType), e.Module, e.Cause)
+}
+
+func (e DecoratorError) Unwrap() error {
+	return e.Cause
+}
+
+// ConsumerPanicError indicates that a handler wrapped with WrapConsumer
+// panicked while processing a message.
+type ConsumerPanicError struct {
+	Message reflect.Type // message type of the handler
+	Panic   any
+	Stack   []byte
+}
+
+func (e ConsumerPanicError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "handler for %s panicked: %v\n", formatType(e.Message), e.Panic)
+
+	if len(e.Stack) > 0 {
+		b.WriteString("\nStack trace:\n")
+		b.Write(e.Stack)
+	}
+
+	return b.String()
+}
+
+// ContextValueError indicates that a service registered with
+// godi.FromContextValue found no usable value in the scope's context.
+type ContextValueError struct {
+	Key         any
+	ServiceType reflect.Type
+	Found       reflect.Type // type of the value found, or nil if none
+}
+
+func (e ContextValueError) Error() string {
+	var b strings.Builder
+	if e.Found == nil {
+		fmt.Fprintf(&b, "context has no value for key %#v (want %s)\n\n", e.Key, formatType(e.ServiceType))
+	} else {
+		fmt.Fprintf(&b, "context value for key %#v is %s, not %s\n\n", e.Key, formatType(e.Found), formatType(e.ServiceType))
+	}
+
+	b.WriteString("To resolve this:\n")
+	b.WriteString("  • Store the value with context.WithValue before creating the scope\n")
+	b.WriteString("  • Resolve the service from that scope rather than from the provider\n")
+
+	return b.String()
+}
+
 // BuildError wraps errors that occur during provider building
 type BuildError struct {
 	Phase   string // "validation", "graph", "singleton-creation", etc.
@@ -414,61 +1060,5 @@ func (e DisposalError) Unwrap() []error {
 
 // formatType formats a reflect.Type for error messages.
 func formatType(t reflect.Type) string {
-	if t == nil {
-		return "<nil>"
-	}
-
-	// Handle common cases with cleaner output
-	switch t.Kind() {
-	case reflect.Pointer:
-		// Format pointers as *Type instead of *package.Type
-		elem := t.Elem()
-		if elem.PkgPath() != "" && elem.Name() != "" {
-			// Named type with package
-			return "*" + elem.Name()
-		}
-		return t.String()
-	case reflect.Slice:
-		// Format slices as []Type
-		elem := t.Elem()
-		if elem.PkgPath() != "" && elem.Name() != "" {
-			// Named type with package
-			return "[]" + elem.Name()
-		}
-		return t.String()
-	case reflect.Map:
-		// Format maps more concisely
-		key := t.Key()
-		elem := t.Elem()
-		keyStr := key.Name()
-		if keyStr == "" {
-			keyStr = key.String()
-		}
-		elemStr := elem.Name()
-		if elemStr == "" {
-			elemStr = elem.String()
-		}
-		return "map[" + keyStr + "]" + elemStr
-	case reflect.Interface:
-		// For interfaces, just use the name if available
-		if t.Name() != "" {
-			return t.Name()
-		}
-		return t.String()
-	case reflect.Struct:
-		// For structs, use the short name if available
-		if t.Name() != "" {
-			return t.Name()
-		}
-		return t.String()
-	case reflect.Func:
-		// For functions, use String() which gives a nice representation
-		return t.String()
-	default:
-		// For basic types and others, prefer the name if available
-		if t.Name() != "" {
-			return t.Name()
-		}
-		return t.String()
-	}
+	return reflection.FormatType(t)
 }
@@ -39,6 +39,7 @@ func TestErrors(t *testing.T) {
 		assert.Contains(t, errStr, "Scoped")
 		assert.Contains(t, errStr, "cannot depend on")
 		assert.Contains(t, errStr, "To resolve this")
+		assert.Contains(t, errStr, "godi.ScopedAccessor[")
 	})
 
 	t.Run("AlreadyRegisteredError", func(t *testing.T) {
@@ -76,6 +77,22 @@ func TestErrors(t *testing.T) {
 			assert.ErrorIs(t, err, baseCause)
 		})
 
+		t.Run("with_path", func(t *testing.T) {
+			t.Parallel()
+			err := ResolutionError{
+				ServiceType: svcType,
+				Cause:       baseCause,
+				Path: []ResolutionFrame{
+					{ServiceType: reflect.TypeFor[*TServiceWithDeps](), Lifetime: Scoped, Registered: true},
+					{ServiceType: svcType, ServiceKey: "primary", Lifetime: Transient, Registered: true},
+				},
+			}
+			errStr := err.Error()
+			assert.Contains(t, errStr, "*TServiceWithDeps (Scoped) -> *TService[primary] (Transient): ")
+			assert.NotContains(t, errStr, "Make sure the service is registered")
+			assert.ErrorIs(t, err, baseCause)
+		})
+
 		t.Run("actionable_message", func(t *testing.T) {
 			t.Parallel()
 			err := ResolutionError{
@@ -86,6 +103,111 @@ func TestErrors(t *testing.T) {
 		})
 	})
 
+	t.Run("GroupMemberError", func(t *testing.T) {
+		t.Parallel()
+		err := GroupMemberError{
+			Group:        "handlers",
+			ElementTypes: []reflect.Type{reflect.TypeFor[TInterface](), reflect.TypeFor[*TDependency]()},
+			MemberType:   svcType,
+			Source:       "constructor func() *TService",
+		}
+		errStr := err.Error()
+		assert.Contains(t, errStr, `group "handlers"`)
+		assert.Contains(t, errStr, "[]TInterface, []*TDependency")
+		assert.Contains(t, errStr, "godi.As[TInterface]()")
+		assert.NotContains(t, errStr, "godi.As[*TDependency]()")
+	})
+
+	t.Run("ServiceLocatorError", func(t *testing.T) {
+		t.Parallel()
+		err := ServiceLocatorError{
+			ServiceType: svcType,
+			ServiceKey:  "primary",
+			Parameter:   reflect.TypeFor[Provider](),
+			Source:      "constructor func(godi.Provider) *TService",
+		}
+		errStr := err.Error()
+		assert.Contains(t, errStr, "*TService[primary] takes Provider")
+		assert.Contains(t, errStr, "ServiceLocatorAllowlist")
+	})
+
+	t.Run("LayerViolationError", func(t *testing.T) {
+		t.Parallel()
+		err := LayerViolationError{
+			From:    "handlers",
+			To:      "repositories",
+			Allowed: []string{"services"},
+			Path:    []reflect.Type{svcType, depType},
+			Source:  "constructor func(*TDependency) *TService",
+		}
+		errStr := err.Error()
+		assert.Contains(t, errStr, `"handlers" may not depend on "repositories": *TService -> *TDependency`)
+		assert.Contains(t, errStr, "may only depend on: services")
+		assert.Contains(t, errStr, `Add "repositories" to the DependsOn of layer "handlers"`)
+	})
+
+	t.Run("ExportNameConflictError", func(t *testing.T) {
+		t.Parallel()
+		err := ExportNameConflictError{
+			Name:         "users",
+			ServiceTypes: []reflect.Type{svcType, reflect.TypeFor[*TDependency]()},
+			Sources:      []string{"constructor func() *TService", "constructor func() *TDependency"},
+		}
+		errStr := err.Error()
+		assert.Contains(t, errStr, `export name "users" is used by 2 registrations`)
+		assert.Contains(t, errStr, "*TDependency (constructor func() *TDependency)")
+	})
+
+	t.Run("ExportNameNotFoundError", func(t *testing.T) {
+		t.Parallel()
+		err := &ExportNameNotFoundError{Name: "user", Available: []string{"orders", "users"}}
+		assert.Contains(t, err.Error(), `no service exported as "user" (exported names: orders, users)`)
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("InjectError", func(t *testing.T) {
+		t.Parallel()
+		err := &InjectError{Target: reflect.TypeFor[*TServiceWithDeps](), Field: "Dep", Cause: baseCause}
+		assert.Equal(t, "failed to inject field Dep of *TServiceWithDeps: base error", err.Error())
+		assert.ErrorIs(t, err, baseCause)
+	})
+
+	t.Run("ContextValueError", func(t *testing.T) {
+		t.Parallel()
+		missing := ContextValueError{Key: "requestID", ServiceType: reflect.TypeFor[string]()}
+		assert.Contains(t, missing.Error(), `context has no value for key "requestID" (want string)`)
+		assert.Contains(t, missing.Error(), "context.WithValue")
+
+		mistyped := ContextValueError{Key: "tenant", ServiceType: reflect.TypeFor[int](), Found: reflect.TypeFor[string]()}
+		assert.Contains(t, mistyped.Error(), `context value for key "tenant" is string, not int`)
+	})
+
+	t.Run("CapabilityError", func(t *testing.T) {
+		t.Parallel()
+		err := &CapabilityError{ServiceType: svcType, ServiceKey: "primary"}
+		assert.Contains(t, err.Error(), "*TService[primary] is not exposed by this sealed provider")
+		assert.Contains(t, err.Error(), "godi.AllowServices")
+		assert.ErrorIs(t, err, ErrProviderSealed)
+
+		byName := &CapabilityError{Name: "billing"}
+		assert.Contains(t, byName.Error(), `service exported as "billing" is not exposed`)
+	})
+
+	t.Run("PrivateServiceError", func(t *testing.T) {
+		t.Parallel()
+		direct := &PrivateServiceError{ServiceType: svcType, Module: "billing"}
+		assert.Contains(t, direct.Error(), `*TService is private to module "billing" and cannot be resolved directly`)
+		assert.ErrorIs(t, direct, ErrServiceNotFound)
+
+		consumer := PrivateServiceError{
+			ServiceType:    svcType,
+			Module:         "billing",
+			Consumer:       depType,
+			ConsumerSource: "constructor func(*TService) *TDependency",
+		}
+		assert.Contains(t, consumer.Error(), `*TDependency depends on *TService, which is private to module "billing"`)
+	})
+
 	t.Run("TimeoutError", func(t *testing.T) {
 		t.Parallel()
 		err := TimeoutError{
@@ -264,6 +386,34 @@ func TestErrors(t *testing.T) {
 		assert.Contains(t, errMsg, "Stack trace")
 	})
 
+	t.Run("ConsumerPanicError", func(t *testing.T) {
+		t.Parallel()
+		err := &ConsumerPanicError{
+			Message: reflect.TypeFor[string](),
+			Panic:   "poison",
+			Stack:   []byte("goroutine 1 [running]:"),
+		}
+		errMsg := err.Error()
+		assert.Contains(t, errMsg, "handler for string panicked: poison")
+		assert.Contains(t, errMsg, "Stack trace")
+	})
+
+	t.Run("OptionalFailureError", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		var err error = &OptionalFailureError{
+			ServiceType:    reflect.TypeFor[*TService](),
+			Field:          "Cache",
+			DependencyType: reflect.TypeFor[*TDependency](),
+			ScopeID:        "s1",
+			Cause:          boom,
+		}
+		assert.Equal(t, "optional field Cache of *TService was left nil in scope s1: *TDependency failed: boom", err.Error())
+		assert.ErrorIs(t, err, boom)
+		_, ok := errors.AsType[*OptionalFailureError](err)
+		assert.True(t, ok)
+	})
+
 	t.Run("ErrorWrapping", func(t *testing.T) {
 		t.Parallel()
 		wrappers := []error{
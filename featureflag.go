@@ -0,0 +1,111 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// FeatureGate decides whether a feature flag is on. Set it with
+// ProviderOptions.FeatureGate to switch between the implementations of a
+// service registered with godi.Flagged, typically backed by a feature-flag
+// service for canary rollouts.
+//
+// Enabled is called on every resolution of a service with flagged
+// registrations, with the context of the scope resolving it, so it should
+// answer from memory rather than make a network call.
+type FeatureGate interface {
+	Enabled(ctx context.Context, flag string) bool
+}
+
+// Flagged is an AddOption that registers an implementation of a service to
+// be used only while the feature flag is on. The service must also be
+// registered without Flagged, under the same type and name and with the same
+// lifetime; that registration is resolved while the flag is off. Consumers
+// depend on the service as usual and never see the flag.
+//
+// Example:
+//
+//	services.AddScoped(NewLegacyBilling, godi.As[Billing]())
+//	services.AddScoped(NewStripeBilling, godi.As[Billing](), godi.Flagged("new-billing"))
+//
+// The flag is checked every time the service is resolved, and each
+// implementation is cached under its own lifetime: once the flag flips, a
+// scope resolves the other implementation, while services constructed
+// before keep the one they were given. Both implementations of a singleton
+// are built with the provider. If several flagged registrations are on, the
+// first registered wins. Without ProviderOptions.FeatureGate every flag is
+// off.
+func Flagged(flag string) AddOption {
+	return flaggedOption(flag)
+}
+
+type flaggedOption string
+
+func (o flaggedOption) String() string {
+	return "Flagged(" + strconv.Quote(string(o)) + ")"
+}
+
+func (o flaggedOption) applyAddOption(opts *addOptions) {
+	opts.flag = string(o)
+	opts.hasFlag = true
+}
+
+// flagKey is the key a flagged registration is stored under, so that it
+// does not collide with the unflagged registration of its type and key.
+type flagKey struct {
+	flag string
+	key  any
+}
+
+func (k flagKey) String() string {
+	if k.key == nil {
+		return fmt.Sprintf("Flagged(%q)", k.flag)
+	}
+	return fmt.Sprintf("%v, Flagged(%q)", k.key, k.flag)
+}
+
+// applyFeatureFlags attaches each flagged registration to the unflagged one
+// it replaces while its flag is on.
+func applyFeatureFlags(all []*descriptor, services map[TypeKey]*descriptor) error {
+	for _, d := range all {
+		if d == nil || d.flag == "" {
+			continue
+		}
+		key := d.Key.(flagKey)
+		fallback := services[TypeKey{Type: d.Type, Key: key.key}]
+		if fallback == nil {
+			return &ValidationError{
+				ServiceType: d.Type,
+				Cause: fmt.Errorf("registered with godi.Flagged(%q) but not without it, so nothing is resolved while the flag is off",
+					d.flag),
+			}
+		}
+		if fallback.Lifetime != d.Lifetime {
+			return &ValidationError{
+				ServiceType: d.Type,
+				Cause: fmt.Errorf("registered with godi.Flagged(%q) as %s but without it as %s; both must have the same lifetime",
+					d.flag, d.Lifetime, fallback.Lifetime),
+			}
+		}
+		fallback.flagged = append(fallback.flagged, d)
+	}
+	return nil
+}
+
+// selectFlagged returns the registration of the service registered as
+// descriptor to resolve in s, and the key to cache its instance under: the
+// first of its flagged registrations whose flag is on, or descriptor itself.
+func (s *scope) selectFlagged(key instanceKey, descriptor *descriptor) (instanceKey, *descriptor) {
+	gate := s.rootProvider.featureGate
+	if gate == nil {
+		return key, descriptor
+	}
+	ctx := s.resolvedContext()
+	for _, variant := range descriptor.flagged {
+		if gate.Enabled(ctx, variant.flag) {
+			return instanceKey{Type: key.Type, Key: variant.Key}, variant
+		}
+	}
+	return key, descriptor
+}
@@ -0,0 +1,195 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGate turns on the flags whose switch is set.
+type testGate map[string]*atomic.Bool
+
+func (g testGate) Enabled(_ context.Context, flag string) bool {
+	on, ok := g[flag]
+	return ok && on.Load()
+}
+
+func TestFlagged(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, gate FeatureGate, opts ...ModuleOption) Provider {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(opts...)
+		p, err := c.BuildWithOptions(&ProviderOptions{FeatureGate: gate})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("resolves_the_flagged_registration_while_the_flag_is_on", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		p := build(t, testGate{"new-deps": on},
+			AddScoped(NewTDependencyWithName("legacy")),
+			AddScoped(NewTDependencyWithName("new"), Flagged("new-deps")),
+		)
+
+		assert.Equal(t, "legacy", RequireResolveFrom[*TDependency](t, NewTestScope(t, p)).Name)
+		on.Store(true)
+		assert.Equal(t, "new", RequireResolveFrom[*TDependency](t, NewTestScope(t, p)).Name)
+		on.Store(false)
+		assert.Equal(t, "legacy", RequireResolveFrom[*TDependency](t, NewTestScope(t, p)).Name)
+	})
+
+	t.Run("consumers_get_the_flagged_dependency", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		on.Store(true)
+		p := build(t, testGate{"new-deps": on},
+			AddScoped(NewTService),
+			AddScoped(NewTDependencyWithName("legacy")),
+			AddScoped(NewTDependencyWithName("new"), Flagged("new-deps")),
+			AddScoped(NewTServiceWithDeps),
+		)
+
+		s := NewTestScope(t, p)
+		svc := RequireResolveFrom[*TServiceWithDeps](t, s)
+		assert.Equal(t, "new", svc.Dep.Name)
+		assert.Same(t, svc.Dep, RequireResolveFrom[*TDependency](t, s))
+	})
+
+	t.Run("scoped_instances_keep_their_implementation", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		p := build(t, testGate{"new-deps": on},
+			AddScoped(NewTDependencyWithName("legacy")),
+			AddScoped(NewTDependencyWithName("new"), Flagged("new-deps")),
+		)
+
+		s := NewTestScope(t, p)
+		first := RequireResolveFrom[*TDependency](t, s)
+		on.Store(true)
+		// The flagged registration is cached separately, so the scope now
+		// resolves it, while the instance resolved before stays intact.
+		assert.Equal(t, "new", RequireResolveFrom[*TDependency](t, s).Name)
+		assert.Equal(t, "legacy", first.Name)
+	})
+
+	t.Run("singletons_switch_between_built_instances", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		var builds atomic.Int32
+		p := build(t, testGate{"new-deps": on},
+			AddSingleton(func() *TDependency { builds.Add(1); return &TDependency{Name: "legacy"} }),
+			AddSingleton(func() *TDependency { builds.Add(1); return &TDependency{Name: "new"} }, Flagged("new-deps")),
+		)
+		assert.Equal(t, int32(2), builds.Load())
+
+		legacy := RequireResolve[*TDependency](t, p)
+		on.Store(true)
+		assert.Equal(t, "new", RequireResolve[*TDependency](t, p).Name)
+		on.Store(false)
+		assert.Same(t, legacy, RequireResolve[*TDependency](t, p))
+	})
+
+	t.Run("first_flag_on_wins", func(t *testing.T) {
+		t.Parallel()
+		a, b := &atomic.Bool{}, &atomic.Bool{}
+		a.Store(true)
+		b.Store(true)
+		p := build(t, testGate{"a": a, "b": b},
+			AddTransient(NewTDependencyWithName("legacy")),
+			AddTransient(NewTDependencyWithName("a"), Flagged("a")),
+			AddTransient(NewTDependencyWithName("b"), Flagged("b")),
+		)
+
+		assert.Equal(t, "a", RequireResolve[*TDependency](t, p).Name)
+		a.Store(false)
+		assert.Equal(t, "b", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("keyed_and_interface_registrations", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		on.Store(true)
+		p := build(t, testGate{"new-deps": on},
+			AddTransient(NewTDependencyWithName("legacy"), Name("primary")),
+			AddTransient(NewTDependencyWithName("new"), Name("primary"), Flagged("new-deps")),
+			AddTransient(NewTServiceWithID("legacy"), As[TInterface]()),
+			AddTransient(NewTServiceWithID("new"), As[TInterface](), Flagged("new-deps")),
+		)
+
+		dep, err := ResolveKeyed[*TDependency](p, "primary")
+		require.NoError(t, err)
+		assert.Equal(t, "new", dep.Name)
+		assert.Equal(t, "new", RequireResolve[TInterface](t, p).GetID())
+	})
+
+	t.Run("without_a_gate_every_flag_is_off", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, nil,
+			AddTransient(NewTDependencyWithName("legacy")),
+			AddTransient(NewTDependencyWithName("new"), Flagged("new-deps")),
+		)
+		assert.Equal(t, "legacy", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("pruning_keeps_flagged_registrations", func(t *testing.T) {
+		t.Parallel()
+		on := &atomic.Bool{}
+		on.Store(true)
+		c := NewCollection()
+		c.AddSingleton(NewTService)
+		c.AddSingleton(NewTDependencyWithName("legacy"))
+		c.AddSingleton(NewTDependencyWithName("new"), Flagged("new-deps"))
+		c.AddSingleton(NewTServiceWithDeps)
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			FeatureGate:      testGate{"new-deps": on},
+			PruneUnreachable: true,
+			Roots:            []reflect.Type{PtrTypeOf[TServiceWithDeps]()},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.Equal(t, "new", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		for name, tc := range map[string]struct {
+			opts    []ModuleOption
+			message string
+		}{
+			"no_fallback": {
+				[]ModuleOption{AddScoped(NewTDependency, Flagged("new-deps"))},
+				`registered with godi.Flagged("new-deps") but not without it`,
+			},
+			"lifetime_mismatch": {
+				[]ModuleOption{AddSingleton(NewTDependency), AddScoped(NewTDependency, Flagged("new-deps"))},
+				"both must have the same lifetime",
+			},
+			"empty_flag": {
+				[]ModuleOption{AddScoped(NewTDependency, Flagged(""))},
+				"needs the name of a feature flag",
+			},
+			"group": {
+				[]ModuleOption{AddScoped(NewTDependency, Group("deps"), Flagged("new-deps"))},
+				"cannot be used with godi.Group",
+			},
+		} {
+			c := NewCollection()
+			c.AddModules(tc.opts...)
+			_, err := c.Build()
+			var validation *ValidationError
+			if assert.True(t, errors.As(err, &validation), "%s: got %v", name, err) {
+				assert.Contains(t, err.Error(), tc.message, name)
+			}
+		}
+	})
+}
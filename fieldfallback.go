@@ -0,0 +1,148 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// WithFieldFallback is an AddOption that gives the registered constructor a
+// default for its dependency of type T: when no service of type T is
+// registered, constructor is called to produce one instead of failing or,
+// for an optional In field, leaving the zero value. It applies to unkeyed
+// dependencies of type T, whether taken as parameters or as In struct fields.
+//
+// The fallback constructor must return a value assignable to T, optionally
+// followed by an error, and may take dependencies of its own other than T.
+// It is called every time the registration's constructor needs it, and its
+// result is not cached or disposed by the container. A registered service of
+// type T, including one from ProviderOptions.Fallback, always takes
+// precedence.
+//
+// Example:
+//
+//	type Params struct {
+//	    godi.In
+//	    Logger Logger `optional:"true"`
+//	}
+//
+//	services.AddScoped(NewHandler, godi.WithFieldFallback[Logger](NewNopLogger))
+func WithFieldFallback[T any](constructor any) AddOption {
+	return fieldFallbackOption{serviceType: reflect.TypeFor[T](), constructor: constructor}
+}
+
+type fieldFallbackOption struct {
+	serviceType reflect.Type
+	constructor any
+}
+
+func (o fieldFallbackOption) String() string {
+	return fmt.Sprintf("WithFieldFallback[%s](%T)", formatType(o.serviceType), o.constructor)
+}
+
+func (o fieldFallbackOption) applyAddOption(opts *addOptions) {
+	opts.fieldFallbacks = append(opts.fieldFallbacks, o)
+}
+
+// fieldFallback is a validated WithFieldFallback constructor.
+type fieldFallback struct {
+	info *reflection.ConstructorInfo
+}
+
+// newFieldFallbacks validates the WithFieldFallback options of d and
+// indexes them by service type.
+func newFieldFallbacks(d *descriptor, opts []fieldFallbackOption, analyzer *reflection.Analyzer) (map[reflect.Type]*fieldFallback, error) {
+	invalid := func(format string, args ...any) error {
+		return &ValidationError{ServiceType: d.Type, Cause: fmt.Errorf(format, args...)}
+	}
+	if d.IsInstance {
+		return nil, invalid("godi.WithFieldFallback requires a constructor, not a value")
+	}
+
+	fallbacks := make(map[reflect.Type]*fieldFallback, len(opts))
+	for _, opt := range opts {
+		name := opt.String()
+		if _, dup := fallbacks[opt.serviceType]; dup {
+			return nil, invalid("%s: more than one fallback for %s", name, formatType(opt.serviceType))
+		}
+		if opt.constructor == nil || reflect.TypeOf(opt.constructor).Kind() != reflect.Func {
+			return nil, invalid("%s: fallback must be a constructor function", name)
+		}
+		if !dependsOn(d.Dependencies, opt.serviceType) {
+			return nil, invalid("%s: constructor has no unkeyed dependency on %s", name, formatType(opt.serviceType))
+		}
+
+		info, err := analyzer.Analyze(opt.constructor)
+		if err != nil {
+			return nil, &ReflectionAnalysisError{Constructor: opt.constructor, Operation: "analyze", Cause: err}
+		}
+		fnType := info.Type
+		if info.IsResultObject || fnType.NumOut() == 0 || fnType.NumOut() > 2 ||
+			(fnType.NumOut() == 2 && !info.HasErrorReturn) ||
+			!fnType.Out(0).AssignableTo(opt.serviceType) {
+			return nil, invalid("%s: fallback must return %s, optionally followed by an error", name, formatType(opt.serviceType))
+		}
+		if dependsOn(info.Dependencies(), opt.serviceType) {
+			return nil, invalid("%s: fallback cannot depend on %s itself", name, formatType(opt.serviceType))
+		}
+		fallbacks[opt.serviceType] = &fieldFallback{info: info}
+	}
+	return fallbacks, nil
+}
+
+// dependsOn reports whether deps include an unkeyed dependency on t.
+func dependsOn(deps []*reflection.Dependency, t reflect.Type) bool {
+	for _, dep := range deps {
+		if dep != nil && dep.Type == t && dep.Key == nil && dep.Group == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldFallbackFor returns the fallback r's constructor declared for its
+// dependency on serviceType, if that dependency failed only because no such
+// service is registered.
+func (r *resolution) fieldFallbackFor(serviceType reflect.Type, err error) *fieldFallback {
+	if r.top() || r.descriptor.fieldFallbacks == nil {
+		return nil
+	}
+	fallback := r.descriptor.fieldFallbacks[serviceType]
+	if fallback == nil {
+		return nil
+	}
+	// A missing transitive dependency is reported for its own type and is a
+	// construction failure of serviceType, which must propagate.
+	resErr, ok := err.(*ResolutionError)
+	if !ok || resErr.ServiceType != serviceType || !resErr.ServiceNotFound() {
+		return nil
+	}
+	return fallback
+}
+
+// invokeFieldFallback calls fallback on behalf of r's constructor.
+func (r *resolution) invokeFieldFallback(serviceType reflect.Type, fallback *fieldFallback) (any, error) {
+	results, err := r.scope.rootProvider.analyzer.GetInvoker().Invoke(fallback.info, r)
+	if err == nil && isNilServiceResult(results[0]) {
+		err = errors.New("fallback returned nil")
+	}
+	if err != nil {
+		if panicErr, ok := errors.AsType[*reflection.PanicError](err); ok {
+			err = &ConstructorPanicError{
+				Constructor: fallback.info.Type,
+				Panic:       panicErr.Panic,
+				Stack:       panicErr.Stack,
+			}
+		} else {
+			err = &ConstructorInvocationError{
+				Constructor: fallback.info.Type,
+				Parameters:  extractParameterTypes(fallback.info),
+				Cause:       err,
+			}
+		}
+		return nil, r.dependencyError(instanceKey{Type: serviceType}, nil, err)
+	}
+	return results[0].Interface(), nil
+}
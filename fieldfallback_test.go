@@ -0,0 +1,135 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fallbackLogger interface{ Log(string) string }
+
+type nopFallbackLogger struct{ prefix string }
+
+func (l *nopFallbackLogger) Log(msg string) string { return l.prefix + msg }
+
+type fallbackHandler struct{ logger fallbackLogger }
+
+func TestWithFieldFallback(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		In
+		Logger fallbackLogger `optional:"true"`
+	}
+	newHandler := func(p params) *fallbackHandler { return &fallbackHandler{logger: p.Logger} }
+	newNop := func() *nopFallbackLogger { return &nopFallbackLogger{prefix: "nop:"} }
+
+	t.Run("fills_unregistered_optional_field", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, AddScoped(newHandler, WithFieldFallback[fallbackLogger](newNop)))
+
+		h := RequireResolve[*fallbackHandler](t, s)
+		require.NotNil(t, h.logger)
+		assert.Equal(t, "nop:hi", h.logger.Log("hi"))
+	})
+
+	t.Run("registered_service_wins", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			AddScoped(newHandler, WithFieldFallback[fallbackLogger](newNop)),
+			AddSingleton(func() fallbackLogger { return &nopFallbackLogger{prefix: "real:"} }),
+		)
+
+		assert.Equal(t, "real:hi", RequireResolve[*fallbackHandler](t, s).logger.Log("hi"))
+	})
+
+	t.Run("applies_to_parameters", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			AddTransient(func(l fallbackLogger) *fallbackHandler { return &fallbackHandler{logger: l} },
+				WithFieldFallback[fallbackLogger](newNop)),
+		)
+
+		assert.Equal(t, "nop:hi", RequireResolve[*fallbackHandler](t, s).logger.Log("hi"))
+	})
+
+	t.Run("fallback_takes_dependencies", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			AddScoped(newHandler, WithFieldFallback[fallbackLogger](func(dep *TDependency) fallbackLogger {
+				return &nopFallbackLogger{prefix: dep.Name + ":"}
+			})),
+			AddSingleton(NewTDependency),
+		)
+
+		assert.Equal(t, "dep:hi", RequireResolve[*fallbackHandler](t, s).logger.Log("hi"))
+	})
+
+	t.Run("fallback_errors_propagate", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("no log sink")
+		s := BuildScope(t,
+			AddScoped(newHandler, WithFieldFallback[fallbackLogger](func() (fallbackLogger, error) {
+				return nil, boom
+			})),
+		)
+
+		_, err := Resolve[*fallbackHandler](s)
+		require.ErrorIs(t, err, boom)
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.Len(t, resErr.Path, 2)
+	})
+
+	t.Run("broken_registration_is_not_replaced", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			AddScoped(newHandler, WithFieldFallback[fallbackLogger](newNop)),
+			AddScoped(func(*TDependency) fallbackLogger { return &nopFallbackLogger{} }),
+		)
+
+		_, err := Resolve[*fallbackHandler](s)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.Contains(t, err.Error(), "*TDependency")
+	})
+
+	t.Run("invalid_fallbacks", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name string
+			add  func(Collection)
+			want string
+		}{
+			{"no_dependency", func(c Collection) {
+				c.AddScoped(NewTDependency, WithFieldFallback[fallbackLogger](newNop))
+			}, "no unkeyed dependency"},
+			{"wrong_return_type", func(c Collection) {
+				c.AddScoped(newHandler, WithFieldFallback[fallbackLogger](NewTDependency))
+			}, "fallback must return"},
+			{"not_a_function", func(c Collection) {
+				c.AddScoped(newHandler, WithFieldFallback[fallbackLogger](&nopFallbackLogger{}))
+			}, "must be a constructor function"},
+			{"depends_on_itself", func(c Collection) {
+				c.AddScoped(newHandler, WithFieldFallback[fallbackLogger](func(l fallbackLogger) fallbackLogger { return l }))
+			}, "cannot depend on"},
+			{"duplicate", func(c Collection) {
+				c.AddScoped(newHandler,
+					WithFieldFallback[fallbackLogger](newNop),
+					WithFieldFallback[fallbackLogger](newNop))
+			}, "more than one fallback"},
+			{"value_registration", func(c Collection) {
+				c.AddValue(&fallbackHandler{}, WithFieldFallback[fallbackLogger](newNop))
+			}, "requires a constructor"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				tt.add(c)
+				require.ErrorContains(t, c.Err(), tt.want)
+			})
+		}
+	})
+}
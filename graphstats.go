@@ -0,0 +1,215 @@
+package godi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// GraphStats summarizes the shape of a dependency graph so teams can track
+// its complexity over time, e.g. failing CI when a service's fan-out grows
+// past a limit.
+//
+// Edges run from a service to the registrations satisfying its parameters,
+// so group dependencies count every member. Built-in types and missing
+// optional dependencies are not edges.
+type GraphStats struct {
+	// Services has one entry per registration, in registration order.
+	Services []ServiceStats
+
+	// MaxFanIn, MaxFanOut and MaxDepth are the largest values in Services.
+	MaxFanIn  int
+	MaxFanOut int
+	MaxDepth  int
+
+	// Cycles lists the strongly connected components of more than one
+	// service, and services depending on themselves. Build rejects cycles,
+	// so it is only non-empty for collections that failed to build.
+	Cycles [][]ServiceInfo
+}
+
+// ServiceStats describes one registration in GraphStats.
+type ServiceStats struct {
+	Service ServiceInfo
+
+	// FanIn is the number of registrations depending on the service
+	// directly, FanOut the number of registrations it depends on directly.
+	FanIn  int
+	FanOut int
+
+	// Depth is the length of the longest dependency chain below the
+	// service: 0 without dependencies. The services of a cycle count as
+	// one step.
+	Depth int
+}
+
+// GraphStatsOf returns the statistics of the dependency graph GraphOf
+// reports for p.
+//
+// Example:
+//
+//	stats, err := godi.GraphStatsOf(provider)
+//	if err == nil && stats.MaxDepth > 8 {
+//	    log.Printf("dependency chains are getting long: %v", stats)
+//	}
+func GraphStatsOf(p Provider) (*GraphStats, error) {
+	view, err := GraphOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return NewGraphStats(view), nil
+}
+
+// NewGraphStats computes the statistics of view, such as the graph of a
+// collection that is not built yet.
+//
+// Example:
+//
+//	stats := godi.NewGraphStats(services.Graph())
+//	for _, s := range stats.Services {
+//	    if s.FanOut > 15 {
+//	        t.Errorf("%v depends on %d services", s.Service.ServiceType, s.FanOut)
+//	    }
+//	}
+func NewGraphStats(view GraphView) *GraphStats {
+	services := view.Services()
+	index := make(map[ServiceInfo]int, len(services))
+	for i, info := range services {
+		if _, seen := index[info]; !seen {
+			index[info] = i
+		}
+	}
+
+	// edges[i] holds the distinct registrations service i depends on.
+	edges := make([][]int, len(services))
+	stats := &GraphStats{Services: make([]ServiceStats, len(services))}
+	for i, info := range services {
+		stats.Services[i].Service = info
+		seen := make(map[int]bool)
+		for _, dep := range view.Dependencies(info) {
+			for _, provider := range view.Providers(dep) {
+				j, ok := index[provider]
+				if !ok || seen[j] {
+					continue
+				}
+				seen[j] = true
+				edges[i] = append(edges[i], j)
+			}
+		}
+		stats.Services[i].FanOut = len(edges[i])
+		for _, j := range edges[i] {
+			stats.Services[j].FanIn++
+		}
+	}
+
+	component, components := stronglyConnected(edges)
+	for _, members := range components {
+		selfLoop := len(members) == 1 && slices.Contains(edges[members[0]], members[0])
+		if len(members) > 1 || selfLoop {
+			cycle := make([]ServiceInfo, len(members))
+			for k, m := range members {
+				cycle[k] = services[m]
+			}
+			stats.Cycles = append(stats.Cycles, cycle)
+		}
+	}
+
+	// Depths over the graph of components, which has no cycles. Tarjan's
+	// algorithm numbers components dependencies first.
+	depths := make([]int, len(components))
+	for c, members := range components {
+		for _, m := range members {
+			for _, j := range edges[m] {
+				if component[j] != c {
+					depths[c] = max(depths[c], depths[component[j]]+1)
+				}
+			}
+		}
+	}
+
+	for i := range stats.Services {
+		s := &stats.Services[i]
+		s.Depth = depths[component[i]]
+		stats.MaxFanIn = max(stats.MaxFanIn, s.FanIn)
+		stats.MaxFanOut = max(stats.MaxFanOut, s.FanOut)
+		stats.MaxDepth = max(stats.MaxDepth, s.Depth)
+	}
+	return stats
+}
+
+// String returns a table of the statistics.
+func (s *GraphStats) String() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%d services: max fan-in %d, max fan-out %d, max depth %d, %d cycles\n",
+		len(s.Services), s.MaxFanIn, s.MaxFanOut, s.MaxDepth, len(s.Cycles))
+	fmt.Fprintln(tw, "TYPE\tKEY\tGROUP\tFAN-IN\tFAN-OUT\tDEPTH")
+	for _, stats := range s.Services {
+		key, group := "-", "-"
+		if stats.Service.Key != nil {
+			key = fmt.Sprint(stats.Service.Key)
+		}
+		if stats.Service.Group != "" {
+			group = stats.Service.Group
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\n",
+			formatType(stats.Service.ServiceType), key, group, stats.FanIn, stats.FanOut, stats.Depth)
+	}
+	_ = tw.Flush()
+	return b.String()
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// given by edges, using Tarjan's algorithm: component[i] is the index of
+// node i's component, and components lists the nodes of each, in an order
+// where a component comes after every component it has edges to.
+func stronglyConnected(edges [][]int) (component []int, components [][]int) {
+	n := len(edges)
+	component = make([]int, n)
+	order := make([]int, n) // discovery order, 1-based; 0 is unvisited
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	var stack []int
+	next := 0
+
+	var visit func(v int)
+	visit = func(v int) {
+		next++
+		order[v], low[v] = next, next
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range edges[v] {
+			switch {
+			case order[w] == 0:
+				visit(w)
+				low[v] = min(low[v], low[w])
+			case onStack[w]:
+				low[v] = min(low[v], order[w])
+			}
+		}
+
+		if low[v] == order[v] {
+			var members []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component[w] = len(components)
+				members = append(members, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, members)
+		}
+	}
+
+	for v := range n {
+		if order[v] == 0 {
+			visit(v)
+		}
+	}
+	return component, components
+}
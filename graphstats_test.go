@@ -0,0 +1,95 @@
+package godi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	statsCycleA struct{}
+	statsCycleB struct{}
+	statsTop    struct{}
+)
+
+func TestGraphStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fan_in_fan_out_and_depth", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			AddSingleton(NewTDependency),
+			AddSingleton(NewTServiceWithDeps),
+			AddSingleton(func(*TServiceWithDeps, *TService, context.Context) *statsTop { return &statsTop{} }),
+		)
+
+		stats, err := GraphStatsOf(p)
+		require.NoError(t, err)
+		require.Len(t, stats.Services, 4)
+
+		byType := make(map[any]ServiceStats)
+		for _, s := range stats.Services {
+			byType[s.Service.ServiceType] = s
+		}
+		svc := byType[PtrTypeOf[TService]()]
+		assert.Equal(t, 2, svc.FanIn)
+		assert.Equal(t, 0, svc.FanOut)
+		assert.Equal(t, 0, svc.Depth)
+
+		withDeps := byType[PtrTypeOf[TServiceWithDeps]()]
+		assert.Equal(t, 1, withDeps.FanIn)
+		assert.Equal(t, 2, withDeps.FanOut)
+		assert.Equal(t, 1, withDeps.Depth)
+
+		top := byType[PtrTypeOf[statsTop]()]
+		assert.Equal(t, 2, top.FanOut, "built-in types are not edges")
+		assert.Equal(t, 2, top.Depth)
+
+		assert.Equal(t, 2, stats.MaxFanIn)
+		assert.Equal(t, 2, stats.MaxFanOut)
+		assert.Equal(t, 2, stats.MaxDepth)
+		assert.Empty(t, stats.Cycles)
+		assert.Contains(t, stats.String(), "4 services: max fan-in 2, max fan-out 2, max depth 2, 0 cycles")
+	})
+
+	t.Run("cycles_count_as_one_step", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func(*statsCycleB) *statsCycleA { return &statsCycleA{} })
+		c.AddSingleton(func(*statsCycleA, *TService) *statsCycleB { return &statsCycleB{} })
+		c.AddSingleton(NewTService)
+		c.AddSingleton(func(*statsCycleA) *statsTop { return &statsTop{} })
+
+		stats := NewGraphStats(c.Graph())
+		require.Len(t, stats.Cycles, 1)
+		assert.ElementsMatch(t,
+			[]any{PtrTypeOf[statsCycleA](), PtrTypeOf[statsCycleB]()},
+			[]any{stats.Cycles[0][0].ServiceType, stats.Cycles[0][1].ServiceType})
+		assert.Equal(t, 1, stats.Services[0].Depth)
+		assert.Equal(t, 2, stats.Services[3].Depth)
+	})
+
+	t.Run("scope_reports_its_provider", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTService))
+
+		stats, err := GraphStatsOf(NewTestScope(t, p))
+		require.NoError(t, err)
+		assert.Len(t, stats.Services, 1)
+	})
+
+	t.Run("sealed", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t, AddSingleton(NewTService), AddSingleton(NewTDependency)),
+			AllowServices(PtrTypeOf[TService]()))
+		require.NoError(t, err)
+
+		stats, err := GraphStatsOf(sealed)
+		require.NoError(t, err)
+		require.Len(t, stats.Services, 1, "only the exposed services are counted")
+		assert.Equal(t, PtrTypeOf[TService](), stats.Services[0].Service.ServiceType)
+	})
+}
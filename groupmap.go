@@ -0,0 +1,128 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ResolveGroupMap resolves the members of a group like ResolveGroup, keyed
+// by the godi.Name they were registered with, so a dispatcher can look its
+// handlers up without keeping a registry of its own. Members registered
+// without a name are left out. Names are unique within a group; across the
+// providers joined by Compose, the first member with a name wins.
+//
+// Example:
+//
+//	services.AddSingleton(NewCreateHandler, godi.Group("handlers"), godi.Name("create"))
+//	services.AddSingleton(NewDeleteHandler, godi.Group("handlers"), godi.Name("delete"))
+//
+//	handlers, err := godi.ResolveGroupMap[Handler](provider, "handlers")
+//	handler := handlers[command.Kind]
+func ResolveGroupMap[T any](provider Provider, group string) (map[any]T, error) {
+	if provider == nil {
+		return nil, ErrProviderNil
+	}
+
+	if group == "" {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       ErrGroupNameEmpty,
+		}
+	}
+
+	resolver, ok := provider.(namedGroupResolver)
+	if !ok {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("unsupported provider implementation %T", provider),
+		}
+	}
+
+	serviceType := reflect.TypeFor[T]()
+	services, names, err := resolver.getNamedGroup(serviceType, group)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[any]T, len(services))
+	for i, service := range services {
+		if names[i] == "" {
+			continue
+		}
+		if _, exists := results[names[i]]; exists {
+			continue
+		}
+		result, ok := service.(T)
+		if !ok {
+			return nil, &TypeMismatchError{
+				Expected: serviceType,
+				Actual:   reflect.TypeOf(service),
+				Context:  fmt.Sprintf("type assertion for group item %q", names[i]),
+			}
+		}
+		results[names[i]] = result
+	}
+	return results, nil
+}
+
+// namedGroupResolver is implemented by the providers and scopes that can
+// report the names of group members.
+type namedGroupResolver interface {
+	// getNamedGroup resolves a group like GetGroup and returns the name of
+	// each member, or "" for members without one.
+	getNamedGroup(serviceType reflect.Type, group string) ([]any, []string, error)
+}
+
+func (p *provider) getNamedGroup(serviceType reflect.Type, group string) ([]any, []string, error) {
+	if p.disposed.Load() != 0 {
+		return nil, nil, p.disposedError(ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return p.rootScope.getNamedGroup(serviceType, group)
+}
+
+func (s *scope) getNamedGroup(serviceType reflect.Type, group string) ([]any, []string, error) {
+	instances, members, err := s.getGroupMembers(nil, serviceType, group)
+	if err != nil {
+		return nil, nil, s.translateError(err, ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return instances, memberNames(members, len(instances)), nil
+}
+
+func (v *sealedView) getNamedGroup(serviceType reflect.Type, group string) ([]any, []string, error) {
+	if serviceType != nil && !v.permits(serviceType) {
+		return nil, nil, v.scope.translateError(&CapabilityError{ServiceType: serviceType, Group: group},
+			ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return v.scope.getNamedGroup(serviceType, group)
+}
+
+func (v *composedView) getNamedGroup(serviceType reflect.Type, group string) ([]any, []string, error) {
+	var instances []any
+	var names []string
+	for _, m := range v.members {
+		if (m.sealed != nil && !m.sealed.permits(serviceType)) ||
+			len(m.owner.findGroupDescriptors(serviceType, group)) == 0 {
+			continue
+		}
+		memberInstances, memberNames, err := m.scope.(namedGroupResolver).getNamedGroup(serviceType, group)
+		if err != nil {
+			return nil, nil, err
+		}
+		instances = append(instances, memberInstances...)
+		names = append(names, memberNames...)
+	}
+	return instances, names, nil
+}
+
+// memberNames returns the names of n group members registered as members.
+// Members of unknown registration, served by a fallback provider, have
+// none.
+func memberNames(members []*descriptor, n int) []string {
+	names := make([]string, n)
+	for i, member := range members {
+		if member != nil {
+			names[i] = member.memberName
+		}
+	}
+	return names
+}
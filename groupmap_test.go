@@ -0,0 +1,103 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveGroupMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keys_members_by_name", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("create"), Group("handlers"), Name("create")),
+			AddSingleton(NewTServiceWithID("delete"), Group("handlers"), Name("delete")),
+			AddSingleton(NewTServiceWithID("unnamed"), Group("handlers")),
+		)
+
+		handlers, err := ResolveGroupMap[*TService](p, "handlers")
+		require.NoError(t, err)
+		require.Len(t, handlers, 2)
+		assert.Equal(t, "create", handlers["create"].ID)
+		assert.Equal(t, "delete", handlers["delete"].ID)
+
+		all, err := ResolveGroup[*TService](p, "handlers")
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("named_members_are_not_keyed_services", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTService, Group("handlers"), Name("create")))
+
+		_, err := ResolveKeyed[*TService](p, "create")
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("interfaces_and_scopes", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddScoped(NewTServiceWithID("a"), Group("handlers"), Name("a"), As[TInterface]()),
+			AddScoped(NewTServiceWithID("b"), Group("handlers"), Name("b"), As[TInterface]()),
+		)
+		s := NewTestScope(t, p)
+
+		handlers, err := ResolveGroupMap[TInterface](s, "handlers")
+		require.NoError(t, err)
+		assert.Equal(t, "a", handlers["a"].GetID())
+		assert.Equal(t, "b", handlers["b"].GetID())
+	})
+
+	t.Run("sealed_and_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		first := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("first"), Group("handlers"), Name("shared")),
+		)
+		second := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("second"), Group("handlers"), Name("shared")),
+			AddSingleton(NewTServiceWithID("other"), Group("handlers"), Name("other")),
+		)
+		sealed, err := Seal(second)
+		require.NoError(t, err)
+		app, err := Compose(first, sealed)
+		require.NoError(t, err)
+
+		handlers, err := ResolveGroupMap[*TService](app, "handlers")
+		require.NoError(t, err)
+		assert.Equal(t, "first", handlers["shared"].ID)
+		assert.Equal(t, "other", handlers["other"].ID)
+	})
+
+	t.Run("empty_group", func(t *testing.T) {
+		t.Parallel()
+		handlers, err := ResolveGroupMap[*TService](BuildProvider(t), "handlers")
+		require.NoError(t, err)
+		assert.Empty(t, handlers)
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		_, err := ResolveGroupMap[*TService](nil, "handlers")
+		assert.ErrorIs(t, err, ErrProviderNil)
+
+		_, err = ResolveGroupMap[*TService](BuildProvider(t), "")
+		assert.ErrorIs(t, err, ErrGroupNameEmpty)
+
+		p, err := NewCollection().Build()
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+		_, err = ResolveGroupMap[*TService](p, "handlers")
+		assert.True(t, errors.Is(err, ErrProviderDisposed), "got %v", err)
+
+		s, err := BuildProvider(t).CreateScope(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+		_, err = ResolveGroupMap[*TService](s, "handlers")
+		assert.ErrorIs(t, err, ErrScopeDisposed)
+	})
+}
@@ -0,0 +1,44 @@
+package godi
+
+// GroupNS is an AddOption like Group that adds the values produced by a
+// constructor to group within namespace. Modules that use a generic group
+// name such as "handlers" can namespace it, usually by their own name, so
+// that their members don't merge with another module's:
+//
+//	var HTTPModule = godi.NewModule("http",
+//	    godi.AddSingleton(NewLogging, godi.GroupNS("http", "middlewares")),
+//	)
+//
+//	middlewares, err := godi.ResolveGroupNS[Middleware](provider, "http", "middlewares")
+//
+// The namespaced group is an ordinary group named GroupName(namespace,
+// group), which is also the name to use in group:"..." tags. An empty
+// namespace leaves group as it is.
+func GroupNS(namespace, group string) AddOption {
+	return addGroupOption(GroupName(namespace, group))
+}
+
+// GroupName returns the name of group within namespace, "namespace/group",
+// as registered by GroupNS.
+func GroupName(namespace, group string) string {
+	if namespace == "" || group == "" {
+		return group
+	}
+	return namespace + "/" + group
+}
+
+// ResolveGroupNS resolves all services of type T in group within namespace,
+// as registered by GroupNS.
+//
+// Example:
+//
+//	middlewares, err := godi.ResolveGroupNS[Middleware](provider, "http", "middlewares")
+func ResolveGroupNS[T any](provider Provider, namespace, group string) ([]T, error) {
+	return ResolveGroup[T](provider, GroupName(namespace, group))
+}
+
+// MustResolveGroupNS resolves all services of type T in group within
+// namespace. It panics if the services cannot be resolved.
+func MustResolveGroupNS[T any](provider Provider, namespace, group string) []T {
+	return MustResolveGroup[T](provider, GroupName(namespace, group))
+}
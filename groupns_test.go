@@ -0,0 +1,55 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupNS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("namespaces_keep_members_apart", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			NewModule("http", AddSingleton(NewTServiceWithID("http"), GroupNS("http", "handlers"))),
+			NewModule("grpc", AddSingleton(NewTServiceWithID("grpc"), GroupNS("grpc", "handlers"))),
+			AddSingleton(NewTServiceWithID("plain"), Group("handlers")),
+		)
+
+		httpHandlers, err := ResolveGroupNS[*TService](p, "http", "handlers")
+		require.NoError(t, err)
+		require.Len(t, httpHandlers, 1)
+		assert.Equal(t, "http", httpHandlers[0].ID)
+
+		grpcHandlers := MustResolveGroupNS[*TService](p, "grpc", "handlers")
+		require.Len(t, grpcHandlers, 1)
+		assert.Equal(t, "grpc", grpcHandlers[0].ID)
+
+		plain := MustResolveGroup[*TService](p, "handlers")
+		require.Len(t, plain, 1)
+		assert.Equal(t, "plain", plain[0].ID)
+	})
+
+	t.Run("tags_use_the_group_name", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Handlers []*TService `group:"http/handlers"`
+		}
+		p := BuildProvider(t,
+			AddSingleton(NewTService, GroupNS("http", "handlers")),
+			AddSingleton(func(in params) *TServiceWithDeps { return &TServiceWithDeps{Svc: in.Handlers[0]} }),
+		)
+
+		assert.NotNil(t, RequireResolve[*TServiceWithDeps](t, p).Svc)
+	})
+
+	t.Run("group_name", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "http/handlers", GroupName("http", "handlers"))
+		assert.Equal(t, "handlers", GroupName("", "handlers"))
+		assert.Equal(t, "", GroupName("http", ""))
+	})
+}
@@ -0,0 +1,22 @@
+module github.com/junioryono/godi/grpc/v5
+
+go 1.26.0
+
+require (
+	github.com/junioryono/godi/v5 v5.0.0
+	github.com/stretchr/testify v1.11.1
+	google.golang.org/grpc v1.70.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.32.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/junioryono/godi/v5 => ../
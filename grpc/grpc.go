@@ -0,0 +1,303 @@
+// Package grpc provides godi integration for gRPC servers.
+//
+// This package provides interceptors that create a scope for each RPC and
+// type-safe handler wrappers for resolving services from it.
+//
+// Example usage:
+//
+//	provider, _ := collection.Build()
+//
+//	server := grpc.NewServer(
+//	    grpc.UnaryInterceptor(godigrpc.UnaryServerInterceptor(provider)),
+//	    grpc.StreamInterceptor(godigrpc.StreamServerInterceptor(provider)),
+//	)
+//
+//	func (s *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+//	    return godigrpc.Handle(UserHandler.GetUser)(ctx, req)
+//	}
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/junioryono/godi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds the configuration for the scope interceptors.
+type Config struct {
+	// ErrorHandler is called when scope creation or a middleware fails. The
+	// error it returns is sent to the client.
+	// If nil, a default handler returning codes.Internal is used.
+	ErrorHandler func(ctx context.Context, fullMethod string, err error) error
+
+	// CloseErrorHandler is called when scope closing fails.
+	// If nil, errors are logged using slog.
+	CloseErrorHandler func(error)
+
+	// Middlewares are functions that run after scope creation, before the
+	// handler. The scope's context carries the incoming metadata, so they can
+	// be used to initialize request context, set user claims, etc.
+	Middlewares []func(scope godi.Scope, fullMethod string) error
+}
+
+// Option configures the scope interceptors.
+type Option func(*Config)
+
+// WithErrorHandler sets the error handler for scope creation and middleware failures.
+func WithErrorHandler(h func(ctx context.Context, fullMethod string, err error) error) Option {
+	return func(c *Config) {
+		if h != nil {
+			c.ErrorHandler = h
+		}
+	}
+}
+
+// WithCloseErrorHandler sets the error handler for scope close failures.
+func WithCloseErrorHandler(h func(error)) Option {
+	return func(c *Config) {
+		if h != nil {
+			c.CloseErrorHandler = h
+		}
+	}
+}
+
+// WithMiddleware adds a middleware function that runs after scope creation.
+// Multiple middlewares are executed in the order they are added.
+//
+// Example:
+//
+//	godigrpc.UnaryServerInterceptor(provider,
+//	    godigrpc.WithMiddleware(func(scope godi.Scope, fullMethod string) error {
+//	        md, _ := metadata.FromIncomingContext(scope.Context())
+//	        reqCtx := godi.MustResolve[*request.Context](scope)
+//	        reqCtx.SetMetadata(md)
+//	        return nil
+//	    }),
+//	)
+func WithMiddleware(mw func(scope godi.Scope, fullMethod string) error) Option {
+	return func(c *Config) {
+		if mw != nil {
+			c.Middlewares = append(c.Middlewares, mw)
+		}
+	}
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		ErrorHandler: func(_ context.Context, fullMethod string, err error) error {
+			slog.Error("failed to prepare scope", "method", fullMethod, "error", err)
+			return status.Error(codes.Internal, "internal error")
+		},
+		CloseErrorHandler: func(err error) {
+			slog.Error("failed to close scope", "error", err)
+		},
+		Middlewares: nil,
+	}
+}
+
+func normalizeConfig(c *Config) {
+	defaults := defaultConfig()
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = defaults.ErrorHandler
+	}
+	if c.CloseErrorHandler == nil {
+		c.CloseErrorHandler = defaults.CloseErrorHandler
+	}
+	// Copy while filtering nils: reslicing in place would mutate a
+	// caller-owned slice assigned via a custom option.
+	middlewares := make([]func(godi.Scope, string) error, 0, len(c.Middlewares))
+	for _, middleware := range c.Middlewares {
+		if middleware != nil {
+			middlewares = append(middlewares, middleware)
+		}
+	}
+	c.Middlewares = middlewares
+}
+
+func newConfig(opts []Option) *Config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	normalizeConfig(cfg)
+	return cfg
+}
+
+// openScope creates the scope for one RPC and runs the configured
+// middlewares. On failure the scope is already closed and the returned
+// error is the one to send to the client.
+func (cfg *Config) openScope(provider godi.Provider, ctx context.Context, fullMethod string) (godi.Scope, error) {
+	scope, err := provider.CreateScope(ctx)
+	if err != nil {
+		return nil, cfg.ErrorHandler(ctx, fullMethod, err)
+	}
+
+	for _, mw := range cfg.Middlewares {
+		if err := mw(scope, fullMethod); err != nil {
+			err = cfg.ErrorHandler(scope.Context(), fullMethod, err)
+			cfg.closeScope(scope)
+			return nil, err
+		}
+	}
+	return scope, nil
+}
+
+func (cfg *Config) closeScope(scope godi.Scope) {
+	if err := scope.Close(); err != nil {
+		cfg.CloseErrorHandler(err)
+	}
+}
+
+// UnaryServerInterceptor creates a grpc.UnaryServerInterceptor that creates
+// a scope for each unary RPC. The scope is attached to the handler's context
+// and can be retrieved using godi.FromContext.
+//
+// The scope is closed when the handler returns, including when it fails or
+// panics. Scope creation and middleware failures are reported to the client
+// through the configured ErrorHandler without calling the handler.
+//
+// Example:
+//
+//	server := grpc.NewServer(
+//	    grpc.UnaryInterceptor(godigrpc.UnaryServerInterceptor(provider)),
+//	)
+func UnaryServerInterceptor(provider godi.Provider, opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scope, err := cfg.openScope(provider, ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer cfg.closeScope(scope)
+
+		return handler(scope.Context(), req)
+	}
+}
+
+// StreamServerInterceptor creates a grpc.StreamServerInterceptor that
+// creates a scope for each streaming RPC. The scope lives for the whole
+// stream: the stream's Context returns the scope's context, and the scope is
+// closed when the handler returns.
+//
+// Example:
+//
+//	server := grpc.NewServer(
+//	    grpc.StreamInterceptor(godigrpc.StreamServerInterceptor(provider)),
+//	)
+func StreamServerInterceptor(provider godi.Provider, opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scope, err := cfg.openScope(provider, stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer cfg.closeScope(scope)
+
+		return handler(srv, &scopedStream{ServerStream: stream, ctx: scope.Context()})
+	}
+}
+
+// scopedStream is a grpc.ServerStream whose context carries the RPC scope.
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Handle wraps a service method for type-safe resolution from the RPC scope.
+// The service type T is resolved from the scope attached to the context by
+// UnaryServerInterceptor, so each call receives its own scoped dependencies.
+//
+// The method signature should be: func(T, context.Context, Req) (Resp, error)
+//
+// Example:
+//
+//	type UserHandler interface {
+//	    GetUser(context.Context, *pb.GetUserRequest) (*pb.User, error)
+//	}
+//
+//	func (s *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+//	    return godigrpc.Handle(UserHandler.GetUser)(ctx, req)
+//	}
+func Handle[T, Req, Resp any](method func(T, context.Context, Req) (Resp, error)) func(context.Context, Req) (Resp, error) {
+	return func(ctx context.Context, req Req) (Resp, error) {
+		var zero Resp
+		service, err := resolve[T](ctx)
+		if err != nil {
+			return zero, err
+		}
+		return method(service, ctx, req)
+	}
+}
+
+// HandleStream wraps a streaming service method for type-safe resolution
+// from the scope attached to the stream by StreamServerInterceptor.
+//
+// The method signature should be: func(T, Req, Stream) error for
+// server-streaming RPCs; use HandleBidiStream for client and bidirectional
+// streams.
+//
+// Example:
+//
+//	func (s *userServer) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+//	    return godigrpc.HandleStream(UserHandler.ListUsers)(req, stream)
+//	}
+func HandleStream[T, Req any, Stream grpc.ServerStream](method func(T, Req, Stream) error) func(Req, Stream) error {
+	return func(req Req, stream Stream) error {
+		service, err := resolve[T](stream.Context())
+		if err != nil {
+			return err
+		}
+		return method(service, req, stream)
+	}
+}
+
+// HandleBidiStream wraps a client-streaming or bidirectional streaming
+// service method for type-safe resolution from the scope attached to the
+// stream by StreamServerInterceptor.
+//
+// The method signature should be: func(T, Stream) error
+//
+// Example:
+//
+//	func (s *chatServer) Chat(stream pb.ChatService_ChatServer) error {
+//	    return godigrpc.HandleBidiStream(ChatHandler.Chat)(stream)
+//	}
+func HandleBidiStream[T any, Stream grpc.ServerStream](method func(T, Stream) error) func(Stream) error {
+	return func(stream Stream) error {
+		service, err := resolve[T](stream.Context())
+		if err != nil {
+			return err
+		}
+		return method(service, stream)
+	}
+}
+
+// resolve resolves T from the RPC scope in ctx, translating failures into
+// gRPC status errors.
+func resolve[T any](ctx context.Context) (T, error) {
+	var zero T
+	scope, err := godi.FromContext(ctx)
+	if err != nil {
+		slog.Error("failed to get scope from context", "error", err)
+		return zero, status.Error(codes.Internal, "internal error")
+	}
+
+	service, err := godi.Resolve[T](scope)
+	if err != nil {
+		slog.Error("failed to resolve service", "error", err)
+		return zero, status.Error(codes.Internal, "internal error")
+	}
+	return service, nil
+}
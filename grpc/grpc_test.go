@@ -0,0 +1,291 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Test types
+type testService struct {
+	ID     string
+	closed bool
+}
+
+func (s *testService) Close() error {
+	s.closed = true
+	return nil
+}
+
+type testHandler struct {
+	Service *testService
+}
+
+func newTestHandler(svc *testService) *testHandler {
+	return &testHandler{Service: svc}
+}
+
+type getRequest struct{ Name string }
+type getResponse struct{ Message string }
+
+func (h *testHandler) Get(ctx context.Context, req *getRequest) (*getResponse, error) {
+	return &getResponse{Message: h.Service.ID + ":" + req.Name}, nil
+}
+
+func (h *testHandler) List(req *getRequest, stream grpc.ServerStream) error {
+	return stream.SendMsg(&getResponse{Message: h.Service.ID + ":" + req.Name})
+}
+
+func (h *testHandler) Echo(stream grpc.ServerStream) error {
+	return stream.SendMsg(&getResponse{Message: h.Service.ID})
+}
+
+// testStream is a minimal grpc.ServerStream for driving interceptors
+// without a network connection.
+type testStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []any
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func (s *testStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func buildProvider(t *testing.T) godi.Provider {
+	t.Helper()
+	collection := godi.NewCollection()
+	collection.AddScoped(func() *testService { return &testService{ID: "scoped"} })
+	collection.AddScoped(newTestHandler)
+
+	provider, err := collection.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+	return provider
+}
+
+var unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+var streamInfo = &grpc.StreamServerInfo{FullMethod: "/test.Service/List", IsServerStream: true}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Run("creates scope per call and closes it", func(t *testing.T) {
+		provider := buildProvider(t)
+		interceptor := UnaryServerInterceptor(provider)
+
+		var services []*testService
+		handler := func(ctx context.Context, req any) (any, error) {
+			scope, err := godi.FromContext(ctx)
+			require.NoError(t, err)
+			svc, err := godi.Resolve[*testService](scope)
+			require.NoError(t, err)
+			services = append(services, svc)
+			return "ok", nil
+		}
+
+		for range 2 {
+			resp, err := interceptor(context.Background(), nil, unaryInfo, handler)
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+		}
+
+		require.Len(t, services, 2)
+		assert.NotSame(t, services[0], services[1])
+		assert.True(t, services[0].closed)
+		assert.True(t, services[1].closed)
+	})
+
+	t.Run("scope context carries incoming metadata", func(t *testing.T) {
+		provider := buildProvider(t)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("user", "alice"))
+
+		var user []string
+		interceptor := UnaryServerInterceptor(provider, WithMiddleware(func(scope godi.Scope, fullMethod string) error {
+			assert.Equal(t, unaryInfo.FullMethod, fullMethod)
+			md, _ := metadata.FromIncomingContext(scope.Context())
+			user = md.Get("user")
+			return nil
+		}))
+
+		_, err := interceptor(ctx, nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice"}, user)
+	})
+
+	t.Run("closes scope when handler fails", func(t *testing.T) {
+		provider := buildProvider(t)
+		interceptor := UnaryServerInterceptor(provider)
+		handlerErr := status.Error(codes.NotFound, "missing")
+
+		var svc *testService
+		_, err := interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			scope, _ := godi.FromContext(ctx)
+			svc = godi.MustResolve[*testService](scope)
+			return nil, handlerErr
+		})
+		assert.Equal(t, handlerErr, err)
+		assert.True(t, svc.closed)
+	})
+
+	t.Run("middleware error skips handler", func(t *testing.T) {
+		provider := buildProvider(t)
+		var handlerErr error
+		interceptor := UnaryServerInterceptor(provider,
+			WithMiddleware(func(godi.Scope, string) error { return errors.New("unauthenticated") }),
+			WithErrorHandler(func(_ context.Context, _ string, err error) error {
+				handlerErr = err
+				return status.Error(codes.Unauthenticated, err.Error())
+			}),
+		)
+
+		called := false
+		_, err := interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			called = true
+			return nil, nil
+		})
+		assert.False(t, called)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.EqualError(t, handlerErr, "unauthenticated")
+	})
+
+	t.Run("scope creation failure returns internal error", func(t *testing.T) {
+		provider := buildProvider(t)
+		require.NoError(t, provider.Close())
+
+		interceptor := UnaryServerInterceptor(provider)
+		_, err := interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			t.Fatal("handler must not run")
+			return nil, nil
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("reports close errors", func(t *testing.T) {
+		collection := godi.NewCollection()
+		collection.AddScoped(func() godi.Disposable { return failingCloser{} })
+		provider, err := collection.Build()
+		require.NoError(t, err)
+		defer provider.Close()
+
+		var closeErr error
+		interceptor := UnaryServerInterceptor(provider, WithCloseErrorHandler(func(err error) { closeErr = err }))
+		_, err = interceptor(context.Background(), nil, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			scope, _ := godi.FromContext(ctx)
+			return godi.Resolve[godi.Disposable](scope)
+		})
+		require.NoError(t, err)
+		assert.Error(t, closeErr)
+	})
+}
+
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return errors.New("close failed") }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Run("stream context carries the scope for the whole stream", func(t *testing.T) {
+		provider := buildProvider(t)
+		interceptor := StreamServerInterceptor(provider)
+		stream := &testStream{ctx: context.Background()}
+
+		var svc *testService
+		err := interceptor(nil, stream, streamInfo, func(srv any, ss grpc.ServerStream) error {
+			scope, err := godi.FromContext(ss.Context())
+			require.NoError(t, err)
+			svc = godi.MustResolve[*testService](scope)
+			assert.Same(t, svc, godi.MustResolve[*testService](scope))
+			return ss.SendMsg("hello")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []any{"hello"}, stream.sent, "calls pass through to the wrapped stream")
+		assert.True(t, svc.closed)
+	})
+
+	t.Run("scope creation failure returns internal error", func(t *testing.T) {
+		provider := buildProvider(t)
+		require.NoError(t, provider.Close())
+
+		interceptor := StreamServerInterceptor(provider)
+		err := interceptor(nil, &testStream{ctx: context.Background()}, streamInfo, func(any, grpc.ServerStream) error {
+			t.Fatal("handler must not run")
+			return nil
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("resolves service from call scope", func(t *testing.T) {
+		provider := buildProvider(t)
+		interceptor := UnaryServerInterceptor(provider)
+		get := Handle((*testHandler).Get)
+
+		resp, err := interceptor(context.Background(), &getRequest{Name: "alice"}, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			return get(ctx, req.(*getRequest))
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "scoped:alice", resp.(*getResponse).Message)
+	})
+
+	t.Run("missing scope returns internal error", func(t *testing.T) {
+		_, err := Handle((*testHandler).Get)(context.Background(), &getRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("resolution failure returns internal error", func(t *testing.T) {
+		collection := godi.NewCollection()
+		provider, err := collection.Build()
+		require.NoError(t, err)
+		defer provider.Close()
+
+		_, err = UnaryServerInterceptor(provider)(context.Background(), &getRequest{}, unaryInfo, func(ctx context.Context, req any) (any, error) {
+			return Handle((*testHandler).Get)(ctx, req.(*getRequest))
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestHandleStream(t *testing.T) {
+	t.Run("server streaming", func(t *testing.T) {
+		provider := buildProvider(t)
+		stream := &testStream{ctx: context.Background()}
+		list := HandleStream((*testHandler).List)
+
+		err := StreamServerInterceptor(provider)(nil, stream, streamInfo, func(srv any, ss grpc.ServerStream) error {
+			return list(&getRequest{Name: "bob"}, ss)
+		})
+		require.NoError(t, err)
+		require.Len(t, stream.sent, 1)
+		assert.Equal(t, "scoped:bob", stream.sent[0].(*getResponse).Message)
+	})
+
+	t.Run("bidirectional streaming", func(t *testing.T) {
+		provider := buildProvider(t)
+		stream := &testStream{ctx: context.Background()}
+		echo := HandleBidiStream((*testHandler).Echo)
+
+		err := StreamServerInterceptor(provider)(nil, stream, streamInfo, func(srv any, ss grpc.ServerStream) error {
+			return echo(ss)
+		})
+		require.NoError(t, err)
+		require.Len(t, stream.sent, 1)
+		assert.Equal(t, "scoped", stream.sent[0].(*getResponse).Message)
+	})
+
+	t.Run("missing scope returns internal error", func(t *testing.T) {
+		err := HandleBidiStream((*testHandler).Echo)(grpc.ServerStream(&testStream{ctx: context.Background()}))
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
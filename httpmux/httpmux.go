@@ -0,0 +1,188 @@
+// Package httpmux mounts HTTP routes registered with godi on a router.
+//
+// Routes are registered anywhere in an application's modules with Handle,
+// HandleFunc or AddRoute, which add them to a group. ServeMux and MountOn
+// collect that group when the provider is built and mount every route, so a
+// module that owns a feature also owns its routes and no central file has to
+// list them all.
+//
+// Example usage:
+//
+//	services.AddSingleton(NewUserController)
+//	services.AddModules(
+//	    godihttpmux.AddRoute(func(c *UserController) godihttpmux.Route {
+//	        return godihttpmux.Get("/users/{id}", c.GetByID)
+//	    }),
+//	    godihttpmux.ServeMux(),
+//	)
+//
+//	provider, _ := services.Build()
+//	mux := godi.MustResolve[*http.ServeMux](provider)
+//	http.ListenAndServe(":8080", godihttp.ScopeMiddleware(provider)(mux))
+package httpmux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// RoutesGroup is the group routes are added to, the group "routes" in the
+// namespace "httpmux" (see godi.GroupNS).
+const RoutesGroup = "httpmux/routes"
+
+// ErrInvalidRoute is wrapped by the error of mounting a route without a
+// pattern or a handler.
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Route is an HTTP route: requests matching Method and Pattern are served by
+// Handler.
+type Route struct {
+	// Method is the HTTP method the route matches, such as http.MethodGet.
+	// If empty, the route matches every method.
+	Method string
+
+	// Pattern is the path pattern in the syntax of the router the route is
+	// mounted on, such as "/users/{id}".
+	Pattern string
+
+	// Handler serves the route's requests.
+	Handler http.Handler
+}
+
+// String returns the route in the pattern syntax of http.ServeMux, such as
+// "GET /users/{id}".
+func (r Route) String() string {
+	if r.Method == "" {
+		return r.Pattern
+	}
+	return r.Method + " " + r.Pattern
+}
+
+// Get returns a Route serving GET requests for pattern with handler.
+func Get(pattern string, handler http.HandlerFunc) Route {
+	return Route{Method: http.MethodGet, Pattern: pattern, Handler: handler}
+}
+
+// Post returns a Route serving POST requests for pattern with handler.
+func Post(pattern string, handler http.HandlerFunc) Route {
+	return Route{Method: http.MethodPost, Pattern: pattern, Handler: handler}
+}
+
+// Put returns a Route serving PUT requests for pattern with handler.
+func Put(pattern string, handler http.HandlerFunc) Route {
+	return Route{Method: http.MethodPut, Pattern: pattern, Handler: handler}
+}
+
+// Patch returns a Route serving PATCH requests for pattern with handler.
+func Patch(pattern string, handler http.HandlerFunc) Route {
+	return Route{Method: http.MethodPatch, Pattern: pattern, Handler: handler}
+}
+
+// Delete returns a Route serving DELETE requests for pattern with handler.
+func Delete(pattern string, handler http.HandlerFunc) Route {
+	return Route{Method: http.MethodDelete, Pattern: pattern, Handler: handler}
+}
+
+// AddRoute registers constructor, a constructor returning a Route, as a
+// member of RoutesGroup. Its parameters are injected like those of any
+// singleton, so the route's handler can be a method of a controller:
+//
+//	services.AddModules(godihttpmux.AddRoute(func(c *UserController) godihttpmux.Route {
+//	    return godihttpmux.Get("/users/{id}", c.GetByID)
+//	}))
+//
+// Handlers that need request-scoped services resolve them from the request's
+// scope, for example with the Handle function of the net/http integration.
+func AddRoute(constructor any, opts ...godi.AddOption) godi.ModuleOption {
+	return godi.AddSingleton(constructor, append(opts[:len(opts):len(opts)], godi.Group(RoutesGroup))...)
+}
+
+// Handle registers a Route serving requests for method and pattern with
+// handler. An empty method matches every method.
+func Handle(method, pattern string, handler http.Handler) godi.ModuleOption {
+	route := Route{Method: method, Pattern: pattern, Handler: handler}
+	return AddRoute(func() Route { return route })
+}
+
+// HandleFunc is like Handle for a handler function.
+func HandleFunc(method, pattern string, handler http.HandlerFunc) godi.ModuleOption {
+	return Handle(method, pattern, handler)
+}
+
+// Router is a router routes can be mounted on, such as *http.ServeMux.
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// MethodRouter is a Router that takes a route's method separately from its
+// pattern, such as chi.Router. Mount passes routes with a method to Method
+// instead of combining method and pattern for Handle.
+type MethodRouter interface {
+	Router
+	Method(method, pattern string, handler http.Handler)
+}
+
+// Mount mounts routes on router in order. Routers panic on a pattern they
+// reject or one that conflicts with a route already mounted; Mount returns
+// such a panic as an error naming the route and mounts no further routes.
+func Mount(router Router, routes ...Route) error {
+	for _, route := range routes {
+		if err := mount(router, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mount(router Router, route Route) (err error) {
+	if route.Pattern == "" {
+		return fmt.Errorf("%w: route %q has no pattern", ErrInvalidRoute, route)
+	}
+	if route.Handler == nil {
+		return fmt.Errorf("%w: route %q has no handler", ErrInvalidRoute, route)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("mount route %q: %v", route, r)
+		}
+	}()
+
+	if methodRouter, ok := router.(MethodRouter); ok && route.Method != "" {
+		methodRouter.Method(route.Method, route.Pattern, route.Handler)
+		return nil
+	}
+	router.Handle(route.String(), route.Handler)
+	return nil
+}
+
+type routesParams struct {
+	godi.In
+
+	Routes []Route `group:"httpmux/routes"`
+}
+
+// ServeMux registers an *http.ServeMux singleton with every route of
+// RoutesGroup mounted on it. Routes are mounted when the provider is built,
+// so a route the mux rejects fails the build.
+func ServeMux(opts ...godi.AddOption) godi.ModuleOption {
+	return godi.AddSingleton(func(params routesParams) (*http.ServeMux, error) {
+		mux := http.NewServeMux()
+		if err := Mount(mux, params.Routes...); err != nil {
+			return nil, err
+		}
+		return mux, nil
+	}, opts...)
+}
+
+// MountOn mounts every route of RoutesGroup on router, such as a chi.Router
+// the application created itself, when the provider is built. A route the
+// router rejects fails the build.
+func MountOn(router Router) godi.ModuleOption {
+	return godi.AddSingleton(func(params routesParams) error {
+		return Mount(router, params.Routes...)
+	})
+}
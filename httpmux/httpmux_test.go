@@ -0,0 +1,145 @@
+package httpmux
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greeter struct {
+	greeting string
+}
+
+func (g *greeter) Greet(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, g.greeting+", "+r.PathValue("name"))
+}
+
+// methodRouter records the routes mounted on it the way chi.Router takes
+// them.
+type methodRouter struct {
+	handled []string
+}
+
+func (m *methodRouter) Handle(pattern string, _ http.Handler) {
+	m.handled = append(m.handled, "Handle "+pattern)
+}
+
+func (m *methodRouter) Method(method, pattern string, _ http.Handler) {
+	m.handled = append(m.handled, "Method "+method+" "+pattern)
+}
+
+func serve(t *testing.T, handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestServeMux(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mounts_routes_from_every_module", func(t *testing.T) {
+		t.Parallel()
+		c := godi.NewCollection()
+		c.AddSingleton(func() *greeter { return &greeter{greeting: "hello"} })
+		c.AddModules(
+			godi.NewModule("greetings",
+				AddRoute(func(g *greeter) Route { return Get("/greet/{name}", g.Greet) }),
+			),
+			godi.NewModule("health",
+				HandleFunc("", "/healthz", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			),
+			ServeMux(),
+		)
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		mux, err := godi.Resolve[*http.ServeMux](p)
+		require.NoError(t, err)
+
+		rec := serve(t, mux, http.MethodGet, "/greet/gopher")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello, gopher", rec.Body.String())
+
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(t, mux, http.MethodPost, "/greet/gopher").Code)
+		assert.Equal(t, http.StatusNoContent, serve(t, mux, http.MethodDelete, "/healthz").Code)
+	})
+
+	t.Run("no_routes", func(t *testing.T) {
+		t.Parallel()
+		c := godi.NewCollection()
+		c.AddModules(ServeMux())
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		mux, err := godi.Resolve[*http.ServeMux](p)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, serve(t, mux, http.MethodGet, "/").Code)
+	})
+
+	t.Run("conflicting_routes_fail_the_build", func(t *testing.T) {
+		t.Parallel()
+		handler := http.NotFoundHandler()
+		c := godi.NewCollection()
+		c.AddModules(
+			Handle(http.MethodGet, "/users/{id}", handler),
+			Handle(http.MethodGet, "/users/{name}", handler),
+			ServeMux(),
+		)
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `mount route "GET /users/{name}"`)
+	})
+
+	t.Run("invalid_routes_fail_the_build", func(t *testing.T) {
+		t.Parallel()
+		c := godi.NewCollection()
+		c.AddModules(Handle(http.MethodGet, "/users", nil), ServeMux())
+		_, err := c.Build()
+		assert.True(t, errors.Is(err, ErrInvalidRoute), "got %v", err)
+	})
+}
+
+func TestMountOn(t *testing.T) {
+	t.Parallel()
+
+	router := &methodRouter{}
+	handler := http.NotFoundHandler()
+	c := godi.NewCollection()
+	c.AddModules(
+		Handle(http.MethodPost, "/users", handler),
+		Handle("", "/static/*", handler),
+		MountOn(router),
+	)
+	p, err := c.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	assert.Equal(t, []string{"Method POST /users", "Handle /static/*"}, router.handled)
+}
+
+func TestMount(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	require.NoError(t, Mount(mux,
+		Post("/items", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) }),
+		Delete("/items/{id}", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+	))
+
+	assert.Equal(t, http.StatusCreated, serve(t, mux, http.MethodPost, "/items").Code)
+	assert.Equal(t, http.StatusNoContent, serve(t, mux, http.MethodDelete, "/items/7").Code)
+
+	err := Mount(mux, Route{Method: http.MethodGet, Handler: http.NotFoundHandler()})
+	assert.True(t, errors.Is(err, ErrInvalidRoute), "got %v", err)
+}
@@ -0,0 +1,70 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IDGenerator generates the IDs of a provider's scopes, as returned by
+// Scope.ID and shown in diagnostics. Set it with ProviderOptions.IDGenerator,
+// e.g. to derive scope IDs from trace IDs or to make them unique across
+// processes.
+//
+// NewScopeID is called once per scope, including the provider's root
+// scope, and may be called concurrently. IDs must be unique among the
+// provider's open scopes: CreateScope retries a few times when an ID is
+// taken and then fails with an error wrapping ErrScopeIDInUse. A generator
+// that cannot produce an ID, e.g. because its entropy source failed,
+// returns an error instead; CreateScope reports it rather than panicking.
+//
+// Scopes created with WithScopeID do not call the generator.
+type IDGenerator interface {
+	NewScopeID() (string, error)
+}
+
+// IDGeneratorFunc adapts a function to an IDGenerator.
+type IDGeneratorFunc func() (string, error)
+
+// NewScopeID calls f.
+func (f IDGeneratorFunc) NewScopeID() (string, error) {
+	return f()
+}
+
+// maxScopeIDAttempts bounds how often generateScopeID asks the generator
+// for another ID when the previous one was taken.
+const maxScopeIDAttempts = 4
+
+// generateScopeID obtains an ID from the provider's IDGenerator and
+// reserves it like an ID given to WithScopeID.
+func (p *provider) generateScopeID() (string, error) {
+	var err error
+	for range maxScopeIDAttempts {
+		var id string
+		id, err = callIDGenerator(p.idGenerator)
+		if err != nil {
+			return "", err
+		}
+		if err = p.reserveScopeID(id); err == nil || !errors.Is(err, ErrScopeIDInUse) {
+			return id, err
+		}
+	}
+	return "", err
+}
+
+// callIDGenerator calls g, reporting an empty ID or a panic as an error.
+func callIDGenerator(g IDGenerator) (id string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &ValidationError{Cause: fmt.Errorf("IDGenerator panicked: %v", r)}
+		}
+	}()
+
+	id, err = g.NewScopeID()
+	if err != nil {
+		return "", &ValidationError{Cause: fmt.Errorf("IDGenerator failed: %w", err)}
+	}
+	if id == "" {
+		return "", &ValidationError{Cause: errors.New("IDGenerator returned an empty scope ID")}
+	}
+	return id, nil
+}
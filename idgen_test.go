@@ -0,0 +1,136 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerator(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, g IDGenerator) Provider {
+		t.Helper()
+		p, err := NewCollection().BuildWithOptions(&ProviderOptions{IDGenerator: g})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("generates_scope_ids", func(t *testing.T) {
+		t.Parallel()
+		var n atomic.Int64
+		p := build(t, IDGeneratorFunc(func() (string, error) {
+			return fmt.Sprintf("req-%d", n.Add(1)), nil
+		}))
+
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		defer s.Close()
+		assert.Equal(t, "req-2", s.ID(), "the root scope takes the first ID")
+
+		named, err := CreateScope(context.Background(), p, WithScopeID("explicit"))
+		require.NoError(t, err)
+		defer named.Close()
+		assert.Equal(t, "explicit", named.ID())
+		assert.Equal(t, int64(2), n.Load(), "WithScopeID does not call the generator")
+	})
+
+	t.Run("retries_ids_in_use", func(t *testing.T) {
+		t.Parallel()
+		ids := []string{"root", "a", "a", "b"}
+		var mu sync.Mutex
+		p := build(t, IDGeneratorFunc(func() (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			id := ids[0]
+			ids = ids[1:]
+			return id, nil
+		}))
+
+		first, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		defer second.Close()
+		assert.Equal(t, []string{"a", "b"}, []string{first.ID(), second.ID()})
+	})
+
+	t.Run("gives_up_when_ids_stay_taken", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		p := build(t, IDGeneratorFunc(func() (string, error) {
+			if calls.Add(1) == 1 {
+				return "root", nil
+			}
+			return "same", nil
+		}))
+
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		_, err = p.CreateScope(context.Background())
+		require.ErrorIs(t, err, ErrScopeIDInUse)
+
+		require.NoError(t, s.Close())
+		again, err := p.CreateScope(context.Background())
+		require.NoError(t, err, "the ID is free once its scope closes")
+		require.NoError(t, again.Close())
+	})
+
+	t.Run("failures_are_errors", func(t *testing.T) {
+		t.Parallel()
+		entropy := errors.New("entropy source unavailable")
+		var fail atomic.Bool
+		p := build(t, IDGeneratorFunc(func() (string, error) {
+			if fail.Load() {
+				return "", entropy
+			}
+			return "root", nil
+		}))
+
+		fail.Store(true)
+		_, err := p.CreateScope(context.Background())
+		require.ErrorIs(t, err, entropy)
+		_, ok := errors.AsType[*ValidationError](err)
+		assert.True(t, ok)
+	})
+
+	t.Run("empty_ids_and_panics_are_errors", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		p := build(t, IDGeneratorFunc(func() (string, error) {
+			switch calls.Add(1) {
+			case 1:
+				return "root", nil
+			case 2:
+				return "", nil
+			default:
+				panic("boom")
+			}
+		}))
+
+		_, err := p.CreateScope(context.Background())
+		require.ErrorContains(t, err, "empty scope ID")
+		require.NotPanics(t, func() {
+			_, err = p.CreateScope(context.Background())
+		})
+		require.ErrorContains(t, err, "IDGenerator panicked: boom")
+	})
+
+	t.Run("root_scope_failure_fails_build", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewCollection().BuildWithOptions(&ProviderOptions{
+			IDGenerator: IDGeneratorFunc(func() (string, error) { return "", errors.New("no ids") }),
+		})
+		require.ErrorContains(t, err, "no ids")
+		_, ok := errors.AsType[*BuildError](err)
+		assert.True(t, ok)
+	})
+}
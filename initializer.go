@@ -0,0 +1,65 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Initializer is implemented by services that finish their setup after
+// construction, so their constructors can stay cheap and free of I/O.
+// The container calls Initialize once for each instance a constructor
+// returns, with the resolving scope's context, before any ValidateWith
+// check or decorator runs and before the instance is cached or returned.
+// Registered instances, such as those added with AddValue, are not
+// initialized.
+//
+// An instance whose Initialize fails is never cached: the resolution fails
+// with a ResolutionError whose Cause is an *InitializeError, the instance is
+// closed if it is Disposable, and the next resolution constructs a new one.
+//
+// Example:
+//
+//	func NewCache(cfg *Config) *Cache { return &Cache{addr: cfg.CacheAddr} }
+//
+//	func (c *Cache) Initialize(ctx context.Context) error {
+//	    return c.warm(ctx)
+//	}
+type Initializer interface {
+	Initialize(ctx context.Context) error
+}
+
+// initialize calls Initialize on instance if it implements Initializer and
+// descriptor constructed it.
+func (s *scope) initialize(r *resolution, requested instanceKey, descriptor *descriptor, instance any) error {
+	initializer, ok := instance.(Initializer)
+	if !ok || descriptor.IsInstance {
+		return nil
+	}
+
+	if err := callInitialize(s, initializer); err != nil {
+		if d, ok := disposableFor(descriptor, instance); ok {
+			closeOrphan(d)
+		}
+		return &ResolutionError{
+			ServiceType: requested.Type,
+			ServiceKey:  requested.Key,
+			Cause: &InitializeError{
+				ServiceType: reflect.TypeOf(instance),
+				Cause:       err,
+			},
+			Path: append(r.path(), newResolutionFrame(requested, descriptor)),
+		}
+	}
+	return nil
+}
+
+// callInitialize calls Initialize, returning a panic as an error.
+func callInitialize(s *scope, initializer Initializer) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("Initialize panicked: %v", p)
+		}
+	}()
+	return initializer.Initialize(s.resolvedContext())
+}
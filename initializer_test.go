@@ -0,0 +1,119 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKeyInit struct{}
+
+// TInitialized records its Initialize calls and fails them while err is set.
+type TInitialized struct {
+	TDisposable
+	calls atomic.Int32
+	value string
+	err   error
+}
+
+func (s *TInitialized) Initialize(ctx context.Context) error {
+	s.calls.Add(1)
+	if s.err != nil {
+		return s.err
+	}
+	if v, ok := ctx.Value(ctxKeyInit{}).(string); ok {
+		s.value = v
+	}
+	return nil
+}
+
+func TestInitializer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("initializes_once_with_the_scope_context", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(func() *TInitialized { return &TInitialized{} }))
+		s, err := p.CreateScope(context.WithValue(context.Background(), ctxKeyInit{}, "request"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		first := RequireResolveFrom[*TInitialized](t, s)
+		assert.Equal(t, "request", first.value)
+		assert.Same(t, first, RequireResolveFrom[*TInitialized](t, s))
+		assert.Equal(t, int32(1), first.calls.Load())
+	})
+
+	t.Run("failures_are_not_cached", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("warm-up failed")
+		var created []*TInitialized
+		p := BuildProvider(t, AddScoped(func() *TInitialized {
+			s := &TInitialized{}
+			if len(created) == 0 {
+				s.err = boom
+			}
+			created = append(created, s)
+			return s
+		}))
+		s := NewTestScope(t, p)
+
+		_, err := Resolve[*TInitialized](s)
+		require.ErrorIs(t, err, boom)
+		initErr, ok := errors.AsType[*InitializeError](err)
+		require.True(t, ok, "expected InitializeError, got %v", err)
+		assert.Equal(t, PtrTypeOf[TInitialized](), initErr.ServiceType)
+		assert.True(t, created[0].IsClosed(), "the failed instance is disposed")
+
+		second := RequireResolve[*TInitialized](t, s)
+		assert.Same(t, created[1], second)
+	})
+
+	t.Run("singletons_fail_the_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() *TInitialized { return &TInitialized{err: errors.New("boom")} })
+		_, err := c.Build()
+		require.ErrorAs(t, err, new(*InitializeError))
+	})
+
+	t.Run("runs_before_checks_and_decorators", func(t *testing.T) {
+		t.Parallel()
+		var order []string
+		p := BuildProvider(t, NewModule("m",
+			AddSingleton(func() *TInitialized { return &TInitialized{} }, ValidateWith(func(s *TInitialized) error {
+				order = append(order, "check")
+				assert.Equal(t, int32(1), s.calls.Load())
+				return nil
+			})),
+			ModuleDecorate(func(s *TInitialized) *TInitialized {
+				order = append(order, "decorate")
+				return s
+			}),
+		))
+		RequireResolve[*TInitialized](t, p)
+		assert.Equal(t, []string{"check", "decorate"}, order)
+	})
+
+	t.Run("registered_instances_are_not_initialized", func(t *testing.T) {
+		t.Parallel()
+		instance := &TInitialized{}
+		p := BuildProvider(t, AddSingleton(instance))
+		RequireResolve[*TInitialized](t, p)
+		assert.Zero(t, instance.calls.Load())
+	})
+
+	t.Run("panics_are_errors", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(func() *TPanickingInit { return &TPanickingInit{} }))
+		_, err := Resolve[*TPanickingInit](NewTestScope(t, p))
+		require.ErrorContains(t, err, "Initialize panicked: boom")
+	})
+}
+
+type TPanickingInit struct{}
+
+func (*TPanickingInit) Initialize(context.Context) error { panic("boom") }
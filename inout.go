@@ -13,6 +13,7 @@ import (
 //   - `optional:"true"` - Field is optional and won't cause an error if the service is not found
 //   - `name:"serviceName"` - Field should be resolved as a keyed/named service
 //   - `group:"groupName"` - Field should be filled from a value group (slice fields only)
+//   - `soft:"true"` - With group, only members that are already constructed are included
 //
 // Example:
 //
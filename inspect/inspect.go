@@ -0,0 +1,203 @@
+// Package inspect reports how godi reads constructors: the services a
+// constructor depends on and the services it provides, with the struct tags
+// of godi.In and godi.Out fields already interpreted. It is meant for code
+// generators, linters and frameworks built on top of godi.
+//
+// The package follows the compatibility promise of the godi module: its
+// exported identifiers are not removed or changed within a major version,
+// although fields may be added to its structs. It reports exactly what
+// godi's own analysis sees, so a constructor inspected here is registered
+// the same way.
+//
+// Example usage:
+//
+//	c, err := inspect.Analyze(NewUserService)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, p := range c.Params {
+//	    fmt.Printf("needs %s\n", inspect.FormatType(p.Type))
+//	}
+package inspect
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// Constructor describes a constructor function, or a value registered as
+// an instance.
+type Constructor struct {
+	// Type is the function's type, or the value's type for instances.
+	Type reflect.Type
+
+	// IsFunc reports whether the constructor is a function. Instances have
+	// no parameters and provide Type itself.
+	IsFunc bool
+
+	// ParamObject reports whether the function takes a godi.In struct, in
+	// which case Params lists the struct's fields.
+	ParamObject bool
+
+	// ResultObject reports whether the function returns a godi.Out struct,
+	// in which case Results lists the struct's fields.
+	ResultObject bool
+
+	// HasError reports whether the function's last result is an error.
+	HasError bool
+
+	// Params are the function's parameters, or its godi.In fields.
+	Params []Param
+
+	// Results are the services the function provides: its non-error
+	// results, or its godi.Out fields.
+	Results []Result
+}
+
+// Param is a constructor parameter or a godi.In field.
+type Param struct {
+	// Type is the parameter's type. Group fields have a slice type.
+	Type reflect.Type
+
+	// Field is the name of the godi.In field, or empty for a parameter.
+	Field string
+
+	// Index is the parameter's position or the field's index in its struct.
+	Index int
+
+	// Key is the name:"..." tag, or nil.
+	Key any
+
+	// Group is the group:"..." tag, or empty.
+	Group string
+
+	// Optional is set by optional:"true": a missing service leaves the
+	// field zero.
+	Optional bool
+
+	// Soft is set by soft:"true" on a group field: only members already
+	// constructed are injected.
+	Soft bool
+}
+
+// Result is a service a constructor provides: a result or a godi.Out field.
+type Result struct {
+	// Type is the service type.
+	Type reflect.Type
+
+	// Field is the name of the godi.Out field, or empty for a result.
+	Field string
+
+	// Index is the result's position or the field's index in its struct.
+	Index int
+
+	// Key is the name:"..." tag, or nil.
+	Key any
+
+	// Group is the group:"..." tag, or empty.
+	Group string
+
+	// Optional is set by optional:"true": the constructor may leave the
+	// field nil.
+	Optional bool
+
+	// Profiles are the build profiles of a profile:"..." tag. Empty means
+	// the field is registered in every profile.
+	Profiles []string
+}
+
+// Dependency is a service a constructor needs resolved to be called.
+type Dependency struct {
+	// Type is the service type. For groups it is the element type of the
+	// group field.
+	Type reflect.Type
+
+	// Key is the key of a keyed service, or nil.
+	Key any
+
+	// Group is the group name, or empty.
+	Group string
+
+	// Optional reports whether the constructor can be called without it.
+	Optional bool
+
+	// Soft reports whether a group dependency only receives members that
+	// are already constructed.
+	Soft bool
+}
+
+// Analyze describes constructor. It returns an error for nil and for
+// functions godi cannot use as constructors.
+func Analyze(constructor any) (*Constructor, error) {
+	info, err := reflection.New().Analyze(constructor)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Constructor{
+		Type:         info.Type,
+		IsFunc:       info.IsFunc,
+		ParamObject:  info.IsParamObject,
+		ResultObject: info.IsResultObject,
+		HasError:     info.HasErrorReturn,
+		Params:       make([]Param, 0, len(info.Parameters)),
+		Results:      make([]Result, 0, len(info.Returns)),
+	}
+	for _, p := range info.Parameters {
+		c.Params = append(c.Params, Param{
+			Type:     p.Type,
+			Field:    p.Name,
+			Index:    p.Index,
+			Key:      p.Key,
+			Group:    p.Group,
+			Optional: p.Optional,
+			Soft:     p.Soft,
+		})
+	}
+	for _, r := range info.Returns {
+		if r.IsError {
+			continue
+		}
+		c.Results = append(c.Results, Result{
+			Type:     r.Type,
+			Field:    r.Name,
+			Index:    r.Index,
+			Key:      r.Key,
+			Group:    r.Group,
+			Optional: r.Optional,
+			Profiles: slices.Clone(r.Profiles),
+		})
+	}
+	if !info.IsFunc {
+		c.Results = append(c.Results, Result{Type: info.Type})
+	}
+	return c, nil
+}
+
+// Dependencies returns the services constructor needs, in parameter order.
+func Dependencies(constructor any) ([]Dependency, error) {
+	info, err := reflection.New().Analyze(constructor)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(info.Dependencies()))
+	for _, d := range info.Dependencies() {
+		deps = append(deps, Dependency{
+			Type:     d.Type,
+			Key:      d.Key,
+			Group:    d.Group,
+			Optional: d.Optional,
+			Soft:     d.Soft,
+		})
+	}
+	return deps, nil
+}
+
+// FormatType formats t the way godi's error messages do, without package
+// paths: *UserService rather than *github.com/acme/app.UserService.
+func FormatType(t reflect.Type) string {
+	return reflection.FormatType(t)
+}
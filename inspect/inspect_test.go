@@ -0,0 +1,119 @@
+package inspect_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/junioryono/godi/v5/inspect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	database struct{}
+	cache    struct{}
+	handler  interface{ Handle() }
+	service  struct{}
+	metrics  struct{}
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	t.Run("function", func(t *testing.T) {
+		t.Parallel()
+		c, err := inspect.Analyze(func(context.Context, *database) (*service, error) { return nil, nil })
+		require.NoError(t, err)
+
+		assert.True(t, c.IsFunc)
+		assert.True(t, c.HasError)
+		assert.False(t, c.ParamObject)
+		require.Len(t, c.Params, 2)
+		assert.Equal(t, reflect.TypeFor[*database](), c.Params[1].Type)
+		assert.Equal(t, 1, c.Params[1].Index)
+		require.Len(t, c.Results, 1)
+		assert.Equal(t, reflect.TypeFor[*service](), c.Results[0].Type)
+	})
+
+	t.Run("parameter_object", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			godi.In
+			DB       *database `name:"primary"`
+			Cache    *cache    `optional:"true"`
+			Handlers []handler `group:"routes" soft:"true"`
+		}
+		c, err := inspect.Analyze(func(params) *service { return nil })
+		require.NoError(t, err)
+
+		assert.True(t, c.ParamObject)
+		require.Len(t, c.Params, 3)
+		assert.Equal(t, inspect.Param{Type: reflect.TypeFor[*database](), Field: "DB", Index: 1, Key: "primary"}, c.Params[0])
+		assert.True(t, c.Params[1].Optional)
+		assert.Equal(t, "routes", c.Params[2].Group)
+		assert.True(t, c.Params[2].Soft)
+	})
+
+	t.Run("result_object", func(t *testing.T) {
+		t.Parallel()
+		type result struct {
+			godi.Out
+			Service *service
+			Metrics *metrics `optional:"true" profile:"dev,test"`
+			Handler handler  `group:"routes"`
+		}
+		c, err := inspect.Analyze(func() result { return result{} })
+		require.NoError(t, err)
+
+		assert.True(t, c.ResultObject)
+		require.Len(t, c.Results, 3)
+		assert.Equal(t, "Service", c.Results[0].Field)
+		assert.True(t, c.Results[1].Optional)
+		assert.Equal(t, []string{"dev", "test"}, c.Results[1].Profiles)
+		assert.Equal(t, "routes", c.Results[2].Group)
+	})
+
+	t.Run("instance", func(t *testing.T) {
+		t.Parallel()
+		c, err := inspect.Analyze(&database{})
+		require.NoError(t, err)
+
+		assert.False(t, c.IsFunc)
+		assert.Empty(t, c.Params)
+		assert.Equal(t, []inspect.Result{{Type: reflect.TypeFor[*database]()}}, c.Results)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+		_, err := inspect.Analyze(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	type params struct {
+		godi.In
+		DB       *database
+		Handlers []handler `group:"routes"`
+	}
+	deps, err := inspect.Dependencies(func(params) (*service, error) { return nil, errors.New("unused") })
+	require.NoError(t, err)
+
+	assert.Equal(t, []inspect.Dependency{
+		{Type: reflect.TypeFor[*database]()},
+		{Type: reflect.TypeFor[handler](), Group: "routes"},
+	}, deps)
+}
+
+func TestFormatType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "*service", inspect.FormatType(reflect.TypeFor[*service]()))
+	assert.Equal(t, "[]handler", inspect.FormatType(reflect.TypeFor[[]handler]()))
+	assert.Equal(t, "<nil>", inspect.FormatType(nil))
+}
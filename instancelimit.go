@@ -0,0 +1,81 @@
+package godi
+
+import "strconv"
+
+// MaxInstancesPerScope is an AddOption that bounds how many instances of
+// the registration one scope may construct, overriding
+// ProviderOptions.MaxInstancesPerScope for it. Resolving one more fails with
+// an InstanceLimitError. It suits transients that a request should only
+// need a few of, where a loop resolving them without bound would otherwise
+// pile up instances, and their disposables, until the scope closes.
+//
+// Example:
+//
+//	services.AddTransient(NewUpload, godi.MaxInstancesPerScope(16))
+//
+// It cannot be used with AddSingleton: singletons are constructed once,
+// outside any scope.
+func MaxInstancesPerScope(n int) AddOption {
+	return maxInstancesOption(n)
+}
+
+type maxInstancesOption int
+
+func (o maxInstancesOption) String() string {
+	return "MaxInstancesPerScope(" + strconv.Itoa(int(o)) + ")"
+}
+
+func (o maxInstancesOption) applyAddOption(opts *addOptions) {
+	opts.maxInstances = int(o)
+	opts.hasMaxInstances = true
+}
+
+// instanceLimits counts the instances a scope has constructed, for
+// ProviderOptions.MaxInstancesPerScope and godi.MaxInstancesPerScope.
+type instanceLimits struct {
+	total         int
+	perDescriptor map[*descriptor]int
+}
+
+// checkInstanceLimit counts the construction of an instance of descriptor
+// by s, failing instead if it would exceed the registration's own limit or,
+// for registrations without one, the scope's. Singletons and refreshing
+// services are constructed outside the scopes that use them and are not
+// counted.
+func (s *scope) checkInstanceLimit(r *resolution, requested instanceKey, d *descriptor) error {
+	limit, own := s.rootProvider.maxInstancesPerScope, d.maxInstances > 0
+	if own {
+		limit = d.maxInstances
+	}
+	if limit <= 0 || d.Lifetime == Singleton || d.refresh != nil {
+		return nil
+	}
+
+	s.limitsMu.Lock()
+	defer s.limitsMu.Unlock()
+
+	count := s.limits.total
+	if own {
+		count = s.limits.perDescriptor[d]
+	}
+	if count >= limit {
+		return &InstanceLimitError{
+			ServiceType:  requested.Type,
+			ServiceKey:   requested.Key,
+			ScopeID:      s.id,
+			Limit:        limit,
+			Registration: own,
+			Path:         append(r.path(), newResolutionFrame(requested, d)),
+		}
+	}
+
+	if !own {
+		s.limits.total++
+		return nil
+	}
+	if s.limits.perDescriptor == nil {
+		s.limits.perDescriptor = make(map[*descriptor]int)
+	}
+	s.limits.perDescriptor[d]++
+	return nil
+}
@@ -0,0 +1,140 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxInstancesPerScope(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, limit int, opts ...ModuleOption) Provider {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(opts...)
+		p, err := c.BuildWithOptions(&ProviderOptions{MaxInstancesPerScope: limit})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("fails_past_the_provider_limit", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 3, AddTransient(NewTTransient))
+		s := NewTestScope(t, p)
+
+		for range 3 {
+			RequireResolveFrom[*TTransient](t, s)
+		}
+		_, err := Resolve[*TTransient](s)
+		limitErr, ok := errors.AsType[*InstanceLimitError](err)
+		require.True(t, ok, "got %v", err)
+		assert.Equal(t, s.ID(), limitErr.ScopeID)
+		assert.Equal(t, 3, limitErr.Limit)
+		assert.False(t, limitErr.Registration)
+		assert.Contains(t, err.Error(), "reached its limit of 3 instances")
+	})
+
+	t.Run("scopes_are_counted_separately", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 1, AddScoped(NewTService))
+
+		for range 3 {
+			RequireResolveFrom[*TService](t, NewTestScope(t, p))
+		}
+	})
+
+	t.Run("cached_instances_are_counted_once", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 1, AddScoped(NewTService))
+		s := NewTestScope(t, p)
+
+		first := RequireResolveFrom[*TService](t, s)
+		assert.Same(t, first, RequireResolveFrom[*TService](t, s))
+	})
+
+	t.Run("singletons_are_not_counted", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 1,
+			AddSingleton(NewTService),
+			AddSingleton(NewTDependency),
+			AddScoped(NewTServiceWithDeps),
+		)
+
+		RequireResolveFrom[*TServiceWithDeps](t, NewTestScope(t, p))
+	})
+
+	t.Run("dependencies_report_their_path", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 2,
+			AddScoped(NewTService),
+			AddScoped(NewTDependency),
+			AddScoped(NewTServiceWithDeps),
+		)
+
+		_, err := Resolve[*TServiceWithDeps](NewTestScope(t, p))
+		limitErr, ok := errors.AsType[*InstanceLimitError](err)
+		require.True(t, ok, "got %v", err)
+		assert.Equal(t, PtrTypeOf[TDependency](), limitErr.ServiceType)
+		require.Len(t, limitErr.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), limitErr.Path[0].ServiceType)
+	})
+
+	t.Run("registration_limit_overrides_the_provider", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 1,
+			AddTransient(NewTTransient, MaxInstancesPerScope(2)),
+			AddScoped(NewTService),
+		)
+		s := NewTestScope(t, p)
+
+		// The registration's instances are not counted against the
+		// provider's limit.
+		RequireResolveFrom[*TTransient](t, s)
+		RequireResolveFrom[*TTransient](t, s)
+		RequireResolveFrom[*TService](t, s)
+
+		_, err := Resolve[*TTransient](s)
+		limitErr, ok := errors.AsType[*InstanceLimitError](err)
+		require.True(t, ok, "got %v", err)
+		assert.True(t, limitErr.Registration)
+		assert.Contains(t, err.Error(), "limit of 2 instances of *TTransient")
+	})
+
+	t.Run("registration_limit_without_provider_limit", func(t *testing.T) {
+		t.Parallel()
+		p := build(t, 0, AddTransient(NewTTransient, MaxInstancesPerScope(1)))
+		s := NewTestScope(t, p)
+
+		RequireResolveFrom[*TTransient](t, s)
+		_, err := Resolve[*TTransient](s)
+		_, ok := errors.AsType[*InstanceLimitError](err)
+		assert.True(t, ok, "got %v", err)
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		t.Parallel()
+		for name, tc := range map[string]struct {
+			opt     ModuleOption
+			message string
+		}{
+			"zero":      {AddTransient(NewTTransient, MaxInstancesPerScope(0)), "limit must be positive"},
+			"singleton": {AddSingleton(NewTTransient, MaxInstancesPerScope(1)), "cannot be used with AddSingleton"},
+		} {
+			c := NewCollection()
+			c.AddModules(tc.opt)
+			_, err := c.Build()
+			var validation *ValidationError
+			if assert.True(t, errors.As(err, &validation), "%s: got %v", name, err) {
+				assert.Contains(t, err.Error(), tc.message, name)
+			}
+		}
+
+		_, err := NewCollection().BuildWithOptions(&ProviderOptions{MaxInstancesPerScope: -1})
+		var validation *ValidationError
+		assert.True(t, errors.As(err, &validation), "got %v", err)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -41,6 +43,11 @@ type Analyzer struct {
 	// + misses). Used by tests to assert that callers cache the result and
 	// don't re-Analyze on the hot path. Not part of the public API.
 	analyzeCalls atomic.Int64
+
+	// Cache counters for functions, reported by CacheStats. Instances are
+	// never cached and count as neither.
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
 }
 
 // ConstructorInfo contains analyzed information about a constructor function or instance.
@@ -67,6 +74,7 @@ type ParameterInfo struct {
 	Index    int          // Parameter index or field index
 	Optional bool         // From optional:"true" tag
 	Group    string       // From group:"name" tag
+	Soft     bool         // From soft:"true" tag
 	Key      any          // From name:"key" tag
 	IsSlice  bool         // True if this is a slice type (for groups)
 	ElemType reflect.Type // Element type if slice
@@ -81,6 +89,12 @@ type ReturnInfo struct {
 	Group   string // From group:"name" tag
 	Key     any    // From name:"key" tag
 	IsError bool   // True if this is error type
+	// Optional is set from optional:"true" on an Out struct field: the
+	// constructor may leave the field nil.
+	Optional bool
+	// Profiles is set from profile:"a,b" on an Out struct field: the field
+	// is registered only when one of the profiles is active.
+	Profiles []string
 }
 
 // TagInfo contains parsed struct tag information.
@@ -88,7 +102,10 @@ type TagInfo struct {
 	Optional bool
 	Name     string
 	Group    string
+	Soft     bool
 	Ignore   bool
+	// Profiles is parsed from profile:"a,b" on Out struct fields.
+	Profiles []string
 }
 
 // Dependency represents a single dependency of a service.
@@ -102,6 +119,10 @@ type Dependency struct {
 	// Group for group dependencies (optional)
 	Group string
 
+	// Soft marks a group dependency that only receives members that are
+	// already constructed
+	Soft bool
+
 	// Optional indicates if this dependency can be nil
 	Optional bool
 
@@ -114,11 +135,13 @@ type Dependency struct {
 
 // ResultField represents a field in a result object (Out struct)
 type ResultField struct {
-	Name  string
-	Type  reflect.Type
-	Key   any    // for named results
-	Group string // for group results
-	Index int    // field index in struct
+	Name     string
+	Type     reflect.Type
+	Key      any      // for named results
+	Group    string   // for group results
+	Index    int      // field index in struct
+	Optional bool     // from optional:"true"; the field may be left nil
+	Profiles []string // from profile:"a,b"; registered only in those profiles
 }
 
 // ParamField represents a field in a parameter object (In struct)
@@ -181,9 +204,11 @@ func (a *Analyzer) Analyze(constructor any) (*ConstructorInfo, error) {
 	a.mu.RLock()
 	if cached, ok := a.cache[cacheKey]; ok {
 		a.mu.RUnlock()
+		a.cacheHits.Add(1)
 		return cached, nil
 	}
 	a.mu.RUnlock()
+	a.cacheMisses.Add(1)
 
 	// Perform analysis
 	info := &ConstructorInfo{
@@ -252,8 +277,20 @@ func (a *Analyzer) analyzeParamObject(info *ConstructorInfo, structType reflect.
 		return fmt.Errorf("In parameter must be a struct, got %v", structType.Kind())
 	}
 
-	params := make([]ParameterInfo, 0)
+	params, err := a.appendParamFields(nil, structType, "", []reflect.Type{structType})
+	if err != nil {
+		return err
+	}
+	info.Parameters = params
+	return nil
+}
 
+// appendParamFields appends the dependencies of an In struct's fields to
+// params. Fields that are In structs themselves, embedded or named, are
+// flattened: their fields become dependencies of the outer struct, named
+// with prefix. path holds the In structs being flattened, to reject structs
+// that contain themselves.
+func (a *Analyzer) appendParamFields(params []ParameterInfo, structType reflect.Type, prefix string, path []reflect.Type) ([]ParameterInfo, error) {
 	for i := 0; i < structType.NumField(); i++ {
 		field := structType.Field(i)
 
@@ -275,13 +312,37 @@ func (a *Analyzer) analyzeParamObject(info *ConstructorInfo, structType reflect.
 			continue
 		}
 
+		if hasEmbeddedType(field.Type, inType) {
+			nested := field.Type
+			if nested.Kind() == reflect.Pointer {
+				nested = nested.Elem()
+			}
+			if tagInfo.Name != "" || tagInfo.Group != "" || tagInfo.Optional || tagInfo.Soft {
+				return nil, fmt.Errorf("field %s%s: nested parameter objects cannot be tagged; tag their fields instead", prefix, field.Name)
+			}
+			if slices.Contains(path, nested) {
+				return nil, fmt.Errorf("field %s%s: parameter object %v contains itself", prefix, field.Name, nested)
+			}
+			var err error
+			params, err = a.appendParamFields(params, nested, prefix+field.Name+".", append(path, nested))
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if tagInfo.Soft && tagInfo.Group == "" {
+			return nil, fmt.Errorf("field %s%s: soft tag requires a group tag", prefix, field.Name)
+		}
+
 		param := ParameterInfo{
 			Type:     field.Type,
-			Name:     field.Name,
+			Name:     prefix + field.Name,
 			Tag:      string(field.Tag),
 			Index:    i,
 			Optional: tagInfo.Optional,
 			Group:    tagInfo.Group,
+			Soft:     tagInfo.Soft,
 			IsSlice:  field.Type.Kind() == reflect.Slice,
 			ElemType: a.getSliceElemType(field.Type),
 		}
@@ -294,8 +355,7 @@ func (a *Analyzer) analyzeParamObject(info *ConstructorInfo, structType reflect.
 		params = append(params, param)
 	}
 
-	info.Parameters = params
-	return nil
+	return params, nil
 }
 
 // analyzeReturns analyzes function return values or Out struct fields.
@@ -377,11 +437,13 @@ func (a *Analyzer) analyzeResultObject(info *ConstructorInfo, structType reflect
 		}
 
 		ret := ReturnInfo{
-			Type:  field.Type,
-			Name:  field.Name,
-			Tag:   string(field.Tag),
-			Index: i,
-			Group: tagInfo.Group,
+			Type:     field.Type,
+			Name:     field.Name,
+			Tag:      string(field.Tag),
+			Index:    i,
+			Group:    tagInfo.Group,
+			Optional: tagInfo.Optional,
+			Profiles: tagInfo.Profiles,
 		}
 
 		// Set key from name tag
@@ -414,6 +476,7 @@ func (a *Analyzer) buildDependencies(info *ConstructorInfo) []*Dependency {
 			Type:      param.Type,
 			Key:       param.Key,
 			Group:     param.Group,
+			Soft:      param.Soft,
 			Optional:  param.Optional,
 			Index:     param.Index,
 			FieldName: param.Name,
@@ -521,6 +584,20 @@ func (a *Analyzer) parseFieldTags(tag reflect.StructTag) TagInfo {
 		info.Group = val
 	}
 
+	// Check for soft tag (soft groups)
+	if val, ok := tag.Lookup("soft"); ok {
+		info.Soft = val == "true"
+	}
+
+	// Check for profile tag (conditional result-object fields)
+	if val, ok := tag.Lookup("profile"); ok {
+		for _, name := range strings.Split(val, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				info.Profiles = append(info.Profiles, name)
+			}
+		}
+	}
+
 	// Check for ignore tag
 	if val, ok := tag.Lookup("inject"); ok && val == "-" {
 		info.Ignore = true
@@ -564,6 +641,12 @@ func (a *Analyzer) CacheSize() int {
 	return len(a.cache)
 }
 
+// CacheStats returns how many function analyses were served from the cache
+// and how many had to be performed.
+func (a *Analyzer) CacheStats() (hits, misses uint64) {
+	return a.cacheHits.Load(), a.cacheMisses.Load()
+}
+
 // AnalyzeCalls returns the total number of calls made to Analyze. It is
 // intended for use by tests that want to assert callers cache the result
 // rather than re-analyzing on every operation. Not part of the public API.
@@ -577,6 +660,12 @@ func HasEmbeddedIn(t reflect.Type) bool {
 	return hasEmbeddedType(t, inType)
 }
 
+// HasEmbeddedOut reports whether t (or the struct it points to) embeds Out,
+// i.e. whether it is a result object.
+func HasEmbeddedOut(t reflect.Type) bool {
+	return hasEmbeddedType(t, outType)
+}
+
 // hasEmbeddedType checks if a type has an embedded field of the given type.
 func hasEmbeddedType(t, embedded reflect.Type) bool {
 	if t.Kind() == reflect.Pointer {
@@ -197,6 +197,69 @@ func TestAnalyzer_ParamObject(t *testing.T) {
 	assert.True(t, handlersParam.IsSlice, "Handlers should be a slice")
 }
 
+func TestAnalyzer_NestedParamObject(t *testing.T) {
+	type Common struct {
+		reflection.In
+		Database *Database
+		Logger   Logger `optional:"true"`
+	}
+	type params struct {
+		reflection.In
+		Common
+		Ignored *Common `inject:"-"`
+		Shared  *Common
+		Replica *Database `name:"replica"`
+	}
+
+	analyzer := reflection.New()
+	info, err := analyzer.Analyze(func(params) *UserService { return nil })
+	require.NoError(t, err)
+
+	names := make([]string, len(info.Parameters))
+	for i, param := range info.Parameters {
+		names[i] = param.Name
+	}
+	assert.Equal(t, []string{"Common.Database", "Common.Logger", "Shared.Database", "Shared.Logger", "Replica"}, names)
+	assert.True(t, info.Parameters[1].Optional)
+	assert.Equal(t, "replica", info.Parameters[4].Key)
+
+	type tagged struct {
+		reflection.In
+		Common Common `optional:"true"`
+	}
+	_, err = analyzer.Analyze(func(tagged) *UserService { return nil })
+	require.ErrorContains(t, err, "nested parameter objects cannot be tagged")
+
+	type recursive struct {
+		reflection.In
+		Next *recursive
+	}
+	_, err = analyzer.Analyze(func(recursive) *UserService { return nil })
+	require.ErrorContains(t, err, "contains itself")
+}
+
+func TestAnalyzer_SoftGroup(t *testing.T) {
+	analyzer := reflection.New()
+
+	type softParams struct {
+		reflection.In
+		Handlers []func() `group:"handlers" soft:"true"`
+	}
+	info, err := analyzer.Analyze(func(p softParams) *UserService { return nil })
+	require.NoError(t, err)
+	require.Len(t, info.Parameters, 1)
+	assert.True(t, info.Parameters[0].Soft)
+	assert.True(t, info.Dependencies()[0].Soft)
+
+	type softWithoutGroup struct {
+		reflection.In
+		Logger Logger `soft:"true"`
+	}
+	_, err = analyzer.Analyze(func(p softWithoutGroup) *UserService { return nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "soft tag requires a group tag")
+}
+
 func TestAnalyzer_ResultObject(t *testing.T) {
 	analyzer := reflection.New()
 
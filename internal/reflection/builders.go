@@ -80,9 +80,27 @@ func (b *ParamObjectBuilder) BuildParamObject(
 	// Create new instance of the param struct
 	// Always create a pointer first, then we'll convert if needed
 	structPtr := reflect.New(structType)
-	structValue := structPtr.Elem()
+	if err := b.PopulateParamObject(structPtr.Elem(), resolver); err != nil {
+		return reflect.Value{}, err
+	}
 
-	// Populate each field
+	// Return the appropriate type (pointer or value)
+	if paramType.Kind() == reflect.Pointer {
+		return structPtr, nil
+	}
+	return structPtr.Elem(), nil
+}
+
+// PopulateParamObject resolves the exported fields of an addressable struct
+// value in place, using the same tags as In structs. Fields of optional
+// dependencies that are not registered keep their current value. A failed
+// field is reported as a *FieldError.
+func (b *ParamObjectBuilder) PopulateParamObject(structValue reflect.Value, resolver DependencyResolver) error {
+	if resolver == nil {
+		return fmt.Errorf("resolver cannot be nil")
+	}
+
+	structType := structValue.Type()
 	for i := 0; i < structType.NumField(); i++ {
 		field := structType.Field(i)
 
@@ -104,6 +122,17 @@ func (b *ParamObjectBuilder) BuildParamObject(
 			continue
 		}
 
+		// Nested In structs are populated field by field
+		if hasEmbeddedType(field.Type, inType) {
+			if err := b.populateNested(structValue.Field(i), resolver); err != nil {
+				if fieldErr, ok := err.(*FieldError); ok {
+					return &FieldError{Field: field.Name + "." + fieldErr.Field, Cause: fieldErr.Cause}
+				}
+				return &FieldError{Field: field.Name, Cause: err}
+			}
+			continue
+		}
+
 		// Resolve dependency for this field
 		fieldValue, err := b.resolveFieldDependency(&field, tagInfo, resolver)
 		if err != nil {
@@ -113,7 +142,12 @@ func (b *ParamObjectBuilder) BuildParamObject(
 			if tagInfo.Optional && isServiceNotFound(err) {
 				continue
 			}
-			return reflect.Value{}, fmt.Errorf("failed to resolve field %s: %w", field.Name, err)
+			if tagInfo.Optional {
+				if handler, ok := resolver.(OptionalFailureHandler); ok && handler.ForgiveOptional(field.Name, field.Type, err) {
+					continue
+				}
+			}
+			return &FieldError{Field: field.Name, Cause: err}
 		}
 
 		// Set the field value
@@ -123,11 +157,36 @@ func (b *ParamObjectBuilder) BuildParamObject(
 		}
 	}
 
-	// Return the appropriate type (pointer or value)
-	if paramType.Kind() == reflect.Pointer {
-		return structPtr, nil
+	return nil
+}
+
+// populateNested populates a field holding a nested In struct, or a
+// pointer to one, which it allocates.
+func (b *ParamObjectBuilder) populateNested(field reflect.Value, resolver DependencyResolver) error {
+	if field.Kind() != reflect.Pointer {
+		return b.PopulateParamObject(field, resolver)
+	}
+	nested := reflect.New(field.Type().Elem())
+	if err := b.PopulateParamObject(nested.Elem(), resolver); err != nil {
+		return err
 	}
-	return structValue, nil
+	field.Set(nested)
+	return nil
+}
+
+// FieldError reports the struct field whose dependency could not be
+// resolved.
+type FieldError struct {
+	Field string
+	Cause error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("failed to resolve field %s: %v", e.Field, e.Cause)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Cause
 }
 
 // isServiceNotFound reports whether err is a direct "service not registered"
@@ -158,7 +217,15 @@ func (b *ParamObjectBuilder) resolveFieldDependency(
 		}
 
 		elemType := fieldType.Elem()
-		values, err := resolver.GetGroup(elemType, tagInfo.Group)
+		var (
+			values []any
+			err    error
+		)
+		if soft, ok := resolver.(SoftGroupResolver); ok && tagInfo.Soft {
+			values, err = soft.GetSoftGroup(elemType, tagInfo.Group)
+		} else {
+			values, err = resolver.GetGroup(elemType, tagInfo.Group)
+		}
 		if err != nil {
 			return reflect.Value{}, err
 		}
@@ -299,6 +366,21 @@ type DependencyResolver interface {
 	GetGroup(t reflect.Type, group string) ([]any, error)
 }
 
+// SoftGroupResolver is implemented by resolvers that support soft groups,
+// fields tagged soft:"true" that receive only the group members already
+// constructed. Other resolvers resolve soft groups like regular ones.
+type SoftGroupResolver interface {
+	GetSoftGroup(t reflect.Type, group string) ([]any, error)
+}
+
+// OptionalFailureHandler is implemented by resolvers that can let an
+// optional field survive the failure of its registered service.
+// ForgiveOptional is called with the field and the resolution error; if it
+// returns true, the field is left at its zero value.
+type OptionalFailureHandler interface {
+	ForgiveOptional(field string, fieldType reflect.Type, err error) bool
+}
+
 // PanicError represents a panic that occurred during constructor invocation.
 // It captures the panic value and stack trace for debugging.
 type PanicError struct {
@@ -311,6 +393,34 @@ func (e PanicError) Error() string {
 	return fmt.Sprintf("constructor %v panicked: %v", e.Constructor, e.Panic)
 }
 
+// ReturnError wraps the non-nil error returned by a constructor, telling it
+// apart from failures to build the constructor's arguments.
+type ReturnError struct {
+	Err error
+}
+
+func (e *ReturnError) Error() string {
+	return "constructor error: " + e.Err.Error()
+}
+
+func (e *ReturnError) Unwrap() error {
+	return e.Err
+}
+
+// ArgumentError wraps a failure to build a constructor's arguments, which
+// may itself carry a ReturnError or PanicError of a dependency.
+type ArgumentError struct {
+	Err error
+}
+
+func (e *ArgumentError) Error() string {
+	return "failed to build arguments: " + e.Err.Error()
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
 // ConstructorInvoker invokes constructors with resolved dependencies.
 type ConstructorInvoker struct {
 	analyzer     *Analyzer
@@ -330,6 +440,17 @@ func NewConstructorInvoker(analyzer *Analyzer) *ConstructorInvoker {
 func (ci *ConstructorInvoker) Invoke(
 	info *ConstructorInfo,
 	resolver DependencyResolver,
+) (results []reflect.Value, err error) {
+	return ci.InvokeWith(info, resolver)
+}
+
+// InvokeWith is like Invoke, but passes leading as the constructor's first
+// arguments and resolves only the remaining parameters. Parameter objects
+// cannot take leading arguments.
+func (ci *ConstructorInvoker) InvokeWith(
+	info *ConstructorInfo,
+	resolver DependencyResolver,
+	leading ...reflect.Value,
 ) (results []reflect.Value, err error) {
 	// Handle instance values
 	if !info.IsFunc {
@@ -340,9 +461,9 @@ func (ci *ConstructorInvoker) Invoke(
 	// Build arguments into a pooled scratch slice so the per-resolve
 	// allocation cost is zero for the args backing array. The pool is
 	// returned after Call (which copies the values it needs).
-	argsPtr, err := ci.buildArguments(info, resolver)
+	argsPtr, err := ci.buildArguments(info, resolver, leading)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build arguments: %w", err)
+		return nil, &ArgumentError{Err: err}
 	}
 	defer releaseArgs(argsPtr)
 
@@ -362,7 +483,7 @@ func (ci *ConstructorInvoker) Invoke(
 		lastResult := results[len(results)-1]
 		if !lastResult.IsNil() {
 			if err, ok := lastResult.Interface().(error); ok {
-				return nil, fmt.Errorf("constructor error: %w", err)
+				return nil, &ReturnError{Err: err}
 			}
 		}
 	}
@@ -392,7 +513,12 @@ func (ci *ConstructorInvoker) invokeWithRecovery(info *ConstructorInfo, args []r
 func (ci *ConstructorInvoker) buildArguments(
 	info *ConstructorInfo,
 	resolver DependencyResolver,
+	leading []reflect.Value,
 ) (*[]reflect.Value, error) {
+	if len(leading) > len(info.Parameters) || (info.IsParamObject && len(leading) > 0) {
+		return nil, fmt.Errorf("%d leading arguments for %v", len(leading), info.Type)
+	}
+
 	if info.IsParamObject {
 		// Build the In struct
 		paramType := info.Type.In(0)
@@ -413,8 +539,9 @@ func (ci *ConstructorInvoker) buildArguments(
 
 	argsPtr := borrowArgs(numParams)
 	args := *argsPtr
-	for i, param := range info.Parameters {
-		value, err := ci.resolveParameter(&param, resolver)
+	copy(args, leading)
+	for i := len(leading); i < numParams; i++ {
+		value, err := ci.resolveParameter(&info.Parameters[i], resolver)
 		if err != nil {
 			releaseArgs(argsPtr)
 			return nil, fmt.Errorf("failed to resolve parameter %d: %w", i, err)
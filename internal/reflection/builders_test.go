@@ -381,6 +381,35 @@ func TestConstructorInvoker_ResolveParameter(t *testing.T) {
 	})
 }
 
+func TestConstructorInvoker_InvokeWith(t *testing.T) {
+	analyzer := reflection.New()
+	invoker := reflection.NewConstructorInvoker(analyzer)
+
+	resolver := NewTestResolver()
+	resolver.values[reflect.TypeFor[*Database]()] = &Database{ConnectionString: "resolved"}
+
+	t.Run("leading arguments are passed through", func(t *testing.T) {
+		constructor := func(prefix string, db *Database) string {
+			return prefix + db.ConnectionString
+		}
+
+		info, err := analyzer.Analyze(constructor)
+		require.NoError(t, err)
+
+		results, err := invoker.InvokeWith(info, resolver, reflect.ValueOf("db:"))
+		require.NoError(t, err)
+		assert.Equal(t, "db:resolved", results[0].Interface())
+	})
+
+	t.Run("too many leading arguments", func(t *testing.T) {
+		info, err := analyzer.Analyze(func(string) string { return "" })
+		require.NoError(t, err)
+
+		_, err = invoker.InvokeWith(info, resolver, reflect.ValueOf("a"), reflect.ValueOf("b"))
+		assert.Error(t, err)
+	})
+}
+
 // Test edge cases in BuildParamObject
 func TestParamObjectBuilder_EdgeCases(t *testing.T) {
 	analyzer := reflection.New()
@@ -552,6 +581,50 @@ func TestParamObjectBuilder_MoreErrorScenarios(t *testing.T) {
 	})
 }
 
+// Test populating an existing struct in place
+func TestParamObjectBuilder_PopulateParamObject(t *testing.T) {
+	analyzer := reflection.New()
+	builder := reflection.NewParamObjectBuilder(analyzer)
+
+	type Target struct {
+		DB      *Database
+		Backup  *Database `name:"backup"`
+		Skipped *Database `inject:"-"`
+		Name    string    `inject:"-"`
+		hidden  *Database
+	}
+
+	t.Run("fills tagged fields and keeps the rest", func(t *testing.T) {
+		resolver := NewTestResolver()
+		db := &Database{ConnectionString: "primary"}
+		resolver.values[reflect.TypeFor[*Database]()] = db
+		resolver.keyedValues["backup"] = &Database{ConnectionString: "backup"}
+
+		target := &Target{Name: "preset"}
+		err := builder.PopulateParamObject(reflect.ValueOf(target).Elem(), resolver)
+		require.NoError(t, err)
+
+		assert.Same(t, db, target.DB)
+		assert.Equal(t, "backup", target.Backup.ConnectionString)
+		assert.Nil(t, target.Skipped)
+		assert.Nil(t, target.hidden)
+		assert.Equal(t, "preset", target.Name)
+	})
+
+	t.Run("reports the failing field", func(t *testing.T) {
+		resolver := NewTestResolver()
+		resolver.shouldFail = true
+		resolver.failError = errors.New("database not found")
+
+		err := builder.PopulateParamObject(reflect.ValueOf(&Target{}).Elem(), resolver)
+		fieldErr, ok := errors.AsType[*reflection.FieldError](err)
+		require.True(t, ok, "expected FieldError, got %v", err)
+		assert.Equal(t, "DB", fieldErr.Field)
+		assert.ErrorIs(t, err, resolver.failError)
+		assert.EqualError(t, err, "failed to resolve field DB: database not found")
+	})
+}
+
 // Test ConstructorInvoker with more param object edge cases
 func TestConstructorInvoker_MoreParamObjectEdgeCases(t *testing.T) {
 	analyzer := reflection.New()
@@ -0,0 +1,394 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// An InvokeOption modifies the behavior of Invoke, InvokeScoped and
+// InvokeEach.
+type InvokeOption interface {
+	applyInvokeOption(*invokeOptions)
+}
+
+type invokeOptions struct {
+	maxConcurrency   int
+	perMemberScope   bool
+	memoizePerInvoke bool
+}
+
+// MaxConcurrency is an InvokeOption that limits how many group members
+// InvokeEach runs at once. Values below 1 mean no limit, which is also the
+// default.
+func MaxConcurrency(n int) InvokeOption {
+	return maxConcurrencyOption(n)
+}
+
+type maxConcurrencyOption int
+
+func (o maxConcurrencyOption) String() string {
+	return fmt.Sprintf("MaxConcurrency(%d)", int(o))
+}
+
+func (o maxConcurrencyOption) applyInvokeOption(opts *invokeOptions) {
+	opts.maxConcurrency = int(o)
+}
+
+// PerMemberScope is an InvokeOption that resolves each group member in its
+// own child scope, closed once the member's function returns. The function
+// receives that scope's context, so scoped dependencies of one member are
+// never shared with another.
+func PerMemberScope() InvokeOption {
+	return perMemberScopeOption{}
+}
+
+type perMemberScopeOption struct{}
+
+func (perMemberScopeOption) String() string {
+	return "PerMemberScope()"
+}
+
+func (perMemberScopeOption) applyInvokeOption(opts *invokeOptions) {
+	opts.perMemberScope = true
+}
+
+// MemoizePerInvoke is an InvokeOption for Invoke that shares transients
+// within the call: every parameter of the function, and every constructor
+// run to resolve them, that needs the same transient service gets one
+// instance instead of a new one each. The next call starts afresh. Like
+// other transients, the instances are disposed with the scope.
+func MemoizePerInvoke() InvokeOption {
+	return memoizePerInvokeOption{}
+}
+
+type memoizePerInvokeOption struct{}
+
+func (memoizePerInvokeOption) String() string {
+	return "MemoizePerInvoke()"
+}
+
+func (memoizePerInvokeOption) applyInvokeOption(opts *invokeOptions) {
+	opts.memoizePerInvoke = true
+}
+
+// Invoke resolves the parameters of fn from p and calls it. fn may take
+// services, keyed services and godi.In parameter objects like a
+// constructor, and return nothing or an error, which Invoke returns.
+// Resolution failures are returned without calling fn.
+//
+// Example:
+//
+//	err := godi.Invoke(scope, func(orders *OrderService, audit *AuditLog) error {
+//	    return orders.Reconcile(audit)
+//	}, godi.MemoizePerInvoke())
+func Invoke(p Provider, fn any, opts ...InvokeOption) error {
+	if p == nil {
+		return ErrProviderNil
+	}
+	fnType := reflect.TypeOf(fn)
+	if fn == nil || fnType.Kind() != reflect.Func {
+		return &ValidationError{ServiceType: fnType, Cause: fmt.Errorf("Invoke requires a function, got %T", fn)}
+	}
+	if fnType.NumOut() > 1 || fnType.NumOut() == 1 && fnType.Out(0) != reflect.TypeFor[error]() {
+		return &ValidationError{ServiceType: fnType, Cause: fmt.Errorf("Invoke function must return nothing or an error")}
+	}
+
+	options := invokeOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyInvokeOption(&options)
+		}
+	}
+
+	target, err := invokerOf(p)
+	if err != nil {
+		return err
+	}
+	call, err := target.invocation(options.memoizePerInvoke)
+	if err != nil {
+		return err
+	}
+	info, err := call.analyzer.Analyze(fn)
+	if err != nil {
+		return &ReflectionAnalysisError{Constructor: fn, Operation: "analyze", Cause: err}
+	}
+
+	_, err = call.analyzer.GetInvoker().Invoke(info, call.resolver)
+	// A failed argument carries the ReturnError of the dependency's
+	// constructor; only fn's own error is returned as is.
+	if _, failed := errors.AsType[*reflection.ArgumentError](err); !failed {
+		if returnErr, ok := errors.AsType[*reflection.ReturnError](err); ok {
+			return returnErr.Err
+		}
+	}
+	if err != nil {
+		return call.translate(err, ResolutionSite{Operation: "Invoke", ServiceType: fnType})
+	}
+	return nil
+}
+
+// InvokeScoped creates a scope of p for ctx, calls Invoke with fn on it and
+// closes the scope, also when resolution or fn fails or fn panics, so a
+// unit of work needs neither CreateScope nor a deferred Close. A
+// context.Context parameter of fn receives the scope's context. Close
+// errors are joined with the invocation's error.
+//
+// Example:
+//
+//	for job := range jobs {
+//	    err := godi.InvokeScoped(ctx, provider, func(ctx context.Context, worker *JobWorker) error {
+//	        return worker.Run(ctx, job)
+//	    })
+//	}
+func InvokeScoped(ctx context.Context, p Provider, fn any, opts ...InvokeOption) (err error) {
+	if p == nil {
+		return ErrProviderNil
+	}
+	s, err := p.CreateScope(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := s.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return Invoke(s, fn, opts...)
+}
+
+// sharedInstances holds the instances shared within one top-level call.
+type sharedInstances struct {
+	mu        sync.Mutex
+	instances map[instanceKey]any
+}
+
+// resolveShared returns the instance of a PerResolution service, or of a
+// transient shared by MemoizePerInvoke, for r's top-level call, creating it
+// on first use. cached reports whether the instance was already shared.
+func (s *scope) resolveShared(r *resolution, key instanceKey, descriptor *descriptor) (any, bool, error) {
+	shared := r.sharedInstances()
+	shared.mu.Lock()
+	instance, ok := shared.instances[key]
+	shared.mu.Unlock()
+	if ok {
+		s.rootProvider.cacheHits.Add(1)
+		return instance, true, nil
+	}
+
+	instance, err := s.createInstance(r, key, descriptor)
+	if err != nil {
+		return nil, false, err
+	}
+
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	if existing, ok := shared.instances[key]; ok {
+		// Constructed concurrently by a goroutine of the same call; the
+		// spare instance is disposed with the scope.
+		return existing, true, nil
+	}
+	if shared.instances == nil {
+		shared.instances = make(map[instanceKey]any)
+	}
+	shared.instances[key] = instance
+	return instance, false, nil
+}
+
+// InvokeEach resolves the members of a group and calls fn for each of them
+// concurrently, waiting for all calls to finish. fn receives the context of
+// the scope the member was resolved in. Failures and panics of individual
+// members do not stop the others; they are returned together, in member
+// order, as InvokeEachErrors.
+//
+// Example:
+//
+//	err := godi.InvokeEach(scope, "handlers", func(ctx context.Context, h Handler) error {
+//	    return h.Handle(ctx)
+//	}, godi.MaxConcurrency(8))
+func InvokeEach[T any](p Provider, group string, fn func(context.Context, T) error, opts ...InvokeOption) error {
+	elemType := reflect.TypeFor[T]()
+	if p == nil {
+		return ErrProviderNil
+	}
+	if fn == nil {
+		return &ValidationError{ServiceType: elemType, Cause: fmt.Errorf("InvokeEach function cannot be nil")}
+	}
+	if group == "" {
+		return &ValidationError{ServiceType: elemType, Cause: ErrGroupNameEmpty}
+	}
+
+	options := invokeOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyInvokeOption(&options)
+		}
+	}
+
+	target, err := invokerOf(p)
+	if err != nil {
+		return err
+	}
+	call, err := target.invocation(false)
+	if err != nil {
+		return err
+	}
+	ctx := call.context
+
+	if options.perMemberScope {
+		if n := target.groupSize(elemType, group); n > 0 {
+			return runEach(group, n, options.maxConcurrency, func(i int) error {
+				return invokeInChildScope(ctx, p, group, i, fn)
+			})
+		}
+		// No local members: a fallback provider's group is resolved as a
+		// whole below.
+	}
+
+	members, err := ResolveGroup[T](p, group)
+	if err != nil {
+		return err
+	}
+	return runEach(group, len(members), options.maxConcurrency, func(i int) error {
+		return fn(ctx, members[i])
+	})
+}
+
+// invokeInChildScope resolves member i of group in a new child scope of p
+// and calls fn with it.
+func invokeInChildScope[T any](ctx context.Context, p Provider, group string, i int, fn func(context.Context, T) error) (err error) {
+	child, err := p.CreateScope(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := child.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+
+	target, err := invokerOf(child)
+	if err != nil {
+		return err
+	}
+	instance, err := target.groupMember(reflect.TypeFor[T](), group, i)
+	if err != nil {
+		return err
+	}
+	member, ok := instance.(T)
+	if !ok {
+		return &TypeMismatchError{
+			Expected: reflect.TypeFor[T](),
+			Actual:   reflect.TypeOf(instance),
+			Context:  "group member type assertion",
+		}
+	}
+	return fn(child.Context(), member)
+}
+
+// invoker is implemented by the Provider and Scope types of this package,
+// sealed and composed views included, so the helpers calling functions with
+// resolved parameters work with any of them.
+type invoker interface {
+	// invocation starts a call resolving its parameters from the target.
+	// shareTransients is set by MemoizePerInvoke.
+	invocation(shareTransients bool) (*invocation, error)
+
+	// groupSize and groupMember resolve the registered members of a group
+	// one at a time, for PerMemberScope.
+	groupSize(elemType reflect.Type, group string) int
+	groupMember(elemType reflect.Type, group string, i int) (any, error)
+}
+
+// invocation resolves the parameters of one call.
+type invocation struct {
+	analyzer  *reflection.Analyzer
+	resolver  reflection.DependencyResolver
+	context   context.Context // the context of the scope resolving
+	translate func(err error, site ResolutionSite) error
+}
+
+// invokerOf returns the invoker behind p.
+func invokerOf(p Provider) (invoker, error) {
+	switch v := p.(type) {
+	case nil:
+		return nil, ErrProviderNil
+	case invoker:
+		return v, nil
+	default:
+		return nil, errUnsupportedProvider(p)
+	}
+}
+
+func (p *provider) invocation(shareTransients bool) (*invocation, error) {
+	if p.isDisposed() {
+		return nil, ErrProviderDisposed
+	}
+	return p.rootScope.invocation(shareTransients)
+}
+
+func (p *provider) groupSize(elemType reflect.Type, group string) int {
+	return p.rootScope.groupSize(elemType, group)
+}
+
+func (p *provider) groupMember(elemType reflect.Type, group string, i int) (any, error) {
+	return p.rootScope.groupMember(elemType, group, i)
+}
+
+func (s *scope) invocation(shareTransients bool) (*invocation, error) {
+	return &invocation{
+		analyzer: s.rootProvider.analyzer,
+		// The root frame stands for the call itself, so PerResolution
+		// services are shared among all parameters.
+		resolver:  &resolution{scope: s, shareTransients: shareTransients},
+		context:   s.Context(),
+		translate: s.translateError,
+	}, nil
+}
+
+func (s *scope) groupSize(elemType reflect.Type, group string) int {
+	return len(s.rootProvider.findGroupDescriptors(elemType, group))
+}
+
+func (s *scope) groupMember(elemType reflect.Type, group string, i int) (any, error) {
+	descriptors := s.rootProvider.findGroupDescriptors(elemType, group)
+	if i >= len(descriptors) {
+		return nil, &ResolutionError{ServiceType: elemType, Cause: ErrServiceNotFound}
+	}
+	descriptor := descriptors[i]
+	key := instanceKey{Type: descriptor.Type, Key: descriptor.Key, Group: descriptor.Group}
+	return s.resolve(nil, key, descriptor)
+}
+
+// runEach calls call(i) for i in [0, n) with at most limit calls running at
+// once (unlimited when limit < 1) and joins the failures in index order.
+func runEach(group string, n, limit int, call func(i int) error) error {
+	if limit < 1 || limit > n {
+		limit = n
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = &InvokeEachError{Group: group, Index: i, Cause: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+			if err := call(i); err != nil {
+				errs[i] = &InvokeEachError{Group: group, Index: i, Cause: err}
+			}
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
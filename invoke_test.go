@@ -0,0 +1,356 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invokeScoped struct{ id int64 }
+
+func TestInvokeEach(t *testing.T) {
+	t.Parallel()
+
+	handlers := func(n int) ModuleOption {
+		opts := make([]ModuleOption, n)
+		for i := range n {
+			opts[i] = AddSingleton(NewTServiceWithValue("h", i), Group("handlers"))
+		}
+		return NewModule("handlers", opts...)
+	}
+
+	t.Run("calls_every_member", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, handlers(5))
+
+		var mu sync.Mutex
+		var seen []int
+		err := InvokeEach(s, "handlers", func(ctx context.Context, h *TService) error {
+			assert.Same(t, s.Context(), ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, h.Value)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, seen)
+	})
+
+	t.Run("max_concurrency_bounds_parallelism", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, handlers(8))
+
+		var running, peak atomic.Int64
+		err := InvokeEach(p, "handlers", func(context.Context, *TService) error {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		}, MaxConcurrency(2))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, peak.Load(), int64(2))
+	})
+
+	t.Run("aggregates_errors_and_panics_in_member_order", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, handlers(4))
+		boom := errors.New("boom")
+
+		err := InvokeEach(s, "handlers", func(_ context.Context, h *TService) error {
+			switch h.Value {
+			case 1:
+				return boom
+			case 3:
+				panic("bad handler")
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, boom)
+
+		var failed []int
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			invokeErr, ok := e.(*InvokeEachError)
+			require.True(t, ok)
+			failed = append(failed, invokeErr.Index)
+		}
+		assert.Equal(t, []int{1, 3}, failed)
+		assert.Contains(t, err.Error(), "bad handler")
+	})
+
+	t.Run("per_member_scope", func(t *testing.T) {
+		t.Parallel()
+		var ids atomic.Int64
+		type member struct{ dep *invokeScoped }
+		s := BuildScope(t,
+			AddScoped(func() *invokeScoped { return &invokeScoped{id: ids.Add(1)} }),
+			AddScoped(func(d *invokeScoped) *member { return &member{dep: d} }, Group("members")),
+			AddScoped(func(d *invokeScoped) *member { return &member{dep: d} }, Group("members")),
+		)
+
+		var mu sync.Mutex
+		deps := map[int64]bool{}
+		err := InvokeEach(s, "members", func(ctx context.Context, m *member) error {
+			child, err := FromContext(ctx)
+			require.NoError(t, err)
+			assert.NotEqual(t, s.ID(), child.ID())
+			mu.Lock()
+			defer mu.Unlock()
+			deps[m.dep.id] = true
+			return nil
+		}, PerMemberScope())
+		require.NoError(t, err)
+		assert.Len(t, deps, 2, "each member gets its own scoped dependencies")
+	})
+
+	t.Run("sealed_and_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t, handlers(2)), AllowServices(PtrTypeOf[TService]()))
+		require.NoError(t, err)
+		app, err := Compose(sealed, BuildProvider(t, handlers(3)))
+		require.NoError(t, err)
+
+		for p, members := range map[Provider]int64{sealed: 2, app: 5} {
+			var calls atomic.Int64
+			err := InvokeEach(p, "handlers", func(ctx context.Context, h *TService) error {
+				child, err := FromContext(ctx)
+				require.NoError(t, err)
+				_, unwrapped := child.(*scope)
+				assert.False(t, unwrapped, "member scopes are created through the view")
+				calls.Add(1)
+				return nil
+			}, PerMemberScope())
+			require.NoError(t, err)
+			assert.Equal(t, members, calls.Load())
+		}
+	})
+
+	t.Run("empty_group", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t)
+
+		called := false
+		err := InvokeEach(s, "handlers", func(context.Context, *TService) error {
+			called = true
+			return nil
+		}, MaxConcurrency(4))
+		require.NoError(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t)
+		fn := func(context.Context, *TService) error { return nil }
+
+		require.ErrorIs(t, InvokeEach[*TService](nil, "handlers", fn), ErrProviderNil)
+		require.ErrorIs(t, InvokeEach(s, "", fn), ErrGroupNameEmpty)
+		var validationErr *ValidationError
+		require.ErrorAs(t, InvokeEach[*TService](s, "handlers", nil), &validationErr)
+	})
+}
+
+func TestInvoke(t *testing.T) {
+	t.Parallel()
+
+	transients := NewModule("transients",
+		AddTransient(NewTDependency),
+		AddTransient(NewTService),
+		AddTransient(NewTServiceWithDeps),
+	)
+
+	t.Run("resolves_parameters", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, transients)
+
+		called := false
+		err := Invoke(s, func(svc *TServiceWithDeps, info ResolveInfo) {
+			called = true
+			assert.Equal(t, "dep", svc.Dep.Name)
+			assert.Equal(t, s.ID(), info.ScopeID)
+			assert.Nil(t, info.ServiceType)
+		})
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("transients_are_not_shared_by_default", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, transients)
+
+		err := Invoke(s, func(dep *TDependency, svc *TServiceWithDeps) {
+			assert.NotSame(t, dep, svc.Dep)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("memoize_per_invoke_shares_transients_within_the_call", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, transients)
+
+		var first *TDependency
+		err := Invoke(s, func(dep *TDependency, svc *TServiceWithDeps, again *TDependency) {
+			assert.Same(t, dep, svc.Dep, "nested resolutions share the instance")
+			assert.Same(t, dep, again)
+			first = dep
+		}, MemoizePerInvoke())
+		require.NoError(t, err)
+
+		err = Invoke(s, func(dep *TDependency) {
+			assert.NotSame(t, first, dep, "each call starts afresh")
+		}, MemoizePerInvoke())
+		require.NoError(t, err)
+
+		dep, err := Resolve[*TDependency](s)
+		require.NoError(t, err)
+		assert.NotSame(t, first, dep)
+	})
+
+	t.Run("sealed_and_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t, transients))
+		require.NoError(t, err)
+		app, err := Compose(BuildProvider(t, AddSingleton(NewTDisposable)), sealed)
+		require.NoError(t, err)
+
+		for _, p := range []Provider{sealed, app} {
+			called := false
+			err := Invoke(p, func(dep *TDependency, svc *TServiceWithDeps, got Provider) {
+				called = true
+				assert.Same(t, dep, svc.Dep)
+				assert.Same(t, p, got)
+			}, MemoizePerInvoke())
+			require.NoError(t, err)
+			assert.True(t, called)
+		}
+	})
+
+	t.Run("returns_function_error", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, transients)
+
+		want := errors.New("reconcile failed")
+		err := Invoke(s, func(*TService) error { return want }, MemoizePerInvoke())
+		assert.Same(t, want, err)
+	})
+
+	t.Run("dependency_errors_keep_paths", func(t *testing.T) {
+		t.Parallel()
+		flaky := &flakyDependency{err: errors.New("connection refused")}
+		s := BuildScope(t, AddTransient(flaky.constructor))
+
+		err := Invoke(s, func(*TDependency) {})
+		require.ErrorIs(t, err, flaky.err)
+		_, ok := errors.AsType[*ConstructorInvocationError](err)
+		assert.True(t, ok, "expected the dependency's failure, got %v", err)
+	})
+
+	t.Run("resolution_errors_keep_paths", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, AddTransient(NewTServiceWithDeps), AddTransient(NewTService))
+
+		called := false
+		err := Invoke(s, func(*TServiceWithDeps) { called = true }, MemoizePerInvoke())
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.False(t, called)
+
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		require.Len(t, resErr.Path, 2)
+		assert.Equal(t, PtrTypeOf[TServiceWithDeps](), resErr.Path[0].ServiceType)
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, transients)
+
+		assert.ErrorIs(t, Invoke(nil, func() {}), ErrProviderNil)
+
+		var validationErr *ValidationError
+		assert.ErrorAs(t, Invoke(s, nil), &validationErr)
+		assert.ErrorAs(t, Invoke(s, "not a function"), &validationErr)
+		assert.ErrorAs(t, Invoke(s, func() *TService { return nil }), &validationErr)
+	})
+}
+
+func TestInvokeScoped(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves_from_a_new_scope_and_closes_it", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+
+		var seen *TDisposable
+		var scopeID string
+		err := InvokeScoped(context.Background(), p, func(ctx context.Context, d *TDisposable, info ScopeInfo) {
+			seen = d
+			scopeID = info.ID
+			s, err := FromContext(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, s.ID(), info.ID)
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, p.(*provider).rootScope.id, scopeID)
+		assert.True(t, seen.IsClosed())
+	})
+
+	t.Run("closes_the_scope_when_fn_fails", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+		boom := errors.New("boom")
+
+		var seen *TDisposable
+		err := InvokeScoped(context.Background(), p, func(d *TDisposable) error {
+			seen = d
+			return boom
+		})
+		assert.ErrorIs(t, err, boom)
+		assert.True(t, seen.IsClosed())
+	})
+
+	t.Run("closes_the_scope_when_fn_panics", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+
+		var seen *TDisposable
+		err := InvokeScoped(context.Background(), p, func(d *TDisposable) {
+			seen = d
+			panic("boom")
+		})
+		assert.ErrorContains(t, err, "boom")
+		assert.True(t, seen.IsClosed())
+	})
+
+	t.Run("joins_close_errors", func(t *testing.T) {
+		t.Parallel()
+		closeErr := errors.New("close failed")
+		p := BuildProvider(t, AddScoped(func() *TDisposable {
+			d := NewTDisposable()
+			d.SetCloseError(closeErr)
+			return d
+		}))
+
+		err := InvokeScoped(context.Background(), p, func(*TDisposable) {})
+		assert.ErrorIs(t, err, closeErr)
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, InvokeScoped(context.Background(), nil, func() {}), ErrProviderNil)
+
+		p := BuildProvider(t)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, InvokeScoped(context.Background(), p, nil), &validationErr)
+	})
+}
@@ -0,0 +1,64 @@
+package godi
+
+// KeyedFallback is an AddOption for an unkeyed registration that makes it
+// serve every key of its type without a registration of its own: resolving
+// the type with key "x", directly or through a name:"x" In field, returns
+// the unkeyed service unless a service keyed "x" is registered. This suits
+// per-tenant overrides of a shared default. The instance is the unkeyed one,
+// cached and disposed under its own lifetime.
+//
+// Example:
+//
+//	services.AddSingleton(NewDefaultPricing, godi.KeyedFallback())
+//	services.AddSingleton(NewAcmePricing, godi.Name("acme"))
+//
+//	godi.MustResolveKeyed[Pricing](provider, "acme")   // *AcmePricing
+//	godi.MustResolveKeyed[Pricing](provider, "globex") // *DefaultPricing
+func KeyedFallback() AddOption {
+	return keyedFallbackOption{}
+}
+
+type keyedFallbackOption struct{}
+
+func (keyedFallbackOption) String() string {
+	return "KeyedFallback()"
+}
+
+func (keyedFallbackOption) applyAddOption(opts *addOptions) {
+	opts.keyedFallback = true
+}
+
+// keyedFallback returns the unkeyed key of key's type when key has no
+// registration and the unkeyed one was registered with KeyedFallback, and
+// key otherwise.
+func (p *provider) keyedFallback(key instanceKey) instanceKey {
+	if p.findDescriptor(key.Type, key.Key) != nil {
+		return key
+	}
+	if d := p.findDescriptor(key.Type, nil); d != nil && d.keyedFallback {
+		return instanceKey{Type: key.Type}
+	}
+	return key
+}
+
+// applyKeyedFallbacks points keyed dependencies served by a KeyedFallback
+// registration at it, so Build orders, validates and prunes them like
+// dependencies on the unkeyed service. The dependencies are replaced rather
+// than modified because snapshots share them with the collection.
+func applyKeyedFallbacks(all []*descriptor, services map[TypeKey]*descriptor) {
+	for _, d := range all {
+		for i, dep := range d.Dependencies {
+			if dep == nil || dep.Key == nil || dep.Group != "" {
+				continue
+			}
+			if services[TypeKey{Type: dep.Type, Key: dep.Key}] != nil {
+				continue
+			}
+			if unkeyed := services[TypeKey{Type: dep.Type}]; unkeyed != nil && unkeyed.keyedFallback {
+				fallback := *dep
+				fallback.Key = nil
+				d.Dependencies[i] = &fallback
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedFallback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing_key_resolves_the_unkeyed_service", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTServiceWithID("default"), KeyedFallback()),
+			AddSingleton(NewTServiceWithID("acme"), Name("acme")),
+		)
+
+		acme, err := ResolveKeyed[*TService](p, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, "acme", acme.ID)
+
+		globex, err := ResolveKeyed[*TService](p, "globex")
+		require.NoError(t, err)
+		assert.Equal(t, "default", globex.ID)
+		assert.Same(t, RequireResolve[*TService](t, p), globex)
+	})
+
+	t.Run("no_fallback_without_the_option", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTService))
+
+		_, err := ResolveKeyed[*TService](p, "globex")
+		require.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("named_fields_fall_back_at_build", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Service *TService `name:"globex"`
+		}
+		p := BuildProvider(t,
+			AddSingleton(func(in params) *TServiceWithDeps { return &TServiceWithDeps{Svc: in.Service} }),
+			AddSingleton(NewTServiceWithID("default"), KeyedFallback()),
+		)
+
+		assert.Equal(t, "default", RequireResolve[*TServiceWithDeps](t, p).Svc.ID)
+	})
+
+	t.Run("rejects_names_and_groups", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, KeyedFallback(), Name("x"))
+		c.AddSingleton(NewTDependency, KeyedFallback(), Group("x"))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot use godi.KeyedFallback with godi.Name or godi.Group")
+	})
+}
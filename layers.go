@@ -0,0 +1,171 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Layer assigns services to a named architectural layer for Layers.
+type Layer struct {
+	// Name identifies the layer in DependsOn and in errors.
+	Name string
+
+	// Packages lists the import paths whose types belong to the layer. A
+	// path ending in "/..." also matches its subpackages. Pointer, slice,
+	// and other unnamed types are classified by their element type.
+	Packages []string
+
+	// Types lists individual types that belong to the layer. They take
+	// precedence over Packages.
+	Types []reflect.Type
+
+	// DependsOn names the layers this layer may depend on. A layer may
+	// always depend on itself and on services that belong to no layer.
+	DependsOn []string
+}
+
+// Layers returns a GraphValidator that enforces allowed dependency
+// directions between layers, giving import-boundary checks at the level of
+// the DI graph. Pass it in ProviderOptions.Validators.
+//
+// Dependencies are followed through services that belong to no layer, so a
+// handler cannot reach a repository through an unassigned helper. Each
+// violation is reported as a LayerViolationError carrying the full path.
+//
+// Example:
+//
+//	provider, err := services.BuildWithOptions(&godi.ProviderOptions{
+//	    Validators: []godi.GraphValidator{godi.Layers(
+//	        godi.Layer{Name: "handlers", Packages: []string{"example.com/app/handlers/..."}, DependsOn: []string{"services"}},
+//	        godi.Layer{Name: "services", Packages: []string{"example.com/app/services/..."}, DependsOn: []string{"repositories"}},
+//	        godi.Layer{Name: "repositories", Packages: []string{"example.com/app/repositories/..."}},
+//	    )},
+//	})
+func Layers(layers ...Layer) GraphValidator {
+	return layerValidator{layers: slices.Clone(layers)}
+}
+
+// elementKinds are the unnamed type kinds classified by their element type.
+var elementKinds = []reflect.Kind{reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan, reflect.Map}
+
+type layerValidator struct {
+	layers []Layer
+}
+
+func (v layerValidator) Validate(g GraphView) []error {
+	if err := v.check(); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, svc := range g.Services() {
+		from := v.layerOf(svc.ServiceType)
+		if from == nil {
+			continue
+		}
+
+		visited := map[ServiceInfo]struct{}{svc: {}}
+		var walk func(current ServiceInfo, path []reflect.Type)
+		walk = func(current ServiceInfo, path []reflect.Type) {
+			for _, dep := range g.Dependencies(current) {
+				for _, target := range g.Providers(dep) {
+					if _, seen := visited[target]; seen {
+						continue
+					}
+					visited[target] = struct{}{}
+					targetPath := append(slices.Clip(path), target.ServiceType)
+
+					to := v.layerOf(target.ServiceType)
+					if to == nil {
+						walk(target, targetPath)
+						continue
+					}
+					if to.Name == from.Name || slices.Contains(from.DependsOn, to.Name) {
+						continue
+					}
+					errs = append(errs, &LayerViolationError{
+						From:    from.Name,
+						To:      to.Name,
+						Allowed: slices.Clone(from.DependsOn),
+						Path:    targetPath,
+						Source:  g.Source(svc),
+					})
+				}
+			}
+		}
+		walk(svc, []reflect.Type{svc.ServiceType})
+	}
+	return errs
+}
+
+// check rejects layer configurations that cannot be enforced.
+func (v layerValidator) check() error {
+	names := make(map[string]struct{}, len(v.layers))
+	for _, layer := range v.layers {
+		if layer.Name == "" {
+			return &ValidationError{Cause: fmt.Errorf("godi.Layers: layer name cannot be empty")}
+		}
+		if _, dup := names[layer.Name]; dup {
+			return &ValidationError{Cause: fmt.Errorf("godi.Layers: layer %q is defined more than once", layer.Name)}
+		}
+		names[layer.Name] = struct{}{}
+	}
+	for _, layer := range v.layers {
+		for _, name := range layer.DependsOn {
+			if _, ok := names[name]; !ok {
+				return &ValidationError{Cause: fmt.Errorf("godi.Layers: layer %q depends on undefined layer %q", layer.Name, name)}
+			}
+		}
+	}
+	return nil
+}
+
+// layerOf returns the layer t belongs to, or nil. Types listed explicitly
+// win; otherwise the longest matching package path does.
+func (v layerValidator) layerOf(t reflect.Type) *Layer {
+	if t == nil {
+		return nil
+	}
+	named := t
+	for named.Name() == "" && slices.Contains(elementKinds, named.Kind()) {
+		named = named.Elem()
+	}
+
+	for i := range v.layers {
+		if slices.Contains(v.layers[i].Types, t) || slices.Contains(v.layers[i].Types, named) {
+			return &v.layers[i]
+		}
+	}
+
+	pkg := named.PkgPath()
+	if pkg == "" {
+		return nil
+	}
+	var match *Layer
+	longest := -1
+	for i := range v.layers {
+		for _, pattern := range v.layers[i].Packages {
+			if n := packageMatch(pattern, pkg); n > longest {
+				match, longest = &v.layers[i], n
+			}
+		}
+	}
+	return match
+}
+
+// packageMatch reports how specifically pattern matches the import path pkg
+// as the length of the matched prefix, or -1 if it does not match.
+func packageMatch(pattern, pkg string) int {
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if pkg == base || strings.HasPrefix(pkg, base+"/") {
+			return len(base)
+		}
+		return -1
+	}
+	if pkg == pattern {
+		return len(pattern)
+	}
+	return -1
+}
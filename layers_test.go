@@ -0,0 +1,134 @@
+package godi
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	layerHandler    struct{}
+	layerService    struct{}
+	layerRepository struct{}
+	layerHelper     struct{}
+)
+
+func TestLayers(t *testing.T) {
+	t.Parallel()
+
+	layers := func() GraphValidator {
+		return Layers(
+			Layer{Name: "handlers", Types: []reflect.Type{PtrTypeOf[layerHandler]()}, DependsOn: []string{"services"}},
+			Layer{Name: "services", Types: []reflect.Type{PtrTypeOf[layerService]()}, DependsOn: []string{"repositories"}},
+			Layer{Name: "repositories", Types: []reflect.Type{PtrTypeOf[layerRepository]()}},
+		)
+	}
+	build := func(t *testing.T, validator GraphValidator, modules ...ModuleOption) error {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(modules...)
+		p, err := c.BuildWithOptions(&ProviderOptions{Validators: []GraphValidator{validator}})
+		if p != nil {
+			t.Cleanup(func() { _ = p.Close() })
+		}
+		return err
+	}
+	newRepository := func() *layerRepository { return &layerRepository{} }
+
+	t.Run("allowed_directions_build", func(t *testing.T) {
+		t.Parallel()
+		err := build(t, layers(),
+			AddSingleton(newRepository),
+			AddSingleton(func(*layerRepository) *layerService { return &layerService{} }),
+			AddScoped(func(*layerService, *TService) *layerHandler { return &layerHandler{} }),
+			AddSingleton(NewTService),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("direct_violation", func(t *testing.T) {
+		t.Parallel()
+		err := build(t, layers(),
+			AddSingleton(newRepository),
+			AddScoped(func(*layerRepository) *layerHandler { return &layerHandler{} }),
+		)
+		violation, ok := errors.AsType[*LayerViolationError](err)
+		require.True(t, ok, "expected LayerViolationError, got %v", err)
+		assert.Equal(t, "handlers", violation.From)
+		assert.Equal(t, "repositories", violation.To)
+		assert.Equal(t, []string{"services"}, violation.Allowed)
+		assert.Equal(t, []reflect.Type{PtrTypeOf[layerHandler](), PtrTypeOf[layerRepository]()}, violation.Path)
+		assert.Contains(t, violation.Source, "layers_test.go")
+	})
+
+	t.Run("violation_through_unassigned_service", func(t *testing.T) {
+		t.Parallel()
+		err := build(t, layers(),
+			AddSingleton(newRepository),
+			AddSingleton(func(*layerRepository) *layerHelper { return &layerHelper{} }),
+			AddScoped(func(*layerHelper) *layerHandler { return &layerHandler{} }),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "*layerHandler -> *layerHelper -> *layerRepository")
+	})
+
+	t.Run("lower_layers_cannot_depend_upwards", func(t *testing.T) {
+		t.Parallel()
+		err := build(t, layers(),
+			AddSingleton(func() *layerService { return &layerService{} }),
+			AddSingleton(func(*layerService) *layerRepository { return &layerRepository{} }),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"repositories" may not depend on "services"`)
+		assert.Contains(t, err.Error(), `Layer "repositories" may not depend on other layers`)
+	})
+
+	t.Run("packages", func(t *testing.T) {
+		t.Parallel()
+		validator := Layers(
+			Layer{Name: "app", Packages: []string{"github.com/junioryono/godi/..."}},
+			Layer{Name: "stdlib", Packages: []string{"bytes"}},
+		)
+		err := build(t, validator,
+			AddSingleton(func() *bytes.Buffer { return &bytes.Buffer{} }),
+			AddSingleton(func(*bytes.Buffer) []*TService { return nil }),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"app" may not depend on "stdlib"`)
+	})
+
+	t.Run("invalid_configuration", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name   string
+			layers []Layer
+			want   string
+		}{
+			{"empty_name", []Layer{{}}, "layer name cannot be empty"},
+			{"duplicate", []Layer{{Name: "a"}, {Name: "a"}}, `layer "a" is defined more than once`},
+			{"undefined", []Layer{{Name: "a", DependsOn: []string{"b"}}}, `layer "a" depends on undefined layer "b"`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				err := build(t, Layers(tt.layers...))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
+
+func TestPackageMatch(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, len("example.com/app"), packageMatch("example.com/app", "example.com/app"))
+	assert.Equal(t, -1, packageMatch("example.com/app", "example.com/app/handlers"))
+	assert.Equal(t, len("example.com/app"), packageMatch("example.com/app/...", "example.com/app/handlers"))
+	assert.Equal(t, len("example.com/app"), packageMatch("example.com/app/...", "example.com/app"))
+	assert.Equal(t, -1, packageMatch("example.com/app/...", "example.com/application"))
+}
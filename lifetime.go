@@ -23,6 +23,12 @@ const (
 	// Transient specifies that a new instance of the service will be created every time it is requested.
 	// Transient services are never cached and always create new instances.
 	Transient
+
+	// PerResolution specifies that a new instance of the service will be created for each
+	// top-level Resolve or Invoke call and shared by every service constructed for that call.
+	// Instances are not cached in the scope; like Transient services, they are disposed with it.
+	// This suits unit-of-work objects that the services of one operation must share.
+	PerResolution
 )
 
 // String returns the string representation of the ServiceLifetime.
@@ -34,15 +40,17 @@ func (sl Lifetime) String() string {
 		return "Scoped"
 	case Transient:
 		return "Transient"
+	case PerResolution:
+		return "PerResolution"
 	default:
 		return fmt.Sprintf("Unknown(%d)", int(sl))
 	}
 }
 
 // IsValid checks if the service lifetime is valid.
-// Returns true if the lifetime is Singleton, Scoped, or Transient.
+// Returns true if the lifetime is Singleton, Scoped, Transient, or PerResolution.
 func (sl Lifetime) IsValid() bool {
-	return sl >= Singleton && sl <= Transient
+	return sl >= Singleton && sl <= PerResolution
 }
 
 // MarshalText implements encoding.TextMarshaler interface.
@@ -61,6 +69,8 @@ func (sl *Lifetime) UnmarshalText(text []byte) error {
 		*sl = Scoped
 	case "Transient", "transient":
 		*sl = Transient
+	case "PerResolution", "perResolution":
+		*sl = PerResolution
 	default:
 		return &LifetimeError{Value: string(text)}
 	}
@@ -1,7 +1,9 @@
 package godi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 
@@ -13,7 +15,7 @@ func TestLifetime(t *testing.T) {
 	t.Parallel()
 
 	// All valid lifetimes for reuse in tests
-	validLifetimes := []Lifetime{Singleton, Scoped, Transient}
+	validLifetimes := []Lifetime{Singleton, Scoped, Transient, PerResolution}
 
 	t.Run("String", func(t *testing.T) {
 		t.Parallel()
@@ -24,6 +26,7 @@ func TestLifetime(t *testing.T) {
 			{Singleton, "Singleton"},
 			{Scoped, "Scoped"},
 			{Transient, "Transient"},
+			{PerResolution, "PerResolution"},
 			{Lifetime(-1), "Unknown(-1)"},
 			{Lifetime(999), "Unknown(999)"},
 		}
@@ -38,7 +41,7 @@ func TestLifetime(t *testing.T) {
 			assert.True(t, lt.IsValid(), "%s should be valid", lt)
 		}
 		assert.False(t, Lifetime(-1).IsValid())
-		assert.False(t, Lifetime(3).IsValid())
+		assert.False(t, Lifetime(4).IsValid())
 		assert.False(t, Lifetime(999).IsValid())
 	})
 
@@ -48,6 +51,7 @@ func TestLifetime(t *testing.T) {
 		assert.Equal(t, Lifetime(0), Singleton)
 		assert.Equal(t, Lifetime(1), Scoped)
 		assert.Equal(t, Lifetime(2), Transient)
+		assert.Equal(t, Lifetime(3), PerResolution)
 
 		// Zero value should be Singleton
 		var zero Lifetime
@@ -80,6 +84,8 @@ func TestLifetime(t *testing.T) {
 				{"scoped", Scoped},
 				{"Transient", Transient},
 				{"transient", Transient},
+				{"PerResolution", PerResolution},
+				{"perResolution", PerResolution},
 			}
 			for _, tc := range cases {
 				var got Lifetime
@@ -220,3 +226,78 @@ func TestLifetime(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+type unitOfWork struct{ TDisposable }
+
+type uowOrders struct{ uow *unitOfWork }
+
+type uowInvoices struct{ uow *unitOfWork }
+
+type uowCheckout struct {
+	orders   *uowOrders
+	invoices *uowInvoices
+}
+
+func TestPerResolution(t *testing.T) {
+	t.Parallel()
+
+	services := NewModule("checkout",
+		AddPerResolution(func() *unitOfWork { return &unitOfWork{} }),
+		AddTransient(func(u *unitOfWork) *uowOrders { return &uowOrders{uow: u} }),
+		AddTransient(func(u *unitOfWork) *uowInvoices { return &uowInvoices{uow: u} }),
+		AddTransient(func(o *uowOrders, i *uowInvoices) *uowCheckout { return &uowCheckout{orders: o, invoices: i} }),
+	)
+
+	t.Run("shared_within_one_resolution", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, services)
+
+		first := RequireResolve[*uowCheckout](t, s)
+		assert.Same(t, first.orders.uow, first.invoices.uow)
+
+		second := RequireResolve[*uowCheckout](t, s)
+		assert.Same(t, second.orders.uow, second.invoices.uow)
+		assert.NotSame(t, first.orders.uow, second.orders.uow)
+	})
+
+	t.Run("resolved_directly_is_new_each_time", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, services)
+
+		assert.NotSame(t, RequireResolve[*unitOfWork](t, s), RequireResolve[*unitOfWork](t, s))
+	})
+
+	t.Run("shared_among_invoke_parameters", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, services)
+
+		err := Invoke(s, func(u *unitOfWork, orders *uowOrders, checkout *uowCheckout) {
+			assert.Same(t, u, orders.uow)
+			assert.Same(t, u, checkout.invoices.uow)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("disposed_with_the_scope", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, services)
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		checkout := RequireResolve[*uowCheckout](t, s)
+		require.NoError(t, s.Close())
+		assert.True(t, checkout.orders.uow.IsClosed())
+	})
+
+	t.Run("cannot_depend_on_scoped", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTDependency)
+		c.AddModules(AddPerResolution(func(*TDependency) *unitOfWork { return &unitOfWork{} }))
+
+		_, err := c.Build()
+		conflict, ok := errors.AsType[*LifetimeConflictError](err)
+		require.True(t, ok, "expected LifetimeConflictError, got %v", err)
+		assert.Equal(t, PerResolution, conflict.ServiceLifetime)
+	})
+}
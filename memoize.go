@@ -0,0 +1,134 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Memoize is an AddOption for AddTransient that caches instances per scope
+// and per key, where key is computed from the scope's context when the
+// service is resolved. Resolutions within one scope that produce the same
+// key share an instance; different keys, or different scopes, get their
+// own. Cached instances are disposed with the scope like any transient.
+//
+// It replaces hand-rolled per-request caches, e.g. one permission set per
+// user ID within a request that acts on behalf of several users.
+//
+// Example:
+//
+//	services.AddTransient(LoadPermissions, godi.Memoize(func(ctx context.Context) string {
+//	    return auth.UserID(ctx)
+//	}))
+//
+// The constructor must produce a single service value; godi.Group is not
+// supported.
+func Memoize[K comparable](key func(ctx context.Context) K) AddOption {
+	if key == nil {
+		return addMemoizeOption{policy: &memoizePolicy{}}
+	}
+	return addMemoizeOption{policy: &memoizePolicy{
+		key:     func(ctx context.Context) any { return key(ctx) },
+		keyType: reflect.TypeFor[K](),
+	}}
+}
+
+type addMemoizeOption struct {
+	policy *memoizePolicy
+}
+
+func (o addMemoizeOption) String() string {
+	return fmt.Sprintf("Memoize(func(context.Context) %s)", formatType(o.policy.keyType))
+}
+
+func (o addMemoizeOption) applyAddOption(opt *addOptions) {
+	opt.memoize = o.policy
+}
+
+// memoizePolicy configures a Memoize registration.
+type memoizePolicy struct {
+	key     func(context.Context) any
+	keyType reflect.Type
+}
+
+// memoizedKey is the instanceKey.Key under which a scope caches a memoized
+// instance. Its type never collides with user keys.
+type memoizedKey struct {
+	registration any // flightKey of the descriptor
+	key          any
+}
+
+// resolveMemoized returns the scope's instance of a memoized transient for
+// the key derived from the scope context, creating it under single-flight.
+// cached reports whether the instance was already memoized.
+func (s *scope) resolveMemoized(r *resolution, key instanceKey, descriptor *descriptor) (any, bool, error) {
+	value := descriptor.memoize.key(s.resolvedContext())
+	if value != nil && !reflect.ValueOf(value).Comparable() {
+		return nil, false, &ValidationError{
+			ServiceType: descriptor.Type,
+			Cause:       fmt.Errorf("godi.Memoize key of type %T is not comparable", value),
+		}
+	}
+	cacheKey := instanceKey{Type: key.Type, Key: memoizedKey{registration: flightKey(descriptor), key: value}}
+	if instance, ok := s.getMemoized(cacheKey, descriptor); ok {
+		s.rootProvider.cacheHits.Add(1)
+		return instance, true, nil
+	}
+
+	newFlight := &scopeFlight{done: make(chan struct{})}
+	raw, loaded := s.inflight.LoadOrStore(cacheKey.Key, newFlight)
+	flight := raw.(*scopeFlight)
+	if loaded {
+		<-flight.done
+		return flight.instance, false, flight.err
+	}
+	defer func() {
+		s.inflight.Delete(cacheKey.Key)
+		close(flight.done)
+	}()
+
+	if instance, ok := s.getMemoized(cacheKey, descriptor); ok {
+		flight.instance = instance
+		return instance, true, nil
+	}
+
+	// createInstance tracks the instance for disposal with the scope.
+	flight.instance, flight.err = s.createInstance(r, key, descriptor)
+	if flight.err == nil {
+		s.setMemoized(cacheKey, descriptor, flight.instance)
+	}
+	return flight.instance, false, flight.err
+}
+
+// getMemoized looks up a memoized instance in the scope's InstanceCache, or
+// among its instances when ProviderOptions.NewInstanceCache is not set.
+func (s *scope) getMemoized(key instanceKey, descriptor *descriptor) (any, bool) {
+	if s.memoCache == nil {
+		return s.getInstance(key)
+	}
+	if s.disposed.Load() != 0 {
+		return nil, false
+	}
+	return s.memoCache.Get(publicCacheKey(key, descriptor))
+}
+
+// setMemoized caches a memoized instance created by the scope.
+func (s *scope) setMemoized(key instanceKey, descriptor *descriptor, instance any) {
+	if s.memoCache != nil {
+		s.memoCache.Add(publicCacheKey(key, descriptor), instance)
+		return
+	}
+	s.instancesMu.Lock()
+	if s.instances != nil {
+		s.storeInstanceLocked(descriptor, key, instance)
+	}
+	s.instancesMu.Unlock()
+}
+
+func publicCacheKey(key instanceKey, descriptor *descriptor) CacheKey {
+	return CacheKey{
+		ServiceType: key.Type,
+		ServiceKey:  descriptor.Key,
+		MemoKey:     key.Key.(memoizedKey).key,
+	}
+}
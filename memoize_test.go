@@ -0,0 +1,154 @@
+package godi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoUserKey struct{}
+
+type memoPermissions struct {
+	TDisposable
+	User string
+}
+
+func memoUser(ctx context.Context) string {
+	user, _ := ctx.Value(memoUserKey{}).(string)
+	return user
+}
+
+func TestMemoize(t *testing.T) {
+	t.Parallel()
+
+	newPermissions := func(builds *atomic.Int64) func(ctx context.Context) *memoPermissions {
+		return func(ctx context.Context) *memoPermissions {
+			builds.Add(1)
+			return &memoPermissions{User: memoUser(ctx)}
+		}
+	}
+	userScope := func(t *testing.T, p Provider, user string) Scope {
+		t.Helper()
+		s, err := p.CreateScope(context.WithValue(t.Context(), memoUserKey{}, user))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	}
+
+	t.Run("cached_per_scope_and_key", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddTransient(newPermissions(&builds), Memoize(memoUser)))
+
+		alice := userScope(t, p, "alice")
+		first := RequireResolveFrom[*memoPermissions](t, alice)
+		assert.Same(t, first, RequireResolveFrom[*memoPermissions](t, alice))
+		assert.Equal(t, "alice", first.User)
+
+		bob := RequireResolveFrom[*memoPermissions](t, userScope(t, p, "bob"))
+		assert.Equal(t, "bob", bob.User)
+
+		other := RequireResolveFrom[*memoPermissions](t, userScope(t, p, "alice"))
+		assert.NotSame(t, first, other, "scopes never share memoized instances")
+		assert.Equal(t, int64(3), builds.Load())
+	})
+
+	t.Run("key_follows_child_scope_context", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddTransient(newPermissions(&builds), Memoize(memoUser)))
+
+		parent := userScope(t, p, "alice")
+		child, err := parent.CreateScope(context.WithValue(parent.Context(), memoUserKey{}, "bob"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = child.Close() })
+
+		assert.Equal(t, "bob", RequireResolveFrom[*memoPermissions](t, child).User)
+	})
+
+	t.Run("shared_by_consumers_in_scope", func(t *testing.T) {
+		t.Parallel()
+		type consumer struct{ perms *memoPermissions }
+		var builds atomic.Int64
+		p := BuildProvider(t,
+			AddTransient(newPermissions(&builds), Memoize(memoUser)),
+			AddTransient(func(perms *memoPermissions) *consumer { return &consumer{perms: perms} }),
+		)
+
+		s := userScope(t, p, "alice")
+		a := RequireResolveFrom[*consumer](t, s)
+		b := RequireResolveFrom[*consumer](t, s)
+		assert.NotSame(t, a, b, "the consumer itself stays transient")
+		assert.Same(t, a.perms, b.perms)
+		assert.Equal(t, int64(1), builds.Load())
+	})
+
+	t.Run("disposed_with_scope", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddTransient(newPermissions(&builds), Memoize(memoUser)))
+
+		s := userScope(t, p, "alice")
+		perms := RequireResolveFrom[*memoPermissions](t, s)
+		require.NoError(t, s.Close())
+		assert.True(t, perms.IsClosed())
+	})
+
+	t.Run("concurrent_resolutions_build_once", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddTransient(newPermissions(&builds), Memoize(memoUser)))
+		s := userScope(t, p, "alice")
+
+		var wg sync.WaitGroup
+		results := make([]*memoPermissions, 16)
+		for i := range results {
+			wg.Go(func() { results[i], _ = Resolve[*memoPermissions](s) })
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(1), builds.Load())
+		for _, perms := range results {
+			assert.Same(t, results[0], perms)
+		}
+	})
+
+	t.Run("invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		ctor := func() *memoPermissions { return &memoPermissions{} }
+		tests := []struct {
+			name   string
+			module ModuleOption
+			want   string
+		}{
+			{"scoped", AddScoped(ctor, Memoize(memoUser)), "can only be used with AddTransient"},
+			{"group", AddTransient(ctor, Memoize(memoUser), Group("perms")), "does not support godi.Group"},
+			{"nil_key", AddTransient(ctor, Memoize[string](nil)), "key function cannot be nil"},
+			{"multiple_outputs", AddTransient(func() (*memoPermissions, *TService) { return nil, nil }, Memoize(memoUser)), "single service value"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddModules(tt.module)
+				_, err := c.Build()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+
+	t.Run("non_comparable_key", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddTransient(func() *memoPermissions { return &memoPermissions{} },
+			Memoize(func(context.Context) any { return []string{"a"} })))
+
+		_, err := Resolve[*memoPermissions](NewTestScope(t, p))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not comparable")
+	})
+}
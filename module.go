@@ -2,9 +2,11 @@ package godi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // ModuleOption represents a registration action within a module.
@@ -84,27 +86,162 @@ func AddTransient(service any, opts ...AddOption) ModuleOption {
 	}
 }
 
-// An AddOption modifies the default behavior of AddSingleton, AddScoped, and AddTransient.
-type AddOption interface {
-	applyAddOption(*addOptions)
+// AddPerResolution creates a ModuleBuilder for adding a per-resolution
+// service: one instance is created per top-level Resolve or Invoke call and
+// shared by every service constructed for it. The service must be a
+// constructor that returns a service value.
+// Registration errors are recorded on the collection and reported by Build.
+func AddPerResolution(service any, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		c, ok := s.(*collection)
+		if !ok {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.AddPerResolution requires a collection created by godi.NewCollection"),
+			}
+		}
+		c.recordErr(c.addService(service, PerResolution, opts...))
+		return nil
+	}
 }
 
-type addOptions struct {
-	Name  string
-	Group string
-	As    []any
+// AddValue creates a ModuleBuilder for registering an existing value as a
+// singleton, such as configuration. Values of built-in types and
+// containers of them must be named.
+// Registration errors are recorded on the collection and reported by Build.
+//
+// Example:
+//
+//	var ConfigModule = godi.NewModule("config",
+//	    godi.AddValue([]string{"https://example.com"}, godi.Name("allowedOrigins")),
+//	    godi.AddValue(map[string]int{"free": 60, "pro": 600}, godi.Name("rateLimits")),
+//	)
+//
+//	type ServerParams struct {
+//	    godi.In
+//	    AllowedOrigins []string `name:"allowedOrigins"`
+//	}
+func AddValue(value any, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		s.AddValue(value, opts...)
+		return nil
+	}
 }
 
-func (o *addOptions) Validate() error {
-	if o.Group != "" {
-		if o.Name != "" {
+// Contextual returns a ModuleOption registering a transient service built for
+// the service that depends on it, such as a logger or metrics scope
+// pre-tagged with its consumer's name. fn receives the consumer's type, or
+// nil when the service is resolved directly rather than injected.
+//
+// Example:
+//
+//	godi.Contextual(func(consumer reflect.Type) *zap.Logger {
+//	    if consumer == nil {
+//	        return base
+//	    }
+//	    return base.Named(consumer.String())
+//	})
+func Contextual[T any](fn func(consumer reflect.Type) T, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		if fn == nil {
+			s.AddTransient(nil, opts...)
+			return nil
+		}
+		s.AddTransient(func(info ResolveInfo) T { return fn(info.Consumer) }, opts...)
+		return nil
+	}
+}
+
+// FromContextValue returns a ModuleOption registering a scoped service whose
+// value is read from the resolving scope's context with key, so constructors
+// can take a request ID or tenant as a named parameter instead of looking it
+// up in a context.Context. A string key also names the service; other keys
+// need a godi.Name option to be injected by name.
+//
+// Resolution fails with a ContextValueError when the context has no value
+// for key or the value is not a T.
+//
+// Example:
+//
+//	godi.FromContextValue[string]("requestID")
+//
+//	type Params struct {
+//	    godi.In
+//	    RequestID string `name:"requestID"`
+//	}
+func FromContextValue[T any](key any, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		serviceType := reflect.TypeFor[T]()
+		if key == nil {
+			return &ValidationError{ServiceType: serviceType, Cause: fmt.Errorf("godi.FromContextValue: %w", ErrServiceKeyNil)}
+		}
+		if !reflect.TypeOf(key).Comparable() {
 			return &ValidationError{
-				ServiceType: nil,
-				Cause:       fmt.Errorf("cannot use both godi.Name and godi.Group: name:%q provided with group:%q", o.Name, o.Group),
+				ServiceType: serviceType,
+				Cause:       fmt.Errorf("godi.FromContextValue: context key of type %T is not comparable", key),
 			}
 		}
+
+		addOpts := opts
+		if name, ok := key.(string); ok {
+			addOpts = append([]AddOption{Name(name)}, opts...)
+		}
+		s.AddScoped(func(ctx context.Context) (T, error) {
+			raw := ctx.Value(key)
+			value, ok := raw.(T)
+			if !ok {
+				return value, &ContextValueError{Key: key, ServiceType: serviceType, Found: reflect.TypeOf(raw)}
+			}
+			return value, nil
+		}, addOpts...)
+		return nil
 	}
+}
+
+// An AddOption modifies the default behavior of AddSingleton, AddScoped, and AddTransient.
+type AddOption interface {
+	applyAddOption(*addOptions)
+}
+
+type addOptions struct {
+	Name       string
+	Group      string
+	As         []any
+	ExportName string
+
+	RefreshEvery time.Duration
+	refreshing   bool // set by AddRefreshing
+	unloadable   bool // set by Unloadable
+
+	memoize *memoizePolicy // set by Memoize
+	clone   *clonePolicy   // set by Clone
+	private bool           // set by Private
+
+	audited     bool   // set by Audited
+	auditReason string // set by Audited
+
+	deprecated  bool   // set by Deprecated
+	deprecation string // set by Deprecated
+
+	excludeFromSnapshot bool // set by ExcludeFromSnapshot
+
+	fieldFallbacks []fieldFallbackOption // set by WithFieldFallback
+	disposeWith    *disposeWithOption    // set by DisposeWith
+	validators     []validateWithOption  // set by ValidateWith
+	keyedFallback  bool                  // set by KeyedFallback
+	bundle         bool                  // set by Bundle
+	curried        []any                 // set by Curry
+
+	maxInstances    int  // set by MaxInstancesPerScope
+	hasMaxInstances bool // set by MaxInstancesPerScope
+
+	circuitBreaker *circuitBreakerOption // set by CircuitBreaker
+
+	flag    string // set by Flagged
+	hasFlag bool   // set by Flagged
+}
 
+func (o *addOptions) Validate() error {
 	// Names must be representable inside a backquoted string. The only
 	// limitation for raw string literals as per
 	// https://golang.org/ref/spec#raw_string_lit is that they cannot contain
@@ -122,6 +259,127 @@ func (o *addOptions) Validate() error {
 		}
 	}
 
+	if o.ExportName != "" && o.Group != "" {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("cannot use both godi.ExportName and godi.Group: group members cannot be resolved individually"),
+		}
+	}
+
+	if o.audited && strings.TrimSpace(o.auditReason) == "" {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Audited requires a reason"),
+		}
+	}
+
+	if o.deprecated && strings.TrimSpace(o.deprecation) == "" {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Deprecated requires a message"),
+		}
+	}
+
+	if o.RefreshEvery < 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid godi.RefreshEvery(%v): interval cannot be negative", o.RefreshEvery),
+		}
+	}
+	if o.RefreshEvery != 0 && !o.refreshing {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.RefreshEvery can only be used with godi.AddRefreshing"),
+		}
+	}
+	if o.refreshing && o.unloadable {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Unloadable cannot be used with godi.AddRefreshing; use godi.Invalidate to unload it"),
+		}
+	}
+	if o.refreshing && (o.Group != "" || len(o.As) > 0) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.AddRefreshing does not support godi.Group or godi.As"),
+		}
+	}
+	if o.unloadable && (o.Group != "" || len(o.As) > 0) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Unloadable does not support godi.Group or godi.As"),
+		}
+	}
+	if o.hasMaxInstances && o.maxInstances <= 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid godi.MaxInstancesPerScope(%d): limit must be positive", o.maxInstances),
+		}
+	}
+	if o.hasMaxInstances && (o.refreshing || o.unloadable) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.MaxInstancesPerScope cannot be used with godi.AddRefreshing or godi.Unloadable: their instances are shared by every scope"),
+		}
+	}
+	if o.circuitBreaker != nil && (o.circuitBreaker.failures <= 0 || o.circuitBreaker.coolDown <= 0) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid godi.%s: failures and cool-down must be positive", o.circuitBreaker),
+		}
+	}
+	if o.bundle && (o.refreshing || o.unloadable || o.memoize != nil) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Bundle cannot be refreshed, unloaded or memoized: its lifetime follows its fields"),
+		}
+	}
+	if o.hasFlag && o.flag == "" {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Flagged needs the name of a feature flag"),
+		}
+	}
+	if o.hasFlag && (o.Group != "" || o.keyedFallback) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Flagged cannot be used with godi.Group or godi.KeyedFallback: it replaces one registration of a service"),
+		}
+	}
+	if o.keyedFallback && (o.Name != "" || o.Group != "") {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("cannot use godi.KeyedFallback with godi.Name or godi.Group: only unkeyed registrations can serve other keys"),
+		}
+	}
+	if o.private && (o.Group != "" || o.ExportName != "") {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Private cannot be used with godi.Group or godi.ExportName"),
+		}
+	}
+	if o.memoize != nil {
+		if o.memoize.key == nil {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.Memoize key function cannot be nil"),
+			}
+		}
+		if o.Group != "" {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.Memoize does not support godi.Group"),
+			}
+		}
+	}
+
+	if o.clone != nil && o.clone.clone == nil {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("godi.Clone function cannot be nil"),
+		}
+	}
+
 	for _, i := range o.As {
 		t := reflect.TypeOf(i)
 
@@ -165,6 +423,9 @@ func (o *addOptions) Validate() error {
 //	c.AddSingleton(NewReadOnlyConnection, godi.Name("ro"))
 //	c.AddSingleton(NewReadWriteConnection, godi.Name("rw"))
 //
+// Combined with Group, the name labels the value within its group instead,
+// for ResolveGroupMap; the value can then only be resolved with its group.
+//
 // This option cannot be provided for constructors which produce result
 // objects.
 func Name(name string) AddOption {
@@ -181,6 +442,60 @@ func (o addNameOption) applyAddOption(opt *addOptions) {
 	opt.Name = string(o)
 }
 
+// ExportName is an AddOption that makes the service resolvable by a string
+// identifier through godi.ResolveByName, for plugin systems that only
+// know service names at runtime. Exported names must be unique across the
+// collection; duplicates are reported by Build.
+//
+//	c.AddSingleton(NewUserService, godi.ExportName("user-service"))
+//
+//	svc, err := godi.ResolveByName(provider, "user-service")
+//
+// The constructor must produce a single service value; godi.Group is not
+// supported. With godi.As, the name resolves the same instance as the
+// interfaces it is registered under.
+func ExportName(name string) AddOption {
+	return addExportNameOption(name)
+}
+
+type addExportNameOption string
+
+func (o addExportNameOption) String() string {
+	return fmt.Sprintf("ExportName(%q)", string(o))
+}
+
+func (o addExportNameOption) applyAddOption(opt *addOptions) {
+	opt.ExportName = string(o)
+}
+
+// Private is an AddOption that hides a service registered inside a module
+// from the rest of the application: only services registered in the same
+// module, or in modules nested within it, may depend on it, and it cannot
+// be resolved from a Provider or Scope. Build reports every consumer
+// outside the module as a PrivateServiceError.
+//
+// Example:
+//
+//	var BillingModule = godi.NewModule("billing",
+//	    godi.AddSingleton(newStripeClient, godi.Private()),
+//	    godi.AddSingleton(NewBillingService), // may depend on the client
+//	)
+//
+// Private cannot be combined with godi.Group or godi.ExportName.
+func Private() AddOption {
+	return addPrivateOption{}
+}
+
+type addPrivateOption struct{}
+
+func (addPrivateOption) String() string {
+	return "Private()"
+}
+
+func (addPrivateOption) applyAddOption(opt *addOptions) {
+	opt.private = true
+}
+
 // Group is an AddOption that specifies that all values produced by a
 // constructor should be added to the specified group. See also the package
 // documentation about Value Groups.
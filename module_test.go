@@ -1,6 +1,7 @@
 package godi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -275,7 +276,7 @@ func TestModule(t *testing.T) {
 			wantErr string
 		}{
 			{"valid", &addOptions{Name: "test"}, ""},
-			{"name_and_group", &addOptions{Name: "n", Group: "g"}, "cannot use both"},
+			{"name_and_group", &addOptions{Name: "n", Group: "g"}, ""},
 			{"name_backtick", &addOptions{Name: "n`ame"}, "backquotes"},
 			{"group_backtick", &addOptions{Group: "g`roup"}, "backquotes"},
 			{"nil_As", &addOptions{As: []any{nil}}, "invalid"},
@@ -352,3 +353,210 @@ func TestModule(t *testing.T) {
 		})
 	})
 }
+
+func TestContextual(t *testing.T) {
+	t.Parallel()
+
+	type taggedLogger struct{ Name string }
+	type orders struct{ Log *taggedLogger }
+	type billing struct{ Log *taggedLogger }
+
+	newLogger := func(consumer reflect.Type) *taggedLogger {
+		if consumer == nil {
+			return &taggedLogger{Name: "root"}
+		}
+		return &taggedLogger{Name: consumer.String()}
+	}
+
+	t.Run("tags_each_consumer", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			Contextual(newLogger),
+			AddScoped(func(log *taggedLogger) *orders { return &orders{Log: log} }),
+			AddSingleton(func(log *taggedLogger) *billing { return &billing{Log: log} }),
+		)
+
+		assert.Equal(t, PtrTypeOf[orders]().String(), RequireResolveFrom[*orders](t, s).Log.Name)
+		assert.Equal(t, PtrTypeOf[billing]().String(), RequireResolveFrom[*billing](t, s).Log.Name)
+		assert.Equal(t, "root", RequireResolveFrom[*taggedLogger](t, s).Name)
+	})
+
+	t.Run("is_transient", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(Contextual(newLogger, Name("tagged")))
+
+		require.NoError(t, c.Err())
+		descriptor := c.(*collection).services[TypeKey{Type: PtrTypeOf[taggedLogger](), Key: "tagged"}]
+		require.NotNil(t, descriptor)
+		assert.Equal(t, Transient, descriptor.Lifetime)
+	})
+
+	t.Run("nil_function", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(Contextual[*taggedLogger](nil))
+
+		require.ErrorIs(t, c.Err(), ErrConstructorNil)
+	})
+}
+
+func TestFromContextValue(t *testing.T) {
+	t.Parallel()
+
+	type tenantKey struct{}
+	type handler struct {
+		RequestID string
+		Tenant    int
+	}
+	newHandler := func(p struct {
+		In
+		RequestID string `name:"requestID"`
+		Tenant    int    `name:"tenant"`
+	}) *handler {
+		return &handler{RequestID: p.RequestID, Tenant: p.Tenant}
+	}
+
+	p := BuildProvider(t,
+		FromContextValue[string]("requestID"),
+		FromContextValue[int](tenantKey{}, Name("tenant")),
+		AddScoped(newHandler),
+	)
+	scopeWith := func(t *testing.T, ctx context.Context) Scope {
+		t.Helper()
+		s, err := p.CreateScope(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	}
+
+	t.Run("injects_values_by_name", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.WithValue(t.Context(), "requestID", "req-42") //nolint:staticcheck // SA1029: the test covers string context keys
+		ctx = context.WithValue(ctx, tenantKey{}, 7)
+
+		h := RequireResolveFrom[*handler](t, scopeWith(t, ctx))
+		assert.Equal(t, "req-42", h.RequestID)
+		assert.Equal(t, 7, h.Tenant)
+	})
+
+	t.Run("read_per_scope", func(t *testing.T) {
+		t.Parallel()
+		for _, id := range []string{"a", "b"} {
+			ctx := context.WithValue(t.Context(), "requestID", id) //nolint:staticcheck // SA1029: the test covers string context keys
+			value, err := ResolveKeyed[string](scopeWith(t, ctx), "requestID")
+			require.NoError(t, err)
+			assert.Equal(t, id, value)
+		}
+	})
+
+	t.Run("missing_or_mistyped_value", func(t *testing.T) {
+		t.Parallel()
+		_, err := ResolveKeyed[string](scopeWith(t, t.Context()), "requestID")
+		ctxErr, ok := errors.AsType[*ContextValueError](err)
+		require.True(t, ok, "expected ContextValueError, got %v", err)
+		assert.Equal(t, "requestID", ctxErr.Key)
+		assert.Nil(t, ctxErr.Found)
+
+		ctx := context.WithValue(t.Context(), tenantKey{}, "seven")
+		_, err = ResolveKeyed[int](scopeWith(t, ctx), "tenant")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is string, not int")
+	})
+
+	t.Run("invalid_keys", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(FromContextValue[string](nil))
+		require.ErrorIs(t, c.Err(), ErrServiceKeyNil)
+
+		c = NewCollection()
+		c.AddModules(FromContextValue[string]([]string{"key"}))
+		require.Error(t, c.Err())
+		assert.Contains(t, c.Err().Error(), "not comparable")
+	})
+}
+
+func TestPrivate(t *testing.T) {
+	t.Parallel()
+
+	type client struct{}
+	type billing struct{ client *client }
+	type reports struct{ client *client }
+
+	newClient := func() *client { return &client{} }
+	newBilling := func(c *client) *billing { return &billing{client: c} }
+	billingModule := NewModule("billing",
+		AddSingleton(newClient, Private()),
+		AddSingleton(newBilling),
+	)
+
+	t.Run("usable_within_module", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, billingModule)
+		assert.NotNil(t, RequireResolve[*billing](t, p).client)
+	})
+
+	t.Run("usable_from_nested_modules", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("billing",
+			AddSingleton(newClient, Private()),
+			NewModule("invoices", AddScoped(newBilling)),
+		))
+		assert.NotNil(t, RequireResolveFrom[*billing](t, NewTestScope(t, p)).client)
+	})
+
+	t.Run("not_resolvable_directly", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, billingModule)
+
+		_, err := Resolve[*client](p)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		privateErr, ok := errors.AsType[*PrivateServiceError](err)
+		require.True(t, ok, "expected PrivateServiceError, got %v", err)
+		assert.Equal(t, "billing", privateErr.Module)
+		assert.Nil(t, privateErr.Consumer)
+
+		var target struct {
+			Client *client `optional:"true"`
+		}
+		require.NoError(t, Inject(p, &target))
+		assert.Nil(t, target.Client, "optional fields treat private services as missing")
+	})
+
+	t.Run("consumers_outside_module_fail_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(billingModule, NewModule("reports",
+			AddSingleton(func(c *client) *reports { return &reports{client: c} }),
+		))
+
+		_, err := c.Build()
+		privateErr, ok := errors.AsType[*PrivateServiceError](err)
+		require.True(t, ok, "expected PrivateServiceError, got %v", err)
+		assert.Equal(t, PtrTypeOf[reports](), privateErr.Consumer)
+		assert.Contains(t, err.Error(), `module "reports"`)
+	})
+
+	t.Run("invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name   string
+			module ModuleOption
+			want   string
+		}{
+			{"outside_module", AddSingleton(newClient, Private()), "only be used for registrations inside a module"},
+			{"group", NewModule("m", AddSingleton(newClient, Private(), Group("clients"))), "cannot be used with godi.Group"},
+			{"export_name", NewModule("m", AddSingleton(newClient, Private(), ExportName("client"))), "or godi.ExportName"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddModules(tt.module)
+				require.Error(t, c.Err())
+				assert.Contains(t, c.Err().Error(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package godi
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"time"
+)
+
+// ResolutionEvent describes the resolution of one registered service,
+// reported to ProviderOptions.OnServiceResolved when it succeeds and to
+// ProviderOptions.OnServiceError when it fails. Every dependency resolved
+// while constructing a service is reported as its own event, before the
+// service's, and Path links them: an event's Path minus its last frame is
+// the Path of the event of the service that depended on it.
+type ResolutionEvent struct {
+	// ServiceType, ServiceKey and Group identify the resolved service.
+	ServiceType reflect.Type
+	ServiceKey  any
+	Group       string
+
+	// ScopeID is the ID of the scope the service was resolved in.
+	ScopeID string
+
+	// Path is the chain of services being resolved, from the service
+	// originally requested down to this one. It has a single frame for a
+	// direct resolution.
+	Path []ResolutionFrame
+
+	// Cached reports whether the instance came from the singleton, scope
+	// or memoization cache rather than from a constructor.
+	Cached bool
+
+	// Duration is the time the resolution took, including constructing
+	// the service and its dependencies.
+	Duration time.Duration
+
+	// Instance is the resolved service; it is nil for failed resolutions.
+	Instance any
+
+	// Err is the resolution error; it is nil for successful resolutions.
+	Err error
+}
+
+// resolveObserved resolves a registered service like resolveLifetime and
+// reports the outcome to the provider's resolution callbacks.
+func (s *scope) resolveObserved(r *resolution, key instanceKey, descriptor *descriptor) (any, error) {
+	p := s.rootProvider
+	observed := !descriptor.unobserved
+	sampled := observed && p.onResolved != nil && (p.sampleRate == 0 || rand.Float64() < p.sampleRate)
+	tracing := p.tracing.Load() != 0
+	if !sampled && (!observed || p.onResolveError == nil) && !tracing {
+		instance, _, err := s.resolveLifetime(r, key, descriptor)
+		return instance, err
+	}
+
+	start := time.Now()
+	instance, cached, err := s.resolveLifetime(r, key, descriptor)
+	duration := time.Since(start)
+
+	var callback func(ResolutionEvent)
+	switch {
+	case err != nil && observed:
+		callback = p.onResolveError
+	case err == nil && sampled:
+		callback = p.onResolved
+	}
+	if callback == nil && !tracing {
+		return instance, err
+	}
+
+	frame := newResolutionFrame(key, descriptor)
+	event := ResolutionEvent{
+		ServiceType: frame.ServiceType,
+		ServiceKey:  frame.ServiceKey,
+		Group:       frame.Group,
+		ScopeID:     s.id,
+		Path:        append(r.path(), frame),
+		Cached:      cached,
+		Duration:    duration,
+		Instance:    instance,
+		Err:         err,
+	}
+	if tracing {
+		p.trace(s, &event, descriptor.Lifetime, start)
+	}
+	if callback != nil {
+		callback(event)
+	}
+	return instance, err
+}
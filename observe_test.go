@@ -0,0 +1,163 @@
+package godi
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolutionCallbacks(t *testing.T) {
+	t.Parallel()
+
+	type recorded struct {
+		mu     sync.Mutex
+		events []ResolutionEvent
+	}
+	build := func(t *testing.T, modules ...ModuleOption) (Provider, *recorded) {
+		t.Helper()
+		rec := &recorded{}
+		record := func(e ResolutionEvent) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.events = append(rec.events, e)
+		}
+		c := NewCollection()
+		c.AddModules(modules...)
+		p, err := c.BuildWithOptions(&ProviderOptions{OnServiceResolved: record, OnServiceError: record})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p, rec
+	}
+	pathTypes := func(path []ResolutionFrame) []reflect.Type {
+		types := make([]reflect.Type, len(path))
+		for i, frame := range path {
+			types[i] = frame.ServiceType
+		}
+		return types
+	}
+
+	t.Run("reports_dependency_tree", func(t *testing.T) {
+		t.Parallel()
+		p, rec := build(t,
+			AddSingleton(NewTService),
+			AddScoped(NewTDependency),
+			AddScoped(NewTServiceWithDeps),
+		)
+		scope := NewTestScope(t, p)
+		rec.mu.Lock()
+		rec.events = nil
+		rec.mu.Unlock()
+
+		RequireResolveFrom[*TServiceWithDeps](t, scope)
+		require.Len(t, rec.events, 3)
+
+		svc, dep, root := rec.events[0], rec.events[1], rec.events[2]
+		rootType := PtrTypeOf[TServiceWithDeps]()
+		assert.Equal(t, []reflect.Type{rootType, PtrTypeOf[TService]()}, pathTypes(svc.Path))
+		assert.True(t, svc.Cached, "singletons are built with the provider")
+		assert.Equal(t, []reflect.Type{rootType, PtrTypeOf[TDependency]()}, pathTypes(dep.Path))
+		assert.False(t, dep.Cached)
+		assert.Equal(t, []reflect.Type{rootType}, pathTypes(root.Path))
+		assert.Equal(t, rootType, root.ServiceType)
+		assert.Equal(t, scope.ID(), root.ScopeID)
+		assert.IsType(t, &TServiceWithDeps{}, root.Instance)
+		assert.False(t, root.Cached)
+		assert.GreaterOrEqual(t, root.Duration, dep.Duration)
+
+		RequireResolveFrom[*TServiceWithDeps](t, scope)
+		require.Len(t, rec.events, 4)
+		assert.True(t, rec.events[3].Cached)
+	})
+
+	t.Run("reports_errors_with_key", func(t *testing.T) {
+		t.Parallel()
+		p, rec := build(t, AddScoped(NewTServiceError, Name("broken")))
+		_, err := ResolveKeyed[*TService](NewTestScope(t, p), "broken")
+		require.Error(t, err)
+
+		require.Len(t, rec.events, 1)
+		event := rec.events[0]
+		assert.Equal(t, "broken", event.ServiceKey)
+		assert.Nil(t, event.Instance)
+		require.Error(t, event.Err)
+		assert.Contains(t, event.Err.Error(), "constructor error")
+	})
+}
+
+func TestResolutionCallbackControls(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, options *ProviderOptions) (Provider, *[]ResolutionEvent) {
+		t.Helper()
+		var mu sync.Mutex
+		var events []ResolutionEvent
+		record := func(e ResolutionEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}
+		options.OnServiceResolved = record
+		options.OnServiceError = record
+		c := NewCollection()
+		c.AddSingleton(NewTService)
+		c.AddSingleton(NewTDependency)
+		p, err := c.BuildWithOptions(options)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p, &events
+	}
+
+	t.Run("filter_selects_registrations", func(t *testing.T) {
+		t.Parallel()
+		p, events := build(t, &ProviderOptions{
+			CallbackFilter: func(service ServiceInfo) bool {
+				return service.ServiceType == PtrTypeOf[TService]()
+			},
+		})
+
+		RequireResolve[*TService](t, p)
+		RequireResolve[*TDependency](t, p)
+		require.Len(t, *events, 1)
+		assert.Equal(t, PtrTypeOf[TService](), (*events)[0].ServiceType)
+	})
+
+	t.Run("sampling_skips_successes", func(t *testing.T) {
+		t.Parallel()
+		p, events := build(t, &ProviderOptions{CallbackSampleRate: 1e-12})
+
+		for range 100 {
+			RequireResolve[*TService](t, p)
+		}
+		assert.Empty(t, *events)
+	})
+
+	t.Run("failures_are_always_reported", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTServiceError)
+		var failures int
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			OnServiceResolved:  func(ResolutionEvent) {},
+			OnServiceError:     func(ResolutionEvent) { failures++ },
+			CallbackSampleRate: 1e-12,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		_, err = Resolve[*TService](NewTestScope(t, p))
+		require.Error(t, err)
+		assert.Equal(t, 1, failures)
+	})
+
+	t.Run("rejects_invalid_rates", func(t *testing.T) {
+		t.Parallel()
+		for _, rate := range []float64{-0.5, 1.5} {
+			_, err := NewCollection().BuildWithOptions(&ProviderOptions{CallbackSampleRate: rate})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "CallbackSampleRate")
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+)
+
+// Profiler labels set on constructor invocations under
+// ProviderOptions.ProfileLabels.
+const (
+	// ProfileLabelService is the constructed service type, such as
+	// "*db.Pool", followed by its key in brackets for keyed services.
+	ProfileLabelService = "godi.service"
+
+	// ProfileLabelLifetime is the lifetime of the constructed service.
+	ProfileLabelLifetime = "godi.lifetime"
+
+	// ProfileLabelScope is the name given to the constructing scope with
+	// WithScopeName, "root" for the provider's own scope, or "scope" for
+	// an unnamed one.
+	ProfileLabelScope = "godi.scope"
+)
+
+// withProfileLabels runs invoke, the constructor of descriptor, with
+// profiler labels naming the service and s. Labels already on the scope's
+// context, such as a route set by middleware, are kept. A constructor
+// invoked by another is labelled with its own service for as long as it
+// runs.
+func (s *scope) withProfileLabels(requested instanceKey, descriptor *descriptor, invoke func()) {
+	service := requested.Type.String()
+	if requested.Key != nil {
+		service = fmt.Sprintf("%s[%v]", service, requested.Key)
+	}
+
+	pprof.Do(s.resolvedContext(), pprof.Labels(
+		ProfileLabelService, service,
+		ProfileLabelLifetime, descriptor.Lifetime.String(),
+		ProfileLabelScope, s.profileName(),
+	), func(context.Context) { invoke() })
+}
+
+// profileName is the ProfileLabelScope value of s.
+func (s *scope) profileName() string {
+	switch {
+	case s.name != "":
+		return s.name
+	case s == s.rootProvider.rootScope:
+		return "root"
+	default:
+		return "scope"
+	}
+}
@@ -0,0 +1,57 @@
+package godi
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goroutineLabels returns the goroutine profile, which lists the labels of
+// every goroutine.
+func goroutineLabels(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(&buf, 1))
+	return buf.String()
+}
+
+func TestProfileLabels(t *testing.T) {
+	t.Parallel()
+
+	var seen string
+	c := NewCollection()
+	c.AddScoped(func() *TDependency {
+		seen = goroutineLabels(t)
+		return NewTDependency()
+	}, Name("profiled"))
+	p, err := c.BuildWithOptions(&ProviderOptions{ProfileLabels: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("route", "/checkout"))
+	s, err := CreateScope(ctx, p, WithScopeName("checkout"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	_, err = ResolveKeyed[*TDependency](s, "profiled")
+	require.NoError(t, err)
+	assert.Contains(t, seen, `"godi.lifetime":"Scoped", "godi.scope":"checkout", "godi.service":"*godi.TDependency[profiled]", "route":"/checkout"`)
+}
+
+func TestScopeProfileName(t *testing.T) {
+	t.Parallel()
+
+	p := BuildProvider(t)
+	root := p.(*provider).rootScope
+	assert.Equal(t, "root", root.profileName())
+	assert.Equal(t, "scope", NewTestScope(t, p).(*scope).profileName())
+
+	named, err := CreateScope(context.Background(), p, WithScopeName("job"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = named.Close() })
+	assert.Equal(t, "job", named.(*scope).profileName())
+}
@@ -0,0 +1,55 @@
+package godi
+
+import "slices"
+
+// applyProfiles drops the result-object fields whose profile:"..." tag names
+// none of the active profiles, along with their sibling links, so one call
+// of their constructor caches only the fields that remain registered.
+func applyProfiles(
+	active []string,
+	all []*descriptor,
+	services map[TypeKey]*descriptor,
+	groups map[GroupKey][]*descriptor,
+) (
+	[]*descriptor,
+	map[TypeKey]*descriptor,
+	map[GroupKey][]*descriptor,
+) {
+	inactive := make(map[*descriptor]bool)
+	for _, d := range all {
+		if d != nil && len(d.profiles) > 0 &&
+			!slices.ContainsFunc(d.profiles, func(p string) bool { return slices.Contains(active, p) }) {
+			inactive[d] = true
+		}
+	}
+	if len(inactive) == 0 {
+		return all, services, groups
+	}
+
+	kept := make([]*descriptor, 0, len(all)-len(inactive))
+	for _, d := range all {
+		if d == nil || inactive[d] {
+			continue
+		}
+		if len(d.siblings) > 0 {
+			d.siblings = slices.DeleteFunc(slices.Clone(d.siblings), func(s *descriptor) bool { return inactive[s] })
+		}
+		kept = append(kept, d)
+	}
+
+	for key, d := range services {
+		if inactive[d] {
+			delete(services, key)
+		}
+	}
+	for key, members := range groups {
+		members = slices.DeleteFunc(members, func(d *descriptor) bool { return inactive[d] })
+		if len(members) == 0 {
+			delete(groups, key)
+		} else {
+			groups[key] = members
+		}
+	}
+
+	return kept, services, groups
+}
@@ -0,0 +1,94 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	type storeResult struct {
+		Out
+		Service  *TService
+		Debug    *TDisposable `profile:"dev, test"`
+		Inspects TInterface   `group:"inspectors" profile:"dev"`
+	}
+	newStore := func() storeResult {
+		return storeResult{Service: NewTService(), Debug: NewTDisposable(), Inspects: NewTService()}
+	}
+
+	buildWith := func(t *testing.T, profiles ...string) Provider {
+		t.Helper()
+		c := NewCollection()
+		c.AddSingleton(newStore)
+		p, err := c.BuildWithOptions(&ProviderOptions{Profiles: profiles})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("active_profile_registers_fields", func(t *testing.T) {
+		t.Parallel()
+		p := buildWith(t, "test", "dev")
+
+		RequireResolve[*TDisposable](t, p)
+		inspectors, err := ResolveGroup[TInterface](p, "inspectors")
+		require.NoError(t, err)
+		assert.Len(t, inspectors, 1)
+	})
+
+	t.Run("any_listed_profile_is_enough", func(t *testing.T) {
+		t.Parallel()
+		p := buildWith(t, "test")
+
+		RequireResolve[*TDisposable](t, p)
+		inspectors, err := ResolveGroup[TInterface](p, "inspectors")
+		require.NoError(t, err)
+		assert.Empty(t, inspectors)
+	})
+
+	t.Run("inactive_fields_are_not_registered", func(t *testing.T) {
+		t.Parallel()
+		p := buildWith(t)
+
+		RequireResolve[*TService](t, p)
+		_, err := Resolve[*TDisposable](p)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("inactive_values_are_still_closed", func(t *testing.T) {
+		t.Parallel()
+		var debug *TDisposable
+		c := NewCollection()
+		c.AddSingleton(func() storeResult {
+			result := newStore()
+			debug = result.Debug
+			return result
+		})
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		assert.True(t, debug.IsClosed())
+	})
+
+	t.Run("collection_is_unchanged", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(newStore)
+
+		prod, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = prod.Close() })
+		dev, err := c.BuildWithOptions(&ProviderOptions{Profiles: []string{"dev"}})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = dev.Close() })
+
+		_, err = Resolve[*TDisposable](prod)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		RequireResolve[*TDisposable](t, dev)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,6 +23,15 @@ type Disposable interface {
 	Close() error
 }
 
+// trackedDisposable is a Disposable awaiting disposal along with the
+// registration that produced it, if known, which StopAll uses to order
+// disposal by dependency.
+type trackedDisposable struct {
+	Disposable
+	descriptor *descriptor
+	created    time.Time // set for disposables tracked by a scope
+}
+
 type disposableIdentity struct {
 	typ   reflect.Type
 	value any
@@ -33,7 +44,11 @@ func identifyDisposable(d Disposable) (disposableIdentity, bool) {
 	if d == nil {
 		return disposableIdentity{}, false
 	}
-	value := reflect.ValueOf(d)
+	var v any = d
+	if custom, ok := d.(*customDisposable); ok {
+		v = custom.disposalIdentity()
+	}
+	value := reflect.ValueOf(v)
 	if !value.IsValid() {
 		return disposableIdentity{}, false
 	}
@@ -43,7 +58,7 @@ func identifyDisposable(d Disposable) (disposableIdentity, bool) {
 	if value.IsNil() {
 		return disposableIdentity{}, false
 	}
-	return disposableIdentity{typ: value.Type(), value: d}, true
+	return disposableIdentity{typ: value.Type(), value: v}, true
 }
 
 // Provider is the main dependency injection container interface
@@ -72,6 +87,299 @@ type ProviderOptions struct {
 	// cancelled. Other constructors cannot be preempted, but an expired deadline
 	// is checked after they return and can never produce a successful provider.
 	BuildTimeout time.Duration
+
+	// Fallback is consulted for services the built provider has no
+	// registration for, e.g. a shared platform provider maintained by
+	// another team. Precedence is strict: a registration in the built
+	// provider always wins, keyed lookups fall back per (type, key), and a
+	// group falls back only when the built provider has no members for it.
+	// Fallback services are resolved from the fallback provider itself and
+	// remain owned by it; closing the built provider does not close them.
+	//
+	// The fallback must already be built, so fallback chains cannot form
+	// cycles. Built-in types (context.Context, Provider, Scope) never fall
+	// back.
+	Fallback Provider
+
+	// DisallowServiceLocator rejects at build time every constructor that
+	// takes Provider or Scope as a parameter, directly or through an In
+	// struct, so that dependencies are declared instead of looked up. All
+	// offending constructors are reported together as ServiceLocatorErrors.
+	DisallowServiceLocator bool
+
+	// ServiceLocatorAllowlist lists service types whose constructors may
+	// still take Provider or Scope when DisallowServiceLocator is set, e.g.
+	// factories that resolve handlers by name.
+	ServiceLocatorAllowlist []reflect.Type
+
+	// ScopeWaitTimeout bounds how long closing a scope waits for goroutines
+	// tracked by its ScopeWaitGroup. Services are disposed once it expires
+	// and Close reports the goroutines still running. Zero waits until they
+	// finish.
+	ScopeWaitTimeout time.Duration
+
+	// BeforeClose, if set, is called when Close or StopAll starts, after
+	// the provider stops creating scopes and before anything is closed,
+	// with the scopes still open, oldest first. It may wait for them to
+	// finish, e.g. for in-flight requests to complete; scopes closed by
+	// the time it returns are not closed again.
+	BeforeClose func(open []ScopeInfo)
+
+	// RequireScopesClosed makes Close and StopAll report the scopes still
+	// open once BeforeClose returns with an OpenScopesError. They are
+	// closed anyway, before the singletons they may use. By default they
+	// are closed silently.
+	RequireScopesClosed bool
+
+	// Validators enforce application-specific rules on the dependency
+	// graph, e.g. lifetime conventions or layering constraints. They run
+	// after godi's own validation, and the errors of all validators are
+	// reported together.
+	Validators []GraphValidator
+
+	// TranslateError is called with every error returned by Get, GetKeyed,
+	// GetGroup, ResolveByName, and Inject on the provider or its scopes,
+	// and by the helpers built on them such as godi.Resolve, so an
+	// application can map container failures to its own error taxonomy in
+	// one place. Errors passed between constructors while resolving are not
+	// translated. Returning nil keeps the original error.
+	TranslateError func(err error, site ResolutionSite) error
+
+	// Profiles lists the active build profiles, such as "dev" or "test".
+	// A result-object field tagged profile:"dev,test" is registered only
+	// when one of its profiles is active; untagged fields always are.
+	Profiles []string
+
+	// FeatureGate decides which registration of a service registered with
+	// godi.Flagged is resolved. If nil, every flag is off and the unflagged
+	// registrations are resolved.
+	FeatureGate FeatureGate
+
+	// PruneUnreachable drops every registration that is not reachable from
+	// Roots through constructor dependencies before the graph is built and
+	// validated, so unused services from shared modules cost nothing.
+	// Constructors that return nothing are always kept because they run on
+	// their own. Services that are only looked up dynamically, through an
+	// injected Provider or Scope or by name, must be listed in Roots.
+	PruneUnreachable bool
+
+	// Roots lists the service types the application resolves directly when
+	// PruneUnreachable is set. Every registration of a root type is kept,
+	// including keyed services and group members.
+	Roots []reflect.Type
+
+	// OnPrune, if set, is called during Build with the registrations that
+	// PruneUnreachable removed, in registration order.
+	OnPrune func(pruned []ServiceInfo)
+
+	// OnAuditedResolution is called every time a service registered with
+	// godi.Audited is resolved, whether directly or as a dependency, and
+	// whether or not the instance was cached. It runs synchronously on the
+	// resolving goroutine, so it should record the event and return. Build
+	// fails if any registration is audited and this is nil.
+	OnAuditedResolution func(event AuditEvent)
+
+	// OnDeprecatedResolution is called the first time each service
+	// registered with godi.Deprecated is resolved, whether directly or as a
+	// dependency, to report consumers that still need migrating. It runs
+	// synchronously on the resolving goroutine. If nil, deprecations are not
+	// reported.
+	OnDeprecatedResolution func(event DeprecationEvent)
+
+	// OnServiceConstructed is called after each successful constructor
+	// invocation, unlike resolutions served from the singleton or scope
+	// cache. duration includes resolving the constructor's dependencies. A
+	// constructor producing several services (multiple return values or a
+	// godi.Out struct) is reported once, for the registration that was
+	// requested. Registered instances are never reported.
+	OnServiceConstructed func(service ServiceInfo, duration time.Duration)
+
+	// IgnoreDuplicateRegistrations keeps the first of several identical
+	// registrations, the same constructor function registered with the same
+	// lifetime, name, and types, as happens when a shared module is included
+	// twice. Group members are never considered duplicates. By default Build
+	// fails with a DuplicateRegistrationError naming the modules of both
+	// registrations.
+	IgnoreDuplicateRegistrations bool
+
+	// OnServiceResolved and OnServiceError are called after every
+	// resolution of a registered service, directly or as a dependency and
+	// including cache hits, that succeeded or failed respectively. The
+	// event carries the scope ID and the dependency path, so a tracer can
+	// assemble the events of one request into a tree. They run
+	// synchronously on the resolving goroutine. Built-in services such as
+	// context.Context, and services resolved from the Fallback provider, are
+	// not reported.
+	OnServiceResolved func(event ResolutionEvent)
+	OnServiceError    func(event ResolutionEvent)
+
+	// CallbackSampleRate is the fraction of successful resolutions, between
+	// 0 and 1, reported to OnServiceResolved. Resolutions that are not
+	// sampled skip the callback's bookkeeping, such as timing and building
+	// the path, unless OnServiceError needs it. Failures are always reported
+	// to OnServiceError. Zero, the default, reports every resolution.
+	CallbackSampleRate float64
+
+	// CallbackFilter, if set, selects the registrations whose resolutions
+	// are reported to OnServiceResolved and OnServiceError. It is called
+	// once per registration during Build; resolutions of the others cost
+	// nothing extra.
+	CallbackFilter func(service ServiceInfo) bool
+
+	// NewInstanceCache, if set, is called for every scope, including the
+	// provider's root scope, to create the cache holding the instances of
+	// transients registered with godi.Memoize. By default a scope keeps
+	// every memoized instance until it is closed.
+	NewInstanceCache func() InstanceCache
+
+	// OnDisposalMismatch, if set, is called during Build for every
+	// registered type with a cleanup method the container will never call:
+	// one named Close, CloseContext, Shutdown, Stop or Dispose whose
+	// signature matches neither Disposable nor DisposableWithContext, such as
+	// Close() without an error result, or one with a pointer receiver on a
+	// type registered by value. Such services silently leak their resources.
+	OnDisposalMismatch func(err *DisposalMethodError)
+
+	// StrictDisposal makes Build fail with the DisposalMethodErrors that
+	// OnDisposalMismatch would receive.
+	StrictDisposal bool
+
+	// AllowScopedFromRoot lets Scoped services be resolved from the provider
+	// itself, outside any scope. Such instances are cached by the provider
+	// for its lifetime, like singletons. By default resolving one fails with
+	// ErrScopedResolvedFromRoot, since it usually means a scope was
+	// forgotten.
+	AllowScopedFromRoot bool
+
+	// ValidateScopes checks at runtime that no scoped service is resolved
+	// while a singleton or refreshing service is being constructed, from
+	// any scope. Build already rejects such dependencies when constructors
+	// declare them; this also catches the ones resolved dynamically, such as
+	// the dependencies of a WithFieldFallback constructor, and those that
+	// AllowScopedFromRoot would otherwise let through. The error wraps
+	// ErrScopedResolvedFromRoot and carries the resolution path.
+	ValidateScopes bool
+
+	// OnMutation turns godi.Clone registrations into a mutation detector
+	// for development: consumers receive the shared instance instead of a
+	// copy, and every resolution compares it with a copy taken when it was
+	// first handed out. A difference is reported once, then compared
+	// against the mutated value.
+	OnMutation func(err *MutationError)
+
+	// MaxConcurrentConstructions, when greater than one, lets Build run up
+	// to that many singleton constructors at once, such as constructors that
+	// each dial a database. A singleton is constructed once every service it
+	// depends on is ready. Zero or one, the default, constructs singletons
+	// one at a time in dependency order. Either way, a singleton that a
+	// constructor resolves before its turn is constructed on demand, once.
+	MaxConcurrentConstructions int
+
+	// IDGenerator, if set, generates the IDs of the provider's scopes. By
+	// default scope IDs come from a per-provider counter ("s1", "s2", ...),
+	// which is cheap and cannot fail.
+	IDGenerator IDGenerator
+
+	// MaxInstancesPerScope, if positive, bounds how many instances each
+	// scope, including the provider's root scope, may construct: scoped and
+	// transient services, but not singletons. Resolving one more fails with
+	// an InstanceLimitError, which stops a loop that resolves transients
+	// without bound from growing the scope until it closes. Registrations
+	// with their own godi.MaxInstancesPerScope are bounded by it instead and
+	// not counted here.
+	MaxInstancesPerScope int
+
+	// AutoCollectSlices satisfies a dependency on a slice of an interface,
+	// such as a []EventHandler parameter, by collecting every registration
+	// assignable to the interface, in registration order, when nothing is
+	// registered as the slice itself and the dependency has no group or
+	// name. Private services and the services being constructed are left
+	// out; the Fallback provider is not consulted. Group tags remain the
+	// way to ask for a curated set. Build fails if a singleton or transient
+	// would collect scoped services.
+	AutoCollectSlices bool
+
+	// ProfileLabels runs each constructor invocation under runtime/pprof
+	// labels naming the service, its lifetime and the constructing scope
+	// (see ProfileLabelService), so CPU profiles attribute construction
+	// cost to services and, with scopes named by WithScopeName, to the
+	// requests that caused it. Setting the labels costs a few allocations
+	// per constructor call, which is why it is off by default.
+	ProfileLabels bool
+
+	// ScopePoolSize bounds how many closed scopes GetPooledScope keeps
+	// for reuse, with the instances they retained. Zero keeps up to 64; a
+	// pooled scope closed while the pool is full disposes its instances.
+	ScopePoolSize int
+
+	// OnOptionalFailure, if set, lets an optional:"true" field whose
+	// service is registered but fails to construct be left nil, like the
+	// field of a service that is not registered, and reports every such
+	// failure to it. By default the failure fails the consumer. Failures
+	// caused by a closed scope or provider, or a cancelled context, are
+	// never forgiven. DiagnosticsOf counts the forgiven failures, and
+	// OptionalFailuresOf lists them per scope.
+	OnOptionalFailure func(err *OptionalFailureError)
+
+	// Clock, if set, replaces the system clock for the container's own
+	// timing: refresh intervals, ScopeWaitTimeout and scope creation times.
+	// See Clock.
+	Clock Clock
+}
+
+// validate checks options that can be rejected before any build work starts.
+func (o *ProviderOptions) validate() error {
+	if o.Fallback != nil {
+		if fallback, ok := o.Fallback.(interface{ isDisposed() bool }); ok && fallback.isDisposed() {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("fallback provider %s: %w", o.Fallback.ID(), ErrProviderDisposed),
+			}
+		}
+	}
+	if o.ScopeWaitTimeout < 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid ScopeWaitTimeout %v: timeout cannot be negative", o.ScopeWaitTimeout),
+		}
+	}
+	if o.MaxInstancesPerScope < 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid MaxInstancesPerScope %d: limit cannot be negative", o.MaxInstancesPerScope),
+		}
+	}
+	if o.ScopePoolSize < 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid ScopePoolSize %d: size cannot be negative", o.ScopePoolSize),
+		}
+	}
+	if o.MaxConcurrentConstructions < 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid MaxConcurrentConstructions %d: limit cannot be negative", o.MaxConcurrentConstructions),
+		}
+	}
+	if o.PruneUnreachable && len(o.Roots) == 0 {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("PruneUnreachable requires at least one root service type"),
+		}
+	}
+	if !(o.CallbackSampleRate >= 0 && o.CallbackSampleRate <= 1) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid CallbackSampleRate %v: rate must be between 0 and 1", o.CallbackSampleRate),
+		}
+	}
+	if slices.Contains(o.Roots, nil) {
+		return &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid Roots: %w", ErrServiceTypeNil),
+		}
+	}
+	return nil
 }
 
 // provider is the concrete implementation of Provider
@@ -79,8 +387,9 @@ type provider struct {
 	id string
 
 	// Service registry (immutable after build)
-	services map[TypeKey]*descriptor
-	groups   map[GroupKey][]*descriptor
+	services    map[TypeKey]*descriptor
+	groups      map[GroupKey][]*descriptor
+	descriptors []*descriptor // every registration, in registration order
 
 	// Dependency graph (immutable after build)
 	graph *graph.DependencyGraph
@@ -96,12 +405,19 @@ type provider struct {
 	singletonKeys   []instanceKey
 	singletonKeysMu sync.Mutex
 
+	// In-flight singleton constructions (see constructSingleton).
+	singletonFlights sync.Map // map[any]*singletonFlight
+
+	// Singleton constructions blocked on another flight (see awaitSingleton)
+	singletonWaits   []singletonWait
+	singletonWaitsMu sync.Mutex
+
 	// Scoped descriptors with no return values (initialization functions),
 	// invoked when each scope is created. Immutable after build.
 	voidReturnScopedDescriptors []*descriptor
 
 	// Track disposable instances for cleanup
-	disposables   []Disposable
+	disposables   []trackedDisposable
 	disposableSet map[disposableIdentity]struct{}
 	disposablesMu sync.Mutex
 
@@ -115,6 +431,130 @@ type provider struct {
 	// Scope ID counter (atomic, scoped to this provider)
 	scopeCounter atomic.Uint64
 
+	// scopeIDs holds the IDs of open scopes created with WithScopeID or
+	// generated by idGenerator, guarded by scopesMu; customScopeIDs counts
+	// them so generating an ID needs no lock while there are none.
+	scopeIDs       map[string]struct{}
+	customScopeIDs atomic.Int64
+
+	// idGenerator generates scope IDs in place of scopeCounter
+	// (see ProviderOptions.IDGenerator).
+	idGenerator IDGenerator
+
+	// clock times refreshes, scope close timeouts and scope creation (see
+	// ProviderOptions.Clock). Never nil after build.
+	clock Clock
+
+	// refreshing holds the instance generations of AddRefreshing
+	// registrations. Built once at build time and read without locking.
+	refreshing map[*descriptor]*refreshState
+
+	// circuits holds the state of godi.CircuitBreaker registrations, keyed
+	// by the first member of each registration. Built once at build time
+	// and read without locking.
+	circuits map[*descriptor]*circuitState
+
+	// fallback resolves services with no registration in this provider
+	// (see ProviderOptions.Fallback). Immutable after build.
+	fallback Provider
+
+	// exports maps godi.ExportName names to their registrations.
+	// Immutable after build.
+	exports map[string]*descriptor
+
+	// scopeWaitTimeout bounds how long scope Close waits for ScopeWaitGroup
+	// goroutines (see ProviderOptions.ScopeWaitTimeout).
+	scopeWaitTimeout time.Duration
+
+	// hasPrivate reports whether any registration uses godi.Private, so
+	// top-level resolution only looks for private services when needed.
+	hasPrivate bool
+
+	// translate maps resolution errors returned to callers (see
+	// ProviderOptions.TranslateError).
+	translate func(error, ResolutionSite) error
+
+	// onAudited receives resolutions of godi.Audited services (see
+	// ProviderOptions.OnAuditedResolution).
+	onAudited func(AuditEvent)
+
+	// onDeprecated receives the first resolution of each godi.Deprecated
+	// registration (see ProviderOptions.OnDeprecatedResolution), and
+	// deprecationsReported holds the descriptors already reported.
+	onDeprecated         func(DeprecationEvent)
+	deprecationsReported sync.Map
+
+	// onConstructed receives constructor invocations (see
+	// ProviderOptions.OnServiceConstructed).
+	onConstructed func(ServiceInfo, time.Duration)
+
+	// onResolved and onResolveError receive resolutions of registered
+	// services (see ProviderOptions.OnServiceResolved).
+	onResolved     func(ResolutionEvent)
+	onResolveError func(ResolutionEvent)
+	sampleRate     float64 // of onResolved; 0 reports every resolution
+
+	// tracers are the CaptureTrace calls in progress, and tracing their
+	// number, checked on every resolution without taking tracersMu.
+	tracers   []*traceRecorder
+	tracersMu sync.Mutex
+	tracing   atomic.Int32
+
+	// onMutation, if set, reports mutations of godi.Clone instances
+	// instead of handing out copies.
+	onMutation func(*MutationError)
+
+	// maxConcurrentConstructions bounds the singleton constructors Build
+	// runs at once; zero or one builds them sequentially.
+	maxConcurrentConstructions int
+
+	// newInstanceCache creates the memoization cache of each scope (see
+	// ProviderOptions.NewInstanceCache).
+	newInstanceCache func() InstanceCache
+
+	// allowScopedFromRoot is ProviderOptions.AllowScopedFromRoot.
+	allowScopedFromRoot bool
+
+	// validateScopes is ProviderOptions.ValidateScopes.
+	validateScopes bool
+
+	// featureGate is ProviderOptions.FeatureGate.
+	featureGate FeatureGate
+
+	// maxInstancesPerScope is ProviderOptions.MaxInstancesPerScope.
+	maxInstancesPerScope int
+
+	// scopePool holds the state of closed pooled scopes for GetPooledScope,
+	// up to scopePoolSize of them.
+	scopePool     []*scopeState
+	scopePoolSize int
+	scopePoolMu   sync.Mutex
+
+	// onOptionalFailure is ProviderOptions.OnOptionalFailure.
+	onOptionalFailure func(*OptionalFailureError)
+
+	// beforeClose is ProviderOptions.BeforeClose.
+	beforeClose func([]ScopeInfo)
+
+	// requireScopesClosed is ProviderOptions.RequireScopesClosed.
+	requireScopesClosed bool
+
+	// buildInfo is resolved for godi.BuildInfo. Immutable after build.
+	buildInfo BuildInfo
+
+	// autoCollect is ProviderOptions.AutoCollectSlices, and collections
+	// caches the members collected into each slice type.
+	autoCollect bool
+	collections sync.Map // map[reflect.Type][]*descriptor
+
+	// profileLabels is ProviderOptions.ProfileLabels.
+	profileLabels bool
+
+	// Counters reported by DiagnosticsOf
+	constructions    atomic.Uint64
+	cacheHits        atomic.Uint64
+	optionalFailures atomic.Uint64
+
 	// State
 	disposed  atomic.Int32
 	closeDone chan struct{}
@@ -128,6 +568,11 @@ type instanceKey struct {
 	Group string
 }
 
+// isDisposed reports whether Close has been called on the provider.
+func (p *provider) isDisposed() bool {
+	return p.disposed.Load() != 0
+}
+
 // ID returns the unique identifier for the provider.
 // The ID is generated when the provider is built and is unique within the process.
 func (p *provider) ID() string {
@@ -137,28 +582,25 @@ func (p *provider) ID() string {
 // Get resolves a service from the root scope
 func (p *provider) Get(serviceType reflect.Type) (any, error) {
 	if p.disposed.Load() != 0 {
-		return nil, ErrProviderDisposed
-	}
-
-	if serviceType == nil {
-		return nil, ErrServiceTypeNil
+		return nil, p.disposedError(ResolutionSite{Operation: "Get", ServiceType: serviceType})
 	}
 
 	return p.rootScope.Get(serviceType)
 }
 
-// GetKeyed resolves a keyed service from the root scope
-func (p *provider) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+// ResolveMany resolves several services from the root scope
+func (p *provider) ResolveMany(serviceTypes ...reflect.Type) ([]any, error) {
 	if p.disposed.Load() != 0 {
-		return nil, ErrProviderDisposed
+		return nil, p.disposedError(ResolutionSite{Operation: "ResolveMany"})
 	}
 
-	if serviceType == nil {
-		return nil, ErrServiceTypeNil
-	}
+	return p.rootScope.ResolveMany(serviceTypes...)
+}
 
-	if key == nil {
-		return nil, ErrServiceKeyNil
+// GetKeyed resolves a keyed service from the root scope
+func (p *provider) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	if p.disposed.Load() != 0 {
+		return nil, p.disposedError(ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: key})
 	}
 
 	return p.rootScope.GetKeyed(serviceType, key)
@@ -167,25 +609,68 @@ func (p *provider) GetKeyed(serviceType reflect.Type, key any) (any, error) {
 // GetGroup resolves all services in a group from the root scope
 func (p *provider) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
 	if p.disposed.Load() != 0 {
-		return nil, ErrProviderDisposed
+		return nil, p.disposedError(ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
 	}
 
-	if serviceType == nil {
-		return nil, ErrServiceTypeNil
+	return p.rootScope.GetGroup(serviceType, group)
+}
+
+// ResolveByName resolves an exported service from the root scope
+func (p *provider) ResolveByName(name string) (any, error) {
+	if p.disposed.Load() != 0 {
+		return nil, p.disposedError(ResolutionSite{Operation: "ResolveByName", Name: name})
 	}
 
-	if group == "" {
-		return nil, &ValidationError{
-			ServiceType: serviceType,
-			Cause:       ErrGroupNameEmpty,
-		}
+	return p.rootScope.ResolveByName(name)
+}
+
+// Inject fills the fields of target from the root scope
+func (p *provider) Inject(target any) error {
+	if p.disposed.Load() != 0 {
+		return p.disposedError(ResolutionSite{Operation: "Inject", ServiceType: reflect.TypeOf(target)})
 	}
 
-	return p.rootScope.GetGroup(serviceType, group)
+	return p.rootScope.Inject(target)
+}
+
+// disposedError reports a resolution attempted on a closed provider.
+func (p *provider) disposedError(site ResolutionSite) error {
+	site.ScopeID = p.rootScope.id
+	return p.translateError(ErrProviderDisposed, site)
+}
+
+// translateError passes a resolution error through
+// ProviderOptions.TranslateError. A nil translation keeps the original.
+func (p *provider) translateError(err error, site ResolutionSite) error {
+	if err == nil || p.translate == nil {
+		return err
+	}
+	if translated := p.translate(err, site); translated != nil {
+		return translated
+	}
+	return err
 }
 
-// CreateScope creates a new service scope
+// CreateScope creates a new service scope. It may be called from
+// constructors, including singleton constructors run by Build.
 func (p *provider) CreateScope(ctx context.Context) (Scope, error) {
+	return p.createScopeWith(ctx)
+}
+
+// createScopeWith creates a scope configured by opts. A scope created from
+// the provider has no parent, so InheritScoped has no effect.
+func (p *provider) createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error) {
+	var options scopeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyScopeOption(&options)
+		}
+	}
+	return p.createScope(ctx, options)
+}
+
+// createScope creates and tracks a scope without a parent.
+func (p *provider) createScope(ctx context.Context, options scopeOptions) (Scope, error) {
 	if p.disposed.Load() != 0 {
 		return nil, ErrProviderDisposed
 	}
@@ -199,7 +684,7 @@ func (p *provider) CreateScope(ctx context.Context) (Scope, error) {
 
 	// Create scope with cancellable context
 	ctx, cancel := context.WithCancel(ctx)
-	s, err := newScope(p, nil, ctx, cancel)
+	s, err := newScope(p, nil, ctx, cancel, options)
 	if err != nil {
 		return nil, err
 	}
@@ -233,84 +718,34 @@ func (p *provider) CreateScope(ctx context.Context) (Scope, error) {
 	return s, nil
 }
 
-// Close disposes the provider and all its resources
-func (p *provider) Close() (result error) {
-	if !p.disposed.CompareAndSwap(0, 1) {
-		<-p.closeDone
-		return p.closeErr
-	}
-	defer func() {
-		p.closeErr = result
-		close(p.closeDone)
-	}()
-
-	var errors []error
-
-	// Close all scopes
-	p.scopesMu.Lock()
-	scopes := make([]*scope, 0, len(p.scopes))
-	for s := range p.scopes {
-		if s.parentScope == nil {
-			scopes = append(scopes, s)
-		}
-	}
-	p.scopes = nil
-	p.scopesMu.Unlock()
-
-	for _, s := range scopes {
-		if s != nil {
-			if err := s.Close(); err != nil {
-				errors = append(errors, fmt.Errorf("scope %s: %w", s.ID(), err))
-			}
-		}
-	}
-
-	// Close root scope. The field is deliberately not nil-ed: concurrent
-	// Get/GetKeyed/GetGroup calls read it without synchronization, and a
-	// closed root scope already rejects resolution with ErrScopeDisposed.
-	if p.rootScope != nil {
-		if err := p.rootScope.Close(); err != nil {
-			errors = append(errors, fmt.Errorf("root scope: %w", err))
-		}
-	}
+// Close disposes the provider and all its resources, in the order StopAll
+// does.
+func (p *provider) Close() error {
+	return p.shutdown(nil)
+}
 
-	// Dispose all singleton disposables.
-	// disposableSet is deliberately retained: trackDisposable consults it
-	// after close so a singleton constructed concurrently with Close is
-	// closed eagerly, exactly once, instead of leaking.
+// takeDisposables hands the singleton disposables to a closing provider, in
+// creation order. disposableSet is deliberately retained: trackDisposable
+// consults it after close so a singleton constructed concurrently with Close
+// is closed eagerly, exactly once, instead of leaking.
+func (p *provider) takeDisposables() []trackedDisposable {
 	p.disposablesMu.Lock()
 	disposables := p.disposables
 	p.disposables = nil
 	p.disposablesMu.Unlock()
+	return disposables
+}
 
-	// Dispose in reverse order of creation; panic-isolate each Close so one
-	// misbehaving disposable cannot abort the rest of the teardown loop.
-	for i := len(disposables) - 1; i >= 0; i-- {
-		if disposables[i] != nil {
-			if err := safeClose(disposables[i]); err != nil {
-				errors = append(errors, fmt.Errorf("singleton disposable %d: %w", i, err))
-			}
-		}
-	}
-
-	// Clear all internal state - clear singletons from sync.Map.
-	// voidReturnScopedDescriptors is deliberately left intact: it is
-	// immutable after build and read without synchronization by newScope.
+// clearSingletons drops the singletons of a closed provider.
+// voidReturnScopedDescriptors is deliberately left intact: it is immutable
+// after build and read without synchronization by newScope.
+func (p *provider) clearSingletons() {
 	p.singletonKeysMu.Lock()
 	for _, key := range p.singletonKeys {
 		p.singletons.Delete(key)
 	}
 	p.singletonKeys = nil
 	p.singletonKeysMu.Unlock()
-
-	if len(errors) > 0 {
-		return &DisposalError{
-			Context: "provider",
-			Errors:  errors,
-		}
-	}
-
-	return nil
 }
 
 // getSingleton retrieves a singleton instance using lock-free sync.Map.
@@ -322,13 +757,13 @@ func (p *provider) getSingleton(key instanceKey) (any, bool) {
 // setSingleton stores a singleton instance using lock-free sync.Map.
 // It also tracks the instance if it implements the Disposable interface
 // for proper cleanup during provider disposal.
-func (p *provider) setSingleton(key instanceKey, instance any) {
+func (p *provider) setSingleton(descriptor *descriptor, key instanceKey, instance any) {
 	if instance == nil {
 		return
 	}
 
 	p.cacheSingleton(key, instance)
-	p.trackDisposable(instance)
+	p.trackDisposable(descriptor, instance)
 }
 
 func (p *provider) cacheSingleton(key instanceKey, instance any) {
@@ -341,8 +776,8 @@ func (p *provider) cacheSingleton(key instanceKey, instance any) {
 
 }
 
-func (p *provider) trackDisposable(instance any) {
-	if d, ok := instance.(Disposable); ok {
+func (p *provider) trackDisposable(descriptor *descriptor, instance any) {
+	if d, ok := disposableFor(descriptor, instance); ok {
 		p.disposablesMu.Lock()
 		if identity, identifiable := identifyDisposable(d); identifiable {
 			if _, exists := p.disposableSet[identity]; exists {
@@ -361,7 +796,7 @@ func (p *provider) trackDisposable(instance any) {
 			closeOrphan(d)
 			return
 		}
-		p.disposables = append(p.disposables, d)
+		p.disposables = append(p.disposables, trackedDisposable{Disposable: d, descriptor: descriptor})
 		p.disposablesMu.Unlock()
 	}
 }
@@ -377,6 +812,34 @@ func (p *provider) findDescriptor(serviceType reflect.Type, key any) *descriptor
 	return p.services[typeKey]
 }
 
+// privateError reports a godi.Private service requested directly rather
+// than as a dependency, or nil.
+func (p *provider) privateError(serviceType reflect.Type, key any) error {
+	d := p.findDescriptor(serviceType, key)
+	if d == nil || !d.private {
+		return nil
+	}
+	return &PrivateServiceError{
+		ServiceType: d.Type,
+		ServiceKey:  d.Key,
+		Module:      strings.Join(d.modules, "/"),
+	}
+}
+
+// resolveFallback resolves an unregistered service from the fallback
+// provider. It reports false when no fallback is configured.
+func (p *provider) resolveFallback(key instanceKey) (any, bool, error) {
+	if p.fallback == nil {
+		return nil, false, nil
+	}
+	if key.Key != nil {
+		instance, err := p.fallback.GetKeyed(key.Type, key.Key)
+		return instance, true, err
+	}
+	instance, err := p.fallback.Get(key.Type)
+	return instance, true, err
+}
+
 // findGroupDescriptors finds all descriptors for a specific type within a group.
 // Returns an empty slice if the type is nil, group is empty, or no services are found.
 func (p *provider) findGroupDescriptors(serviceType reflect.Type, group string) []*descriptor {
@@ -403,6 +866,10 @@ func (p *provider) createAllSingletonsWithContext(ctx context.Context) error {
 		}
 	}
 
+	if p.maxConcurrentConstructions > 1 {
+		return p.createSingletonsConcurrently(ctx, sorted)
+	}
+
 	// Create instances in dependency order
 	for _, node := range sorted {
 		// Check context before each singleton creation
@@ -416,41 +883,8 @@ func (p *provider) createAllSingletonsWithContext(ctx context.Context) error {
 		default:
 		}
 
-		if node == nil || node.Provider == nil {
-			continue
-		}
-
-		descriptor, ok := node.Provider.(*descriptor)
-		if !ok {
-			return &ValidationError{
-				ServiceType: nil,
-				Cause:       fmt.Errorf("invalid provider type: %T", node.Provider),
-			}
-		}
-
-		if descriptor.Lifetime != Singleton {
-			continue
-		}
-
-		// Create instance key
-		key := instanceKey{
-			Type:  descriptor.Type,
-			Key:   descriptor.Key,
-			Group: descriptor.Group,
-		}
-
-		// Check if already created
-		if _, exists := p.getSingleton(key); exists {
-			continue
-		}
-
-		_, err := p.rootScope.createInstance(descriptor)
-		if err != nil {
-			return &ResolutionError{
-				ServiceType: descriptor.Type,
-				ServiceKey:  descriptor.Key,
-				Cause:       err,
-			}
+		if err := p.createSingleton(node); err != nil {
+			return err
 		}
 		if err := ctx.Err(); err != nil {
 			return &BuildError{
@@ -472,6 +906,240 @@ func (p *provider) createAllSingletonsWithContext(ctx context.Context) error {
 	return nil
 }
 
+// createSingleton creates the singleton of a graph node, unless the node is
+// not a singleton or its instance already exists.
+func (p *provider) createSingleton(node *graph.Node) error {
+	descriptor, err := singletonDescriptor(node)
+	if descriptor == nil {
+		return err
+	}
+
+	// Create instance key
+	key := instanceKey{
+		Type:  descriptor.Type,
+		Key:   descriptor.Key,
+		Group: descriptor.Group,
+	}
+
+	_, err = p.constructSingleton(nil, key, descriptor)
+	if err != nil && !isNotProvided(err, descriptor) {
+		return &ResolutionError{
+			ServiceType: descriptor.Type,
+			ServiceKey:  descriptor.Key,
+			Cause:       err,
+		}
+	}
+	return nil
+}
+
+// constructSingleton runs createInstance for a singleton in the root scope
+// unless it is already cached. Construction is single-flight per
+// registration, or per constructor for sister outputs of one (see
+// flightKey): concurrent requests for the same singleton wait for one
+// invocation, while singletons of different registrations are constructed
+// in parallel. Waiting for a flight that can only finish after this one
+// fails with a *CircularDependencyError (see awaitSingleton).
+func (p *provider) constructSingleton(r *resolution, key instanceKey, descriptor *descriptor) (any, error) {
+	fkey := flightKey(descriptor)
+	newFlight := &singletonFlight{scopeFlight: scopeFlight{done: make(chan struct{})}, key: key}
+	raw, loaded := p.singletonFlights.LoadOrStore(fkey, newFlight)
+	flight := raw.(*singletonFlight)
+
+	if loaded {
+		if err := p.awaitSingleton(r.singletonFlight(), flight); err != nil {
+			return nil, err
+		}
+		// A sister output's flight caches our key too.
+		if instance, ok := p.getSingleton(key); ok {
+			return instance, nil
+		}
+		if flight.err != nil {
+			return nil, flight.err
+		}
+		return nil, &ResolutionError{
+			ServiceType: key.Type,
+			ServiceKey:  key.Key,
+			Cause:       ErrSingletonNotInitialized,
+		}
+	}
+
+	flight.outer = r.singletonFlight()
+	defer func() {
+		p.singletonFlights.Delete(fkey)
+		close(flight.done)
+	}()
+
+	// Another flight may have finished between the caller's cache miss and
+	// LoadOrStore.
+	if instance, ok := p.getSingleton(key); ok {
+		flight.instance = instance
+		return instance, nil
+	}
+
+	flight.instance, flight.err = p.rootScope.createInstance(r, key, descriptor)
+	return flight.instance, flight.err
+}
+
+// singletonFlight is the single-flight of a singleton construction. Its
+// constructor may resolve other singletons, through its parameters or the
+// Provider it was given, so each flight records the one it was started
+// from to tell a wait that can never end.
+type singletonFlight struct {
+	scopeFlight
+	key   instanceKey      // requested when the flight started
+	outer *singletonFlight // whose constructor started this one, or nil
+}
+
+// singletonWait records that the constructor of from, or of a flight it
+// started, is waiting for to.
+type singletonWait struct {
+	from, to *singletonFlight
+}
+
+// awaitSingleton waits for flight on behalf of the construction me, nil for
+// a resolution outside any singleton constructor. If flight can only finish
+// after me, because me runs inside it or it is waiting for me, the
+// singletons depend on each other and a *CircularDependencyError is
+// returned instead.
+func (p *provider) awaitSingleton(me, flight *singletonFlight) error {
+	if me == nil {
+		<-flight.done
+		return nil
+	}
+
+	p.singletonWaitsMu.Lock()
+	if cycle := p.singletonCycle(me, flight, nil); cycle != nil {
+		p.singletonWaitsMu.Unlock()
+		path := make([]string, len(cycle))
+		for i, f := range cycle {
+			path[i] = formatType(f.key.Type)
+		}
+		return &CircularDependencyError{Node: path[0], Path: path}
+	}
+	wait := singletonWait{from: me, to: flight}
+	p.singletonWaits = append(p.singletonWaits, wait)
+	p.singletonWaitsMu.Unlock()
+
+	<-flight.done
+
+	p.singletonWaitsMu.Lock()
+	i := slices.Index(p.singletonWaits, wait)
+	p.singletonWaits = slices.Delete(p.singletonWaits, i, i+1)
+	p.singletonWaitsMu.Unlock()
+	return nil
+}
+
+// singletonCycle returns the flights that keep flight from finishing until
+// me has, from flight down to me, or nil if it can finish on its own.
+// Called with singletonWaitsMu held.
+func (p *provider) singletonCycle(me, flight *singletonFlight, seen []*singletonFlight) []*singletonFlight {
+	if slices.Contains(seen, flight) {
+		return nil
+	}
+	if chain := flight.startedInside(me); chain != nil {
+		return chain
+	}
+	for _, wait := range p.singletonWaits {
+		chain := flight.startedInside(wait.from)
+		if chain == nil {
+			continue
+		}
+		if rest := p.singletonCycle(me, wait.to, append(seen, flight)); rest != nil {
+			return append(chain, rest...)
+		}
+	}
+	return nil
+}
+
+// startedInside returns the flights from f down to inner if inner is f or
+// was started, directly or not, by f's constructor, and nil otherwise.
+func (f *singletonFlight) startedInside(inner *singletonFlight) []*singletonFlight {
+	var chain []*singletonFlight
+	for g := inner; g != nil; g = g.outer {
+		chain = append(chain, g)
+		if g == f {
+			slices.Reverse(chain)
+			return chain
+		}
+	}
+	return nil
+}
+
+// singletonProvider is the Provider injected into a singleton constructor.
+// It resolves from the provider it wraps, on behalf of the construction it
+// was injected into, so a constructor resolving a singleton that is waiting
+// for it fails instead of deadlocking (see awaitSingleton).
+type singletonProvider struct {
+	*provider
+	flight *singletonFlight
+}
+
+// frame returns a top-level frame resolving on behalf of p.flight.
+func (p *singletonProvider) frame() *resolution {
+	return &resolution{scope: p.rootScope, flight: p.flight}
+}
+
+// Get resolves a service from the root scope
+func (p *singletonProvider) Get(serviceType reflect.Type) (any, error) {
+	if p.disposed.Load() != 0 {
+		return nil, p.disposedError(ResolutionSite{Operation: "Get", ServiceType: serviceType})
+	}
+
+	instance, err := p.rootScope.get(p.frame(), serviceType)
+	if err != nil {
+		return nil, p.rootScope.translateError(err, ResolutionSite{Operation: "Get", ServiceType: serviceType})
+	}
+	return instance, nil
+}
+
+// GetKeyed resolves a keyed service from the root scope
+func (p *singletonProvider) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	if p.disposed.Load() != 0 {
+		return nil, p.disposedError(ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: key})
+	}
+
+	instance, err := p.rootScope.getKeyed(p.frame(), serviceType, key)
+	if err != nil {
+		return nil, p.rootScope.translateError(err, ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: key})
+	}
+	return instance, nil
+}
+
+// GetGroup resolves all services in a group from the root scope
+func (p *singletonProvider) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	if p.disposed.Load() != 0 {
+		return nil, p.disposedError(ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+
+	instances, err := p.rootScope.getGroup(p.frame(), serviceType, group)
+	if err != nil {
+		return nil, p.rootScope.translateError(err, ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return instances, nil
+}
+
+// singletonDescriptor returns the descriptor of a graph node if it is a
+// singleton, and nil otherwise.
+func singletonDescriptor(node *graph.Node) (*descriptor, error) {
+	if node == nil || node.Provider == nil {
+		return nil, nil
+	}
+
+	descriptor, ok := node.Provider.(*descriptor)
+	if !ok {
+		return nil, &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("invalid provider type: %T", node.Provider),
+		}
+	}
+
+	// Unloadable singletons are created on first use.
+	if descriptor.Lifetime != Singleton || descriptor.refresh != nil {
+		return nil, nil
+	}
+	return descriptor, nil
+}
+
 // extractParameterTypes extracts parameter types from constructor info.
 // Returns a slice of reflect.Type representing each parameter's type,
 // or nil if the info is nil.
@@ -644,3 +1312,106 @@ func MustResolveGroup[T any](provider Provider, group string) []T {
 
 	return services
 }
+
+// ResolveByName resolves the service registered with godi.ExportName(name)
+// from p, for plugin systems that only know service names at runtime.
+// Names p does not export are looked up in its fallback provider.
+//
+// Example:
+//
+//	svc, err := godi.ResolveByName(provider, "user-service")
+func ResolveByName(p Provider, name string) (any, error) {
+	switch v := p.(type) {
+	case nil:
+		return nil, ErrProviderNil
+	case nameResolver:
+		return v.ResolveByName(name)
+	default:
+		return nil, errUnsupportedProvider(p)
+	}
+}
+
+// Inject resolves the exported fields of the struct target points to from
+// p, a scope or a provider's root scope, for objects constructed by a
+// framework rather than by godi. Fields follow In struct semantics: `name`
+// and `group` tags select keyed services and groups, fields tagged
+// `optional:"true"` keep their value when the service is not registered,
+// and `inject:"-"` fields are skipped. The struct does not need to embed
+// godi.In.
+//
+// Fields are assigned as they resolve, so on error the fields before the
+// failing one are already set.
+//
+// Example:
+//
+//	job := &ReportJob{}
+//	err := godi.Inject(scope, job)
+func Inject(p Provider, target any) error {
+	switch v := p.(type) {
+	case nil:
+		return ErrProviderNil
+	case injector:
+		return v.Inject(target)
+	default:
+		return errUnsupportedProvider(p)
+	}
+}
+
+// injector is implemented by the providers and scopes of this package.
+type injector interface {
+	Inject(target any) error
+}
+
+// nameResolver is implemented by the providers and scopes of this package.
+type nameResolver interface {
+	ResolveByName(name string) (any, error)
+}
+
+// errUnsupportedProvider reports a Provider implemented outside this
+// package where one of its own is required.
+func errUnsupportedProvider(p Provider) error {
+	return &ValidationError{
+		ServiceType: nil,
+		Cause:       fmt.Errorf("unsupported provider implementation %T", p),
+	}
+}
+
+// providerOf returns the provider implementation behind a Provider or Scope.
+func providerOf(p Provider) (*provider, error) {
+	switch v := p.(type) {
+	case *provider:
+		return v, nil
+	case *singletonProvider:
+		return v.provider, nil
+	case *scope:
+		return v.rootProvider, nil
+	case *sealedProvider, *sealedScope:
+		return nil, ErrProviderSealed
+	case nil:
+		return nil, ErrProviderNil
+	default:
+		return nil, errUnsupportedProvider(p)
+	}
+}
+
+// scopeOf returns the scope to resolve from for a Provider or Scope: the
+// scope itself, or a provider's root scope.
+func scopeOf(p Provider) (*scope, error) {
+	switch v := p.(type) {
+	case *scope:
+		return v, nil
+	case *provider:
+		if v.disposed.Load() != 0 {
+			return nil, ErrProviderDisposed
+		}
+		return v.rootScope, nil
+	case *singletonProvider:
+		return scopeOf(v.provider)
+	case *sealedProvider, *sealedScope:
+		return nil, ErrProviderSealed
+	case nil:
+		return nil, ErrProviderNil
+	default:
+		return nil, errUnsupportedProvider(p)
+	}
+}
@@ -3,6 +3,8 @@ package godi
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -396,6 +398,270 @@ func TestRootScopeInitializers(t *testing.T) {
 		require.NoError(t, s.Close())
 		assert.Equal(t, int64(2), calls.Load(), "request scope initializer should run after singleton availability")
 	})
+
+	t.Run("may_take_scoped_services", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		p := BuildProvider(t,
+			AddScoped(NewTService),
+			AddScoped(func(*TService) { calls.Add(1) }),
+		)
+		assert.Equal(t, int64(1), calls.Load(), "Build runs the initializer in the root scope")
+
+		_, err := Resolve[*TService](p)
+		assert.ErrorIs(t, err, ErrScopedResolvedFromRoot, "only the initializers may resolve them there")
+	})
+
+	t.Run("failures_are_reported_by_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(func() error { return errors.New("init failure") })
+
+		_, err := c.Build()
+		var buildErr *BuildError
+		require.ErrorAs(t, err, &buildErr)
+		assert.Equal(t, "scope-initialization", buildErr.Phase)
+	})
+}
+
+func TestScopedResolvedFromRoot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fails_by_default", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTService))
+
+		_, err := Resolve[*TService](p)
+		require.ErrorIs(t, err, ErrScopedResolvedFromRoot)
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[TService](), resErr.ServiceType)
+		assert.False(t, resErr.ServiceNotFound())
+
+		RequireResolveFrom[*TService](t, NewTestScope(t, p))
+	})
+
+	t.Run("allowed_by_option", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTService)
+		p, err := c.BuildWithOptions(&ProviderOptions{AllowScopedFromRoot: true})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		first := RequireResolve[*TService](t, p)
+		assert.Same(t, first, RequireResolve[*TService](t, p), "cached for the provider's lifetime")
+		assert.NotSame(t, first, RequireResolveFrom[*TService](t, NewTestScope(t, p)))
+	})
+
+	t.Run("refreshing_services_are_exempt", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddRefreshing(NewTService))
+
+		instance := RequireResolve[*TService](t, p)
+		assert.Same(t, instance, RequireResolveFrom[*TService](t, NewTestScope(t, p)), "one instance is shared")
+	})
+}
+
+func TestProviderFallback(t *testing.T) {
+	t.Parallel()
+
+	buildFallback := func(t *testing.T, register func(Collection)) Provider {
+		t.Helper()
+		c := NewCollection()
+		register(c)
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("resolves_unregistered_services_from_fallback", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform"} })
+		})
+
+		c := NewCollection()
+		c.AddSingleton(func(dep *TDependency) *TService { return &TService{ID: dep.Name} })
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		svc, err := Resolve[*TService](p)
+		require.NoError(t, err)
+		assert.Equal(t, "platform", svc.ID)
+
+		dep, err := Resolve[*TDependency](p)
+		require.NoError(t, err)
+		fromFallback, err := Resolve[*TDependency](fallback)
+		require.NoError(t, err)
+		assert.Same(t, fromFallback, dep, "fallback singletons are shared, not copied")
+	})
+
+	t.Run("primary_registration_wins", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform"} })
+		})
+
+		c := NewCollection()
+		c.AddSingleton(func() *TDependency { return &TDependency{Name: "local"} })
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		dep, err := Resolve[*TDependency](p)
+		require.NoError(t, err)
+		assert.Equal(t, "local", dep.Name)
+	})
+
+	t.Run("keyed_lookups_fall_back_per_key", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform-primary"} }, Name("primary"))
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform-replica"} }, Name("replica"))
+		})
+
+		c := NewCollection()
+		c.AddSingleton(func() *TDependency { return &TDependency{Name: "local-primary"} }, Name("primary"))
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		primary, err := ResolveKeyed[*TDependency](p, "primary")
+		require.NoError(t, err)
+		assert.Equal(t, "local-primary", primary.Name)
+
+		replica, err := ResolveKeyed[*TDependency](p, "replica")
+		require.NoError(t, err)
+		assert.Equal(t, "platform-replica", replica.Name)
+	})
+
+	t.Run("groups_fall_back_only_when_empty", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform"} }, Group("deps"))
+		})
+
+		c := NewCollection()
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		deps, err := ResolveGroup[*TDependency](p, "deps")
+		require.NoError(t, err)
+		require.Len(t, deps, 1)
+		assert.Equal(t, "platform", deps[0].Name)
+
+		c = NewCollection()
+		c.AddSingleton(func() *TDependency { return &TDependency{Name: "local"} }, Group("deps"))
+		p, err = c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		deps, err = ResolveGroup[*TDependency](p, "deps")
+		require.NoError(t, err)
+		require.Len(t, deps, 1, "members are not merged across providers")
+		assert.Equal(t, "local", deps[0].Name)
+	})
+
+	t.Run("scopes_fall_back", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "platform"} })
+		})
+
+		c := NewCollection()
+		c.AddScoped(func(dep *TDependency) *TService { return &TService{ID: dep.Name} })
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		svc, err := Resolve[*TService](s)
+		require.NoError(t, err)
+		assert.Equal(t, "platform", svc.ID)
+	})
+
+	t.Run("chains_through_nested_fallbacks", func(t *testing.T) {
+		t.Parallel()
+		base := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDependency { return &TDependency{Name: "base"} })
+		})
+		middle, err := NewCollection().BuildWithOptions(&ProviderOptions{Fallback: base})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = middle.Close() })
+
+		p, err := NewCollection().BuildWithOptions(&ProviderOptions{Fallback: middle})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		dep, err := Resolve[*TDependency](p)
+		require.NoError(t, err)
+		assert.Equal(t, "base", dep.Name)
+	})
+
+	t.Run("missing_everywhere_is_not_found", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(Collection) {})
+
+		p, err := NewCollection().BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		_, err = Resolve[*TDependency](p)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("optional_dependency_missing_everywhere_is_nil", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Dep *TDependency `optional:"true"`
+		}
+		fallback := buildFallback(t, func(Collection) {})
+
+		var injected *TDependency
+		c := NewCollection()
+		c.AddSingleton(func(p params) *TService {
+			injected = p.Dep
+			return &TService{}
+		})
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.Nil(t, injected)
+	})
+
+	t.Run("disposed_fallback_is_rejected", func(t *testing.T) {
+		t.Parallel()
+		fallback := buildFallback(t, func(Collection) {})
+		require.NoError(t, fallback.Close())
+
+		_, err := NewCollection().BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.ErrorIs(t, err, ErrProviderDisposed)
+	})
+
+	t.Run("closing_primary_leaves_fallback_open", func(t *testing.T) {
+		t.Parallel()
+		disposable := &TDisposable{}
+		fallback := buildFallback(t, func(c Collection) {
+			c.AddSingleton(func() *TDisposable { return disposable })
+		})
+
+		c := NewCollection()
+		c.AddSingleton(func(d *TDisposable) *TService { return &TService{ID: d.Name} })
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+
+		assert.False(t, disposable.IsClosed())
+	})
 }
 
 type closeAliasA interface {
@@ -426,6 +692,292 @@ func (d countedValueDisposable) Close() error {
 	return nil
 }
 
+func TestResolveByName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves_exported_services", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTDependency, ExportName("dependency")),
+			AddScoped(func() *TService { return &TService{ID: "primary"} }, Name("primary"), ExportName("service")),
+		)
+
+		dep, err := ResolveByName(p, "dependency")
+		require.NoError(t, err)
+		assert.Same(t, RequireResolve[*TDependency](t, p), dep)
+
+		s := NewTestScope(t, p)
+		svc, err := ResolveByName(s, "service")
+		require.NoError(t, err)
+		keyed, err := ResolveKeyed[*TService](s, "primary")
+		require.NoError(t, err)
+		assert.Same(t, keyed, svc, "scoped services resolve in the calling scope")
+	})
+
+	t.Run("interface_aliases_share_the_name", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDisposable, As[Disposable](), As[io.Closer](), ExportName("closer")))
+
+		closer, err := ResolveByName(p, "closer")
+		require.NoError(t, err)
+		assert.Same(t, RequireResolve[Disposable](t, p), closer)
+	})
+
+	t.Run("unknown_name", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDependency, ExportName("dependency")))
+
+		_, err := ResolveByName(p, "missing")
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		notFound, ok := errors.AsType[*ExportNameNotFoundError](err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"dependency"}, notFound.Available)
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := ResolveByName(nil, "dependency")
+		require.ErrorIs(t, err, ErrProviderNil)
+	})
+
+	t.Run("falls_back", func(t *testing.T) {
+		t.Parallel()
+		fallback := BuildProvider(t, AddSingleton(NewTDependency, ExportName("dependency")))
+
+		c := NewCollection()
+		p, err := c.BuildWithOptions(&ProviderOptions{Fallback: fallback})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		dep, err := ResolveByName(p, "dependency")
+		require.NoError(t, err)
+		assert.Same(t, RequireResolve[*TDependency](t, fallback), dep)
+	})
+
+	t.Run("duplicate_names_fail_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTDependency, ExportName("shared"))
+		c.AddSingleton(NewTService, ExportName("shared"))
+
+		_, err := c.Build()
+		conflict, ok := errors.AsType[*ExportNameConflictError](err)
+		require.True(t, ok, "expected ExportNameConflictError, got %v", err)
+		assert.Equal(t, "shared", conflict.Name)
+		assert.Len(t, conflict.ServiceTypes, 2)
+	})
+
+	t.Run("invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTService, Group("services"), ExportName("service"))
+		c.AddSingleton(func() (*TService, *TDependency) { return nil, nil }, ExportName("pair"))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot use both godi.ExportName and godi.Group")
+		assert.Contains(t, err.Error(), `godi.ExportName("pair") needs a constructor returning a single service value`)
+	})
+
+	t.Run("disposed", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTDependency, ExportName("dependency"))
+		p, err := c.Build()
+		require.NoError(t, err)
+		s := NewTestScope(t, p)
+		require.NoError(t, s.Close())
+		require.NoError(t, p.Close())
+
+		_, err = ResolveByName(s, "dependency")
+		require.ErrorIs(t, err, ErrScopeDisposed)
+		_, err = ResolveByName(p, "dependency")
+		require.ErrorIs(t, err, ErrProviderDisposed)
+	})
+}
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	// injectTarget stands in for an object constructed by a framework.
+	type injectTarget struct {
+		Service  *TService
+		Named    *TDependency      `name:"audit"`
+		Members  []*TDependency    `group:"deps"`
+		Missing  *TServiceWithDeps `optional:"true"`
+		Skipped  *TService         `inject:"-"`
+		Preset   string            `inject:"-"`
+		internal *TService
+	}
+
+	p := BuildProvider(t,
+		AddScoped(NewTService),
+		AddSingleton(NewTDependencyWithName("audit"), Name("audit")),
+		AddSingleton(NewTDependencyWithName("a"), Group("deps")),
+		AddSingleton(NewTDependencyWithName("b"), Group("deps")),
+	)
+
+	t.Run("fills_fields_with_in_semantics", func(t *testing.T) {
+		t.Parallel()
+		s := NewTestScope(t, p)
+		existing := &TServiceWithDeps{}
+		target := &injectTarget{Preset: "kept", Missing: existing}
+
+		require.NoError(t, Inject(s, target))
+		assert.Same(t, RequireResolveFrom[*TService](t, s), target.Service, "scoped fields resolve in the calling scope")
+		assert.Equal(t, "audit", target.Named.Name)
+		assert.Len(t, target.Members, 2)
+		assert.Same(t, existing, target.Missing, "missing optional fields keep their value")
+		assert.Nil(t, target.Skipped)
+		assert.Nil(t, target.internal)
+		assert.Equal(t, "kept", target.Preset)
+	})
+
+	t.Run("missing_dependency", func(t *testing.T) {
+		t.Parallel()
+		var target struct {
+			Service *TService
+			Deps    *TServiceWithDeps
+		}
+
+		err := Inject(NewTestScope(t, p), &target)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		injectErr, ok := errors.AsType[*InjectError](err)
+		require.True(t, ok, "expected InjectError, got %v", err)
+		assert.Equal(t, "Deps", injectErr.Field)
+	})
+
+	t.Run("invalid_targets", func(t *testing.T) {
+		t.Parallel()
+		var nilTarget *injectTarget
+		for _, target := range []any{nil, injectTarget{}, nilTarget, new(int)} {
+			err := Inject(p, target)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "non-nil pointer to a struct")
+		}
+	})
+
+	t.Run("disposed", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		p, err := c.Build()
+		require.NoError(t, err)
+		s := NewTestScope(t, p)
+		require.NoError(t, s.Close())
+		require.NoError(t, p.Close())
+
+		require.ErrorIs(t, Inject(s, &injectTarget{}), ErrScopeDisposed)
+		require.ErrorIs(t, Inject(p, &injectTarget{}), ErrProviderDisposed)
+		require.ErrorIs(t, Inject(nil, &injectTarget{}), ErrProviderNil)
+	})
+}
+
+func TestTranslateError(t *testing.T) {
+	t.Parallel()
+
+	translate := func(err error, site ResolutionSite) error {
+		if errors.Is(err, ErrServiceNotFound) {
+			return &appNotFound{Code: "dependency_missing", Site: site}
+		}
+		return nil
+	}
+
+	build := func(t *testing.T, modules ...ModuleOption) Provider {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(modules...)
+		p, err := c.BuildWithOptions(&ProviderOptions{TranslateError: translate})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		return p
+	}
+
+	t.Run("translates_each_operation", func(t *testing.T) {
+		t.Parallel()
+		p := build(t)
+		s := NewTestScope(t, p)
+
+		_, err := Resolve[*TService](s)
+		notFound, ok := errors.AsType[*appNotFound](err)
+		require.True(t, ok, "expected translated error, got %v", err)
+		assert.Equal(t, "dependency_missing", notFound.Code)
+		assert.Equal(t, ResolutionSite{Operation: "Get", ScopeID: s.ID(), ServiceType: PtrTypeOf[TService]()}, notFound.Site)
+
+		_, err = p.GetKeyed(PtrTypeOf[TService](), "primary")
+		notFound, ok = errors.AsType[*appNotFound](err)
+		require.True(t, ok)
+		assert.Equal(t, "GetKeyed", notFound.Site.Operation)
+		assert.Equal(t, "primary", notFound.Site.ServiceKey)
+
+		_, err = ResolveByName(s, "missing")
+		notFound, ok = errors.AsType[*appNotFound](err)
+		require.True(t, ok)
+		assert.Equal(t, "missing", notFound.Site.Name)
+
+		var target struct{ Service *TService }
+		notFound, ok = errors.AsType[*appNotFound](Inject(s, &target))
+		require.True(t, ok)
+		assert.Equal(t, "Inject", notFound.Site.Operation)
+	})
+
+	t.Run("nil_translation_keeps_original", func(t *testing.T) {
+		t.Parallel()
+		p := build(t)
+		_, err := p.Get(nil)
+		require.ErrorIs(t, err, ErrServiceTypeNil)
+	})
+
+	t.Run("dependencies_are_not_translated", func(t *testing.T) {
+		t.Parallel()
+		type consumer struct{ dep *TDependency }
+		p := build(t,
+			AddSingleton(NewTService),
+			AddTransient(func(in struct {
+				In
+				Dep     *TDependency `optional:"true"`
+				Service *TService
+			}) *consumer {
+				return &consumer{dep: in.Dep}
+			}),
+		)
+		s := NewTestScope(t, p)
+		c := RequireResolveFrom[*consumer](t, s)
+		assert.Nil(t, c.dep, "optional dependencies still see ErrServiceNotFound")
+
+		var target struct {
+			Dep     *TDependency `optional:"true"`
+			Service *TService
+		}
+		require.NoError(t, Inject(s, &target))
+	})
+
+	t.Run("disposed_provider", func(t *testing.T) {
+		t.Parallel()
+		var sites []ResolutionSite
+		c := NewCollection()
+		p, err := c.BuildWithOptions(&ProviderOptions{TranslateError: func(err error, site ResolutionSite) error {
+			sites = append(sites, site)
+			return fmt.Errorf("container unavailable: %w", err)
+		}})
+		require.NoError(t, err)
+		require.NoError(t, p.Close())
+
+		_, err = p.GetGroup(PtrTypeOf[TService](), "services")
+		require.ErrorIs(t, err, ErrProviderDisposed)
+		assert.Contains(t, err.Error(), "container unavailable")
+		require.Len(t, sites, 1)
+		assert.Equal(t, "services", sites[0].Group)
+	})
+}
+
+// appNotFound stands in for an application's own error taxonomy.
+type appNotFound struct {
+	Code string
+	Site ResolutionSite
+}
+
+func (e *appNotFound) Error() string { return e.Code }
+
 func TestDisposableCloseDeduplication(t *testing.T) {
 	t.Parallel()
 
@@ -517,9 +1069,9 @@ func TestDisposableCloseDeduplication(t *testing.T) {
 		// A constructor that outlives a cancelled Build registers its result
 		// after Close; the orphan must be closed eagerly, and only once.
 		disposable := &countedAliasDisposable{}
-		p.(*provider).trackDisposable(disposable)
+		p.(*provider).trackDisposable(nil, disposable)
 		assert.Equal(t, int64(1), disposable.closeCalls.Load())
-		p.(*provider).trackDisposable(disposable)
+		p.(*provider).trackDisposable(nil, disposable)
 		assert.Equal(t, int64(1), disposable.closeCalls.Load())
 	})
 
@@ -683,3 +1235,38 @@ func (*countedAliasDisposable) AliasB() {}
 func (countedValueDisposable) AliasA() {}
 
 func (countedValueDisposable) AliasB() {}
+
+func TestValidateScopes(t *testing.T) {
+	t.Parallel()
+
+	// The singleton's fallback constructor resolves a scoped service, which
+	// Build's lifetime checks cannot see.
+	register := func(c Collection) {
+		c.AddSingleton(NewTDependency)
+		c.AddScoped(NewTDisposable)
+		c.AddSingleton(NewTServiceWithDeps, WithFieldFallback[*TService](func(d *TDisposable) *TService {
+			return &TService{ID: d.Name}
+		}))
+	}
+
+	t.Run("rejects_scoped_services_of_singletons", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		register(c)
+		_, err := c.BuildWithOptions(&ProviderOptions{AllowScopedFromRoot: true, ValidateScopes: true})
+		require.ErrorIs(t, err, ErrScopedResolvedFromRoot)
+
+		assert.Contains(t, err.Error(), "*TServiceWithDeps (Singleton) -> *TDisposable (Scoped)")
+	})
+
+	t.Run("captured_without_validation", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		register(c)
+		p, err := c.BuildWithOptions(&ProviderOptions{AllowScopedFromRoot: true})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		assert.NotNil(t, RequireResolve[*TServiceWithDeps](t, p).Svc)
+	})
+}
@@ -0,0 +1,125 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// pruneUnreachable removes the registrations that cannot be reached from
+// roots through constructor dependencies. Constructors that return nothing
+// are kept as roots of their own, and every registration made by one Add
+// call is kept or pruned together since one constructor produces them all.
+// It returns the kept registrations and, in registration order, the pruned
+// ones.
+func pruneUnreachable(
+	roots []reflect.Type,
+	all []*descriptor,
+	services map[TypeKey]*descriptor,
+	groups map[GroupKey][]*descriptor,
+) (
+	keptAll []*descriptor,
+	keptServices map[TypeKey]*descriptor,
+	keptGroups map[GroupKey][]*descriptor,
+	pruned []*descriptor,
+	err error,
+) {
+	reachable := make(map[*descriptor]struct{}, len(all))
+	var queue []*descriptor
+	visit := func(d *descriptor) {
+		if d == nil {
+			return
+		}
+		if _, seen := reachable[d]; seen {
+			return
+		}
+		reachable[d] = struct{}{}
+		queue = append(queue, d)
+		for _, variant := range d.flagged {
+			if _, seen := reachable[variant]; !seen {
+				reachable[variant] = struct{}{}
+				queue = append(queue, variant)
+			}
+		}
+		for _, sibling := range d.siblings {
+			if _, seen := reachable[sibling]; !seen {
+				reachable[sibling] = struct{}{}
+				queue = append(queue, sibling)
+			}
+		}
+	}
+
+	rootTypes := make(map[reflect.Type]bool, len(roots))
+	for _, root := range roots {
+		rootTypes[root] = false
+	}
+	for _, d := range all {
+		if d == nil {
+			continue
+		}
+		if _, isRoot := rootTypes[d.Type]; isRoot {
+			rootTypes[d.Type] = true
+			visit(d)
+		} else if d.VoidReturn {
+			visit(d)
+		}
+	}
+	for _, root := range roots {
+		if !rootTypes[root] {
+			return nil, nil, nil, nil, &ValidationError{
+				ServiceType: root,
+				Cause:       fmt.Errorf("root service %s is not registered", formatType(root)),
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		d := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, dep := range d.Dependencies {
+			if dep == nil {
+				continue
+			}
+			if dep.Group != "" {
+				for _, member := range groups[GroupKey{Type: dep.Type, Group: dep.Group}] {
+					visit(member)
+				}
+				continue
+			}
+			visit(services[TypeKey{Type: dep.Type, Key: dep.Key}])
+		}
+	}
+
+	keptAll = make([]*descriptor, 0, len(reachable))
+	for _, d := range all {
+		if d == nil {
+			continue
+		}
+		if _, ok := reachable[d]; ok {
+			keptAll = append(keptAll, d)
+		} else {
+			pruned = append(pruned, d)
+		}
+	}
+
+	keptServices = make(map[TypeKey]*descriptor, len(services))
+	for key, d := range services {
+		if _, ok := reachable[d]; ok {
+			keptServices[key] = d
+		}
+	}
+
+	keptGroups = make(map[GroupKey][]*descriptor, len(groups))
+	for key, members := range groups {
+		var kept []*descriptor
+		for _, d := range members {
+			if _, ok := reachable[d]; ok {
+				kept = append(kept, d)
+			}
+		}
+		if len(kept) > 0 {
+			keptGroups[key] = kept
+		}
+	}
+
+	return keptAll, keptServices, keptGroups, pruned, nil
+}
@@ -0,0 +1,126 @@
+package godi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneUnreachable(t *testing.T) {
+	t.Parallel()
+
+	buildWith := func(t *testing.T, options *ProviderOptions, modules ...ModuleOption) (Provider, error) {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(modules...)
+		p, err := c.BuildWithOptions(options)
+		if p != nil {
+			t.Cleanup(func() { _ = p.Close() })
+		}
+		return p, err
+	}
+
+	t.Run("removes_and_reports_unreachable_services", func(t *testing.T) {
+		t.Parallel()
+		var pruned []ServiceInfo
+		p, err := buildWith(t, &ProviderOptions{
+			PruneUnreachable: true,
+			Roots:            []reflect.Type{PtrTypeOf[TServiceWithDeps]()},
+			OnPrune:          func(services []ServiceInfo) { pruned = services },
+		},
+			AddSingleton(NewTService),
+			AddSingleton(NewTDependency),
+			AddSingleton(NewTServiceWithDeps),
+			AddSingleton(func() *TDisposable { panic("pruned singletons are never built") }),
+			AddSingleton(NewTDependencyWithName("unused"), Name("unused")),
+		)
+		require.NoError(t, err)
+
+		svc := RequireResolve[*TServiceWithDeps](t, p)
+		assert.NotNil(t, svc.Svc)
+		assert.NotNil(t, svc.Dep)
+
+		_, err = Resolve[*TDisposable](p)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		require.Len(t, pruned, 2)
+		assert.Equal(t, PtrTypeOf[TDisposable](), pruned[0].ServiceType)
+		assert.Equal(t, "unused", pruned[1].Key)
+	})
+
+	t.Run("pruned_services_are_not_validated", func(t *testing.T) {
+		t.Parallel()
+		modules := []ModuleOption{
+			AddSingleton(NewTService),
+			AddScoped(NewTDependency),
+			AddSingleton(func(*TDependency) *TDisposable { return NewTDisposable() }),
+		}
+		_, err := buildWith(t, nil, modules...)
+		require.Error(t, err)
+
+		_, err = buildWith(t, &ProviderOptions{
+			PruneUnreachable: true,
+			Roots:            []reflect.Type{PtrTypeOf[TService]()},
+		}, modules...)
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps_keyed_roots_groups_siblings_and_side_effects", func(t *testing.T) {
+		t.Parallel()
+		ran := false
+		p, err := buildWith(t, &ProviderOptions{
+			PruneUnreachable: true,
+			Roots:            []reflect.Type{PtrTypeOf[TServiceWithDeps]()},
+		},
+			AddSingleton(NewTServiceWithID("a"), Group("services")),
+			AddSingleton(NewTServiceWithID("b"), Group("services")),
+			AddSingleton(func(services struct {
+				In
+				All []*TService `group:"services"`
+			}) (*TDependency, *TDisposable) {
+				return &TDependency{Name: "multi"}, NewTDisposable()
+			}),
+			AddSingleton(func() *TServiceWithDeps { return &TServiceWithDeps{} }, Name("keyed")),
+			AddSingleton(func(dep *TDependency) *TServiceWithDeps { return &TServiceWithDeps{Dep: dep} }),
+			AddSingleton(func() { ran = true }),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "multi", RequireResolve[*TServiceWithDeps](t, p).Dep.Name)
+		_, err = ResolveKeyed[*TServiceWithDeps](p, "keyed")
+		require.NoError(t, err, "keyed registrations of a root type are roots")
+		_, err = Resolve[*TDisposable](p)
+		require.NoError(t, err, "siblings of a kept registration are kept")
+		services, err := ResolveGroup[*TService](p, "services")
+		require.NoError(t, err)
+		assert.Len(t, services, 2)
+		assert.True(t, ran)
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name  string
+			roots []reflect.Type
+			want  string
+		}{
+			{"no_roots", nil, "requires at least one root"},
+			{"nil_root", []reflect.Type{nil}, "invalid Roots"},
+			{"unregistered_root", []reflect.Type{PtrTypeOf[TDisposable]()}, "root service *TDisposable is not registered"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := buildWith(t, &ProviderOptions{PruneUnreachable: true, Roots: tt.roots},
+					AddSingleton(NewTService),
+				)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+				_, ok := errors.AsType[*BuildError](err)
+				assert.True(t, ok)
+			})
+		}
+	})
+}
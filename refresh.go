@@ -0,0 +1,379 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// AddRefreshing creates a ModuleOption for a service whose instance is
+// shared across scopes like a singleton but rebuilt periodically (see
+// RefreshEvery) or on demand with Invalidate. Typical uses are credential
+// or configuration clients that must pick up rotated secrets.
+//
+// Each scope pins the instance that was current when the scope first
+// resolved it, so a request never observes two generations. A replaced
+// instance is disposed once every scope using it has been closed. For
+// dependency validation the service behaves as Scoped: singletons and
+// transients cannot depend on it, and it may only depend on singletons and
+// transients itself. Unlike other scoped services it can be resolved from
+// the provider, which pins the instance to the root scope until the
+// provider is closed.
+//
+// Instances are created on first use. The constructor must produce a
+// single value; godi.Group and godi.As are not supported.
+//
+// Example:
+//
+//	services.AddModules(
+//	    godi.AddRefreshing(NewCredentialsClient, godi.RefreshEvery(10*time.Minute)),
+//	)
+func AddRefreshing(service any, opts ...AddOption) ModuleOption {
+	return func(s Collection) error {
+		c, ok := s.(*collection)
+		if !ok {
+			return &ValidationError{
+				ServiceType: nil,
+				Cause:       fmt.Errorf("godi.AddRefreshing requires a collection created by godi.NewCollection"),
+			}
+		}
+		opts = append(opts[:len(opts):len(opts)], addRefreshingOption{})
+		c.recordErr(c.addService(service, Scoped, opts...))
+		return nil
+	}
+}
+
+// RefreshEvery is an AddOption for AddRefreshing that retires the current
+// instance once it is older than d. The next resolution builds a new one.
+// Without it, instances are only replaced by Invalidate.
+func RefreshEvery(d time.Duration) AddOption {
+	return addRefreshEveryOption(d)
+}
+
+type addRefreshEveryOption time.Duration
+
+func (o addRefreshEveryOption) String() string {
+	return fmt.Sprintf("RefreshEvery(%v)", time.Duration(o))
+}
+
+func (o addRefreshEveryOption) applyAddOption(opt *addOptions) {
+	opt.RefreshEvery = time.Duration(o)
+}
+
+// addRefreshingOption marks a registration made through AddRefreshing.
+type addRefreshingOption struct{}
+
+func (addRefreshingOption) applyAddOption(opt *addOptions) {
+	opt.refreshing = true
+}
+
+// Invalidate retires the current instance of a service registered with
+// AddRefreshing. Scopes that already resolved it keep using it; it is
+// disposed when the last of them closes, or immediately if none holds it.
+// The next resolution builds a new instance.
+func Invalidate[T any](p Provider) error {
+	return invalidate(p, reflect.TypeFor[T](), nil)
+}
+
+// InvalidateKeyed is Invalidate for a service registered with godi.Name.
+func InvalidateKeyed[T any](p Provider, key any) error {
+	if key == nil {
+		return ErrServiceKeyNil
+	}
+	return invalidate(p, reflect.TypeFor[T](), key)
+}
+
+func invalidate(p Provider, serviceType reflect.Type, key any) error {
+	root, err := providerOf(p)
+	if err != nil {
+		return err
+	}
+	if root.disposed.Load() != 0 {
+		return ErrProviderDisposed
+	}
+
+	descriptor := root.findDescriptor(serviceType, key)
+	if descriptor == nil {
+		return &ResolutionError{ServiceType: serviceType, ServiceKey: key, Cause: ErrServiceNotFound}
+	}
+	state := root.refreshing[descriptor]
+	if state == nil {
+		return &ValidationError{
+			ServiceType: serviceType,
+			Cause:       fmt.Errorf("service was not registered with godi.AddRefreshing or godi.Unloadable"),
+		}
+	}
+	return state.invalidate()
+}
+
+// Unloadable is an AddOption for AddSingleton that lets the instance be
+// released with Unload and rebuilt lazily on next use, for heavyweight
+// resources such as embedded databases or model runtimes in
+// memory-constrained deployments. The instance is created on first use
+// rather than at Build.
+//
+// The registration stays a singleton: it is validated as one, and any
+// service may depend on it. Like AddRefreshing, each scope holds a
+// reference to the instance it resolved, and an unloaded instance is
+// disposed only once every scope holding it has closed. Singletons and
+// resolutions from the provider hold it through the root scope, so an
+// instance they resolved is only disposed when the provider closes; the
+// next scope to resolve it after Unload still gets a new one. Group and As
+// are not supported.
+//
+// Example:
+//
+//	services.AddSingleton(NewModelRuntime, godi.Unloadable())
+//
+//	// Free memory while idle; the next request loads it again.
+//	err := godi.Unload[*ModelRuntime](provider)
+func Unloadable() AddOption {
+	return addUnloadableOption{}
+}
+
+type addUnloadableOption struct{}
+
+func (addUnloadableOption) String() string {
+	return "Unloadable()"
+}
+
+func (addUnloadableOption) applyAddOption(opt *addOptions) {
+	opt.unloadable = true
+}
+
+// Unload releases the instance of a singleton registered with Unloadable.
+// It is disposed as soon as no scope holds it; the next resolution builds
+// a new one. Unloading a service that is not loaded is a no-op.
+func Unload[T any](p Provider) error {
+	return invalidate(p, reflect.TypeFor[T](), nil)
+}
+
+// UnloadKeyed is Unload for a service registered with godi.Name.
+func UnloadKeyed[T any](p Provider, key any) error {
+	if key == nil {
+		return ErrServiceKeyNil
+	}
+	return invalidate(p, reflect.TypeFor[T](), key)
+}
+
+// refreshState owns the generations of one AddRefreshing or Unloadable
+// registration.
+type refreshState struct {
+	descriptor *descriptor
+	every      time.Duration
+	clock      Clock
+
+	mu       sync.Mutex
+	current  *refreshGeneration
+	building *refreshGeneration // set while the constructor runs under mu
+	timer    ClockTimer
+	closed   bool
+}
+
+// refreshGeneration is one constructed instance and the number of scopes
+// holding it. refs and retired are guarded by the owning state's mu.
+type refreshGeneration struct {
+	state    *refreshState
+	instance any
+	refs     int
+	retired  bool
+
+	// cleanups holds the functions the constructor added through Cleanup,
+	// run after the instance when the generation is disposed.
+	cleanupsMu sync.Mutex
+	cleanups   []Disposable
+	disposed   bool
+}
+
+// acquire returns the current generation with its reference count
+// incremented, building one with construct if there is none. Construction
+// happens under the state lock so concurrent first uses share one instance.
+func (st *refreshState) acquire(construct func() (any, error)) (*refreshGeneration, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.closed {
+		return nil, ErrProviderDisposed
+	}
+
+	if st.current == nil {
+		gen := &refreshGeneration{state: st}
+		st.building = gen
+		instance, err := construct()
+		st.building = nil
+		if err != nil {
+			// Cleanups of a constructor that succeeded before decoration or
+			// validation failed run now; their errors are discarded.
+			_ = gen.dispose()
+			return nil, err
+		}
+		gen.instance = instance
+		st.current = gen
+		if st.every > 0 {
+			st.timer = st.clock.AfterFunc(st.every, func() { _ = st.retire(gen) })
+		}
+	}
+
+	st.current.refs++
+	return st.current, nil
+}
+
+// invalidate retires the current generation, if any.
+func (st *refreshState) invalidate() error {
+	st.mu.Lock()
+	gen := st.current
+	st.mu.Unlock()
+
+	if gen == nil {
+		return nil
+	}
+	return st.retire(gen)
+}
+
+// retire replaces gen as the current generation and disposes it if no
+// scope holds it. It is a no-op when gen is no longer current.
+func (st *refreshState) retire(gen *refreshGeneration) error {
+	st.mu.Lock()
+	if st.current != gen {
+		st.mu.Unlock()
+		return nil
+	}
+	st.current = nil
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+	}
+	gen.retired = true
+	idle := gen.refs == 0
+	st.mu.Unlock()
+
+	if idle {
+		return gen.dispose()
+	}
+	return nil
+}
+
+// close retires the current generation permanently. Called by Provider.Close
+// after every scope, and therefore every reference, has been released.
+func (st *refreshState) close() error {
+	st.mu.Lock()
+	st.closed = true
+	gen := st.current
+	st.mu.Unlock()
+
+	if gen == nil {
+		return nil
+	}
+	return st.retire(gen)
+}
+
+func (gen *refreshGeneration) release() error {
+	st := gen.state
+	st.mu.Lock()
+	gen.refs--
+	idle := gen.refs == 0 && gen.retired
+	st.mu.Unlock()
+
+	if idle {
+		return gen.dispose()
+	}
+	return nil
+}
+
+// dispose closes the instance, then the generation's cleanups in reverse
+// order of Add.
+func (gen *refreshGeneration) dispose() error {
+	gen.cleanupsMu.Lock()
+	gen.disposed = true
+	cleanups := gen.cleanups
+	gen.cleanups = nil
+	gen.cleanupsMu.Unlock()
+
+	var errs []error
+	if d, ok := disposableFor(gen.state.descriptor, gen.instance); ok {
+		errs = append(errs, safeClose(d))
+	}
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		errs = append(errs, safeClose(cleanups[i]))
+	}
+	return errors.Join(errs...)
+}
+
+// addCleanup ties d to the generation. A generation already disposed closes
+// it right away.
+func (gen *refreshGeneration) addCleanup(d Disposable) {
+	gen.cleanupsMu.Lock()
+	if !gen.disposed {
+		gen.cleanups = append(gen.cleanups, d)
+		gen.cleanupsMu.Unlock()
+		return
+	}
+	gen.cleanupsMu.Unlock()
+	_ = safeClose(d)
+}
+
+// refreshLease is a scope's reference to a generation. Scopes track it as a
+// Disposable, so closing the scope releases the reference.
+type refreshLease struct {
+	gen  *refreshGeneration
+	once sync.Once
+}
+
+func (l *refreshLease) Close() (err error) {
+	l.once.Do(func() { err = l.gen.release() })
+	return err
+}
+
+// resolveUnloadable resolves a singleton registered with Unloadable from s:
+// the instance s already holds, or the current generation leased to s.
+func (s *scope) resolveUnloadable(r *resolution, key instanceKey, descriptor *descriptor) (any, bool, error) {
+	for s.inheritScoped {
+		s = s.parentScope
+	}
+	if instance, ok := s.getInstance(key); ok {
+		s.rootProvider.cacheHits.Add(1)
+		return instance, true, nil
+	}
+	instance, err := s.resolveScopedSingleFlight(r, key, descriptor)
+	return instance, false, err
+}
+
+// leaseRefreshing resolves an AddRefreshing service for s: it acquires the
+// current generation, constructing it in the root scope when needed, and
+// pins it to s until s is closed.
+func (s *scope) leaseRefreshing(r *resolution, key instanceKey, descriptor *descriptor) (any, error) {
+	state := s.rootProvider.refreshing[descriptor]
+	if state == nil {
+		return nil, &ValidationError{
+			ServiceType: descriptor.Type,
+			Cause:       fmt.Errorf("refreshing service has no state; it was not registered through the collection"),
+		}
+	}
+
+	gen, err := state.acquire(func() (any, error) {
+		root := s.rootProvider.rootScope
+		info, results, err := root.invokeConstructor(r, key, descriptor)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateServiceResults(info, results); err != nil {
+			return nil, err
+		}
+		return root.decorate(r, key, descriptor, results[0].Interface())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.instancesMu.Lock()
+	if s.instances != nil {
+		s.storeInstanceLocked(descriptor, key, gen.instance)
+	}
+	s.instancesMu.Unlock()
+
+	// A scope closed concurrently closes the lease immediately.
+	s.appendDisposable(nil, &refreshLease{gen: gen})
+	return gen.instance, nil
+}
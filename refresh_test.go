@@ -0,0 +1,274 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type refreshCredentials struct {
+	TDisposable
+	Generation int64
+}
+
+func TestAddRefreshing(t *testing.T) {
+	t.Parallel()
+
+	newCredentials := func(builds *atomic.Int64) func() *refreshCredentials {
+		return func() *refreshCredentials {
+			return &refreshCredentials{Generation: builds.Add(1)}
+		}
+	}
+
+	newScope := func(t *testing.T, p Provider) Scope {
+		t.Helper()
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	}
+
+	t.Run("shared_across_scopes", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddRefreshing(newCredentials(&builds)))
+
+		first := RequireResolveFrom[*refreshCredentials](t, newScope(t, p))
+		second := RequireResolveFrom[*refreshCredentials](t, newScope(t, p))
+		assert.Same(t, first, second)
+		assert.Equal(t, int64(1), builds.Load())
+	})
+
+	t.Run("invalidate_defers_disposal_until_scopes_close", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddRefreshing(newCredentials(&builds)))
+
+		inFlight := newScope(t, p)
+		old := RequireResolveFrom[*refreshCredentials](t, inFlight)
+
+		require.NoError(t, Invalidate[*refreshCredentials](p))
+		assert.False(t, old.IsClosed(), "instance still in use must not be disposed")
+		assert.Same(t, old, RequireResolveFrom[*refreshCredentials](t, inFlight), "a scope keeps its instance")
+
+		fresh := RequireResolveFrom[*refreshCredentials](t, newScope(t, p))
+		assert.Equal(t, int64(2), fresh.Generation)
+
+		require.NoError(t, inFlight.Close())
+		assert.True(t, old.IsClosed())
+		assert.False(t, fresh.IsClosed())
+	})
+
+	t.Run("invalidate_disposes_idle_instance", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddRefreshing(newCredentials(&builds)))
+
+		s := newScope(t, p)
+		creds := RequireResolveFrom[*refreshCredentials](t, s)
+		require.NoError(t, s.Close())
+		assert.False(t, creds.IsClosed(), "the current instance outlives scopes")
+
+		require.NoError(t, Invalidate[*refreshCredentials](p))
+		assert.True(t, creds.IsClosed())
+	})
+
+	t.Run("refresh_every_retires_on_schedule", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddRefreshing(newCredentials(&builds), RefreshEvery(10*time.Millisecond)))
+
+		s := newScope(t, p)
+		creds := RequireResolveFrom[*refreshCredentials](t, s)
+		require.NoError(t, s.Close())
+
+		require.Eventually(t, creds.IsClosed, time.Second, 5*time.Millisecond)
+		fresh := RequireResolveFrom[*refreshCredentials](t, newScope(t, p))
+		assert.NotSame(t, creds, fresh)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddRefreshing(newCredentials(&builds), Name("vault")))
+
+		s := newScope(t, p)
+		creds, err := ResolveKeyed[*refreshCredentials](s, "vault")
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+
+		require.NoError(t, InvalidateKeyed[*refreshCredentials](p, "vault"))
+		assert.True(t, creds.IsClosed())
+	})
+
+	t.Run("provider_close_disposes_current_instance", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		c := NewCollection()
+		c.AddModules(AddRefreshing(newCredentials(&builds)))
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		creds := RequireResolveFrom[*refreshCredentials](t, newScope(t, p))
+		require.NoError(t, p.Close())
+		assert.True(t, creds.IsClosed())
+	})
+
+	t.Run("constructor_errors_are_not_cached", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		p := BuildProvider(t, AddRefreshing(func() (*refreshCredentials, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New("vault unavailable")
+			}
+			return &refreshCredentials{}, nil
+		}))
+
+		_, err := Resolve[*refreshCredentials](newScope(t, p))
+		require.Error(t, err)
+		_, err = Resolve[*refreshCredentials](newScope(t, p))
+		require.NoError(t, err)
+	})
+
+	t.Run("singleton_consumer_is_rejected", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddModules(AddRefreshing(func() *refreshCredentials { return &refreshCredentials{} }))
+		c.AddSingleton(func(*refreshCredentials) *TService { return &TService{} })
+
+		_, err := c.Build()
+		_, ok := errors.AsType[*LifetimeConflictError](err)
+		assert.True(t, ok, "expected LifetimeConflictError, got %v", err)
+	})
+
+	t.Run("scoped_dependency_is_rejected", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTDependency)
+		c.AddModules(AddRefreshing(func(*TDependency) *refreshCredentials { return &refreshCredentials{} }))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refreshing service cannot depend on Scoped *TDependency")
+	})
+
+	t.Run("invalid_registrations", func(t *testing.T) {
+		t.Parallel()
+		ctor := func() *refreshCredentials { return &refreshCredentials{} }
+		tests := []struct {
+			name   string
+			module ModuleOption
+		}{
+			{"group", AddRefreshing(ctor, Group("creds"))},
+			{"as", AddRefreshing(ctor, As[Disposable]())},
+			{"negative_interval", AddRefreshing(ctor, RefreshEvery(-time.Second))},
+			{"multiple_outputs", AddRefreshing(func() (*refreshCredentials, *TService) { return nil, nil })},
+			{"refresh_every_without_add_refreshing", AddSingleton(ctor, RefreshEvery(time.Second))},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddModules(tt.module)
+				_, err := c.Build()
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("invalidate_errors", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDependency))
+
+		require.ErrorIs(t, Invalidate[*TService](p), ErrServiceNotFound)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, Invalidate[*TDependency](p), &validationErr)
+
+		require.ErrorIs(t, InvalidateKeyed[*TDependency](p, nil), ErrServiceKeyNil)
+	})
+}
+
+func TestUnloadable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unload_defers_disposal_and_rebuilds_lazily", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		p := BuildProvider(t, AddSingleton(func() *refreshCredentials {
+			return &refreshCredentials{Generation: builds.Add(1)}
+		}, Unloadable()))
+		assert.Equal(t, int64(0), builds.Load(), "unloadable singletons are created on first use")
+
+		s := NewTestScope(t, p)
+		loaded := RequireResolveFrom[*refreshCredentials](t, s)
+
+		require.NoError(t, Unload[*refreshCredentials](p))
+		assert.False(t, loaded.IsClosed(), "still referenced by an open scope")
+
+		require.NoError(t, s.Close())
+		assert.True(t, loaded.IsClosed())
+
+		reloaded := RequireResolveFrom[*refreshCredentials](t, NewTestScope(t, p))
+		assert.Equal(t, int64(2), reloaded.Generation)
+	})
+
+	t.Run("unload_when_not_loaded_is_noop", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(func() *refreshCredentials { return &refreshCredentials{} }, Unloadable(), Name("model")))
+
+		require.NoError(t, UnloadKeyed[*refreshCredentials](p, "model"))
+	})
+
+	t.Run("singletons_hold_it_until_the_provider_closes", func(t *testing.T) {
+		t.Parallel()
+		var builds atomic.Int64
+		c := NewCollection()
+		c.AddSingleton(func() *refreshCredentials {
+			return &refreshCredentials{Generation: builds.Add(1)}
+		}, Unloadable())
+		c.AddSingleton(func(creds *refreshCredentials) *TServiceWithDeps { return &TServiceWithDeps{} })
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), builds.Load(), "resolved by the singleton at Build")
+		held, err := Resolve[*refreshCredentials](p)
+		require.NoError(t, err)
+		require.NoError(t, Unload[*refreshCredentials](p))
+		assert.False(t, held.IsClosed(), "held by the root scope")
+
+		reloaded := RequireResolveFrom[*refreshCredentials](t, NewTestScope(t, p))
+		assert.Equal(t, int64(2), reloaded.Generation)
+
+		require.NoError(t, p.Close())
+		assert.True(t, held.IsClosed())
+	})
+
+	t.Run("validated_as_singleton", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTDependency)
+		c.AddSingleton(func(*TDependency) *refreshCredentials { return &refreshCredentials{} }, Unloadable())
+
+		_, err := c.Build()
+		_, ok := errors.AsType[*LifetimeConflictError](err)
+		assert.True(t, ok, "expected LifetimeConflictError, got %v", err)
+	})
+
+	t.Run("only_with_add_singleton", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(func() *refreshCredentials { return &refreshCredentials{} }, Unloadable())
+		c.AddModules(AddRefreshing(func() *TService { return &TService{} }, Unloadable()))
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can only be used with AddSingleton")
+		assert.Contains(t, err.Error(), "cannot be used with godi.AddRefreshing")
+	})
+}
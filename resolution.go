@@ -0,0 +1,355 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// ResolutionFrame is one step of a resolution path: a service that was being
+// resolved when a deeper resolution failed.
+type ResolutionFrame struct {
+	ServiceType reflect.Type
+	ServiceKey  any    // nil for non-keyed services
+	Group       string // empty unless the step resolved a group member
+	Lifetime    Lifetime
+
+	// Registered is false for the failing step of a not-found error, which
+	// has no registration and therefore no lifetime.
+	Registered bool
+}
+
+func (f ResolutionFrame) String() string {
+	var b strings.Builder
+	b.WriteString(formatType(f.ServiceType))
+	if f.ServiceKey != nil {
+		fmt.Fprintf(&b, "[%v]", f.ServiceKey)
+	}
+	if f.Group != "" {
+		fmt.Fprintf(&b, " (group %q)", f.Group)
+	}
+	if f.Registered {
+		fmt.Fprintf(&b, " (%s)", f.Lifetime)
+	}
+	return b.String()
+}
+
+// ResolveInfo describes the resolution that is constructing a service.
+// Constructors can take it as a parameter (directly or as an In field) to
+// adapt to their consumer, e.g. to name a logger after the service that
+// requested it.
+//
+// Singleton and Scoped instances are cached, so they observe the info of
+// the resolution that first built them; register per-consumer services as
+// Transient.
+type ResolveInfo struct {
+	// ScopeID is the ID of the scope constructing the service.
+	ScopeID string
+
+	// ServiceType, ServiceKey and Group identify the service being
+	// constructed, as requested.
+	ServiceType reflect.Type
+	ServiceKey  any
+	Group       string
+
+	// Consumer and ConsumerKey identify the service whose constructor
+	// requested this one. Consumer is nil when the service was resolved
+	// directly, e.g. with godi.Resolve.
+	Consumer    reflect.Type
+	ConsumerKey any
+}
+
+// ResolutionSite describes the failed call whose error is passed to
+// ProviderOptions.TranslateError.
+type ResolutionSite struct {
+	// Operation is the method that failed: "Get", "ResolveMany",
+	// "GetKeyed", "GetGroup", "ResolveByName", "Inject", "WrapConsumer", or
+	// "Invoke".
+	Operation string
+
+	// ScopeID is the ID of the scope the call was made on; calls on the
+	// provider report its root scope.
+	ScopeID string
+
+	// ServiceType, ServiceKey and Group identify the requested service.
+	// For ResolveMany it is the type that failed, for Inject the target
+	// type, for WrapConsumer the dependencies type, and for Invoke the
+	// function type.
+	ServiceType reflect.Type
+	ServiceKey  any
+	Group       string
+
+	// Name is the exported name passed to ResolveByName.
+	Name string
+}
+
+// info returns the ResolveInfo for the constructor running in frame r;
+// at the top level only the scope is known.
+func (r *resolution) info(s *scope) ResolveInfo {
+	info := ResolveInfo{ScopeID: s.id}
+	if r.top() {
+		return info
+	}
+	frame := newResolutionFrame(r.key, r.descriptor)
+	info.ServiceType, info.ServiceKey, info.Group = frame.ServiceType, frame.ServiceKey, frame.Group
+	if !r.parent.top() {
+		consumer := newResolutionFrame(r.parent.key, r.parent.descriptor)
+		info.Consumer, info.ConsumerKey = consumer.ServiceType, consumer.ServiceKey
+	}
+	return info
+}
+
+// formatResolutionPath renders a path as "A (Singleton) -> B (Scoped) -> C".
+func formatResolutionPath(path []ResolutionFrame) string {
+	parts := make([]string, len(path))
+	for i, frame := range path {
+		parts[i] = frame.String()
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// resolution is one constructor invocation within a top-level Get, GetKeyed
+// or GetGroup call. Frames link to the frame whose constructor requested
+// them, so a failure deep in the graph can report the full path back to the
+// service originally requested. A nil *resolution is the top level.
+//
+// A frame is the DependencyResolver handed to the constructor invoker, which
+// is how nested resolutions learn their parent.
+type resolution struct {
+	scope      *scope
+	parent     *resolution
+	key        instanceKey
+	descriptor *descriptor
+
+	// shared holds the instances shared within one top-level call: those
+	// of PerResolution services, and with MemoizePerInvoke all transients.
+	// Only the outermost frame holds it; see sharedInstances.
+	shared *sharedInstances
+
+	// shareTransients is set for Invoke calls made with MemoizePerInvoke
+	// and inherited by every frame below.
+	shareTransients bool
+
+	// cleanups holds what the constructor added through Cleanup, if it
+	// takes one; see cleanupList.
+	cleanups *cleanupList
+
+	// flight is set on the top-level frame of a resolution made through
+	// the Provider injected into a singleton constructor; see
+	// singletonFlight.
+	flight *singletonFlight
+}
+
+var (
+	_ reflection.DependencyResolver     = (*resolution)(nil)
+	_ reflection.OptionalFailureHandler = (*resolution)(nil)
+)
+
+// resolutionPool reuses frames across constructor invocations so tracking
+// the path costs no allocation on the resolve hot path. A frame is only
+// reachable while its constructor runs: errors copy the path out, and
+// constructors receive the scope, never the frame.
+var resolutionPool = sync.Pool{
+	New: func() any { return new(resolution) },
+}
+
+// child borrows a frame for invoking descriptor's constructor on behalf of
+// r. Callers must release it once the constructor has returned.
+func (r *resolution) child(s *scope, key instanceKey, descriptor *descriptor) *resolution {
+	frame := resolutionPool.Get().(*resolution)
+	*frame = resolution{scope: s, parent: r, key: key, descriptor: descriptor}
+	if r != nil {
+		frame.shareTransients = r.shareTransients
+	}
+	return frame
+}
+
+// sharedInstances returns the instances shared within r's top-level call,
+// held by its outermost frame.
+func (r *resolution) sharedInstances() *sharedInstances {
+	outer := r
+	for outer.parent != nil {
+		outer = outer.parent
+	}
+	if outer.shared == nil {
+		outer.shared = &sharedInstances{}
+	}
+	return outer.shared
+}
+
+// singletonFlight returns the singleton construction r runs in, if any: the
+// flight of its innermost singleton frame, or the one whose constructor was
+// given the Provider r resolves through.
+func (r *resolution) singletonFlight() *singletonFlight {
+	for f := r; f != nil; f = f.parent {
+		if f.flight != nil {
+			return f.flight
+		}
+		if d := f.descriptor; d != nil && d.Lifetime == Singleton && d.refresh == nil {
+			if raw, ok := f.scope.rootProvider.singletonFlights.Load(flightKey(d)); ok {
+				return raw.(*singletonFlight)
+			}
+		}
+	}
+	return nil
+}
+
+// top reports whether r is the top level: nil, or the root frame of an
+// Invoke call, which resolves no service itself.
+func (r *resolution) top() bool {
+	return r == nil || r.key.Type == nil
+}
+
+func (r *resolution) release() {
+	*r = resolution{}
+	resolutionPool.Put(r)
+}
+
+func (r *resolution) Get(serviceType reflect.Type) (any, error) {
+	instance, err := r.scope.get(r, serviceType)
+	if err != nil {
+		if fallback := r.fieldFallbackFor(serviceType, err); fallback != nil {
+			return r.invokeFieldFallback(serviceType, fallback)
+		}
+	}
+	return instance, err
+}
+
+func (r *resolution) GetKeyed(serviceType reflect.Type, serviceKey any) (any, error) {
+	return r.scope.getKeyed(r, serviceType, serviceKey)
+}
+
+func (r *resolution) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return r.scope.getGroup(r, serviceType, group)
+}
+
+// ForgiveOptional reports the failure of an optional field's service to
+// ProviderOptions.OnOptionalFailure and leaves the field nil, unless the
+// option is unset or the failure came from closing or cancellation.
+func (r *resolution) ForgiveOptional(field string, fieldType reflect.Type, err error) bool {
+	p := r.scope.rootProvider
+	if p.onOptionalFailure == nil ||
+		errors.Is(err, ErrScopeDisposed) || errors.Is(err, ErrProviderDisposed) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	failure := &OptionalFailureError{
+		Field:          field,
+		DependencyType: fieldType,
+		ScopeID:        r.scope.id,
+		Cause:          err,
+	}
+	if r.descriptor != nil {
+		failure.ServiceType = r.descriptor.Type
+	}
+	p.optionalFailures.Add(1)
+	r.scope.optionalFailuresMu.Lock()
+	r.scope.optionalFailures = append(r.scope.optionalFailures, failure)
+	r.scope.optionalFailuresMu.Unlock()
+	p.onOptionalFailure(failure)
+	return true
+}
+
+// path returns the frames from the top-level service down to r.
+func (r *resolution) path() []ResolutionFrame {
+	depth := 0
+	for f := r; !f.top(); f = f.parent {
+		depth++
+	}
+	path := make([]ResolutionFrame, depth, depth+1) // room for a failing leaf
+	for f := r; !f.top(); f = f.parent {
+		depth--
+		path[depth] = newResolutionFrame(f.key, f.descriptor)
+	}
+	return path
+}
+
+// validateScoped rejects resolving the scoped service key while a service
+// shared across scopes is being constructed (ProviderOptions.ValidateScopes):
+// the shared instance would keep the first scope's instance for good.
+func (r *resolution) validateScoped(key instanceKey, descriptor *descriptor) error {
+	for f := r; !f.top(); f = f.parent {
+		if f.descriptor != nil && (f.descriptor.Lifetime == Singleton || f.descriptor.refresh != nil) {
+			return &ResolutionError{
+				ServiceType: key.Type,
+				ServiceKey:  key.Key,
+				Cause:       ErrScopedResolvedFromRoot,
+				Path:        append(r.path(), newResolutionFrame(key, descriptor)),
+			}
+		}
+	}
+	return nil
+}
+
+func newResolutionFrame(key instanceKey, descriptor *descriptor) ResolutionFrame {
+	frame := ResolutionFrame{ServiceType: key.Type, ServiceKey: key.Key, Group: key.Group}
+	if key.Group != "" {
+		// Group members are stored under internal keys; the group name is
+		// what users registered.
+		frame.ServiceKey = nil
+	}
+	if descriptor != nil {
+		frame.Lifetime = descriptor.Lifetime
+		frame.Registered = true
+	}
+	return frame
+}
+
+// dependencyError attaches the resolution path to an error returned while
+// resolving key as a dependency of r's constructor. The innermost failure
+// is annotated once; outer frames pass the error through unchanged so the
+// recorded path always ends at the step that actually failed. At the top
+// level (r.top()) errors are returned as is.
+func (r *resolution) dependencyError(key instanceKey, descriptor *descriptor, err error) error {
+	if r.top() || err == nil || hasResolutionPath(err) {
+		return err
+	}
+	switch err.(type) {
+	case *ContextCancelledError, *InstanceLimitError:
+		return err // already carries its path
+	}
+
+	path := append(r.path(), newResolutionFrame(key, descriptor))
+
+	// Errors can be shared between concurrent resolutions (single-flight),
+	// so never mutate one in place.
+	if resErr, ok := err.(*ResolutionError); ok {
+		annotated := *resErr
+		annotated.Path = path
+		return &annotated
+	}
+	return &ResolutionError{
+		ServiceType: key.Type,
+		ServiceKey:  key.Key,
+		Cause:       err,
+		Path:        path,
+	}
+}
+
+// hasResolutionPath reports whether err wraps a ResolutionError that already
+// carries a path.
+func hasResolutionPath(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *ResolutionError:
+			if len(e.Path) > 0 {
+				return true
+			}
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				if hasResolutionPath(child) {
+					return true
+				}
+			}
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
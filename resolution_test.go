@@ -0,0 +1,265 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pathHandler struct{}
+type pathService struct{}
+type pathRepository struct{}
+type pathCanceller struct{}
+
+func TestResolutionPath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not_found_several_levels_deep", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t,
+			AddScoped(func(*pathService) *pathHandler { return &pathHandler{} }),
+			AddTransient(func(*pathRepository) *pathService { return &pathService{} }),
+		)
+
+		_, err := Resolve[*pathHandler](s)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.Equal(t, []ResolutionFrame{
+			{ServiceType: PtrTypeOf[pathHandler](), Lifetime: Scoped, Registered: true},
+			{ServiceType: PtrTypeOf[pathService](), Lifetime: Transient, Registered: true},
+			{ServiceType: PtrTypeOf[pathRepository]()},
+		}, resErr.Path)
+		assert.Contains(t, err.Error(),
+			"*pathHandler (Scoped) -> *pathService (Transient) -> *pathRepository: service not found")
+	})
+
+	t.Run("keys_appear_in_path", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Repo *pathRepository `name:"replica"`
+		}
+		s := BuildScope(t,
+			AddScoped(func(*pathService) *pathHandler { return &pathHandler{} }, Name("api")),
+			AddScoped(func(params) *pathService { return &pathService{} }),
+		)
+
+		_, err := ResolveKeyed[*pathHandler](s, "api")
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.Contains(t, err.Error(),
+			"*pathHandler[api] (Scoped) -> *pathService (Scoped) -> *pathRepository[replica]: service not found")
+	})
+
+	t.Run("constructor_failure_ends_path_at_failing_service", func(t *testing.T) {
+		t.Parallel()
+		ctorErr := errors.New("connection refused")
+		s := BuildScope(t,
+			AddScoped(func(*pathService) *pathHandler { return &pathHandler{} }),
+			AddScoped(func(*pathRepository) *pathService { return &pathService{} }),
+			AddTransient(func() (*pathRepository, error) { return nil, ctorErr }),
+		)
+
+		_, err := Resolve[*pathHandler](s)
+		require.ErrorIs(t, err, ctorErr)
+		assert.NotErrorIs(t, err, ErrServiceNotFound)
+
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		require.Len(t, resErr.Path, 3)
+		assert.Equal(t, PtrTypeOf[pathRepository](), resErr.ServiceType)
+		assert.Contains(t, err.Error(),
+			"*pathHandler (Scoped) -> *pathService (Scoped) -> *pathRepository (Transient): ")
+		assert.NotContains(t, err.Error(), "Make sure the service is registered")
+	})
+
+	t.Run("group_members_appear_in_path", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Services []*pathService `group:"services"`
+		}
+		s := BuildScope(t,
+			AddScoped(func(params) *pathHandler { return &pathHandler{} }),
+			AddScoped(func(*pathRepository) *pathService { return &pathService{} }, Group("services")),
+		)
+
+		_, err := Resolve[*pathHandler](s)
+		require.ErrorIs(t, err, ErrServiceNotFound)
+		assert.Contains(t, err.Error(),
+			`*pathHandler (Scoped) -> *pathService (group "services") (Scoped) -> *pathRepository: service not found`)
+	})
+
+	t.Run("singleton_failure_at_build", func(t *testing.T) {
+		t.Parallel()
+		ctorErr := errors.New("boom")
+		c := NewCollection()
+		c.AddSingleton(func(*pathService) *pathHandler { return &pathHandler{} })
+		c.AddTransient(func() (*pathService, error) { return nil, ctorErr })
+
+		_, err := c.Build()
+		require.ErrorIs(t, err, ctorErr)
+		assert.Contains(t, err.Error(), "*pathHandler (Singleton) -> *pathService (Transient): ")
+	})
+
+	t.Run("cancellation_stops_construction_mid_graph", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		var repoCalls atomic.Int32
+		p := BuildProvider(t,
+			AddScoped(func(*pathService) *pathHandler { return &pathHandler{} }),
+			// The client disconnects while this constructor's dependencies
+			// are being resolved: after the canceller, before the repository.
+			AddScoped(func(*pathCanceller, *pathRepository) *pathService { return &pathService{} }),
+			AddScoped(func() *pathCanceller {
+				cancel()
+				return &pathCanceller{}
+			}),
+			AddScoped(func() *pathRepository {
+				repoCalls.Add(1)
+				return &pathRepository{}
+			}),
+		)
+		scope, err := p.CreateScope(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = scope.Close() })
+
+		_, err = Resolve[*pathHandler](scope)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, repoCalls.Load())
+
+		cancelled, ok := errors.AsType[*ContextCancelledError](err)
+		require.True(t, ok)
+		assert.Equal(t, PtrTypeOf[pathRepository](), cancelled.ServiceType)
+		assert.Equal(t, []ResolutionFrame{
+			{ServiceType: PtrTypeOf[pathHandler](), Lifetime: Scoped, Registered: true},
+			{ServiceType: PtrTypeOf[pathService](), Lifetime: Scoped, Registered: true},
+			{ServiceType: PtrTypeOf[pathRepository](), Lifetime: Scoped, Registered: true},
+		}, cancelled.Path)
+		assert.Contains(t, err.Error(),
+			"construction cancelled at *pathHandler (Scoped) -> *pathService (Scoped) -> *pathRepository (Scoped): context canceled")
+		assert.NotErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("build_deadline_stops_singleton_construction", func(t *testing.T) {
+		t.Parallel()
+		var repoCalls atomic.Int32
+		c := NewCollection()
+		c.AddSingleton(func(*pathHandler, *pathRepository) *pathService { return &pathService{} })
+		c.AddTransient(func(ctx context.Context) *pathHandler {
+			<-ctx.Done()
+			return &pathHandler{}
+		})
+		c.AddTransient(func() *pathRepository {
+			repoCalls.Add(1)
+			return &pathRepository{}
+		})
+		require.NoError(t, c.Err())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		_, err := c.BuildWithContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, repoCalls.Load())
+		_, ok := errors.AsType[*ContextCancelledError](err)
+		assert.True(t, ok)
+	})
+
+	t.Run("top_level_not_found_has_no_path", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t)
+
+		_, err := Resolve[*pathRepository](s)
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		assert.Empty(t, resErr.Path)
+	})
+
+	t.Run("optional_dependencies_still_skip_missing_services", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Repo *pathRepository `optional:"true"`
+		}
+		s := BuildScope(t,
+			AddScoped(func(*pathService) *pathHandler { return &pathHandler{} }),
+			AddScoped(func(params) *pathService { return &pathService{} }),
+		)
+
+		_, err := Resolve[*pathHandler](s)
+		require.NoError(t, err)
+	})
+}
+
+type infoLogger struct{ info ResolveInfo }
+
+func TestResolveInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("describes_consumer", func(t *testing.T) {
+		t.Parallel()
+		type consumer struct{ log *infoLogger }
+		s := BuildScope(t,
+			AddTransient(func(info ResolveInfo) *infoLogger { return &infoLogger{info: info} }),
+			AddScoped(func(log *infoLogger) *consumer { return &consumer{log: log} }, Name("orders")),
+		)
+
+		c, err := ResolveKeyed[*consumer](s, "orders")
+		require.NoError(t, err)
+		assert.Equal(t, ResolveInfo{
+			ScopeID:     s.ID(),
+			ServiceType: PtrTypeOf[infoLogger](),
+			Consumer:    PtrTypeOf[consumer](),
+			ConsumerKey: "orders",
+		}, c.log.info)
+	})
+
+	t.Run("direct_resolution_has_no_consumer", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t, AddTransient(func(info ResolveInfo) *infoLogger { return &infoLogger{info: info} }))
+
+		log := RequireResolveFrom[*infoLogger](t, s)
+		assert.Nil(t, log.info.Consumer)
+		assert.Equal(t, PtrTypeOf[infoLogger](), log.info.ServiceType)
+	})
+
+	t.Run("group_members_and_param_objects", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Info ResolveInfo
+		}
+		type consumer struct{ logs []*infoLogger }
+		type consumerParams struct {
+			In
+			Logs []*infoLogger `group:"loggers"`
+		}
+		s := BuildScope(t,
+			AddTransient(func(p params) *infoLogger { return &infoLogger{info: p.Info} }, Group("loggers")),
+			AddTransient(func(p consumerParams) *consumer { return &consumer{logs: p.Logs} }),
+		)
+
+		c := RequireResolveFrom[*consumer](t, s)
+		require.Len(t, c.logs, 1)
+		assert.Equal(t, "loggers", c.logs[0].info.Group)
+		assert.Nil(t, c.logs[0].info.ServiceKey, "internal group keys are not exposed")
+		assert.Equal(t, PtrTypeOf[consumer](), c.logs[0].info.Consumer)
+	})
+
+	t.Run("cannot_be_registered", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() ResolveInfo { return ResolveInfo{} })
+
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reserved")
+	})
+}
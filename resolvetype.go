@@ -0,0 +1,59 @@
+package godi
+
+import "reflect"
+
+// TypeOf returns the reflect.Type of T, including interface types, which
+// reflect.TypeOf cannot produce from a value:
+//
+//	godi.TypeOf[Logger]()   // the Logger interface
+//	godi.TypeOf[*Database]() // *Database
+//
+// It pairs with ResolveType and its variants, and with the reflect.Type
+// fields of ProviderOptions such as Roots.
+func TypeOf[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// ResolveType resolves the service registered as serviceType. It is the
+// counterpart of Resolve for frameworks that discover service types at
+// runtime, such as handler types found by scanning a router.
+//
+// Example:
+//
+//	for _, t := range handlerTypes {
+//	    handler, err := godi.ResolveType(scope, t)
+//	    ...
+//	}
+func ResolveType(provider Provider, serviceType reflect.Type) (any, error) {
+	if provider == nil {
+		return nil, ErrProviderNil
+	}
+	return provider.Get(serviceType)
+}
+
+// ResolveKeyedType resolves the service registered as serviceType with
+// key, like ResolveKeyed.
+func ResolveKeyedType(provider Provider, serviceType reflect.Type, key any) (any, error) {
+	if provider == nil {
+		return nil, ErrProviderNil
+	}
+	if key == nil {
+		return nil, ErrServiceKeyNil
+	}
+	return provider.GetKeyed(serviceType, key)
+}
+
+// ResolveGroupType resolves every member of group registered as
+// serviceType, like ResolveGroup.
+func ResolveGroupType(provider Provider, serviceType reflect.Type, group string) ([]any, error) {
+	if provider == nil {
+		return nil, ErrProviderNil
+	}
+	if group == "" {
+		return nil, &ValidationError{
+			ServiceType: serviceType,
+			Cause:       ErrGroupNameEmpty,
+		}
+	}
+	return provider.GetGroup(serviceType, group)
+}
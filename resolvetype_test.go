@@ -0,0 +1,60 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveType(t *testing.T) {
+	t.Parallel()
+
+	p := BuildProvider(t,
+		AddSingleton(NewTService, As[TInterface]()),
+		AddSingleton(NewTDependencyWithName("primary"), Name("primary")),
+		AddSingleton(NewTServiceWithID("a"), Group("handlers")),
+		AddSingleton(NewTServiceWithID("b"), Group("handlers")),
+	)
+
+	t.Run("type_of", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, PtrTypeOf[TService](), TypeOf[*TService]())
+		assert.Equal(t, "TInterface", TypeOf[TInterface]().Name())
+	})
+
+	t.Run("resolves_by_type", func(t *testing.T) {
+		t.Parallel()
+		svc, err := ResolveType(p, TypeOf[TInterface]())
+		require.NoError(t, err)
+		assert.Implements(t, (*TInterface)(nil), svc)
+	})
+
+	t.Run("resolves_keyed_by_type", func(t *testing.T) {
+		t.Parallel()
+		dep, err := ResolveKeyedType(p, TypeOf[*TDependency](), "primary")
+		require.NoError(t, err)
+		assert.Equal(t, "primary", dep.(*TDependency).Name)
+
+		_, err = ResolveKeyedType(p, TypeOf[*TDependency](), nil)
+		require.ErrorIs(t, err, ErrServiceKeyNil)
+	})
+
+	t.Run("resolves_groups_by_type", func(t *testing.T) {
+		t.Parallel()
+		handlers, err := ResolveGroupType(p, TypeOf[*TService](), "handlers")
+		require.NoError(t, err)
+		assert.Len(t, handlers, 2)
+
+		_, err = ResolveGroupType(p, TypeOf[*TService](), "")
+		require.ErrorIs(t, err, ErrGroupNameEmpty)
+	})
+
+	t.Run("rejects_nil_arguments", func(t *testing.T) {
+		t.Parallel()
+		_, err := ResolveType(nil, TypeOf[*TService]())
+		require.ErrorIs(t, err, ErrProviderNil)
+		_, err = ResolveType(p, nil)
+		require.ErrorIs(t, err, ErrServiceTypeNil)
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
-	"strconv"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/junioryono/godi/v5/internal/reflection"
 )
@@ -20,9 +22,79 @@ type Scope interface {
 	Context() context.Context
 }
 
+// A ScopeOption modifies a scope created by godi.CreateScope.
+type ScopeOption interface {
+	applyScopeOption(*scopeOptions)
+}
+
+// CreateScope creates a scope of p like p.CreateScope, configured by opts
+// such as InheritScoped, WithScopeID and WithScopeName. Providers
+// implemented outside this package are supported only without options.
+//
+// Example:
+//
+//	scope, err := godi.CreateScope(ctx, provider, godi.WithScopeName("GET /users"))
+func CreateScope(ctx context.Context, p Provider, opts ...ScopeOption) (Scope, error) {
+	switch v := p.(type) {
+	case nil:
+		return nil, ErrProviderNil
+	case scopeCreator:
+		return v.createScopeWith(ctx, opts...)
+	default:
+		if len(opts) == 0 {
+			return p.CreateScope(ctx)
+		}
+		return nil, errUnsupportedProvider(p)
+	}
+}
+
+// scopeCreator is implemented by the providers and scopes of this package.
+type scopeCreator interface {
+	createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error)
+}
+
+type scopeOptions struct {
+	inheritScoped bool
+	id            string
+	hasID         bool
+	name          string
+
+	pooled bool // set by GetPooledScope
+}
+
+// InheritScoped is a ScopeOption for child scopes that share their parent's
+// scoped services instead of constructing their own. A sub-operation can use
+// it to join the request's database transaction while the transient
+// disposables it creates are still closed with the child.
+//
+// Scoped services resolved through the child are constructed in, cached by,
+// and disposed with the parent; if the parent inherits too, the lookup
+// continues up the chain. Scopes created directly from a Provider have no
+// parent and ignore the option.
+//
+// Example:
+//
+//	child, err := godi.CreateScope(ctx, scope, godi.InheritScoped())
+func InheritScoped() ScopeOption {
+	return inheritScopedOption{}
+}
+
+type inheritScopedOption struct{}
+
+func (inheritScopedOption) String() string {
+	return "InheritScoped()"
+}
+
+func (inheritScopedOption) applyScopeOption(opts *scopeOptions) {
+	opts.inheritScoped = true
+}
+
 // scope provides an isolated resolution context
 type scope struct {
 	id           string
+	customID     bool // set with WithScopeID or generated by IDGenerator, reserved in rootProvider.scopeIDs
+	name         string
+	created      time.Time
 	rootProvider *provider
 	parentScope  *scope
 	context      context.Context
@@ -36,6 +108,24 @@ type scope struct {
 	instances   map[instanceKey]any
 	instancesMu sync.RWMutex
 
+	// records holds what DumpState reports about each entry of instances:
+	// its registration and when it was cached. Guarded by instancesMu.
+	records map[instanceKey]instanceRecord
+
+	// Resolve Scoped services from parentScope instead (InheritScoped)
+	inheritScoped bool
+
+	// Return to the provider's scope pool on Close (GetPooledScope)
+	pooled bool
+
+	// Set on the root scope while Build runs the void scoped initializers,
+	// which may resolve the scoped services they take
+	initializing atomic.Bool
+
+	// Memoized transients, when ProviderOptions.NewInstanceCache is set;
+	// otherwise they are kept in instances.
+	memoCache InstanceCache
+
 	// In-flight constructor invocations (single-flight per registration).
 	// Without this, two goroutines requesting the same Scoped service can both
 	// miss the cache and both run the constructor, violating the per-scope
@@ -44,8 +134,12 @@ type scope struct {
 	// sister output types of one registration share one flight (see flightKey).
 	inflight sync.Map // map[any]*scopeFlight
 
+	// Copies of the godi.Clone instances owned by this scope, taken when
+	// first handed out, under ProviderOptions.OnMutation.
+	snapshots sync.Map // map[*descriptor]any
+
 	// Track disposable scoped instances
-	disposables   []Disposable
+	disposables   []trackedDisposable
 	disposableSet map[disposableIdentity]struct{}
 	disposablesMu sync.Mutex
 
@@ -53,6 +147,19 @@ type scope struct {
 	children   map[*scope]struct{}
 	childrenMu sync.Mutex
 
+	// Instances constructed by this scope, counted when
+	// MaxInstancesPerScope is set
+	limits   instanceLimits
+	limitsMu sync.Mutex
+
+	// Goroutines started by this scope's services, awaited by Close
+	waitGroup ScopeWaitGroup
+
+	// Optional fields left nil while resolving in this scope, reported by
+	// OptionalFailuresOf
+	optionalFailures   []*OptionalFailureError
+	optionalFailuresMu sync.Mutex
+
 	// State
 	disposed  atomic.Int32
 	closeDone chan struct{}
@@ -72,12 +179,43 @@ type scopeConstructionContext struct {
 	context context.Context
 }
 
-func newScope(rootProvider *provider, parent *scope, ctx context.Context, cancel context.CancelFunc) (*scope, error) {
-	s, err := newUninitializedScope(rootProvider, parent, ctx, cancel)
+func newScope(
+	rootProvider *provider,
+	parent *scope,
+	ctx context.Context,
+	cancel context.CancelFunc,
+	options scopeOptions,
+) (*scope, error) {
+	id, reserved := options.id, options.hasID
+	var err error
+	switch {
+	case reserved:
+		err = rootProvider.reserveScopeID(id)
+	case rootProvider.idGenerator != nil:
+		id, err = rootProvider.generateScopeID()
+		reserved = true
+	}
 	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
 		return nil, err
 	}
 
+	s, err := newUninitializedScope(rootProvider, parent, ctx, cancel, id, options.pooled)
+	if err != nil {
+		if reserved {
+			rootProvider.scopesMu.Lock()
+			rootProvider.releaseScopeIDLocked(id)
+			rootProvider.scopesMu.Unlock()
+		}
+		return nil, err
+	}
+	s.inheritScoped = options.inheritScoped && parent != nil
+	s.pooled = options.pooled
+	s.customID = reserved
+	s.name = options.name
+
 	if err := s.initializeScopedServices(); err != nil {
 		// Tear down the partially initialized scope: dispose instances
 		// created by earlier initializers and release the cancellable
@@ -91,12 +229,16 @@ func newScope(rootProvider *provider, parent *scope, ctx context.Context, cancel
 
 // newUninitializedScope creates a scope without running scoped initializers.
 // Build uses it for the root scope so initializers run after singletons are
-// created; every other caller should use newScope.
+// created; every other caller should use newScope. An empty id is
+// generated; any other must have been reserved with reserveScopeID. A pooled
+// scope takes its instances from the provider's scope pool.
 func newUninitializedScope(
 	rootProvider *provider,
 	parent *scope,
 	ctx context.Context,
 	cancel context.CancelFunc,
+	id string,
+	pooled bool,
 ) (*scope, error) {
 	if ctx == nil {
 		ctx = context.Background()
@@ -108,19 +250,35 @@ func newUninitializedScope(
 		return nil, err
 	}
 
-	// Generate scope ID using provider's counter (scoped to this provider)
-	scopeNum := rootProvider.scopeCounter.Add(1)
+	if id == "" {
+		id = rootProvider.nextScopeID()
+	}
 
 	s := &scope{
-		id:            "s" + strconv.FormatUint(scopeNum, 36),
-		rootProvider:  rootProvider,
-		parentScope:   parent,
-		cancel:        cancel,
-		instances:     make(map[instanceKey]any, 8), // Pre-size for typical usage
-		disposableSet: make(map[disposableIdentity]struct{}, 4),
-		closeDone:     make(chan struct{}),
+		id:           id,
+		created:      rootProvider.clock.Now(),
+		rootProvider: rootProvider,
+		parentScope:  parent,
+		cancel:       cancel,
+		closeDone:    make(chan struct{}),
 		// disposables and children are lazily allocated on first use.
 	}
+	var state *scopeState
+	if pooled {
+		state = rootProvider.takeScopeState()
+	}
+	if state != nil {
+		s.instances = state.instances
+		s.records = state.records
+		s.disposables = state.disposables
+		s.disposableSet = state.disposableSet
+	} else {
+		s.instances = make(map[instanceKey]any, 8) // Pre-size for typical usage
+		s.disposableSet = make(map[disposableIdentity]struct{}, 4)
+	}
+	if rootProvider.newInstanceCache != nil {
+		s.memoCache = rootProvider.newInstanceCache()
+	}
 
 	ctx = context.WithValue(ctx, scopeContextKey{}, s)
 	s.context = ctx
@@ -129,8 +287,20 @@ func newUninitializedScope(
 }
 
 func (s *scope) initializeScopedServices() error {
+	if s.inheritScoped {
+		// The parent already ran the initializers for the scoped services
+		// this scope shares.
+		return nil
+	}
+	if s == s.rootProvider.rootScope {
+		// Build runs the initializers in the root scope too, so a failing
+		// one is reported before the first scope is created.
+		s.initializing.Store(true)
+		defer s.initializing.Store(false)
+	}
 	for _, descriptor := range s.rootProvider.voidReturnScopedDescriptors {
-		if _, err := s.createInstance(descriptor); err != nil {
+		key := instanceKey{Type: descriptor.Type, Key: descriptor.Key, Group: descriptor.Group}
+		if _, err := s.createInstance(nil, key, descriptor); err != nil {
 			return &ResolutionError{
 				ServiceType: descriptor.Type,
 				ServiceKey:  descriptor.Key,
@@ -141,6 +311,11 @@ func (s *scope) initializeScopedServices() error {
 	return nil
 }
 
+// isDisposed reports whether Close has been called on the scope.
+func (s *scope) isDisposed() bool {
+	return s.disposed.Load() != 0
+}
+
 // Provider returns the parent provider that created this scope.
 // The provider contains the service registry and dependency graph.
 func (s *scope) Provider() Provider {
@@ -161,6 +336,124 @@ func (s *scope) ID() string {
 
 // Get resolves a service in this scope
 func (s *scope) Get(serviceType reflect.Type) (any, error) {
+	instance, err := s.get(nil, serviceType)
+	if err != nil {
+		return nil, s.translateError(err, ResolutionSite{Operation: "Get", ServiceType: serviceType})
+	}
+	return instance, nil
+}
+
+// GetKeyed resolves a keyed service in this scope
+func (s *scope) GetKeyed(serviceType reflect.Type, serviceKey any) (any, error) {
+	instance, err := s.getKeyed(nil, serviceType, serviceKey)
+	if err != nil {
+		return nil, s.translateError(err, ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: serviceKey})
+	}
+	return instance, nil
+}
+
+// GetGroup resolves all services in a group
+func (s *scope) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	instances, err := s.getGroup(nil, serviceType, group)
+	if err != nil {
+		return nil, s.translateError(err, ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return instances, nil
+}
+
+// ResolveByName resolves an exported service in this scope. Names not
+// exported by this provider are looked up in the fallback provider.
+func (s *scope) ResolveByName(name string) (any, error) {
+	instance, err := s.resolveByName(name)
+	if err != nil {
+		return nil, s.translateError(err, ResolutionSite{Operation: "ResolveByName", Name: name})
+	}
+	return instance, nil
+}
+
+// resolveByName resolves an exported service without translating errors.
+func (s *scope) resolveByName(name string) (any, error) {
+	if s.disposed.Load() != 0 {
+		return nil, ErrScopeDisposed
+	}
+
+	descriptor := s.rootProvider.exports[name]
+	if descriptor == nil {
+		if fallback := s.rootProvider.fallback; fallback != nil {
+			return ResolveByName(fallback, name)
+		}
+		return nil, &ExportNameNotFoundError{
+			Name:      name,
+			Available: slices.Sorted(maps.Keys(s.rootProvider.exports)),
+		}
+	}
+
+	if descriptor.Key != nil {
+		return s.getKeyed(nil, descriptor.Type, descriptor.Key)
+	}
+	return s.get(nil, descriptor.Type)
+}
+
+// Inject fills the fields of target in this scope; see godi.Inject.
+func (s *scope) Inject(target any) error {
+	if err := s.inject(target, untranslated{s}); err != nil {
+		return s.translateError(err, ResolutionSite{Operation: "Inject", ServiceType: reflect.TypeOf(target)})
+	}
+	return nil
+}
+
+// inject fills target's fields with services from resolver.
+func (s *scope) inject(target any, resolver reflection.DependencyResolver) error {
+	if s.disposed.Load() != 0 {
+		return ErrScopeDisposed
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return &ValidationError{
+			ServiceType: reflect.TypeOf(target),
+			Cause:       fmt.Errorf("inject target must be a non-nil pointer to a struct"),
+		}
+	}
+
+	builder := reflection.NewParamObjectBuilder(s.rootProvider.analyzer)
+	if err := builder.PopulateParamObject(value.Elem(), resolver); err != nil {
+		if fieldErr, ok := errors.AsType[*reflection.FieldError](err); ok {
+			return &InjectError{Target: value.Type(), Field: fieldErr.Field, Cause: fieldErr.Cause}
+		}
+		return err
+	}
+	return nil
+}
+
+// translateError applies ProviderOptions.TranslateError to an error about
+// to be returned from this scope.
+func (s *scope) translateError(err error, site ResolutionSite) error {
+	site.ScopeID = s.id
+	return s.rootProvider.translateError(err, site)
+}
+
+// untranslated resolves from a scope without ProviderOptions.TranslateError,
+// for callers that must still recognise godi's own errors, e.g. to skip
+// optional dependencies that are not registered.
+type untranslated struct {
+	s *scope
+}
+
+func (u untranslated) Get(serviceType reflect.Type) (any, error) {
+	return u.s.get(nil, serviceType)
+}
+
+func (u untranslated) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	return u.s.getKeyed(nil, serviceType, key)
+}
+
+func (u untranslated) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return u.s.getGroup(nil, serviceType, group)
+}
+
+// get resolves serviceType on behalf of r (nil at the top level).
+func (s *scope) get(r *resolution, serviceType reflect.Type) (any, error) {
 	if s.disposed.Load() != 0 {
 		return nil, ErrScopeDisposed
 	}
@@ -169,18 +462,35 @@ func (s *scope) Get(serviceType reflect.Type) (any, error) {
 		return nil, ErrServiceTypeNil
 	}
 
+	if r.top() && s.rootProvider.hasPrivate {
+		if err := s.rootProvider.privateError(serviceType, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	key := instanceKey{Type: serviceType}
-	instance, err := s.resolve(key, nil)
+	instance, err := s.resolve(r, key, nil)
 	// If Close ran while resolve was in flight, surface that as
 	// ErrScopeDisposed instead of a stale "not found" / dangling instance.
 	if s.disposed.Load() != 0 {
-		return nil, ErrScopeDisposed
+		return nil, disposedError(err)
 	}
-	return instance, err
+	return instance, r.dependencyError(key, s.rootProvider.findDescriptor(key.Type, nil), err)
 }
 
-// GetKeyed resolves a keyed service in this scope
-func (s *scope) GetKeyed(serviceType reflect.Type, serviceKey any) (any, error) {
+// disposedError is the error for a resolution that finished after the scope
+// was closed. Cancelling a scope's context also closes it, so a construction
+// stopped by that cancellation reports the cancellation rather than
+// ErrScopeDisposed.
+func disposedError(err error) error {
+	if cancelled, ok := errors.AsType[*ContextCancelledError](err); ok {
+		return cancelled
+	}
+	return ErrScopeDisposed
+}
+
+// getKeyed resolves a keyed service on behalf of r (nil at the top level).
+func (s *scope) getKeyed(r *resolution, serviceType reflect.Type, serviceKey any) (any, error) {
 	if s.disposed.Load() != 0 {
 		return nil, ErrScopeDisposed
 	}
@@ -196,33 +506,60 @@ func (s *scope) GetKeyed(serviceType reflect.Type, serviceKey any) (any, error)
 	// Keys are used in map lookups; a non-comparable key would panic there.
 	// Value-level comparability: a comparable static type can still wrap a
 	// non-comparable value in an interface field and panic as a map key.
-	if !reflect.ValueOf(serviceKey).Comparable() {
+	if !isComparableKey(serviceKey) {
 		return nil, &ValidationError{
 			ServiceType: serviceType,
 			Cause:       fmt.Errorf("service key of type %T is not comparable and cannot be used as a key", serviceKey),
 		}
 	}
 
-	key := instanceKey{Type: serviceType, Key: serviceKey}
-	instance, err := s.resolve(key, nil)
+	if r.top() && s.rootProvider.hasPrivate {
+		if err := s.rootProvider.privateError(serviceType, serviceKey); err != nil {
+			return nil, err
+		}
+	}
+
+	key := s.rootProvider.keyedFallback(instanceKey{Type: serviceType, Key: serviceKey})
+	instance, err := s.resolve(r, key, nil)
 	if s.disposed.Load() != 0 {
-		return nil, ErrScopeDisposed
+		return nil, disposedError(err)
+	}
+	if err != nil {
+		err = r.dependencyError(key, s.rootProvider.findDescriptor(key.Type, key.Key), err)
 	}
 	return instance, err
 }
 
-// GetGroup resolves all services in a group
-func (s *scope) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+// isComparableKey reports whether key can be used in map lookups. Common key
+// types are checked without reflection, which allocates on every call.
+func isComparableKey(key any) bool {
+	switch key.(type) {
+	case string, int, int64, int32, uint, uint64, uint32, bool:
+		return true
+	}
+	return reflect.ValueOf(key).Comparable()
+}
+
+// getGroup resolves a group on behalf of r (nil at the top level).
+func (s *scope) getGroup(r *resolution, serviceType reflect.Type, group string) ([]any, error) {
+	instances, _, err := s.getGroupMembers(r, serviceType, group)
+	return instances, err
+}
+
+// getGroupMembers resolves a group like getGroup and also returns the
+// registration of each instance. The registrations are nil for groups
+// served by a fallback provider.
+func (s *scope) getGroupMembers(r *resolution, serviceType reflect.Type, group string) ([]any, []*descriptor, error) {
 	if s.disposed.Load() != 0 {
-		return nil, ErrScopeDisposed
+		return nil, nil, ErrScopeDisposed
 	}
 
 	if serviceType == nil {
-		return nil, ErrServiceTypeNil
+		return nil, nil, ErrServiceTypeNil
 	}
 
 	if group == "" {
-		return nil, &ValidationError{
+		return nil, nil, &ValidationError{
 			ServiceType: serviceType,
 			Cause:       ErrGroupNameEmpty,
 		}
@@ -231,37 +568,52 @@ func (s *scope) GetGroup(serviceType reflect.Type, group string) ([]any, error)
 	// Find all descriptors in the group
 	descriptors := s.rootProvider.findGroupDescriptors(serviceType, group)
 	if len(descriptors) == 0 {
-		return []any{}, nil
+		if fallback := s.rootProvider.fallback; fallback != nil {
+			instances, err := fallback.GetGroup(serviceType, group)
+			return instances, make([]*descriptor, len(instances)), err
+		}
+		return []any{}, nil, nil
 	}
 
 	instances := make([]any, 0, len(descriptors))
+	members := make([]*descriptor, 0, len(descriptors))
 	for _, descriptor := range descriptors {
 		key := instanceKey{Type: descriptor.Type, Key: descriptor.Key, Group: descriptor.Group}
-		instance, err := s.resolve(key, descriptor)
+		instance, err := s.resolve(r, key, descriptor)
 		if err != nil {
+			if isNotProvided(err, descriptor) {
+				continue // an optional result its constructor left nil
+			}
 			// Normalize close-vs-resolve races to ErrScopeDisposed, the same
 			// way Get and GetKeyed do.
 			if s.disposed.Load() != 0 {
-				return nil, ErrScopeDisposed
+				return nil, nil, disposedError(err)
 			}
-			return nil, &ResolutionError{
+			return nil, nil, &ResolutionError{
 				ServiceType: descriptor.Type,
 				ServiceKey:  descriptor.Key,
-				Cause:       fmt.Errorf("failed to resolve group member: %w", err),
+				Cause:       fmt.Errorf("failed to resolve group member: %w", r.dependencyError(key, descriptor, err)),
 			}
 		}
 
 		instances = append(instances, instance)
+		members = append(members, descriptor)
 	}
 
 	if s.disposed.Load() != 0 {
-		return nil, ErrScopeDisposed
+		return nil, nil, ErrScopeDisposed
 	}
-	return instances, nil
+	return instances, members, nil
 }
 
-// CreateScope creates a child scope
+// CreateScope creates a child scope. It may be called from constructors,
+// including those of services this scope is resolving.
 func (s *scope) CreateScope(ctx context.Context) (Scope, error) {
+	return s.createScopeWith(ctx)
+}
+
+// createScopeWith creates a child scope configured by opts.
+func (s *scope) createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error) {
 	if s.disposed.Load() != 0 {
 		return nil, ErrScopeDisposed
 	}
@@ -273,8 +625,15 @@ func (s *scope) CreateScope(ctx context.Context) (Scope, error) {
 		return nil, err
 	}
 
+	var options scopeOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyScopeOption(&options)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
-	child, err := newScope(s.rootProvider, s, ctx, cancel)
+	child, err := newScope(s.rootProvider, s, ctx, cancel, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create child scope: %w", err)
 	}
@@ -362,48 +721,73 @@ func (s *scope) Close() (result error) {
 		}
 	}
 
-	// Dispose all disposable scoped instances in reverse order.
-	// disposableSet is deliberately retained: appendDisposable consults it
-	// after close so orphaned constructor results shared across sibling
-	// registrations are still closed exactly once.
-	s.disposablesMu.Lock()
-	disposables := s.disposables
-	s.disposables = nil
-	s.disposablesMu.Unlock()
+	// Let goroutines started by this scope's services observe the
+	// cancelled context and finish before their dependencies are disposed.
+	if err := s.waitGroup.wait(s.rootProvider.clock, s.rootProvider.scopeWaitTimeout); err != nil {
+		errs = append(errs, err)
+	}
 
+	// Dispose all disposable scoped instances in reverse order, or, for a
+	// pooled scope, those it does not retain for the next one.
+	disposables := s.takeDisposables()
+	if s.pooled && !s.rootProvider.isDisposed() {
+		retained, resetErrs := s.retainForPool()
+		errs = append(errs, resetErrs...)
+		errs = append(errs, s.recycle(retained, disposables)...)
+		disposables = nil
+	}
 	for i := len(disposables) - 1; i >= 0; i-- {
-		if err := safeClose(disposables[i]); err != nil {
+		if err := safeClose(disposables[i].Disposable); err != nil {
 			errs = append(errs, fmt.Errorf("failed to dispose scoped instance: %w", err))
 		}
 	}
 
-	// Remove from parent's children
+	s.unlink()
+
+	if len(errs) > 0 {
+		return &DisposalError{
+			Context: "scope",
+			Errors:  errs,
+		}
+	}
+
+	return nil
+}
+
+// takeDisposables hands the disposables tracked by a closing scope to the
+// caller, in creation order. disposableSet is deliberately retained:
+// appendDisposable consults it after close so orphaned constructor results
+// shared across sibling registrations are still closed exactly once.
+func (s *scope) takeDisposables() []trackedDisposable {
+	s.disposablesMu.Lock()
+	disposables := s.disposables
+	s.disposables = nil
+	s.disposablesMu.Unlock()
+	return disposables
+}
+
+// unlink removes a closed scope from its parent and the provider and drops
+// its cached instances.
+func (s *scope) unlink() {
 	if s.parentScope != nil {
 		s.parentScope.childrenMu.Lock()
 		delete(s.parentScope.children, s)
 		s.parentScope.childrenMu.Unlock()
 	}
 
-	// Remove from provider's tracking
 	if s.rootProvider != nil {
 		s.rootProvider.scopesMu.Lock()
 		delete(s.rootProvider.scopes, s)
+		if s.customID {
+			s.rootProvider.releaseScopeIDLocked(s.id)
+		}
 		s.rootProvider.scopesMu.Unlock()
 	}
 
-	// Clear instances
 	s.instancesMu.Lock()
 	s.instances = nil
+	s.records = nil
 	s.instancesMu.Unlock()
-
-	if len(errs) > 0 {
-		return &DisposalError{
-			Context: "scope",
-			Errors:  errs,
-		}
-	}
-
-	return nil
 }
 
 // getInstance retrieves a cached instance from this scope in a thread-safe manner.
@@ -427,7 +811,7 @@ func (s *scope) getInstance(key instanceKey) (any, bool) {
 func (s *scope) setInstance(descriptor *descriptor, key instanceKey, instance any) {
 	switch descriptor.Lifetime {
 	case Singleton:
-		s.rootProvider.setSingleton(key, instance)
+		s.rootProvider.setSingleton(descriptor, key, instance)
 	case Scoped:
 		s.instancesMu.Lock()
 		if s.instances == nil {
@@ -435,22 +819,33 @@ func (s *scope) setInstance(descriptor *descriptor, key instanceKey, instance an
 			// The scope was closed while the constructor was running.
 			// appendDisposable closes the orphan with identity dedup so a
 			// value shared across sibling registrations closes only once.
-			s.appendDisposable(instance)
+			s.appendDisposable(descriptor, instance)
 			return
 		}
-		s.instances[key] = instance
+		s.storeInstanceLocked(descriptor, key, instance)
 		s.instancesMu.Unlock()
-		s.appendDisposable(instance)
-	case Transient:
-		s.appendDisposable(instance)
+		s.appendDisposable(descriptor, instance)
+	case Transient, PerResolution:
+		s.appendDisposable(descriptor, instance)
+	}
+}
+
+// storeInstanceLocked caches instance under key and records it for
+// DumpState. The caller holds instancesMu and has checked that the scope is
+// open.
+func (s *scope) storeInstanceLocked(descriptor *descriptor, key instanceKey, instance any) {
+	s.instances[key] = instance
+	if s.records == nil {
+		s.records = make(map[instanceKey]instanceRecord, 8)
 	}
+	s.records[key] = instanceRecord{descriptor: descriptor, created: s.rootProvider.clock.Now()}
 }
 
 // appendDisposable tracks a Disposable instance for cleanup at scope close.
 // If the scope is already closed, the instance is closed eagerly to avoid a
 // leak.
-func (s *scope) appendDisposable(instance any) {
-	d, ok := instance.(Disposable)
+func (s *scope) appendDisposable(descriptor *descriptor, instance any) {
+	d, ok := disposableFor(descriptor, instance)
 	if !ok {
 		return
 	}
@@ -470,7 +865,7 @@ func (s *scope) appendDisposable(instance any) {
 		closeOrphan(d)
 		return
 	}
-	s.disposables = append(s.disposables, d)
+	s.disposables = append(s.disposables, trackedDisposable{Disposable: d, descriptor: descriptor, created: s.rootProvider.clock.Now()})
 	s.disposablesMu.Unlock()
 }
 
@@ -521,7 +916,7 @@ func flightKey(d *descriptor) any {
 // resolveScopedSingleFlight runs createInstance for a Scoped descriptor under
 // single-flight: concurrent resolutions of the same key (or of sister output
 // keys from the same multi-return ctor) share one constructor invocation.
-func (s *scope) resolveScopedSingleFlight(key instanceKey, descriptor *descriptor) (any, error) {
+func (s *scope) resolveScopedSingleFlight(r *resolution, key instanceKey, descriptor *descriptor) (any, error) {
 	fkey := flightKey(descriptor)
 	newFlight := &scopeFlight{done: make(chan struct{})}
 	raw, loaded := s.inflight.LoadOrStore(fkey, newFlight)
@@ -555,7 +950,7 @@ func (s *scope) resolveScopedSingleFlight(key instanceKey, descriptor *descripto
 		return instance, nil
 	}
 
-	flight.instance, flight.err = s.createInstance(descriptor)
+	flight.instance, flight.err = s.createInstance(r, key, descriptor)
 	return flight.instance, flight.err
 }
 
@@ -563,29 +958,65 @@ var (
 	contextType  = reflect.TypeFor[context.Context]()
 	providerType = reflect.TypeFor[Provider]()
 	scopeType    = reflect.TypeFor[Scope]()
+
+	resolveInfoType    = reflect.TypeFor[ResolveInfo]()
+	scopeInfoType      = reflect.TypeFor[ScopeInfo]()
+	scopeWaitGroupType = reflect.TypeFor[*ScopeWaitGroup]()
+	cleanupType        = reflect.TypeFor[Cleanup]()
+	buildInfoType      = reflect.TypeFor[BuildInfo]()
 )
 
+// resolvedContext is the context.Context injected into constructors: the
+// build context while Build runs eager constructors, else the scope's.
+func (s *scope) resolvedContext() context.Context {
+	if override := s.constructionContext.Load(); override != nil {
+		return override.context
+	}
+	return s.context
+}
+
 // resolve performs the actual service resolution using the appropriate lifetime strategy.
 // It handles singleton caching, scoped caching, and transient creation, while also
 // detecting circular dependencies during resolution.
-func (s *scope) resolve(key instanceKey, descriptor *descriptor) (any, error) {
+func (s *scope) resolve(r *resolution, key instanceKey, descriptor *descriptor) (any, error) {
 	// Find descriptor if not provided
 	if descriptor == nil {
 		if key.Key == nil && key.Group == "" {
 			switch key.Type {
 			case contextType:
-				if override := s.constructionContext.Load(); override != nil {
-					return override.context, nil
-				}
-				return s.context, nil
+				return s.resolvedContext(), nil
 			case providerType:
+				if flight := r.singletonFlight(); flight != nil {
+					return &singletonProvider{provider: s.rootProvider, flight: flight}, nil
+				}
 				return s.rootProvider, nil
 			case scopeType:
 				return s, nil
+			case resolveInfoType:
+				return r.info(s), nil
+			case scopeInfoType:
+				return s.info(), nil
+			case scopeWaitGroupType:
+				return &s.waitGroup, nil
+			case cleanupType:
+				return r.cleanup(s), nil
+			case buildInfoType:
+				return s.rootProvider.buildInfo.clone(), nil
 			}
 		}
 
 		descriptor = s.rootProvider.findDescriptor(key.Type, key.Key)
+		if descriptor == nil && key.Key == nil && key.Group == "" && isScopedAccessor(key.Type) {
+			return newScopedAccessor(key.Type, s.rootProvider), nil
+		}
+		if descriptor == nil && key.Key == nil && key.Group == "" && s.rootProvider.autoCollect && collectable(key.Type) {
+			return s.collect(r, key.Type)
+		}
+		if descriptor == nil && key.Group == "" {
+			if instance, ok, err := s.rootProvider.resolveFallback(key); ok {
+				return instance, err
+			}
+		}
 		if descriptor == nil {
 			return nil, &ResolutionError{
 				ServiceType: key.Type,
@@ -593,44 +1024,143 @@ func (s *scope) resolve(key instanceKey, descriptor *descriptor) (any, error) {
 				Cause:       ErrServiceNotFound,
 			}
 		}
+		if descriptor.flagged != nil {
+			key, descriptor = s.selectFlagged(key, descriptor)
+		}
+	}
+
+	if descriptor.auditReason != "" {
+		s.audit(r, key, descriptor)
+	}
+	if descriptor.deprecation != "" {
+		s.warnDeprecated(r, key, descriptor)
+	}
+
+	var instance any
+	var err error
+	if (descriptor.unobserved || (s.rootProvider.onResolved == nil && s.rootProvider.onResolveError == nil)) &&
+		s.rootProvider.tracing.Load() == 0 {
+		instance, _, err = s.resolveLifetime(r, key, descriptor)
+	} else {
+		instance, err = s.resolveObserved(r, key, descriptor)
+	}
+	if descriptor.cloner != nil && err == nil {
+		instance = s.cloneShared(key, descriptor, instance)
 	}
+	return instance, err
+}
 
-	// Check cache based on lifetime
+// resolveLifetime resolves a registered service using its lifetime's
+// caching strategy. cached reports whether the instance came from a cache.
+func (s *scope) resolveLifetime(r *resolution, key instanceKey, descriptor *descriptor) (instance any, cached bool, err error) {
 	switch descriptor.Lifetime {
 	case Singleton:
-		// Singletons are created at build time, no circular check needed
+		if descriptor.refresh != nil {
+			return s.resolveUnloadable(r, key, descriptor)
+		}
 		if instance, ok := s.rootProvider.getSingleton(key); ok {
-			return instance, nil
+			s.rootProvider.cacheHits.Add(1)
+			if _, absent := instance.(notProvided); absent {
+				return nil, true, errNotProvided(descriptor)
+			}
+			return instance, true, nil
 		}
 
-		// Singleton should have been created at build time
-		return nil, &ResolutionError{
-			ServiceType: key.Type,
-			ServiceKey:  key.Key,
-			Cause:       ErrSingletonNotInitialized,
+		// Build creates every singleton in dependency order, but a
+		// constructor may resolve one dynamically before Build reaches it.
+		if s.rootProvider.isDisposed() {
+			return nil, false, &ResolutionError{
+				ServiceType: key.Type,
+				ServiceKey:  key.Key,
+				Cause:       ErrSingletonNotInitialized,
+			}
 		}
+		instance, err = s.rootProvider.constructSingleton(r, key, descriptor)
+		if _, absent := instance.(notProvided); absent {
+			return nil, false, errNotProvided(descriptor)
+		}
+		return instance, false, err
 
 	case Scoped:
+		if s.inheritScoped {
+			return s.parentScope.resolveLifetime(r, key, descriptor)
+		}
+		// Refreshing services are shared by every scope, so the provider
+		// may resolve them too.
+		if s == s.rootProvider.rootScope && !s.rootProvider.allowScopedFromRoot && !s.initializing.Load() && descriptor.refresh == nil {
+			return nil, false, &ResolutionError{
+				ServiceType: key.Type,
+				ServiceKey:  key.Key,
+				Cause:       ErrScopedResolvedFromRoot,
+			}
+		}
+		if s.rootProvider.validateScopes {
+			if err := r.validateScoped(key, descriptor); err != nil {
+				return nil, false, err
+			}
+		}
 		if instance, ok := s.getInstance(key); ok {
-			return instance, nil
+			s.rootProvider.cacheHits.Add(1)
+			if _, absent := instance.(notProvided); absent {
+				return nil, true, errNotProvided(descriptor)
+			}
+			return instance, true, nil
 		}
-		return s.resolveScopedSingleFlight(key, descriptor)
+		instance, err = s.resolveScopedSingleFlight(r, key, descriptor)
+		return instance, false, err
 
 	case Transient:
+		if descriptor.memoize != nil {
+			return s.resolveMemoized(r, key, descriptor)
+		}
+		if r != nil && r.shareTransients {
+			return s.resolveShared(r, key, descriptor)
+		}
 		// Always create new instance
-		return s.createInstance(descriptor)
+		instance, err = s.createInstance(r, key, descriptor)
+		return instance, false, err
+
+	case PerResolution:
+		if r == nil {
+			// The top-level call: a frame of its own holds the instances
+			// shared by the dependencies constructed for it.
+			r = &resolution{scope: s}
+		}
+		return s.resolveShared(r, key, descriptor)
 
 	default:
-		return nil, &LifetimeError{
+		return nil, false, &LifetimeError{
 			Value: descriptor.Lifetime,
 		}
 	}
 }
 
+// notProvided is cached for a singleton or scoped result-object field tagged
+// optional:"true" that its constructor left nil.
+type notProvided struct{}
+
+// errNotProvided is the error resolving such a field returns.
+func errNotProvided(descriptor *descriptor) error {
+	return &ResolutionError{
+		ServiceType: descriptor.Type,
+		ServiceKey:  descriptor.Key,
+		Cause:       ErrServiceNotProvided,
+	}
+}
+
+// isNotProvided reports whether err is the not-provided error of descriptor
+// itself, as opposed to one of its dependencies.
+func isNotProvided(err error, descriptor *descriptor) bool {
+	resErr, ok := err.(*ResolutionError)
+	return ok && descriptor.optionalResult && resErr.Cause == ErrServiceNotProvided &&
+		resErr.ServiceType == descriptor.Type
+}
+
 // createInstance creates a new instance of a service using its constructor.
 // It handles regular constructors, result objects (Out structs), multi-return
-// constructors, and instance descriptors.
-func (s *scope) createInstance(descriptor *descriptor) (any, error) {
+// constructors, and instance descriptors. The constructor's dependencies are
+// resolved in a child frame of r for the requested key.
+func (s *scope) createInstance(r *resolution, requested instanceKey, descriptor *descriptor) (any, error) {
 	if descriptor == nil {
 		return nil, &ValidationError{
 			ServiceType: nil,
@@ -638,6 +1168,10 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 		}
 	}
 
+	if descriptor.refresh != nil {
+		return s.leaseRefreshing(r, requested, descriptor)
+	}
+
 	if descriptor.IsInstance {
 		instance := descriptor.Instance
 		if instance == nil {
@@ -647,6 +1181,11 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 			}
 		}
 
+		instance, err := s.decorate(r, requested, descriptor, instance)
+		if err != nil {
+			return nil, err
+		}
+
 		key := instanceKey{
 			Type:  descriptor.Type,
 			Key:   descriptor.Key,
@@ -657,43 +1196,9 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 		return instance, nil
 	}
 
-	// Read the pre-analyzed constructor info stashed on the descriptor at
-	// registration time. Falls back to a fresh Analyze for descriptors that
-	// were created outside the normal Add* path (e.g. constructed directly
-	// in tests).
-	info := descriptor.info
-	if info == nil {
-		var err error
-		info, err = s.rootProvider.analyzer.Analyze(descriptor.Constructor.Interface())
-		if err != nil {
-			return nil, &ReflectionAnalysisError{
-				Constructor: descriptor.Constructor.Interface(),
-				Operation:   "analyze",
-				Cause:       err,
-			}
-		}
-	}
-
-	// Get cached invoker (reduces allocations)
-	invoker := s.rootProvider.analyzer.GetInvoker()
-
-	// Invoke constructor
-	results, err := invoker.Invoke(info, s)
+	info, results, err := s.invokeConstructor(r, requested, descriptor)
 	if err != nil {
-		// Check if it's a panic error and wrap appropriately
-		if panicErr, ok := errors.AsType[*reflection.PanicError](err); ok {
-			return nil, &ConstructorPanicError{
-				Constructor: descriptor.ConstructorType,
-				Panic:       panicErr.Panic,
-				Stack:       panicErr.Stack,
-			}
-		}
-
-		return nil, &ConstructorInvocationError{
-			Constructor: descriptor.ConstructorType,
-			Parameters:  extractParameterTypes(info),
-			Cause:       err,
-		}
+		return nil, err
 	}
 
 	if descriptor.VoidReturn {
@@ -735,10 +1240,25 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 			}
 		}
 
+		// Decorate every field before caching any of them, so a failing
+		// decorator leaves nothing half-cached.
+		values := make([]any, len(registrations))
+		for i, reg := range registrations {
+			values[i] = reg.Value
+			if regDescriptor := descriptor.siblingForField(reg.Index); regDescriptor != nil {
+				values[i], err = s.decorate(r, requested, regDescriptor, reg.Value)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		// Find the primary service to return
 		var primaryService any
-		for _, reg := range registrations {
-			value := reg.Value
+		provided := make(map[int]bool, len(registrations))
+		for i, reg := range registrations {
+			provided[reg.Index] = true
+			value := values[i]
 
 			// Each field's registered descriptor is a sibling of the one
 			// being resolved, matched by field index. This works for keyed
@@ -755,9 +1275,16 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 				if len(descriptor.siblings) > 0 {
 					// Sibling-linked registration with no sibling for this
 					// field: the field's registration was removed from the
-					// collection. Skip it rather than falling back to the
-					// registry, which could find (and wrongly shadow) a
-					// replacement registration of the same type.
+					// collection or belongs to an inactive profile. Skip it
+					// rather than falling back to the registry, which could
+					// find (and wrongly shadow) a replacement registration
+					// of the same type. The value was still constructed, so
+					// it is closed along with the fields that are cached.
+					if descriptor.Lifetime == Singleton {
+						s.rootProvider.trackDisposable(descriptor, value)
+					} else {
+						s.appendDisposable(descriptor, value)
+					}
 					continue
 				}
 				// Fallback for descriptors constructed outside the normal
@@ -786,7 +1313,21 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 			s.setInstance(regDescriptor, key, value)
 		}
 
+		// Remember the optional fields the constructor left nil, so resolving
+		// them later reports them as not provided instead of invoking the
+		// constructor again.
+		for _, sibling := range descriptor.siblings {
+			if sibling.optionalResult && !provided[sibling.resultFieldIndex] &&
+				(sibling.Lifetime == Singleton || sibling.Lifetime == Scoped) {
+				key := instanceKey{Type: sibling.Type, Key: sibling.Key, Group: sibling.Group}
+				s.setInstance(sibling, key, notProvided{})
+			}
+		}
+
 		if primaryService == nil {
+			if descriptor.optionalResult {
+				return nil, errNotProvided(descriptor)
+			}
 			return nil, &ValidationError{
 				ServiceType: descriptor.Type,
 				Cause:       fmt.Errorf("result object produced no services"),
@@ -798,17 +1339,29 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 
 	// Handle multi-return constructors
 	if descriptor.MultiReturnIndex >= 0 {
+		primary := results[descriptor.MultiReturnIndex].Interface()
 		if len(descriptor.siblings) > 0 {
+			// Decorate every return value before caching any of them.
+			values := make([]any, len(descriptor.siblings))
+			for i, sibling := range descriptor.siblings {
+				values[i], err = s.decorate(r, requested, sibling, results[sibling.MultiReturnIndex].Interface())
+				if err != nil {
+					return nil, err
+				}
+				if sibling == descriptor {
+					primary = values[i]
+				}
+			}
+
 			// Cache every return value under its sibling's registration
 			// (which carries the actual key or group assigned at Add time).
-			for _, sibling := range descriptor.siblings {
-				value := results[sibling.MultiReturnIndex].Interface()
+			for i, sibling := range descriptor.siblings {
 				key := instanceKey{
 					Type:  sibling.Type,
 					Key:   sibling.Key,
 					Group: sibling.Group,
 				}
-				s.setInstance(sibling, key, value)
+				s.setInstance(sibling, key, values[i])
 			}
 		} else {
 			// Fallback for descriptors constructed outside the normal Add*
@@ -839,7 +1392,7 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 			}
 		}
 
-		return results[descriptor.MultiReturnIndex].Interface(), nil
+		return primary, nil
 	}
 
 	instance := results[0].Interface()
@@ -850,6 +1403,11 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 		}
 	}
 
+	instance, err = s.decorate(r, requested, descriptor, instance)
+	if err != nil {
+		return nil, err
+	}
+
 	key := instanceKey{
 		Type:  descriptor.Type,
 		Key:   descriptor.Key,
@@ -860,6 +1418,113 @@ func (s *scope) createInstance(descriptor *descriptor) (any, error) {
 	return instance, nil
 }
 
+// invokeConstructor runs descriptor's constructor without caching anything,
+// resolving its dependencies from s in a child frame of r.
+func (s *scope) invokeConstructor(r *resolution, requested instanceKey, descriptor *descriptor) (*reflection.ConstructorInfo, []reflect.Value, error) {
+	// Read the pre-analyzed constructor info stashed on the descriptor at
+	// registration time. Falls back to a fresh Analyze for descriptors that
+	// were created outside the normal Add* path (e.g. constructed directly
+	// in tests).
+	info := descriptor.info
+	if info == nil {
+		var err error
+		info, err = s.rootProvider.analyzer.Analyze(descriptor.Constructor.Interface())
+		if err != nil {
+			return nil, nil, &ReflectionAnalysisError{
+				Constructor: descriptor.Constructor.Interface(),
+				Operation:   "analyze",
+				Cause:       err,
+			}
+		}
+	}
+
+	// Stop descending once the resolution context is done; everything below
+	// this constructor would be built only to be discarded.
+	if err := s.resolvedContext().Err(); err != nil {
+		return nil, nil, &ContextCancelledError{
+			ServiceType: requested.Type,
+			ServiceKey:  requested.Key,
+			Path:        append(r.path(), newResolutionFrame(requested, descriptor)),
+			Cause:       err,
+		}
+	}
+
+	if err := s.checkInstanceLimit(r, requested, descriptor); err != nil {
+		return nil, nil, err
+	}
+
+	circuit := s.rootProvider.circuit(descriptor)
+	var probe bool
+	if circuit != nil {
+		var open *CircuitOpenError
+		if probe, open = circuit.acquire(); open != nil {
+			open.ServiceType = requested.Type
+			open.ServiceKey = requested.Key
+			open.Path = append(r.path(), newResolutionFrame(requested, descriptor))
+			return nil, nil, open
+		}
+	}
+
+	// Get cached invoker (reduces allocations)
+	invoker := s.rootProvider.analyzer.GetInvoker()
+
+	// Invoke constructor
+	var start time.Time
+	if s.rootProvider.onConstructed != nil {
+		start = time.Now()
+	}
+	frame := r.child(s, requested, descriptor)
+	var results []reflect.Value
+	var err error
+	if s.rootProvider.profileLabels {
+		s.withProfileLabels(requested, descriptor, func() { results, err = invoker.Invoke(info, frame) })
+	} else {
+		results, err = invoker.Invoke(info, frame)
+	}
+	if frame.cleanups != nil {
+		frame.cleanups.settle(err != nil)
+	}
+	frame.release()
+	if circuit != nil {
+		circuit.release(probe, err)
+	}
+	s.rootProvider.constructions.Add(1)
+	if err == nil && s.rootProvider.onConstructed != nil {
+		s.rootProvider.onConstructed(descriptor.serviceInfo(), time.Since(start))
+	}
+	if err != nil {
+		// A cancelled dependency, one over its scope's instance limit, or one
+		// behind an open circuit aborts the whole construction; report it as
+		// is rather than as a failure of every constructor above it.
+		if cancelled, ok := errors.AsType[*ContextCancelledError](err); ok {
+			return nil, nil, cancelled
+		}
+		if limited, ok := errors.AsType[*InstanceLimitError](err); ok {
+			return nil, nil, limited
+		}
+		if open, ok := errors.AsType[*CircuitOpenError](err); ok {
+			return nil, nil, open
+		}
+
+		// Check if it's a panic error and wrap appropriately
+		if panicErr, ok := errors.AsType[*reflection.PanicError](err); ok {
+			return nil, nil, &ConstructorPanicError{
+				Constructor: descriptor.ConstructorType,
+				Panic:       panicErr.Panic,
+				Stack:       panicErr.Stack,
+			}
+		}
+
+		return nil, nil, &ConstructorInvocationError{
+			Constructor: descriptor.ConstructorType,
+			Parameters:  extractParameterTypes(info),
+			Cause:       err,
+		}
+	}
+
+	return info, results, nil
+}
+
 func isNilServiceResult(value reflect.Value) bool {
 	if !value.IsValid() {
 		return true
@@ -897,10 +1562,10 @@ func validateServiceResults(info *reflection.ConstructorInfo, results []reflect.
 }
 
 // setAliasedInstance stores one produced value under every interface alias for
-// cacheable lifetimes. Transients deliberately store only the requested alias:
-// each resolution is a distinct constructor invocation.
+// cacheable lifetimes. Transient and PerResolution services deliberately
+// store only the requested alias: they are not cached in the scope.
 func (s *scope) setAliasedInstance(descriptor *descriptor, key instanceKey, instance any) {
-	if !descriptor.isAlias || descriptor.Lifetime == Transient || len(descriptor.siblings) == 0 {
+	if !descriptor.isAlias || descriptor.Lifetime == Transient || descriptor.Lifetime == PerResolution || len(descriptor.siblings) == 0 {
 		s.setInstance(descriptor, key, instance)
 		return
 	}
@@ -911,20 +1576,22 @@ func (s *scope) setAliasedInstance(descriptor *descriptor, key instanceKey, inst
 			key := instanceKey{Type: alias.Type, Key: alias.Key, Group: alias.Group}
 			s.rootProvider.cacheSingleton(key, instance)
 		}
-		s.rootProvider.trackDisposable(instance)
+		s.rootProvider.trackDisposable(descriptor, instance)
 	case Scoped:
 		s.instancesMu.Lock()
 		if s.instances == nil {
 			s.instancesMu.Unlock()
-			closeOrphan(instance)
+			if d, ok := disposableFor(descriptor, instance); ok {
+				closeOrphan(d)
+			}
 			return
 		}
 		for _, alias := range descriptor.siblings {
 			key := instanceKey{Type: alias.Type, Key: alias.Key, Group: alias.Group}
-			s.instances[key] = instance
+			s.storeInstanceLocked(alias, key, instance)
 		}
 		s.instancesMu.Unlock()
-		s.appendDisposable(instance)
+		s.appendDisposable(descriptor, instance)
 	}
 }
 
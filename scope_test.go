@@ -123,6 +123,36 @@ func TestScopeDisposal(t *testing.T) {
 		assert.True(t, d.IsClosed())
 	})
 
+	t.Run("group_resolutions_track_each_member_once", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTDisposable, Group("handlers")),
+			AddScoped(NewTDisposable, Group("handlers")),
+		)
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		for range 100 {
+			members, err := ResolveGroup[*TDisposable](s, "handlers")
+			require.NoError(t, err)
+			require.Len(t, members, 2)
+		}
+
+		impl := s.(*scope)
+		impl.disposablesMu.Lock()
+		assert.Len(t, impl.disposables, 1, "only the scoped member belongs to the scope")
+		impl.disposablesMu.Unlock()
+
+		root := p.(*provider)
+		root.disposablesMu.Lock()
+		assert.Len(t, root.disposables, 1, "the singleton member is tracked once")
+		root.disposablesMu.Unlock()
+
+		// TDisposable fails a second Close, so no errors means one each.
+		require.NoError(t, s.Close())
+		require.NoError(t, p.Close())
+	})
+
 	t.Run("provider_closes_active_scopes", func(t *testing.T) {
 		t.Parallel()
 		c := NewCollection()
@@ -290,7 +320,11 @@ func TestBuiltinServiceInjection(t *testing.T) {
 		defer p.Close()
 
 		svc, _ := Resolve[*ProvSvc](p)
-		assert.Same(t, p, svc.Prov)
+		// Singletons get a view of p that remembers their construction.
+		assert.Equal(t, p.ID(), svc.Prov.ID())
+		same, err := Resolve[*ProvSvc](svc.Prov)
+		require.NoError(t, err)
+		assert.Same(t, svc, same)
 	})
 }
 
@@ -982,6 +1016,33 @@ func TestScopeInitFailureCleansUpPartialState(t *testing.T) {
 	}
 }
 
+func TestScopeInitPanicReturnsError(t *testing.T) {
+	t.Parallel()
+
+	initCalls := 0
+	c := NewCollection()
+	c.AddScoped(func() {
+		initCalls++
+		if initCalls > 1 {
+			panic("init panic")
+		}
+	})
+
+	p, err := c.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	var s Scope
+	require.NotPanics(t, func() {
+		s, err = p.CreateScope(t.Context())
+	})
+	require.Error(t, err)
+	assert.Nil(t, s)
+
+	_, ok := errors.AsType[*ConstructorPanicError](err)
+	assert.True(t, ok, "initializer panic should surface as ConstructorPanicError, got %v", err)
+}
+
 func TestNewScopeFailureCancelsDerivedContext(t *testing.T) {
 	t.Parallel()
 
@@ -1001,7 +1062,7 @@ func TestNewScopeFailureCancelsDerivedContext(t *testing.T) {
 	p := pAny.(*provider)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	s, err := newScope(p, nil, ctx, cancel)
+	s, err := newScope(p, nil, ctx, cancel, scopeOptions{})
 	require.Error(t, err)
 	require.Nil(t, s)
 
@@ -1083,3 +1144,216 @@ func TestScopeCancellationCleanup(t *testing.T) {
 		assert.Nil(t, s)
 	})
 }
+
+func TestInheritScoped(t *testing.T) {
+	t.Parallel()
+
+	t.Run("child_shares_parent_scoped_instances", func(t *testing.T) {
+		t.Parallel()
+		var initialized atomic.Int32
+		p := BuildProvider(t,
+			AddScoped(NewTService),
+			AddScoped(func() *TDisposable { return &TDisposable{Name: "tx"} }),
+			AddTransient(func() *TDependency { return &TDependency{Name: "transient"} }),
+			AddScoped(func() { initialized.Add(1) }),
+		)
+		parent := NewTestScope(t, p)
+		before := initialized.Load()
+
+		child, err := CreateScope(parent.Context(), parent, InheritScoped())
+		require.NoError(t, err)
+		grandchild, err := CreateScope(child.Context(), child, InheritScoped())
+		require.NoError(t, err)
+		assert.Equal(t, before, initialized.Load(), "inheriting scopes skip scoped initializers")
+		isolated, err := parent.CreateScope(parent.Context())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = isolated.Close() })
+
+		fromChild := RequireResolveFrom[*TService](t, child)
+		assert.Same(t, RequireResolveFrom[*TService](t, parent), fromChild)
+		assert.Same(t, fromChild, RequireResolveFrom[*TService](t, grandchild))
+		assert.NotSame(t, fromChild, RequireResolveFrom[*TService](t, isolated))
+		assert.NotSame(t, RequireResolveFrom[*TDependency](t, child), RequireResolveFrom[*TDependency](t, parent),
+			"transients are still created per resolution")
+
+		tx := RequireResolveFrom[*TDisposable](t, child)
+		require.NoError(t, child.Close())
+		assert.False(t, tx.IsClosed(), "the parent owns inherited instances")
+		require.NoError(t, parent.Close())
+		assert.True(t, tx.IsClosed())
+	})
+
+	t.Run("child_disposes_its_own_transients", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddTransient(NewTDisposable))
+		parent := NewTestScope(t, p)
+
+		child, err := CreateScope(parent.Context(), parent, InheritScoped())
+		require.NoError(t, err)
+		disposable := RequireResolveFrom[*TDisposable](t, child)
+		require.NoError(t, child.Close())
+		assert.True(t, disposable.IsClosed())
+	})
+
+	t.Run("ignored_for_root_scopes", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTService))
+
+		first, err := CreateScope(context.Background(), p, InheritScoped())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = first.Close() })
+		second, err := CreateScope(context.Background(), p, InheritScoped())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = second.Close() })
+
+		assert.NotSame(t, RequireResolveFrom[*TService](t, first), RequireResolveFrom[*TService](t, second))
+	})
+}
+
+type scopeOrchestrator struct {
+	steps []*TDependency
+}
+
+// Constructors may create and close scopes while they run, e.g. to run each
+// step of a job in its own scope. No lock is held across constructor calls,
+// so this must neither deadlock nor disturb the scope being resolved.
+func TestCreateScopeFromConstructor(t *testing.T) {
+	t.Parallel()
+
+	runSteps := func(create func() (Scope, error)) (*scopeOrchestrator, error) {
+		o := &scopeOrchestrator{}
+		for range 2 {
+			step, err := create()
+			if err != nil {
+				return nil, err
+			}
+			dep, err := Resolve[*TDependency](step)
+			if closeErr := step.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, err
+			}
+			o.steps = append(o.steps, dep)
+		}
+		return o, nil
+	}
+
+	withTimeout := func(t *testing.T, f func()) {
+		t.Helper()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			f()
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("CreateScope from a constructor deadlocked")
+		}
+	}
+
+	t.Run("singleton_during_build", func(t *testing.T) {
+		t.Parallel()
+		withTimeout(t, func() {
+			p := BuildProvider(t,
+				AddScoped(NewTDependency),
+				AddSingleton(func(p Provider) (*scopeOrchestrator, error) {
+					return runSteps(func() (Scope, error) { return p.CreateScope(context.Background()) })
+				}),
+			)
+			o := RequireResolve[*scopeOrchestrator](t, p)
+			require.Len(t, o.steps, 2)
+			assert.NotSame(t, o.steps[0], o.steps[1])
+		})
+	})
+
+	t.Run("scoped_child_scopes", func(t *testing.T) {
+		t.Parallel()
+		withTimeout(t, func() {
+			s := BuildScope(t,
+				AddScoped(NewTDependency),
+				AddScoped(func(s Scope) (*scopeOrchestrator, error) {
+					return runSteps(func() (Scope, error) { return s.CreateScope(s.Context()) })
+				}),
+			)
+			o := RequireResolve[*scopeOrchestrator](t, s)
+			require.Len(t, o.steps, 2)
+			assert.NotSame(t, o.steps[0], o.steps[1])
+			assert.NotSame(t, RequireResolve[*TDependency](t, s), o.steps[0])
+		})
+	})
+
+	t.Run("inherited_scoped_dependencies", func(t *testing.T) {
+		t.Parallel()
+		withTimeout(t, func() {
+			s := BuildScope(t,
+				AddScoped(NewTDependency),
+				AddScoped(func(s Scope, _ *TDependency) (*scopeOrchestrator, error) {
+					return runSteps(func() (Scope, error) { return CreateScope(s.Context(), s, InheritScoped()) })
+				}),
+			)
+			o := RequireResolve[*scopeOrchestrator](t, s)
+			assert.Same(t, RequireResolve[*TDependency](t, s), o.steps[0])
+		})
+	})
+
+	t.Run("concurrent_resolutions", func(t *testing.T) {
+		t.Parallel()
+		withTimeout(t, func() {
+			p := BuildProvider(t,
+				AddScoped(NewTDependency),
+				AddScoped(func(s Scope) (*scopeOrchestrator, error) {
+					return runSteps(func() (Scope, error) { return s.CreateScope(s.Context()) })
+				}),
+			)
+			var wg sync.WaitGroup
+			for range 16 {
+				wg.Go(func() {
+					s, err := p.CreateScope(context.Background())
+					if !assert.NoError(t, err) {
+						return
+					}
+					defer s.Close()
+					_, err = Resolve[*scopeOrchestrator](s)
+					assert.NoError(t, err)
+				})
+			}
+			wg.Wait()
+		})
+	})
+}
+
+func TestCreateScopeWithOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sealed_provider", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t))
+		require.NoError(t, err)
+
+		s, err := CreateScope(t.Context(), sealed, WithScopeID("sealed-req"))
+		require.NoError(t, err)
+		defer s.Close()
+		assert.Equal(t, "sealed-req", s.ID())
+	})
+
+	t.Run("other_implementations_only_without_options", func(t *testing.T) {
+		t.Parallel()
+		wrapped := struct{ Provider }{BuildProvider(t)}
+
+		s, err := CreateScope(t.Context(), wrapped)
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+
+		_, err = CreateScope(t.Context(), wrapped, WithScopeName("job"))
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := CreateScope(t.Context(), nil)
+		require.ErrorIs(t, err, ErrProviderNil)
+	})
+}
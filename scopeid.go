@@ -0,0 +1,94 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrScopeIDInUse is the cause reported by CreateScope when the ID given
+// to WithScopeID belongs to another open scope of the provider.
+var ErrScopeIDInUse = errors.New("scope ID already in use")
+
+// WithScopeID is a ScopeOption that sets the ID of the new scope, as
+// returned by Scope.ID and shown in diagnostics, instead of a generated
+// one. It lets logs correlate scopes with a request or trace ID.
+//
+// IDs are unique among the open scopes of a provider: CreateScope fails
+// with an error wrapping ErrScopeIDInUse when another open scope has the
+// ID. The ID is free again once that scope closes.
+//
+// Example:
+//
+//	scope, err := godi.CreateScope(ctx, provider, godi.WithScopeID("req-"+traceID))
+func WithScopeID(id string) ScopeOption {
+	return scopeIDOption(id)
+}
+
+type scopeIDOption string
+
+func (o scopeIDOption) String() string {
+	return fmt.Sprintf("WithScopeID(%q)", string(o))
+}
+
+func (o scopeIDOption) applyScopeOption(opts *scopeOptions) {
+	opts.id = string(o)
+	opts.hasID = true
+}
+
+// nextScopeID generates the ID of a new scope, skipping IDs given to open
+// scopes with WithScopeID.
+func (p *provider) nextScopeID() string {
+	for {
+		id := "s" + strconv.FormatUint(p.scopeCounter.Add(1), 36)
+		if p.customScopeIDs.Load() == 0 {
+			return id
+		}
+		p.scopesMu.Lock()
+		_, taken := p.scopeIDs[id]
+		p.scopesMu.Unlock()
+		if !taken {
+			return id
+		}
+	}
+}
+
+// reserveScopeID claims id for a scope created with WithScopeID. IDs that
+// nextScopeID has already generated are refused, whether or not their
+// scope is still open.
+func (p *provider) reserveScopeID(id string) error {
+	if id == "" {
+		return &ValidationError{Cause: errors.New("scope ID cannot be empty")}
+	}
+
+	p.scopesMu.Lock()
+	defer p.scopesMu.Unlock()
+	if _, taken := p.scopeIDs[id]; taken {
+		return &ValidationError{Cause: fmt.Errorf("%w: %q", ErrScopeIDInUse, id)}
+	}
+
+	// Announce the reservation before checking the counter, so that
+	// nextScopeID either sees it or has already advanced the counter.
+	p.customScopeIDs.Add(1)
+	if len(id) > 1 && id[0] == 's' {
+		if n, err := strconv.ParseUint(id[1:], 36, 64); err == nil && n <= p.scopeCounter.Load() {
+			p.customScopeIDs.Add(-1)
+			return &ValidationError{Cause: fmt.Errorf("%w: %q", ErrScopeIDInUse, id)}
+		}
+	}
+
+	if p.scopeIDs == nil {
+		p.scopeIDs = make(map[string]struct{})
+	}
+	p.scopeIDs[id] = struct{}{}
+	return nil
+}
+
+// releaseScopeIDLocked frees the ID of a closed scope created with WithScopeID.
+// The caller holds scopesMu.
+func (p *provider) releaseScopeIDLocked(id string) {
+	if _, ok := p.scopeIDs[id]; ok {
+		delete(p.scopeIDs, id)
+		p.customScopeIDs.Add(-1)
+	}
+}
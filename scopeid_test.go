@@ -0,0 +1,113 @@
+package godi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithScopeID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets_the_id", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+
+		s, err := CreateScope(context.Background(), p, WithScopeID("req-abc"))
+		require.NoError(t, err)
+		defer s.Close()
+		assert.Equal(t, "req-abc", s.ID())
+
+		child, err := CreateScope(context.Background(), s, WithScopeID("req-abc/step-1"))
+		require.NoError(t, err)
+		defer child.Close()
+		assert.Equal(t, "req-abc/step-1", child.ID())
+		assert.Contains(t, child.(*scope).String(), "child of req-abc")
+	})
+
+	t.Run("rejects_ids_of_open_scopes", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+
+		s, err := CreateScope(context.Background(), p, WithScopeID("req-1"))
+		require.NoError(t, err)
+
+		_, err = CreateScope(context.Background(), p, WithScopeID("req-1"))
+		require.ErrorIs(t, err, ErrScopeIDInUse)
+		_, err = CreateScope(context.Background(), s, WithScopeID("req-1"))
+		require.ErrorIs(t, err, ErrScopeIDInUse)
+
+		require.NoError(t, s.Close())
+		again, err := CreateScope(context.Background(), p, WithScopeID("req-1"))
+		require.NoError(t, err, "the ID is free once its scope closes")
+		require.NoError(t, again.Close())
+	})
+
+	t.Run("rejects_generated_ids", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+		generated := NewTestScope(t, p)
+
+		_, err := CreateScope(context.Background(), p, WithScopeID(generated.ID()))
+		assert.ErrorIs(t, err, ErrScopeIDInUse)
+	})
+
+	t.Run("generated_ids_skip_reserved_ones", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+		last := NewTestScope(t, p).ID()
+
+		// Reserve the next two IDs the provider would generate.
+		n := p.(*provider).scopeCounter.Load()
+		for i := uint64(1); i <= 2; i++ {
+			reserved, err := CreateScope(context.Background(), p, WithScopeID("s"+strconv.FormatUint(n+i, 36)))
+			require.NoError(t, err)
+			defer reserved.Close()
+		}
+
+		next := NewTestScope(t, p)
+		assert.NotEqual(t, last, next.ID())
+		assert.NotEqual(t, "s"+strconv.FormatUint(n+1, 36), next.ID())
+		assert.NotEqual(t, "s"+strconv.FormatUint(n+2, 36), next.ID())
+	})
+
+	t.Run("rejects_empty_id", func(t *testing.T) {
+		t.Parallel()
+		_, err := CreateScope(context.Background(), BuildProvider(t), WithScopeID(""))
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("concurrent_creation_is_unique", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+
+		var mu sync.Mutex
+		ids := make(map[string]int)
+		var wg sync.WaitGroup
+		for i := range 64 {
+			wg.Go(func() {
+				var opts []ScopeOption
+				if i%2 == 0 {
+					opts = append(opts, WithScopeID("s"+strconv.Itoa(64+i)))
+				}
+				s, err := CreateScope(context.Background(), p, opts...)
+				if err != nil {
+					assert.ErrorIs(t, err, ErrScopeIDInUse)
+					return
+				}
+				mu.Lock()
+				ids[s.ID()]++
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+		for id, n := range ids {
+			assert.Equal(t, 1, n, "scope ID %s used more than once", id)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package godi
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScopeInfo describes the scope resolving a service. Constructors receive
+// it by taking godi.ScopeInfo as a parameter, e.g. to tag logs with the
+// request's scope without depending on the whole Scope; it cannot be
+// registered. Singletons are built in the provider's root scope and
+// receive its info.
+type ScopeInfo struct {
+	// ID is the scope's ID, as returned by Scope.ID.
+	ID string
+
+	// ParentID is the ID of the scope the scope was created from, or empty
+	// for scopes created from the provider.
+	ParentID string
+
+	// Name is the name given with WithScopeName, or empty.
+	Name string
+
+	// Created is when the scope was created.
+	Created time.Time
+}
+
+// WithScopeName is a ScopeOption that names the new scope, e.g. after the
+// route or job it serves. Unlike IDs, names need not be unique. The name
+// is reported in ScopeInfo.
+func WithScopeName(name string) ScopeOption {
+	return scopeNameOption(name)
+}
+
+type scopeNameOption string
+
+func (o scopeNameOption) String() string {
+	return fmt.Sprintf("WithScopeName(%q)", string(o))
+}
+
+func (o scopeNameOption) applyScopeOption(opts *scopeOptions) {
+	opts.name = string(o)
+}
+
+// info returns the ScopeInfo of s.
+func (s *scope) info() ScopeInfo {
+	info := ScopeInfo{ID: s.id, Name: s.name, Created: s.created}
+	if s.parentScope != nil {
+		info.ParentID = s.parentScope.id
+	}
+	return info
+}
@@ -0,0 +1,65 @@
+package godi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scopeTagger struct{ info ScopeInfo }
+
+func TestScopeInfo(t *testing.T) {
+	t.Parallel()
+
+	tagger := AddScoped(func(info ScopeInfo) *scopeTagger { return &scopeTagger{info: info} })
+
+	t.Run("describes_the_resolving_scope", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, tagger)
+
+		before := time.Now()
+		parent, err := CreateScope(context.Background(), p, WithScopeID("req-1"), WithScopeName("GET /users"))
+		require.NoError(t, err)
+		defer parent.Close()
+		child, err := CreateScope(context.Background(), parent, WithScopeName("step"))
+		require.NoError(t, err)
+		defer child.Close()
+
+		info := RequireResolve[*scopeTagger](t, parent).info
+		assert.Equal(t, "req-1", info.ID)
+		assert.Equal(t, "GET /users", info.Name)
+		assert.Empty(t, info.ParentID)
+		assert.False(t, info.Created.Before(before))
+
+		info = RequireResolve[*scopeTagger](t, child).info
+		assert.Equal(t, child.ID(), info.ID)
+		assert.Equal(t, "req-1", info.ParentID)
+		assert.Equal(t, "step", info.Name)
+	})
+
+	t.Run("inherited_scoped_services_see_their_owner", func(t *testing.T) {
+		t.Parallel()
+		parent := BuildScope(t, tagger)
+		child, err := CreateScope(context.Background(), parent, InheritScoped())
+		require.NoError(t, err)
+		defer child.Close()
+
+		assert.Equal(t, parent.ID(), RequireResolve[*scopeTagger](t, child).info.ID)
+	})
+
+	t.Run("resolvable_directly", func(t *testing.T) {
+		t.Parallel()
+		s := BuildScope(t)
+		assert.Equal(t, s.ID(), RequireResolve[ScopeInfo](t, s).ID)
+	})
+
+	t.Run("cannot_be_registered", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() ScopeInfo { return ScopeInfo{} })
+		assert.ErrorContains(t, c.Err(), "reserved")
+	})
+}
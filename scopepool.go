@@ -0,0 +1,307 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Resettable is implemented by scoped services that can serve more than
+// one scope taken from GetPooledScope. When such a scope closes,
+// Reset is called instead of Close, and the instance is handed to the next
+// pooled scope in place of a new one. Reset must clear all per-request
+// state; an instance whose Reset fails is closed as usual.
+type Resettable interface {
+	Reset() error
+}
+
+// defaultScopePoolSize is the number of idle pooled scopes kept when
+// ProviderOptions.ScopePoolSize is zero.
+const defaultScopePoolSize = 64
+
+// scopeState is what a closed pooled scope leaves for the next one: its
+// instance maps, cleared of everything but the instances it retained, and
+// the disposables of those instances.
+type scopeState struct {
+	instances     map[instanceKey]any
+	records       map[instanceKey]instanceRecord
+	disposables   []trackedDisposable
+	disposableSet map[disposableIdentity]struct{}
+}
+
+// GetPooledScope returns a scope of p like CreateScope, but reuses what a
+// closed scope from the same pool left behind: its maps, and its scoped
+// instances that implement Resettable. It suits hot request paths where
+// creating a scope shows up in profiles.
+//
+// Closing the scope returns it to the pool. A scoped instance is reset and
+// retained for the next scope only if everything it holds is a singleton or
+// another retained instance, so no state of the closed scope, such as its
+// context, its transients, or scoped services that cannot be reset, leaks
+// into the next one; every other instance is closed as usual. The returned
+// Scope is never reused: once closed, it stays closed.
+//
+// ProviderOptions.ScopePoolSize bounds the idle scopes kept. Closing the
+// provider closes their instances. Given a scope, GetPooledScope takes from
+// the pool of the provider the scope belongs to; the pooled scope is not a
+// child of it.
+//
+// Example:
+//
+//	scope, err := godi.GetPooledScope(r.Context(), provider)
+//	if err != nil {
+//	    return err
+//	}
+//	defer scope.Close()
+func GetPooledScope(ctx context.Context, p Provider) (Scope, error) {
+	switch v := p.(type) {
+	case nil:
+		return nil, ErrProviderNil
+	case pooledScopeCreator:
+		return v.GetPooledScope(ctx)
+	default:
+		return nil, errUnsupportedProvider(p)
+	}
+}
+
+// pooledScopeCreator is implemented by the providers and scopes of this
+// package.
+type pooledScopeCreator interface {
+	GetPooledScope(ctx context.Context) (Scope, error)
+}
+
+// GetPooledScope returns a pooled scope; see godi.GetPooledScope.
+func (p *provider) GetPooledScope(ctx context.Context) (Scope, error) {
+	return p.createScope(ctx, scopeOptions{pooled: true})
+}
+
+// GetPooledScope returns a scope from the provider's pool; see
+// godi.GetPooledScope.
+func (s *scope) GetPooledScope(ctx context.Context) (Scope, error) {
+	return s.rootProvider.GetPooledScope(ctx)
+}
+
+// takeScopeState returns the state of an idle pooled scope, or nil.
+func (p *provider) takeScopeState() *scopeState {
+	p.scopePoolMu.Lock()
+	defer p.scopePoolMu.Unlock()
+	n := len(p.scopePool)
+	if n == 0 {
+		return nil
+	}
+	state := p.scopePool[n-1]
+	p.scopePool[n-1] = nil
+	p.scopePool = p.scopePool[:n-1]
+	return state
+}
+
+// putScopeState returns state to the pool. It reports false, leaving the
+// state to the caller, when the pool is full or the provider is closed.
+func (p *provider) putScopeState(state *scopeState) bool {
+	p.scopePoolMu.Lock()
+	defer p.scopePoolMu.Unlock()
+	if p.disposed.Load() != 0 || len(p.scopePool) >= p.scopePoolSize {
+		return false
+	}
+	p.scopePool = append(p.scopePool, state)
+	return true
+}
+
+// drainScopePool empties the pool of a closing provider and returns the
+// disposables of the retained instances.
+func (p *provider) drainScopePool() []trackedDisposable {
+	p.scopePoolMu.Lock()
+	pool := p.scopePool
+	p.scopePool = nil
+	p.scopePoolMu.Unlock()
+
+	var disposables []trackedDisposable
+	for _, state := range pool {
+		disposables = append(disposables, state.disposables...)
+	}
+	return disposables
+}
+
+// retainForPool resets the instances of a closing pooled scope that can
+// serve the next scope from the pool and returns their keys. An instance is
+// retained if it is Resettable, its Reset succeeds, and all it was built
+// from is singletons or other retained instances. The instances of one
+// constructor call are retained together or not at all.
+func (s *scope) retainForPool() (map[instanceKey]bool, []error) {
+	s.instancesMu.RLock()
+	instances := make(map[instanceKey]any, len(s.instances))
+	descriptors := make(map[instanceKey]*descriptor, len(s.instances))
+	for key, instance := range s.instances {
+		instances[key] = instance
+		descriptors[key] = s.records[key].descriptor
+	}
+	s.instancesMu.RUnlock()
+
+	retained := make(map[instanceKey]bool)
+	for key, instance := range instances {
+		d := descriptors[key]
+		if _, ok := instance.(Resettable); ok && d != nil && retainable(d) {
+			retained[key] = true
+		}
+	}
+
+	// drop removes key and the instances built along with it.
+	drop := func(key instanceKey) {
+		delete(retained, key)
+		for _, sibling := range descriptors[key].siblings {
+			delete(retained, instanceKey{Type: sibling.Type, Key: sibling.Key, Group: sibling.Group})
+		}
+	}
+	for key := range retained {
+		for _, sibling := range descriptors[key].siblings {
+			siblingKey := instanceKey{Type: sibling.Type, Key: sibling.Key, Group: sibling.Group}
+			if _, cached := instances[siblingKey]; cached && !retained[siblingKey] {
+				drop(key)
+				break
+			}
+		}
+	}
+
+	var errs []error
+	reset := make(map[instanceKey]bool)
+	for {
+		for changed := true; changed; {
+			changed = false
+			for key := range retained {
+				if !s.holdsOnlyRetained(descriptors[key], retained) {
+					drop(key)
+					changed = true
+				}
+			}
+		}
+
+		failed := false
+		for key := range retained {
+			if reset[key] {
+				continue
+			}
+			reset[key] = true
+			if err := safeReset(instances[key].(Resettable)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to reset scoped instance: %w", err))
+				drop(key)
+				failed = true
+			}
+		}
+		if !failed {
+			return retained, errs
+		}
+	}
+}
+
+// retainable reports whether an instance of d can outlive its scope in the
+// pool at all, whatever its dependencies.
+func retainable(d *descriptor) bool {
+	return d.Lifetime == Scoped && d.Group == "" && d.refresh == nil && d.cloner == nil &&
+		len(d.decorators) == 0 && len(d.flagged) == 0 && d.flag == ""
+}
+
+// holdsOnlyRetained reports whether every dependency of d is a singleton or
+// an instance in retained.
+func (s *scope) holdsOnlyRetained(d *descriptor, retained map[instanceKey]bool) bool {
+	p := s.rootProvider
+	for _, dep := range d.Dependencies {
+		if dep == nil {
+			continue
+		}
+		if dep.Group != "" {
+			for _, member := range p.groups[GroupKey{Type: dep.Type, Group: dep.Group}] {
+				if member.Lifetime != Singleton {
+					return false
+				}
+			}
+			continue
+		}
+		if dep.Key == nil && dep.Group == "" {
+			switch dep.Type {
+			case providerType, buildInfoType:
+				continue
+			case contextType, scopeType, resolveInfoType, scopeInfoType, scopeWaitGroupType, cleanupType:
+				return false
+			}
+		}
+
+		key := p.keyedFallback(instanceKey{Type: dep.Type, Key: dep.Key, Group: dep.Group})
+		target := p.findDescriptor(key.Type, key.Key)
+		switch {
+		case target == nil:
+			// Missing optional dependencies are nil; a fallback provider
+			// might have supplied anything.
+			if !isScopedAccessor(dep.Type) && (!dep.Optional || p.fallback != nil) {
+				return false
+			}
+		case target.Lifetime == Singleton && target.refresh == nil:
+		case !retained[key]:
+			return false
+		}
+	}
+	return true
+}
+
+// safeReset calls r.Reset with panic recovery, like safeClose.
+func safeReset(r Resettable) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic during Reset: %v", rec)
+		}
+	}()
+	return r.Reset()
+}
+
+// recycle closes the disposables of a closing pooled scope that it does not
+// retain and returns the rest of its state to the pool. The instance maps are
+// detached from s under its lock, so a constructor finishing late cannot
+// slip its instance into the pool.
+func (s *scope) recycle(retained map[instanceKey]bool, disposables []trackedDisposable) []error {
+	s.instancesMu.Lock()
+	state := &scopeState{
+		instances:     s.instances,
+		records:       s.records,
+		disposableSet: make(map[disposableIdentity]struct{}, len(retained)),
+	}
+	s.instances = nil
+	s.records = nil
+	s.instancesMu.Unlock()
+
+	keep := make(map[disposableIdentity]bool, len(retained))
+	for key, instance := range state.instances {
+		if !retained[key] {
+			delete(state.instances, key)
+			delete(state.records, key)
+			continue
+		}
+		if d, ok := disposableFor(state.records[key].descriptor, instance); ok {
+			if identity, ok := identifyDisposable(d); ok {
+				keep[identity] = true
+			}
+		}
+	}
+
+	var errs []error
+	for i := len(disposables) - 1; i >= 0; i-- {
+		if identity, ok := identifyDisposable(disposables[i].Disposable); ok && keep[identity] {
+			continue
+		}
+		if err := safeClose(disposables[i].Disposable); err != nil {
+			errs = append(errs, fmt.Errorf("failed to dispose scoped instance: %w", err))
+		}
+	}
+	for _, d := range disposables {
+		if identity, ok := identifyDisposable(d.Disposable); ok && keep[identity] {
+			state.disposables = append(state.disposables, d)
+			state.disposableSet[identity] = struct{}{}
+		}
+	}
+
+	if !s.rootProvider.putScopeState(state) {
+		for i := len(state.disposables) - 1; i >= 0; i-- {
+			if err := safeClose(state.disposables[i].Disposable); err != nil {
+				errs = append(errs, fmt.Errorf("failed to dispose scoped instance: %w", err))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,210 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TBuffer is a Resettable, disposable scoped service.
+type TBuffer struct {
+	TDisposable
+	Lines    []string
+	resets   int
+	resetErr error
+}
+
+func (b *TBuffer) Reset() error {
+	b.resets++
+	b.Lines = b.Lines[:0]
+	return b.resetErr
+}
+
+func NewTBuffer() *TBuffer { return &TBuffer{} }
+
+// TBufferUser holds a TBuffer and a TDependency.
+type TBufferUser struct {
+	TBuffer
+	Buf *TBuffer
+	Dep *TDependency
+}
+
+func NewTBufferUser(buf *TBuffer, dep *TDependency) *TBufferUser {
+	return &TBufferUser{Buf: buf, Dep: dep}
+}
+
+func pooledScope(t *testing.T, p Provider) Scope {
+	t.Helper()
+	s, err := GetPooledScope(context.Background(), p)
+	require.NoError(t, err)
+	return s
+}
+
+func TestGetPooledScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reuses_resettable_instances", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTBuffer))
+
+		first := pooledScope(t, p)
+		buf := RequireResolveFrom[*TBuffer](t, first)
+		buf.Lines = append(buf.Lines, "request 1")
+		require.NoError(t, first.Close())
+		assert.Equal(t, 1, buf.resets)
+		assert.False(t, buf.IsClosed())
+
+		second := pooledScope(t, p)
+		assert.NotEqual(t, first.ID(), second.ID())
+		reused := RequireResolveFrom[*TBuffer](t, second)
+		assert.Same(t, buf, reused)
+		assert.Empty(t, reused.Lines)
+		require.NoError(t, second.Close())
+
+		_, err := Resolve[*TBuffer](first)
+		assert.ErrorIs(t, err, ErrScopeDisposed)
+	})
+
+	t.Run("disposes_instances_that_cannot_be_reset", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable))
+
+		first := pooledScope(t, p)
+		disposable := RequireResolveFrom[*TDisposable](t, first)
+		require.NoError(t, first.Close())
+		assert.True(t, disposable.IsClosed())
+
+		second := pooledScope(t, p)
+		assert.NotSame(t, disposable, RequireResolveFrom[*TDisposable](t, second))
+		require.NoError(t, second.Close())
+	})
+
+	t.Run("keeps_instances_holding_only_singletons_and_retained_instances", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTDependency),
+			AddScoped(NewTBuffer),
+			AddScoped(NewTBufferUser),
+		)
+
+		first := pooledScope(t, p)
+		user := RequireResolveFrom[*TBufferUser](t, first)
+		require.NoError(t, first.Close())
+
+		second := pooledScope(t, p)
+		assert.Same(t, user, RequireResolveFrom[*TBufferUser](t, second))
+		assert.Same(t, user.Buf, RequireResolveFrom[*TBuffer](t, second))
+		require.NoError(t, second.Close())
+	})
+
+	t.Run("drops_instances_holding_state_of_the_closed_scope", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddScoped(NewTDependency),
+			AddScoped(NewTBuffer),
+			AddScoped(NewTBufferUser),
+		)
+
+		first := pooledScope(t, p)
+		user := RequireResolveFrom[*TBufferUser](t, first)
+		require.NoError(t, first.Close())
+		// The user holds a scoped dependency that cannot be reset, so it
+		// is closed; the buffer it holds can still be reused.
+		assert.True(t, user.IsClosed())
+		assert.False(t, user.Buf.IsClosed())
+
+		second := pooledScope(t, p)
+		fresh := RequireResolveFrom[*TBufferUser](t, second)
+		assert.NotSame(t, user, fresh)
+		assert.NotSame(t, user.Dep, fresh.Dep)
+		assert.Same(t, user.Buf, fresh.Buf)
+		require.NoError(t, second.Close())
+	})
+
+	t.Run("drops_instances_given_the_scope_context", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(func(context.Context) *TBuffer { return &TBuffer{} }))
+
+		first := pooledScope(t, p)
+		buf := RequireResolveFrom[*TBuffer](t, first)
+		require.NoError(t, first.Close())
+		assert.Zero(t, buf.resets)
+		assert.True(t, buf.IsClosed())
+	})
+
+	t.Run("closes_instances_that_fail_to_reset", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		p := BuildProvider(t, AddScoped(func() *TBuffer { return &TBuffer{resetErr: boom} }))
+
+		first := pooledScope(t, p)
+		buf := RequireResolveFrom[*TBuffer](t, first)
+		err := first.Close()
+		require.ErrorIs(t, err, boom)
+		assert.True(t, buf.IsClosed())
+
+		second := pooledScope(t, p)
+		assert.NotSame(t, buf, RequireResolveFrom[*TBuffer](t, second))
+		assert.ErrorIs(t, second.Close(), boom)
+	})
+
+	t.Run("scopes_from_create_scope_are_not_pooled", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTBuffer))
+
+		s := NewTestScope(t, p)
+		buf := RequireResolveFrom[*TBuffer](t, s)
+		require.NoError(t, s.Close())
+		assert.Zero(t, buf.resets)
+		assert.True(t, buf.IsClosed())
+	})
+
+	t.Run("provider_close_disposes_pooled_instances", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTBuffer)
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		s := pooledScope(t, p)
+		buf := RequireResolveFrom[*TBuffer](t, s)
+		require.NoError(t, s.Close())
+		assert.False(t, buf.IsClosed())
+
+		require.NoError(t, p.Close())
+		assert.True(t, buf.IsClosed())
+		_, err = GetPooledScope(context.Background(), p)
+		assert.ErrorIs(t, err, ErrProviderDisposed)
+		_, err = GetPooledScope(context.Background(), nil)
+		assert.ErrorIs(t, err, ErrProviderNil)
+	})
+
+	t.Run("full_pool_disposes_instances", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTBuffer)
+		p, err := c.BuildWithOptions(&ProviderOptions{ScopePoolSize: 1})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		first, second := pooledScope(t, p), pooledScope(t, p)
+		kept := RequireResolveFrom[*TBuffer](t, first)
+		dropped := RequireResolveFrom[*TBuffer](t, second)
+		require.NoError(t, first.Close())
+		require.NoError(t, second.Close())
+
+		assert.False(t, kept.IsClosed())
+		assert.True(t, dropped.IsClosed())
+	})
+
+	t.Run("negative_pool_size", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewCollection().BuildWithOptions(&ProviderOptions{ScopePoolSize: -1})
+		var validation *ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Contains(t, err.Error(), "invalid ScopePoolSize -1")
+	})
+}
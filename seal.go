@@ -0,0 +1,430 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// A SealOption configures Seal.
+type SealOption interface {
+	applySealOption(*sealOptions)
+}
+
+type sealOptions struct {
+	allowed map[reflect.Type]struct{} // nil allows every type
+}
+
+// AllowServices is a SealOption that limits the sealed view to the given
+// service types; resolving anything else fails with a CapabilityError.
+// Groups are allowed by their element type. Built-in types such as
+// godi.Scope and context.Context must be listed to be resolvable.
+func AllowServices(types ...reflect.Type) SealOption {
+	return allowServicesOption(types)
+}
+
+type allowServicesOption []reflect.Type
+
+func (o allowServicesOption) String() string {
+	names := make([]string, len(o))
+	for i, t := range o {
+		names[i] = formatType(t)
+	}
+	return fmt.Sprintf("AllowServices(%s)", strings.Join(names, ", "))
+}
+
+func (o allowServicesOption) applySealOption(opts *sealOptions) {
+	if opts.allowed == nil {
+		opts.allowed = make(map[reflect.Type]struct{}, len(o))
+	}
+	for _, t := range o {
+		opts.allowed[t] = struct{}{}
+	}
+}
+
+// Seal returns a read-only view of a Provider or Scope to hand to code that
+// must not alter the container, such as third-party plugins. The view
+// resolves services like p, but:
+//
+//   - Close fails with ErrProviderSealed, except on scopes the view's
+//     holder created itself with CreateScope.
+//   - Scopes created from it, their Provider, and the scope found with
+//     FromContext are sealed the same way, as are Provider, Scope, and
+//     context.Context resolved from it.
+//   - Functions that change services at runtime, such as Invalidate and
+//     Unload, fail with ErrProviderSealed.
+//
+// AllowServices further restricts the view to a set of service types.
+// Sealing a sealed view again can only narrow that set.
+//
+// Example:
+//
+//	pluginProvider, err := godi.Seal(provider, godi.AllowServices(
+//	    reflect.TypeFor[*Logger](),
+//	    reflect.TypeFor[PluginAPI](),
+//	))
+func Seal[P Provider](p P, opts ...SealOption) (P, error) {
+	var options sealOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applySealOption(&options)
+		}
+	}
+
+	var view Provider
+	switch v := any(p).(type) {
+	case *provider:
+		view = newSealedProvider(v, options.allowed)
+	case *singletonProvider:
+		view = newSealedProvider(v.provider, options.allowed)
+	case *scope:
+		view = newSealedScope(v, options.allowed, false)
+	case *sealedProvider:
+		view = newSealedProvider(v.root, narrowAllowed(v.allowed, options.allowed))
+	case *sealedScope:
+		view = newSealedScope(v.scope, narrowAllowed(v.allowed, options.allowed), v.owned)
+	case nil:
+		return p, ErrProviderNil
+	default:
+		return p, &ValidationError{
+			ServiceType: nil,
+			Cause:       fmt.Errorf("unsupported provider implementation %T", p),
+		}
+	}
+
+	sealed, _ := view.(P)
+	return sealed, nil
+}
+
+// narrowAllowed intersects two allowlists, where nil allows every type.
+func narrowAllowed(current, requested map[reflect.Type]struct{}) map[reflect.Type]struct{} {
+	if current == nil {
+		return requested
+	}
+	if requested == nil {
+		return current
+	}
+	narrowed := make(map[reflect.Type]struct{})
+	for t := range requested {
+		if _, ok := current[t]; ok {
+			narrowed[t] = struct{}{}
+		}
+	}
+	return narrowed
+}
+
+// sealedView implements the resolution methods shared by sealed providers
+// and sealed scopes. All resolution goes through scope.
+type sealedView struct {
+	scope   *scope
+	allowed map[reflect.Type]struct{}
+	self    Provider // the sealedProvider or sealedScope embedding this view
+}
+
+func (v *sealedView) permits(serviceType reflect.Type) bool {
+	if v.allowed == nil {
+		return true
+	}
+	_, ok := v.allowed[serviceType]
+	return ok
+}
+
+func (v *sealedView) Get(serviceType reflect.Type) (any, error) {
+	instance, err := v.get(nil, serviceType)
+	if err != nil {
+		return nil, v.scope.translateError(err, ResolutionSite{Operation: "Get", ServiceType: serviceType})
+	}
+	return instance, nil
+}
+
+func (v *sealedView) ResolveMany(serviceTypes ...reflect.Type) ([]any, error) {
+	for _, serviceType := range serviceTypes {
+		if serviceType != nil && !v.permits(serviceType) {
+			return nil, v.scope.translateError(&CapabilityError{ServiceType: serviceType},
+				ResolutionSite{Operation: "ResolveMany", ServiceType: serviceType})
+		}
+	}
+	instances, failed, err := v.scope.resolveMany(serviceTypes, func(serviceType reflect.Type) (any, error) {
+		return v.get(nil, serviceType)
+	})
+	if err != nil {
+		return nil, v.scope.translateError(err, ResolutionSite{Operation: "ResolveMany", ServiceType: failed})
+	}
+	return instances, nil
+}
+
+// DumpTo always fails: the registrations of a sealed provider are not part
+// of its capabilities.
+func (v *sealedView) DumpTo(io.Writer) error {
+	return ErrProviderSealed
+}
+
+// GetPooledScope returns a sealed pooled scope of the underlying provider.
+func (v *sealedView) GetPooledScope(ctx context.Context) (Scope, error) {
+	child, err := v.scope.GetPooledScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newSealedScope(child.(*scope), v.allowed, true), nil
+}
+
+func (v *sealedView) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	instance, err := v.getKeyed(nil, serviceType, key)
+	if err != nil {
+		return nil, v.scope.translateError(err, ResolutionSite{Operation: "GetKeyed", ServiceType: serviceType, ServiceKey: key})
+	}
+	return instance, nil
+}
+
+func (v *sealedView) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	instances, err := v.getGroup(nil, serviceType, group)
+	if err != nil {
+		return nil, v.scope.translateError(err, ResolutionSite{Operation: "GetGroup", ServiceType: serviceType, Group: group})
+	}
+	return instances, nil
+}
+
+func (v *sealedView) ResolveByName(name string) (any, error) {
+	instance, err := v.resolveByName(name)
+	if err != nil {
+		return nil, v.scope.translateError(err, ResolutionSite{Operation: "ResolveByName", Name: name})
+	}
+	return instance, nil
+}
+
+func (v *sealedView) Inject(target any) error {
+	if err := v.scope.inject(target, sealedResolver{v: v}); err != nil {
+		return v.scope.translateError(err, ResolutionSite{Operation: "Inject", ServiceType: reflect.TypeOf(target)})
+	}
+	return nil
+}
+
+// get resolves serviceType on behalf of r (nil at the top level), like
+// scope.get, within the view's allowlist.
+func (v *sealedView) get(r *resolution, serviceType reflect.Type) (any, error) {
+	if serviceType != nil && !v.permits(serviceType) {
+		return nil, &CapabilityError{ServiceType: serviceType}
+	}
+
+	// Built-in types would otherwise hand out the unsealed container.
+	switch serviceType {
+	case providerType:
+		return v.self, nil
+	case scopeType:
+		return v.scopeView(), nil
+	case contextType:
+		return v.scopeView().Context(), nil
+	}
+	return v.scope.get(r, serviceType)
+}
+
+func (v *sealedView) getKeyed(r *resolution, serviceType reflect.Type, key any) (any, error) {
+	if serviceType != nil && !v.permits(serviceType) {
+		return nil, &CapabilityError{ServiceType: serviceType, ServiceKey: key}
+	}
+	return v.scope.getKeyed(r, serviceType, key)
+}
+
+func (v *sealedView) getGroup(r *resolution, serviceType reflect.Type, group string) ([]any, error) {
+	if serviceType != nil && !v.permits(serviceType) {
+		return nil, &CapabilityError{ServiceType: serviceType, Group: group}
+	}
+	return v.scope.getGroup(r, serviceType, group)
+}
+
+func (v *sealedView) resolveByName(name string) (any, error) {
+	if v.allowed != nil {
+		// Names exported only by a fallback provider have no type to check.
+		d := v.scope.rootProvider.exports[name]
+		if d == nil || !v.permits(d.Type) {
+			capErr := &CapabilityError{Name: name}
+			if d != nil {
+				capErr.ServiceType = d.Type
+			}
+			return nil, capErr
+		}
+	}
+	return v.scope.resolveByName(name)
+}
+
+// scopeView returns the sealed scope this view resolves from.
+func (v *sealedView) scopeView() *sealedScope {
+	if s, ok := v.self.(*sealedScope); ok {
+		return s
+	}
+	return newSealedScope(v.scope, v.allowed, false)
+}
+
+func (v *sealedView) invocation(shareTransients bool) (*invocation, error) {
+	return &invocation{
+		analyzer:  v.scope.rootProvider.analyzer,
+		resolver:  sealedResolver{v: v, r: &resolution{scope: v.scope, shareTransients: shareTransients}},
+		context:   v.scopeView().Context(),
+		translate: v.scope.translateError,
+	}, nil
+}
+
+func (v *sealedView) groupSize(elemType reflect.Type, group string) int {
+	if !v.permits(elemType) {
+		return 0
+	}
+	return v.scope.groupSize(elemType, group)
+}
+
+func (v *sealedView) groupMember(elemType reflect.Type, group string, i int) (any, error) {
+	if !v.permits(elemType) {
+		return nil, &CapabilityError{ServiceType: elemType, Group: group}
+	}
+	return v.scope.groupMember(elemType, group, i)
+}
+
+// graph returns the graph of the registrations the view exposes.
+func (v *sealedView) graph() *graphView {
+	root := v.scope.rootProvider
+	if v.allowed == nil {
+		return newGraphView(root.descriptors, root.services, root.groups)
+	}
+
+	var all []*descriptor
+	for _, d := range root.descriptors {
+		if d != nil && v.permits(d.Type) {
+			all = append(all, d)
+		}
+	}
+	services := make(map[TypeKey]*descriptor)
+	for key, d := range root.services {
+		if v.permits(key.Type) {
+			services[key] = d
+		}
+	}
+	groups := make(map[GroupKey][]*descriptor)
+	for key, members := range root.groups {
+		if v.permits(key.Type) {
+			groups[key] = members
+		}
+	}
+	return newGraphView(all, services, groups)
+}
+
+// sealedResolver resolves through a sealed view without translating
+// errors, in the frame r: nil for Inject fields, the root frame of the call
+// for Invoke.
+type sealedResolver struct {
+	v *sealedView
+	r *resolution
+}
+
+func (r sealedResolver) Get(serviceType reflect.Type) (any, error) {
+	return r.v.get(r.r, serviceType)
+}
+
+func (r sealedResolver) GetKeyed(serviceType reflect.Type, key any) (any, error) {
+	return r.v.getKeyed(r.r, serviceType, key)
+}
+
+func (r sealedResolver) GetGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return r.v.getGroup(r.r, serviceType, group)
+}
+
+// sealedProvider is the sealed view of a provider, resolving from its root
+// scope.
+type sealedProvider struct {
+	sealedView
+	root *provider
+}
+
+func newSealedProvider(root *provider, allowed map[reflect.Type]struct{}) *sealedProvider {
+	p := &sealedProvider{root: root}
+	p.sealedView = sealedView{scope: root.rootScope, allowed: allowed, self: p}
+	return p
+}
+
+func (p *sealedProvider) ID() string {
+	return p.root.ID()
+}
+
+func (p *sealedProvider) CreateScope(ctx context.Context) (Scope, error) {
+	return p.createScopeWith(ctx)
+}
+
+func (p *sealedProvider) createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error) {
+	child, err := p.root.createScopeWith(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newSealedScope(child.(*scope), p.allowed, true), nil
+}
+
+// Close always fails: only the owner of the provider may close it.
+func (p *sealedProvider) Close() error {
+	return ErrProviderSealed
+}
+
+// StopAll always fails, like Close.
+func (p *sealedProvider) StopAll(context.Context) error {
+	return ErrProviderSealed
+}
+
+// sealedScope is the sealed view of a scope.
+type sealedScope struct {
+	sealedView
+	owned   bool // created through a sealed view, so its holder may close it
+	context context.Context
+}
+
+func newSealedScope(s *scope, allowed map[reflect.Type]struct{}, owned bool) *sealedScope {
+	view := &sealedScope{owned: owned}
+	view.sealedView = sealedView{scope: s, allowed: allowed, self: view}
+	// FromContext must find the sealed view, not the scope it wraps.
+	view.context = context.WithValue(s.context, scopeContextKey{}, Scope(view))
+	return view
+}
+
+func (s *sealedScope) ID() string {
+	return s.scope.ID()
+}
+
+func (s *sealedScope) Provider() Provider {
+	return newSealedProvider(s.scope.rootProvider, s.allowed)
+}
+
+func (s *sealedScope) Context() context.Context {
+	return s.context
+}
+
+// DumpState always fails, like DumpTo.
+func (s *sealedScope) DumpState(io.Writer) error {
+	return ErrProviderSealed
+}
+
+func (s *sealedScope) CreateScope(ctx context.Context) (Scope, error) {
+	return s.createScopeWith(ctx)
+}
+
+func (s *sealedScope) createScopeWith(ctx context.Context, opts ...ScopeOption) (Scope, error) {
+	child, err := s.scope.createScopeWith(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newSealedScope(child.(*scope), s.allowed, true), nil
+}
+
+// Close closes scopes created through a sealed view and fails with
+// ErrProviderSealed for scopes sealed by their owner.
+func (s *sealedScope) Close() error {
+	if !s.owned {
+		return ErrProviderSealed
+	}
+	return s.scope.Close()
+}
+
+// StopAll stops scopes created through a sealed view and fails with
+// ErrProviderSealed for scopes sealed by their owner.
+func (s *sealedScope) StopAll(ctx context.Context) error {
+	if !s.owned {
+		return ErrProviderSealed
+	}
+	return s.scope.StopAll(ctx)
+}
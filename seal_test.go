@@ -0,0 +1,144 @@
+package godi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeal(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T) Provider {
+		t.Helper()
+		return BuildProvider(t,
+			AddSingleton(NewTDependency, ExportName("dependency")),
+			AddScoped(NewTService, ExportName("service")),
+			AddSingleton(NewTDependencyWithName("audit"), Name("audit")),
+			AddRefreshing(NewTDisposable),
+		)
+	}
+
+	t.Run("resolves_like_the_provider", func(t *testing.T) {
+		t.Parallel()
+		p := build(t)
+		sealed, err := Seal(p)
+		require.NoError(t, err)
+
+		assert.Same(t, RequireResolve[*TDependency](t, p), RequireResolve[*TDependency](t, sealed))
+		assert.Equal(t, p.ID(), sealed.ID())
+
+		s, err := sealed.CreateScope(t.Context())
+		require.NoError(t, err)
+		svc := RequireResolveFrom[*TService](t, s)
+		byName, err := ResolveByName(s, "service")
+		require.NoError(t, err)
+		assert.Same(t, svc, byName)
+		require.NoError(t, s.Close(), "scopes created through the view belong to its holder")
+	})
+
+	t.Run("cannot_close_or_replace", func(t *testing.T) {
+		t.Parallel()
+		p := build(t)
+		sealed, err := Seal(p)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, sealed.Close(), ErrProviderSealed)
+		require.ErrorIs(t, Invalidate[*TDisposable](sealed), ErrProviderSealed)
+		require.ErrorIs(t, Unload[*TDisposable](sealed), ErrProviderSealed)
+
+		hostScope := NewTestScope(t, p)
+		sealedScope, err := Seal(hostScope)
+		require.NoError(t, err)
+		require.ErrorIs(t, sealedScope.Close(), ErrProviderSealed)
+		assert.Equal(t, hostScope.ID(), sealedScope.ID())
+		_, err = Resolve[*TService](hostScope)
+		require.NoError(t, err, "the host scope stays open")
+	})
+
+	t.Run("does_not_leak_the_unsealed_container", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(build(t))
+		require.NoError(t, err)
+
+		assert.Same(t, sealed, RequireResolve[Provider](t, sealed))
+		_, isSealed := RequireResolve[Scope](t, sealed).(*sealedScope)
+		assert.True(t, isSealed)
+
+		s, err := sealed.CreateScope(t.Context())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		fromCtx, err := FromContext(s.Context())
+		require.NoError(t, err)
+		assert.Same(t, s, fromCtx)
+		assert.Same(t, s, RequireResolveFrom[Scope](t, s))
+		_, isSealed = s.Provider().(*sealedProvider)
+		assert.True(t, isSealed)
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(build(t), AllowServices(PtrTypeOf[TDependency]()))
+		require.NoError(t, err)
+
+		_, err = Resolve[*TDependency](sealed)
+		require.NoError(t, err)
+		dep, err := ResolveKeyed[*TDependency](sealed, "audit")
+		require.NoError(t, err)
+		assert.Equal(t, "audit", dep.Name)
+		_, err = ResolveByName(sealed, "dependency")
+		require.NoError(t, err)
+
+		_, err = Resolve[*TDisposable](sealed)
+		capErr, ok := errors.AsType[*CapabilityError](err)
+		require.True(t, ok, "expected CapabilityError, got %v", err)
+		assert.Equal(t, PtrTypeOf[TDisposable](), capErr.ServiceType)
+		require.ErrorIs(t, err, ErrProviderSealed)
+
+		_, err = ResolveByName(sealed, "service")
+		require.ErrorIs(t, err, ErrProviderSealed)
+		_, err = Resolve[Provider](sealed)
+		require.ErrorIs(t, err, ErrProviderSealed, "built-in types must be allowed explicitly")
+
+		var target struct {
+			Dep     *TDependency
+			Missing *TDisposable `optional:"true"`
+		}
+		err = Inject(sealed, &target)
+		require.ErrorIs(t, err, ErrProviderSealed, "a denied optional field is not silently skipped")
+	})
+
+	t.Run("resealing_narrows", func(t *testing.T) {
+		t.Parallel()
+		wide, err := Seal(build(t), AllowServices(PtrTypeOf[TDependency](), PtrTypeOf[TDisposable]()))
+		require.NoError(t, err)
+		narrow, err := Seal(wide, AllowServices(PtrTypeOf[TDisposable](), PtrTypeOf[TService]()))
+		require.NoError(t, err)
+
+		s, err := narrow.CreateScope(t.Context())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+		_, err = Resolve[*TDisposable](s)
+		require.NoError(t, err)
+		_, err = Resolve[*TDependency](narrow)
+		require.ErrorIs(t, err, ErrProviderSealed)
+		_, err = Resolve[*TService](s)
+		require.ErrorIs(t, err, ErrProviderSealed, "resealing cannot widen the allowlist")
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := Seal[Provider](nil)
+		require.ErrorIs(t, err, ErrProviderNil)
+	})
+
+	t.Run("option_string", func(t *testing.T) {
+		t.Parallel()
+		opt := AllowServices(PtrTypeOf[TService](), reflect.TypeFor[TInterface]())
+		assert.Equal(t, "AllowServices(*TService, TInterface)", opt.(interface{ String() string }).String())
+	})
+}
@@ -0,0 +1,53 @@
+package godi
+
+import "slices"
+
+// takeOpenScopes hands the scopes still open, oldest first, to a closing
+// provider once ProviderOptions.BeforeClose has returned, and reports them
+// under ProviderOptions.RequireScopesClosed.
+func (p *provider) takeOpenScopes() ([]*scope, error) {
+	if p.beforeClose != nil {
+		p.beforeClose(scopeInfos(p.openScopes()))
+	}
+
+	p.scopesMu.Lock()
+	scopes := make([]*scope, 0, len(p.scopes))
+	for s := range p.scopes {
+		scopes = append(scopes, s)
+	}
+	p.scopes = nil
+	p.scopesMu.Unlock()
+	sortScopes(scopes)
+
+	if !p.requireScopesClosed {
+		return scopes, nil
+	}
+	// Scopes already closing are not left open.
+	open := slices.DeleteFunc(slices.Clone(scopes), func(s *scope) bool { return s.isDisposed() })
+	if len(open) == 0 {
+		return scopes, nil
+	}
+	return scopes, &OpenScopesError{Scopes: scopeInfos(open)}
+}
+
+// openScopes returns the open scopes of the provider, oldest first.
+func (p *provider) openScopes() []*scope {
+	p.scopesMu.Lock()
+	scopes := make([]*scope, 0, len(p.scopes))
+	for s := range p.scopes {
+		if !s.isDisposed() {
+			scopes = append(scopes, s)
+		}
+	}
+	p.scopesMu.Unlock()
+	sortScopes(scopes)
+	return scopes
+}
+
+func scopeInfos(scopes []*scope) []ScopeInfo {
+	infos := make([]ScopeInfo, len(scopes))
+	for i, s := range scopes {
+		infos[i] = s.info()
+	}
+	return infos
+}
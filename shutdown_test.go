@@ -0,0 +1,160 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeLog records the order services are closed in.
+type closeLog struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (l *closeLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = append(l.closed, name)
+}
+
+func (l *closeLog) names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.closed...)
+}
+
+// TLoggedCloser adds its name to a closeLog when closed.
+type TLoggedCloser struct {
+	name string
+	log  *closeLog
+}
+
+func (c *TLoggedCloser) Close() error {
+	c.log.add(c.name)
+	return nil
+}
+
+// TPool is a singleton dependency of TRepo.
+type TPool struct{ TLoggedCloser }
+
+// TRepo depends on TPool.
+type TRepo struct {
+	TLoggedCloser
+	Pool *TPool
+}
+
+func TestProviderCloseOrder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("singletons_close_before_their_dependencies", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		c := NewCollection()
+		c.AddSingleton(func() *TPool { return &TPool{TLoggedCloser{"pool", log}} })
+		c.AddSingleton(func(pool *TPool) *TRepo { return &TRepo{TLoggedCloser{"repo", log}, pool} })
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		// Pretend the pool was constructed after the repository, e.g. by a
+		// reload: creation order must not decide.
+		impl := p.(*provider)
+		impl.disposablesMu.Lock()
+		impl.disposables[0], impl.disposables[1] = impl.disposables[1], impl.disposables[0]
+		impl.disposablesMu.Unlock()
+
+		require.NoError(t, p.Close())
+		assert.Equal(t, []string{"repo", "pool"}, log.names())
+	})
+
+	t.Run("open_scopes_close_before_singletons", func(t *testing.T) {
+		t.Parallel()
+		log := &closeLog{}
+		c := NewCollection()
+		c.AddSingleton(func() *TPool { return &TPool{TLoggedCloser{"pool", log}} })
+		c.AddScoped(func(pool *TPool) *TRepo { return &TRepo{TLoggedCloser{"repo", log}, pool} })
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		RequireResolveFrom[*TRepo](t, s)
+
+		require.NoError(t, p.Close())
+		assert.Equal(t, []string{"repo", "pool"}, log.names())
+	})
+
+	t.Run("before_close_sees_open_scopes", func(t *testing.T) {
+		t.Parallel()
+		var seen []ScopeInfo
+		var first Scope
+		c := NewCollection()
+		p, err := c.BuildWithOptions(&ProviderOptions{
+			BeforeClose: func(open []ScopeInfo) {
+				seen = open
+				_ = first.Close()
+			},
+			RequireScopesClosed: true,
+		})
+		require.NoError(t, err)
+
+		first, err = CreateScope(context.Background(), p, WithScopeName("request"))
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		require.Len(t, seen, 1)
+		assert.Equal(t, first.ID(), seen[0].ID)
+		assert.Equal(t, "request", seen[0].Name)
+	})
+
+	t.Run("require_scopes_closed_reports_leaks", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTDisposable)
+		p, err := c.BuildWithOptions(&ProviderOptions{RequireScopesClosed: true})
+		require.NoError(t, err)
+
+		leaked, err := CreateScope(context.Background(), p, WithScopeName("worker"))
+		require.NoError(t, err)
+		disposable := RequireResolveFrom[*TDisposable](t, leaked)
+		closed, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, closed.Close())
+
+		err = p.Close()
+		var open *OpenScopesError
+		require.True(t, errors.As(err, &open), "got %v", err)
+		require.Len(t, open.Scopes, 1)
+		assert.Equal(t, leaked.ID(), open.Scopes[0].ID)
+		assert.Contains(t, err.Error(), "(worker)")
+		assert.True(t, disposable.IsClosed())
+	})
+
+	t.Run("stop_all_reports_leaks", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewCollection().BuildWithOptions(&ProviderOptions{RequireScopesClosed: true})
+		require.NoError(t, err)
+		_, err = p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		err = StopAll(context.Background(), p)
+		var open *OpenScopesError
+		assert.True(t, errors.As(err, &open), "got %v", err)
+	})
+
+	t.Run("scopes_left_open_are_closed_silently_by_default", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewCollection().Build()
+		require.NoError(t, err)
+		s, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		_, err = s.CreateScope(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+// Package slog injects a *slog.Logger per godi scope that carries the
+// scope's correlation fields, such as request and trace IDs.
+//
+// Module registers a scoped *slog.Logger derived from a base logger with
+// the attributes that extractors read from the scope's context. Every
+// service of a request that takes *slog.Logger logs with the request's
+// fields without passing them along by hand.
+//
+// Example usage:
+//
+//	services.AddModules(godislog.Module(
+//	    godislog.WithLogger(slog.New(slog.NewJSONHandler(os.Stdout, nil))),
+//	    godislog.WithExtractor(godislog.ContextValue(requestIDKey{}, "request_id")),
+//	))
+//	services.AddScoped(NewOrderService) // takes *slog.Logger
+//
+//	func (s *OrderService) Place(ctx context.Context, order Order) error {
+//	    s.logger.InfoContext(ctx, "placing order", "order_id", order.ID)
+//	    // {"msg":"placing order","request_id":"a1b2","order_id":42}
+//	    ...
+//	}
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// Extractor returns the attributes a scope's logger carries, read from
+// the scope's context. It returns nil when the context holds none.
+type Extractor func(ctx context.Context) []stdslog.Attr
+
+// ContextValue returns an Extractor that adds the value stored in the
+// context under key, as set by context.WithValue, as the attribute attr.
+// Contexts without the value add nothing.
+func ContextValue(key any, attr string) Extractor {
+	return func(ctx context.Context) []stdslog.Attr {
+		value := ctx.Value(key)
+		if value == nil {
+			return nil
+		}
+		return []stdslog.Attr{stdslog.Any(attr, value)}
+	}
+}
+
+// Config holds the configuration of Module.
+type Config struct {
+	// Logger is the logger scope loggers are derived from. If nil,
+	// slog.Default() at the time each scope's logger is created.
+	Logger *stdslog.Logger
+
+	// Extractors read the attributes of each scope's logger from its
+	// context, in order.
+	Extractors []Extractor
+
+	// ScopeIDAttr, if set, adds the scope's ID under this attribute.
+	ScopeIDAttr string
+}
+
+// Option configures Module.
+type Option func(*Config)
+
+// WithLogger sets the logger scope loggers are derived from.
+func WithLogger(logger *stdslog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithExtractor adds an Extractor. It may be given several times, for
+// example once for request IDs and once for trace IDs:
+//
+//	godislog.WithExtractor(func(ctx context.Context) []slog.Attr {
+//	    sc := trace.SpanContextFromContext(ctx)
+//	    if !sc.IsValid() {
+//	        return nil
+//	    }
+//	    return []slog.Attr{slog.String("trace_id", sc.TraceID().String())}
+//	})
+func WithExtractor(extractor Extractor) Option {
+	return func(c *Config) {
+		if extractor != nil {
+			c.Extractors = append(c.Extractors, extractor)
+		}
+	}
+}
+
+// WithScopeID adds the ID of the scope under attr, which correlates log
+// lines with godi's own diagnostics, such as ResolutionEvent.ScopeID.
+func WithScopeID(attr string) Option {
+	return func(c *Config) {
+		c.ScopeIDAttr = attr
+	}
+}
+
+// Module registers a scoped *slog.Logger carrying the attributes of its
+// scope. Like every scoped service, it is resolved from a scope, such as
+// the request scope of an HTTP integration; services constructed outside
+// one, such as singletons, cannot take it and should take a logger of their
+// own.
+func Module(opts ...Option) godi.ModuleOption {
+	config := &Config{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(config)
+		}
+	}
+
+	return godi.NewModule("slog",
+		godi.AddScoped(func(ctx context.Context, scope godi.Scope) *stdslog.Logger {
+			return config.scopeLogger(ctx, scope)
+		}),
+	)
+}
+
+// scopeLogger returns the logger of scope, whose context is ctx.
+func (c *Config) scopeLogger(ctx context.Context, scope godi.Scope) *stdslog.Logger {
+	logger := c.Logger
+	if logger == nil {
+		logger = stdslog.Default()
+	}
+
+	var attrs []any
+	if c.ScopeIDAttr != "" {
+		attrs = append(attrs, stdslog.String(c.ScopeIDAttr, scope.ID()))
+	}
+	for _, extract := range c.Extractors {
+		for _, attr := range extract(ctx) {
+			attrs = append(attrs, attr)
+		}
+	}
+	if len(attrs) == 0 {
+		return logger
+	}
+	return logger.With(attrs...)
+}
@@ -0,0 +1,131 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdslog "log/slog"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requestIDKey struct{}
+
+// orderService logs with the logger of its scope.
+type orderService struct {
+	logger *stdslog.Logger
+}
+
+func newOrderService(logger *stdslog.Logger) *orderService {
+	return &orderService{logger: logger}
+}
+
+func newTestProvider(t *testing.T, modules ...godi.ModuleOption) godi.Provider {
+	t.Helper()
+	collection := godi.NewCollection()
+	collection.AddModules(modules...)
+	provider, err := collection.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+	return provider
+}
+
+func newTestScope(t *testing.T, provider godi.Provider, ctx context.Context) godi.Scope {
+	t.Helper()
+	scope, err := provider.CreateScope(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scope.Close() })
+	return scope
+}
+
+// lastRecord decodes the last JSON log line in buf.
+func lastRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(lines[len(lines)-1], &record))
+	return record
+}
+
+func TestModule(t *testing.T) {
+	t.Run("injects a logger carrying the scope's fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		provider := newTestProvider(t,
+			Module(
+				WithLogger(stdslog.New(stdslog.NewJSONHandler(&buf, nil))),
+				WithExtractor(ContextValue(requestIDKey{}, "request_id")),
+				WithScopeID("scope_id"),
+			),
+			godi.AddScoped(newOrderService),
+		)
+		scope := newTestScope(t, provider, context.WithValue(context.Background(), requestIDKey{}, "a1b2"))
+
+		service, err := godi.Resolve[*orderService](scope)
+		require.NoError(t, err)
+		service.logger.Info("placing order", "order_id", 42)
+
+		record := lastRecord(t, &buf)
+		assert.Equal(t, "placing order", record["msg"])
+		assert.Equal(t, "a1b2", record["request_id"])
+		assert.Equal(t, scope.ID(), record["scope_id"])
+		assert.Equal(t, float64(42), record["order_id"])
+
+		logger, err := godi.Resolve[*stdslog.Logger](scope)
+		require.NoError(t, err)
+		assert.Same(t, service.logger, logger, "one logger per scope")
+	})
+
+	t.Run("each scope gets its own fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		provider := newTestProvider(t, Module(
+			WithLogger(stdslog.New(stdslog.NewJSONHandler(&buf, nil))),
+			WithExtractor(ContextValue(requestIDKey{}, "request_id")),
+		))
+
+		for _, id := range []string{"first", "second"} {
+			scope := newTestScope(t, provider, context.WithValue(context.Background(), requestIDKey{}, id))
+			logger, err := godi.Resolve[*stdslog.Logger](scope)
+			require.NoError(t, err)
+			logger.Info("handled")
+			assert.Equal(t, id, lastRecord(t, &buf)["request_id"])
+		}
+	})
+
+	t.Run("runs extractors in order and skips missing values", func(t *testing.T) {
+		var buf bytes.Buffer
+		provider := newTestProvider(t, Module(
+			WithLogger(stdslog.New(stdslog.NewJSONHandler(&buf, nil))),
+			WithExtractor(ContextValue(requestIDKey{}, "request_id")),
+			WithExtractor(func(context.Context) []stdslog.Attr {
+				return []stdslog.Attr{stdslog.String("trace_id", "t1"), stdslog.Int("sampled", 1)}
+			}),
+		))
+		scope := newTestScope(t, provider, context.Background())
+
+		logger, err := godi.Resolve[*stdslog.Logger](scope)
+		require.NoError(t, err)
+		logger.Info("handled")
+		record := lastRecord(t, &buf)
+		assert.NotContains(t, record, "request_id")
+		assert.Equal(t, "t1", record["trace_id"])
+		assert.Equal(t, float64(1), record["sampled"])
+	})
+
+	t.Run("defaults to slog.Default", func(t *testing.T) {
+		provider := newTestProvider(t, Module())
+		scope := newTestScope(t, provider, context.Background())
+
+		logger, err := godi.Resolve[*stdslog.Logger](scope)
+		require.NoError(t, err)
+		assert.Same(t, stdslog.Default(), logger, "a logger without fields is the base logger")
+	})
+
+	t.Run("is scoped", func(t *testing.T) {
+		provider := newTestProvider(t, Module())
+		_, err := godi.Resolve[*stdslog.Logger](provider)
+		assert.ErrorIs(t, err, godi.ErrScopedResolvedFromRoot)
+	})
+}
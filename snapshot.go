@@ -0,0 +1,218 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Snapshot holds the state of a provider's singletons, taken by
+// TakeSnapshot and rolled back by RestoreSnapshot.
+type Snapshot struct {
+	provider *provider
+	entries  []snapshotEntry
+}
+
+// snapshotEntry is the saved state of one singleton instance: a copy made
+// by copyState, or what its Snapshotter returned.
+type snapshotEntry struct {
+	descriptor *descriptor
+	instance   any
+	state      reflect.Value
+	saved      any
+}
+
+// Snapshotter is implemented by singletons that save and restore their own
+// state for TakeSnapshot, such as stores keeping their data in maps
+// that the default shallow copy would share with the live instance.
+// SnapshotState must return a copy that later mutations do not affect, and
+// RestoreState may be called with it any number of times.
+type Snapshotter interface {
+	SnapshotState() any
+	RestoreState(state any) error
+}
+
+// ExcludeFromSnapshot is an AddOption for singletons whose state must not
+// be rolled back by RestoreSnapshot, such as clients that hold
+// locks or goroutines of their own. Disposable services are excluded
+// without it: the resources they own cannot be copied.
+func ExcludeFromSnapshot() AddOption {
+	return addExcludeFromSnapshotOption{}
+}
+
+type addExcludeFromSnapshotOption struct{}
+
+func (addExcludeFromSnapshotOption) String() string {
+	return "ExcludeFromSnapshot()"
+}
+
+func (addExcludeFromSnapshotOption) applyAddOption(opt *addOptions) {
+	opt.excludeFromSnapshot = true
+}
+
+// TakeSnapshot records the state of the constructed singletons of the
+// provider p is or belongs to, so that a test suite can build an expensive
+// provider once, let each test mutate singletons such as in-memory stores
+// or fakes, and roll them back with RestoreSnapshot instead of rebuilding
+// the provider.
+//
+// Singletons implementing Snapshotter save their own state. Otherwise
+// pointers to structs are copied field by field, and maps entry by entry;
+// the copy is shallow, so state reached through pointers, slices or nested
+// maps is shared with the live instance. Other values cannot be mutated by
+// their consumers and need no copy. Services registered with
+// ExcludeFromSnapshot, and Disposable services, are left as they are.
+//
+// Example:
+//
+//	snapshot, err := godi.TakeSnapshot(provider)
+//
+//	t.Cleanup(func() {
+//	    if err := godi.RestoreSnapshot(provider, snapshot); err != nil {
+//	        t.Error(err)
+//	    }
+//	})
+func TakeSnapshot(p Provider) (*Snapshot, error) {
+	root, err := providerOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return root.snapshot()
+}
+
+func (p *provider) snapshot() (*Snapshot, error) {
+	if p.disposed.Load() != 0 {
+		return nil, ErrProviderDisposed
+	}
+
+	snapshot := &Snapshot{provider: p}
+	seen := make(map[snapshotIdentity]bool)
+	for _, d := range p.descriptors {
+		if d.Lifetime != Singleton || d.VoidReturn || d.refresh != nil || d.excludeFromSnapshot {
+			continue
+		}
+		instance, ok := p.getSingleton(instanceKey{Type: d.Type, Key: d.Key, Group: d.Group})
+		if !ok {
+			continue
+		}
+		if _, disposable := disposableFor(d, instance); disposable {
+			continue
+		}
+		snapshotter, isSnapshotter := instance.(Snapshotter)
+		if !isSnapshotter && !copyable(reflect.ValueOf(instance)) {
+			continue
+		}
+		if identity, ok := identifySnapshot(instance); ok {
+			if seen[identity] {
+				continue
+			}
+			seen[identity] = true
+		}
+
+		entry := snapshotEntry{descriptor: d, instance: instance}
+		if isSnapshotter {
+			entry.saved = snapshotter.SnapshotState()
+		} else {
+			entry.state = copyState(reflect.ValueOf(instance))
+		}
+		snapshot.entries = append(snapshot.entries, entry)
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot rolls the singletons recorded by TakeSnapshot back to the
+// state they had then. p must be, or belong to, the provider the snapshot
+// was taken from. A snapshot can be restored any number of times.
+// Singletons constructed after the snapshot keep their state. It must not
+// run while other goroutines use the singletons. Errors returned by
+// Snapshotters are joined, after every singleton has been restored.
+func RestoreSnapshot(p Provider, snapshot *Snapshot) error {
+	root, err := providerOf(p)
+	if err != nil {
+		return err
+	}
+	return root.restoreSnapshot(snapshot)
+}
+
+func (p *provider) restoreSnapshot(snapshot *Snapshot) error {
+	if snapshot == nil {
+		return &ValidationError{Cause: fmt.Errorf("RestoreSnapshot needs a snapshot")}
+	}
+	if snapshot.provider != p {
+		return &ValidationError{Cause: fmt.Errorf("snapshot was taken from provider %s, not %s", snapshot.provider.id, p.id)}
+	}
+	if p.disposed.Load() != 0 {
+		return ErrProviderDisposed
+	}
+
+	var errs []error
+	for _, entry := range snapshot.entries {
+		if snapshotter, ok := entry.instance.(Snapshotter); ok {
+			if err := snapshotter.RestoreState(entry.saved); err != nil {
+				errs = append(errs, &ValidationError{ServiceType: entry.descriptor.Type, Cause: err})
+			}
+			continue
+		}
+		restoreState(reflect.ValueOf(entry.instance), copyState(entry.state))
+	}
+	return errors.Join(errs...)
+}
+
+// snapshotIdentity identifies an instance shared by several registrations,
+// such as the aliases added by godi.As, so its state is recorded once.
+type snapshotIdentity struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// identifySnapshot returns the identity of an instance that several
+// registrations can share: a non-nil pointer or map.
+func identifySnapshot(instance any) (snapshotIdentity, bool) {
+	value := reflect.ValueOf(instance)
+	if (value.Kind() != reflect.Pointer && value.Kind() != reflect.Map) || value.IsNil() {
+		return snapshotIdentity{}, false
+	}
+	return snapshotIdentity{typ: value.Type(), ptr: value.Pointer()}, true
+}
+
+// copyable reports whether copyState can record the state of value: a
+// non-nil pointer to a struct, or a non-nil map.
+func copyable(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer:
+		return !value.IsNil() && value.Type().Elem().Kind() == reflect.Struct
+	case reflect.Map:
+		return !value.IsNil()
+	}
+	return false
+}
+
+// copyState returns a shallow copy of the state of value, a pointer to a
+// struct or a map.
+func copyState(value reflect.Value) reflect.Value {
+	if value.Kind() == reflect.Map {
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return copied
+	}
+	copied := reflect.New(value.Type().Elem())
+	copied.Elem().Set(value.Elem())
+	return copied
+}
+
+// restoreState overwrites the state of live with state, a copy made by
+// copyState.
+func restoreState(live, state reflect.Value) {
+	if live.Kind() == reflect.Map {
+		live.Clear()
+		iter := state.MapRange()
+		for iter.Next() {
+			live.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return
+	}
+	live.Elem().Set(state.Elem())
+}
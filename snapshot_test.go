@@ -0,0 +1,148 @@
+package godi
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TStore is an in-memory store that tests mutate.
+type TStore struct {
+	Users map[string]string
+	Count int
+}
+
+func NewTStore() *TStore {
+	return &TStore{Users: map[string]string{"alice": "admin"}}
+}
+
+// TDeepStore copies its map itself.
+type TDeepStore struct {
+	TStore
+	fail error
+}
+
+func NewTDeepStore() *TDeepStore {
+	return &TDeepStore{TStore: *NewTStore()}
+}
+
+func (s *TDeepStore) SnapshotState() any {
+	return maps.Clone(s.Users)
+}
+
+func (s *TDeepStore) RestoreState(state any) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	s.Users = maps.Clone(state.(map[string]string))
+	return nil
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores_struct_fields", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTStore), AddSingleton(NewTDependencyWithName("original")))
+		snapshot, err := TakeSnapshot(p)
+		require.NoError(t, err)
+
+		store := RequireResolve[*TStore](t, p)
+		store.Count = 3
+		RequireResolve[*TDependency](t, p).Name = "changed"
+
+		for range 2 {
+			require.NoError(t, RestoreSnapshot(p, snapshot))
+			assert.Same(t, store, RequireResolve[*TStore](t, p))
+			assert.Zero(t, store.Count)
+			assert.Equal(t, "original", RequireResolve[*TDependency](t, p).Name)
+			store.Count = 5
+		}
+	})
+
+	t.Run("snapshotters_save_their_own_state", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDeepStore))
+		snapshot, err := TakeSnapshot(p)
+		require.NoError(t, err)
+
+		store := RequireResolve[*TDeepStore](t, p)
+		store.Users["bob"] = "guest"
+
+		require.NoError(t, RestoreSnapshot(p, snapshot))
+		assert.Equal(t, map[string]string{"alice": "admin"}, store.Users)
+
+		store.fail = errors.New("read-only")
+		assert.ErrorIs(t, RestoreSnapshot(p, snapshot), store.fail)
+	})
+
+	t.Run("restores_maps", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(func() map[string]int { return map[string]int{"a": 1} }))
+		snapshot, err := TakeSnapshot(p)
+		require.NoError(t, err)
+
+		m := RequireResolve[map[string]int](t, p)
+		m["b"] = 2
+		delete(m, "a")
+
+		require.NoError(t, RestoreSnapshot(p, snapshot))
+		assert.Equal(t, map[string]int{"a": 1}, m)
+	})
+
+	t.Run("excluded_and_disposable_services_keep_their_state", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTStore, ExcludeFromSnapshot()),
+			AddSingleton(NewTDisposable),
+		)
+		snapshot, err := TakeSnapshot(p)
+		require.NoError(t, err)
+
+		RequireResolve[*TStore](t, p).Count = 3
+		disposable := RequireResolve[*TDisposable](t, p)
+		require.NoError(t, disposable.Close())
+
+		require.NoError(t, RestoreSnapshot(p, snapshot))
+		assert.Equal(t, 3, RequireResolve[*TStore](t, p).Count)
+		assert.True(t, disposable.IsClosed())
+	})
+
+	t.Run("scopes_use_their_provider", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTStore))
+		s := NewTestScope(t, p)
+		snapshot, err := TakeSnapshot(s)
+		require.NoError(t, err)
+
+		RequireResolveFrom[*TStore](t, s).Count = 1
+		require.NoError(t, RestoreSnapshot(p, snapshot))
+		assert.Zero(t, RequireResolve[*TStore](t, p).Count)
+	})
+
+	t.Run("invalid_snapshots", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTStore))
+		other := BuildProvider(t, AddSingleton(NewTStore))
+		snapshot, err := TakeSnapshot(other)
+		require.NoError(t, err)
+
+		var validation *ValidationError
+		assert.True(t, errors.As(RestoreSnapshot(p, snapshot), &validation))
+		assert.True(t, errors.As(RestoreSnapshot(p, nil), &validation))
+
+		sealed, err := Seal(p)
+		require.NoError(t, err)
+		_, err = TakeSnapshot(sealed)
+		assert.ErrorIs(t, err, ErrProviderSealed)
+		_, err = TakeSnapshot(nil)
+		assert.ErrorIs(t, err, ErrProviderNil)
+
+		require.NoError(t, p.Close())
+		_, err = TakeSnapshot(p)
+		assert.ErrorIs(t, err, ErrProviderDisposed)
+	})
+}
@@ -0,0 +1,96 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// getSoftGroup resolves a soft group, a group In field tagged
+// soft:"true": only the members that are already constructed are returned,
+// so consumers of optional, costly group members do not construct them just
+// to fill the slice. Singletons are included once constructed (always, for
+// a built provider) and scoped members once this scope has constructed
+// them; transients are never included, and a fallback provider is never
+// consulted.
+func (s *scope) getSoftGroup(r *resolution, serviceType reflect.Type, group string) ([]any, error) {
+	if s.disposed.Load() != 0 {
+		return nil, ErrScopeDisposed
+	}
+
+	if serviceType == nil {
+		return nil, ErrServiceTypeNil
+	}
+
+	if group == "" {
+		return nil, &ValidationError{
+			ServiceType: serviceType,
+			Cause:       ErrGroupNameEmpty,
+		}
+	}
+
+	descriptors := s.rootProvider.findGroupDescriptors(serviceType, group)
+	instances := make([]any, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		key := instanceKey{Type: descriptor.Type, Key: descriptor.Key, Group: descriptor.Group}
+		if !s.constructed(key, descriptor) {
+			continue
+		}
+
+		// The instance is cached, so this only runs the checks and hooks of
+		// a regular resolution.
+		instance, err := s.resolve(r, key, descriptor)
+		if err != nil {
+			if isNotProvided(err, descriptor) {
+				continue
+			}
+			if s.disposed.Load() != 0 {
+				return nil, ErrScopeDisposed
+			}
+			return nil, &ResolutionError{
+				ServiceType: descriptor.Type,
+				ServiceKey:  descriptor.Key,
+				Cause:       fmt.Errorf("failed to resolve group member: %w", r.dependencyError(key, descriptor, err)),
+			}
+		}
+		instances = append(instances, instance)
+	}
+
+	if s.disposed.Load() != 0 {
+		return nil, ErrScopeDisposed
+	}
+	return instances, nil
+}
+
+// constructed reports whether resolving descriptor from s would return a
+// cached instance.
+func (s *scope) constructed(key instanceKey, descriptor *descriptor) bool {
+	switch descriptor.Lifetime {
+	case Singleton:
+		_, ok := s.rootProvider.getSingleton(key)
+		return ok
+	case Scoped:
+		owner := s
+		for owner.inheritScoped {
+			owner = owner.parentScope
+		}
+		_, ok := owner.getInstance(key)
+		return ok
+	default:
+		return false
+	}
+}
+
+func (r *resolution) GetSoftGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return r.scope.getSoftGroup(r, serviceType, group)
+}
+
+func (u untranslated) GetSoftGroup(serviceType reflect.Type, group string) ([]any, error) {
+	return u.s.getSoftGroup(nil, serviceType, group)
+}
+
+func (r sealedResolver) GetSoftGroup(serviceType reflect.Type, group string) ([]any, error) {
+	if serviceType != nil && !r.v.permits(serviceType) {
+		return nil, &CapabilityError{ServiceType: serviceType, Group: group}
+	}
+	return r.v.scope.getSoftGroup(r.r, serviceType, group)
+}
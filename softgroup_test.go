@@ -0,0 +1,104 @@
+package godi
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type softPlugin struct{ name string }
+
+func TestSoftGroup(t *testing.T) {
+	t.Parallel()
+
+	type softConsumer struct {
+		plugins []*softPlugin
+	}
+	type softParams struct {
+		In
+		Plugins []*softPlugin `group:"plugins" soft:"true"`
+	}
+	newConsumer := func(p softParams) *softConsumer { return &softConsumer{plugins: p.Plugins} }
+	names := func(plugins []*softPlugin) []string {
+		out := make([]string, len(plugins))
+		for i, plugin := range plugins {
+			out[i] = plugin.name
+		}
+		return out
+	}
+
+	t.Run("only_constructed_members", func(t *testing.T) {
+		t.Parallel()
+		var costly atomic.Int64
+		p := BuildProvider(t,
+			AddSingleton(func() *softPlugin { return &softPlugin{name: "eager"} }, Group("plugins")),
+			AddScoped(func() *softPlugin {
+				costly.Add(1)
+				return &softPlugin{name: "costly"}
+			}, Group("plugins")),
+			AddTransient(func() *softPlugin { return &softPlugin{name: "transient"} }, Group("plugins")),
+			AddScoped(newConsumer),
+		)
+
+		scope := NewTestScope(t, p)
+		consumer := RequireResolveFrom[*softConsumer](t, scope)
+		assert.Equal(t, []string{"eager"}, names(consumer.plugins))
+		assert.Zero(t, costly.Load(), "soft groups never construct members")
+
+		// Once the scope has constructed the costly plugin for another
+		// reason, later consumers see it.
+		other := NewTestScope(t, p)
+		_, err := ResolveGroup[*softPlugin](other, "plugins")
+		require.NoError(t, err)
+		consumer = RequireResolveFrom[*softConsumer](t, other)
+		assert.Equal(t, []string{"eager", "costly"}, names(consumer.plugins))
+	})
+
+	t.Run("inherited_scoped_members", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddScoped(func() *softPlugin { return &softPlugin{name: "scoped"} }, Group("plugins")),
+			AddScoped(newConsumer),
+		)
+		parent := NewTestScope(t, p)
+		_, err := ResolveGroup[*softPlugin](parent, "plugins")
+		require.NoError(t, err)
+
+		child, err := CreateScope(t.Context(), parent, InheritScoped())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = child.Close() })
+		consumer := RequireResolveFrom[*softConsumer](t, child)
+		assert.Equal(t, []string{"scoped"}, names(consumer.plugins))
+	})
+
+	t.Run("inject", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(func() *softPlugin { return &softPlugin{name: "eager"} }, Group("plugins")),
+			AddScoped(func() *softPlugin { return &softPlugin{name: "scoped"} }, Group("plugins")),
+		)
+		var target struct {
+			Plugins []*softPlugin `group:"plugins" soft:"true"`
+		}
+		scope, err := p.CreateScope(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = scope.Close() })
+		require.NoError(t, Inject(scope, &target))
+		assert.Equal(t, []string{"eager"}, names(target.Plugins))
+	})
+
+	t.Run("requires_group", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			In
+			Plugin *softPlugin `soft:"true"`
+		}
+		c := NewCollection()
+		c.AddSingleton(func(params) *softConsumer { return nil })
+		require.Error(t, c.Err())
+		assert.Contains(t, c.Err().Error(), "soft tag requires a group tag")
+	})
+}
@@ -0,0 +1,282 @@
+// Package sqlx provides a transaction per godi scope for database/sql.
+//
+// Module registers a scoped *sql.Tx, begun the first time a scope resolves
+// it, that is committed or rolled back when the scope closes. Services of a
+// request share the transaction by depending on *sql.Tx, and the handler
+// decides how it finishes through the scoped *Tx.
+//
+// Example usage:
+//
+//	services.AddSingleton(OpenDB) // returns *sql.DB
+//	services.AddModules(godisqlx.Module())
+//	services.AddScoped(NewUserRepository) // takes *sql.Tx
+//
+//	func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+//	    if err := h.repo.Insert(r.Context(), user); err != nil {
+//	        http.Error(w, err.Error(), http.StatusInternalServerError)
+//	        return // the transaction rolls back when the scope closes
+//	    }
+//	    if err := h.tx.Commit(); err != nil {
+//	        http.Error(w, err.Error(), http.StatusInternalServerError)
+//	        return
+//	    }
+//	    w.WriteHeader(http.StatusCreated)
+//	}
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/junioryono/godi/v5"
+)
+
+// ErrTxFailed is returned by Tx.Commit after a function run with
+// Tx.RunInTx has failed; the transaction is rolled back instead.
+var ErrTxFailed = errors.New("transaction failed")
+
+// ErrTxFinished is returned when a scope's transaction is used after it
+// was committed or rolled back.
+var ErrTxFinished = errors.New("transaction already finished")
+
+// Config holds the configuration of Module.
+type Config struct {
+	// TxOptions are passed to sql.DB.BeginTx when a scope's transaction
+	// begins. If nil, the driver's defaults are used.
+	TxOptions *sql.TxOptions
+}
+
+// Option configures Module.
+type Option func(*Config)
+
+// WithTxOptions sets the options scope transactions begin with, e.g. the
+// isolation level.
+func WithTxOptions(opts *sql.TxOptions) Option {
+	return func(c *Config) {
+		c.TxOptions = opts
+	}
+}
+
+// Module registers the transaction services for a *sql.DB registered
+// elsewhere in the collection:
+//
+//   - *Tx, scoped: the scope's transaction and how it finishes
+//   - *sql.Tx, scoped: the transaction itself, begun when first resolved
+//   - TxRunner, singleton: runs functions in transactions of their own
+//
+// The transaction begins with the values of the scope's context but not
+// its cancellation: a scope cancels its context when it closes, which
+// would roll the transaction back before it could commit. Work abandoned
+// because a request was cancelled is rolled back as incomplete instead.
+func Module(opts ...Option) godi.ModuleOption {
+	config := &Config{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(config)
+		}
+	}
+
+	return godi.NewModule("godi/sqlx",
+		godi.AddScoped(func(ctx context.Context, db *sql.DB) *Tx {
+			return NewTx(ctx, db, config.TxOptions)
+		}),
+		godi.AddScoped(func(tx *Tx) (*sql.Tx, error) {
+			return tx.SQL()
+		}),
+		godi.AddSingleton(NewTxRunner),
+	)
+}
+
+// Tx is a transaction begun lazily and finished when its scope closes.
+//
+// Close, called by the scope, commits the transaction if Complete was
+// called and rolls it back otherwise, so a request that fails or panics
+// before completing never commits. Call Commit instead of Complete to
+// observe commit errors before responding. A transaction that was never
+// begun costs nothing.
+//
+// Tx is safe for concurrent use.
+type Tx struct {
+	ctx  context.Context
+	db   *sql.DB
+	opts *sql.TxOptions
+
+	mu       sync.Mutex
+	tx       *sql.Tx
+	complete bool
+	failed   error
+	finished bool
+}
+
+var (
+	_ TxRunner        = (*Tx)(nil)
+	_ godi.Disposable = (*Tx)(nil)
+)
+
+// NewTx returns a transaction of db that begins with opts and the values
+// of ctx the first time it is used. Module registers one per scope.
+func NewTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) *Tx {
+	return &Tx{ctx: ctx, db: db, opts: opts}
+}
+
+// SQL returns the transaction, beginning it on the first call.
+func (t *Tx) SQL() (*sql.Tx, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.beginLocked()
+}
+
+func (t *Tx) beginLocked() (*sql.Tx, error) {
+	if t.finished {
+		return nil, ErrTxFinished
+	}
+	if t.tx == nil {
+		tx, err := t.db.BeginTx(context.WithoutCancel(t.ctx), t.opts)
+		if err != nil {
+			return nil, fmt.Errorf("begin transaction: %w", err)
+		}
+		t.tx = tx
+	}
+	return t.tx, nil
+}
+
+// RunInTx calls fn with the scope's transaction. Unlike TxRunner
+// implementations that own their transactions, it neither commits nor rolls
+// back: an error from fn, or a panic, marks the transaction failed so that
+// it rolls back when the scope closes. ctx is ignored because the
+// transaction belongs to the scope.
+func (t *Tx) RunInTx(_ context.Context, fn func(*sql.Tx) error) (err error) {
+	tx, err := t.SQL()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			t.fail(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+		if err != nil {
+			t.fail(err)
+		}
+	}()
+	return fn(tx)
+}
+
+func (t *Tx) fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failed == nil {
+		t.failed = err
+	}
+}
+
+// Complete marks the scope's work as done, so the transaction commits when
+// the scope closes. It has no effect once the transaction failed.
+func (t *Tx) Complete() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.complete = true
+}
+
+// Commit commits the transaction now, if it was begun. It returns an error
+// wrapping ErrTxFailed, and rolls back, if a function run with RunInTx
+// failed.
+func (t *Tx) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finishLocked(true)
+}
+
+// Rollback rolls the transaction back now, if it was begun.
+func (t *Tx) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finishLocked(false)
+}
+
+// Close finishes the transaction if Commit or Rollback was not called:
+// it commits if Complete was called and rolls back otherwise.
+func (t *Tx) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.finished {
+		return nil
+	}
+	return t.finishLocked(t.complete && t.failed == nil)
+}
+
+func (t *Tx) finishLocked(commit bool) error {
+	if t.finished {
+		return ErrTxFinished
+	}
+	t.finished = true
+	if t.tx == nil {
+		return nil
+	}
+
+	if t.failed != nil {
+		if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			return errors.Join(fmt.Errorf("%w: %w", ErrTxFailed, t.failed), err)
+		}
+		if commit {
+			return fmt.Errorf("%w: %w", ErrTxFailed, t.failed)
+		}
+		return nil
+	}
+
+	if commit {
+		if err := t.tx.Commit(); err != nil {
+			return fmt.Errorf("commit transaction: %w", err)
+		}
+		return nil
+	}
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("roll back transaction: %w", err)
+	}
+	return nil
+}
+
+// TxRunner runs a function in a transaction. Depending on TxRunner instead
+// of *sql.DB or *sql.Tx lets a service run in its own transaction or in the
+// transaction of its scope, depending on what the application wires in.
+type TxRunner interface {
+	RunInTx(ctx context.Context, fn func(*sql.Tx) error) error
+}
+
+// NewTxRunner returns a TxRunner that runs every function in a new
+// transaction of db, committed if fn returns nil and rolled back if it
+// returns an error or panics. Module registers it as a singleton.
+func NewTxRunner(db *sql.DB) TxRunner {
+	return dbRunner{db: db}
+}
+
+type dbRunner struct {
+	db *sql.DB
+}
+
+func (r dbRunner) RunInTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return errors.Join(err, fmt.Errorf("roll back transaction: %w", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/junioryono/godi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver is a database/sql driver whose transactions record how
+// they finished.
+type recordingDriver struct {
+	mu     sync.Mutex
+	events []string
+	begins atomic.Int32
+}
+
+func (d *recordingDriver) record(event string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *recordingDriver) Events() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.events...)
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	n := c.d.begins.Add(1)
+	c.d.record(fmt.Sprintf("begin %d", n))
+	return &recordingTx{d: c.d, n: n}, nil
+}
+
+type recordingTx struct {
+	d *recordingDriver
+	n int32
+}
+
+func (t *recordingTx) Commit() error {
+	t.d.record(fmt.Sprintf("commit %d", t.n))
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.d.record(fmt.Sprintf("rollback %d", t.n))
+	return nil
+}
+
+var driverCount atomic.Int32
+
+func newTestDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	name := fmt.Sprintf("godi-sqlx-test-%d", driverCount.Add(1))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func newTestProvider(t *testing.T, db *sql.DB, opts ...Option) godi.Provider {
+	t.Helper()
+	collection := godi.NewCollection()
+	collection.AddValue(db)
+	collection.AddModules(Module(opts...))
+	provider, err := collection.Build()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Close() })
+	return provider
+}
+
+func newTestScope(t *testing.T, provider godi.Provider) godi.Scope {
+	t.Helper()
+	scope, err := provider.CreateScope(context.Background())
+	require.NoError(t, err)
+	return scope
+}
+
+func TestModule(t *testing.T) {
+	t.Run("begins lazily and rolls back by default", func(t *testing.T) {
+		db, d := newTestDB(t)
+		provider := newTestProvider(t, db)
+
+		unused := newTestScope(t, provider)
+		_, err := godi.Resolve[*Tx](unused)
+		require.NoError(t, err)
+		require.NoError(t, unused.Close())
+		assert.Empty(t, d.Events(), "a transaction that was never used is never begun")
+
+		scope := newTestScope(t, provider)
+		first, err := godi.Resolve[*sql.Tx](scope)
+		require.NoError(t, err)
+		second, err := godi.Resolve[*sql.Tx](scope)
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		require.NoError(t, scope.Close())
+		assert.Equal(t, []string{"begin 1", "rollback 1"}, d.Events())
+	})
+
+	t.Run("commits completed scopes on close", func(t *testing.T) {
+		db, d := newTestDB(t)
+		scope := newTestScope(t, newTestProvider(t, db))
+
+		_, err := godi.Resolve[*sql.Tx](scope)
+		require.NoError(t, err)
+		godi.MustResolve[*Tx](scope).Complete()
+		require.NoError(t, scope.Close())
+		assert.Equal(t, []string{"begin 1", "commit 1"}, d.Events())
+	})
+
+	t.Run("commit finishes the transaction before close", func(t *testing.T) {
+		db, d := newTestDB(t)
+		scope := newTestScope(t, newTestProvider(t, db))
+
+		tx := godi.MustResolve[*Tx](scope)
+		_, err := tx.SQL()
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+		assert.ErrorIs(t, tx.Rollback(), ErrTxFinished)
+		_, err = tx.SQL()
+		assert.ErrorIs(t, err, ErrTxFinished)
+		require.NoError(t, scope.Close())
+		assert.Equal(t, []string{"begin 1", "commit 1"}, d.Events())
+	})
+
+	t.Run("separate scopes get separate transactions", func(t *testing.T) {
+		db, d := newTestDB(t)
+		provider := newTestProvider(t, db)
+
+		a, b := newTestScope(t, provider), newTestScope(t, provider)
+		txA := godi.MustResolve[*sql.Tx](a)
+		txB := godi.MustResolve[*sql.Tx](b)
+		assert.NotSame(t, txA, txB)
+		godi.MustResolve[*Tx](b).Complete()
+		require.NoError(t, a.Close())
+		require.NoError(t, b.Close())
+		assert.ElementsMatch(t, []string{"begin 1", "begin 2", "rollback 1", "commit 2"}, d.Events())
+	})
+
+	t.Run("failed work in the scope transaction rolls back", func(t *testing.T) {
+		db, d := newTestDB(t)
+		scope := newTestScope(t, newTestProvider(t, db))
+		tx := godi.MustResolve[*Tx](scope)
+
+		boom := errors.New("constraint violated")
+		err := tx.RunInTx(context.Background(), func(*sql.Tx) error { return boom })
+		require.ErrorIs(t, err, boom)
+
+		tx.Complete()
+		err = tx.Commit()
+		require.ErrorIs(t, err, ErrTxFailed)
+		require.ErrorIs(t, err, boom)
+		require.NoError(t, scope.Close())
+		assert.Equal(t, []string{"begin 1", "rollback 1"}, d.Events())
+	})
+
+	t.Run("failed work rolls back on close even when completed", func(t *testing.T) {
+		db, d := newTestDB(t)
+		scope := newTestScope(t, newTestProvider(t, db))
+		tx := godi.MustResolve[*Tx](scope)
+
+		assert.Panics(t, func() {
+			_ = tx.RunInTx(context.Background(), func(*sql.Tx) error { panic("boom") })
+		})
+		tx.Complete()
+		require.NoError(t, scope.Close())
+		assert.Equal(t, []string{"begin 1", "rollback 1"}, d.Events())
+	})
+}
+
+func TestTxRunner(t *testing.T) {
+	t.Run("commits on success", func(t *testing.T) {
+		db, d := newTestDB(t)
+		runner := godi.MustResolve[TxRunner](newTestProvider(t, db))
+
+		require.NoError(t, runner.RunInTx(context.Background(), func(*sql.Tx) error { return nil }))
+		assert.Equal(t, []string{"begin 1", "commit 1"}, d.Events())
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		db, d := newTestDB(t)
+		runner := NewTxRunner(db)
+
+		boom := errors.New("boom")
+		require.ErrorIs(t, runner.RunInTx(context.Background(), func(*sql.Tx) error { return boom }), boom)
+		assert.Equal(t, []string{"begin 1", "rollback 1"}, d.Events())
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		db, d := newTestDB(t)
+		runner := NewTxRunner(db)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = runner.RunInTx(context.Background(), func(*sql.Tx) error { panic("boom") })
+		})
+		assert.Equal(t, []string{"begin 1", "rollback 1"}, d.Events())
+	})
+}
@@ -0,0 +1,397 @@
+package godi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DisposableWithContext is implemented by Disposable services whose
+// shutdown can take a while, such as servers draining connections.
+// StopAll calls CloseContext with its context instead of Close, so the
+// service can give up when the shutdown deadline passes. Close on a
+// Provider or Scope still calls Close.
+type DisposableWithContext interface {
+	Disposable
+	CloseContext(ctx context.Context) error
+}
+
+// StopError is returned by StopAll when services failed to stop. Failures
+// are keyed by service type; goroutines still running when a scope's
+// ScopeWaitGroup wait gave up are reported under *ScopeWaitGroup.
+type StopError struct {
+	Failures map[reflect.Type][]error
+}
+
+func (e StopError) Error() string {
+	types := e.types()
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to stop %d service type(s)", len(types))
+	for _, t := range types {
+		for _, err := range e.Failures[t] {
+			fmt.Fprintf(&b, "\n  %s: %v", formatType(t), err)
+		}
+	}
+	return b.String()
+}
+
+func (e StopError) Unwrap() []error {
+	var errs []error
+	for _, t := range e.types() {
+		errs = append(errs, e.Failures[t]...)
+	}
+	return errs
+}
+
+func (e StopError) types() []reflect.Type {
+	types := make([]reflect.Type, 0, len(e.Failures))
+	for t := range e.Failures {
+		types = append(types, t)
+	}
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return strings.Compare(formatType(a), formatType(b))
+	})
+	return types
+}
+
+// StopAll closes p like Close, bounded by ctx. For a provider, the
+// services that depend on others are stopped before their dependencies
+// across all open scopes, independent of the order they were created in,
+// and singletons are stopped after every scope. For a scope, the scope and
+// its child scopes are stopped that way and singletons are left to the
+// provider. Services implementing DisposableWithContext receive ctx;
+// waiting for ScopeWaitGroup goroutines gives up when ctx is done. Every
+// service is still closed after ctx is done, so nothing leaks.
+//
+// Failures are returned as a *StopError keyed by service type. Once
+// StopAll returns, p is closed and Close returns the same error.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := godi.StopAll(ctx, provider); err != nil {
+//	    log.Printf("shutdown: %v", err)
+//	}
+func StopAll(ctx context.Context, p Provider) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	switch v := p.(type) {
+	case nil:
+		return ErrProviderNil
+	case graceful:
+		return v.StopAll(ctx)
+	default:
+		return errUnsupportedProvider(p)
+	}
+}
+
+// graceful is implemented by the providers and scopes of this package.
+type graceful interface {
+	StopAll(ctx context.Context) error
+}
+
+// StopAll stops the provider; see godi.StopAll.
+func (p *provider) StopAll(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return p.shutdown(ctx)
+}
+
+// shutdown closes the provider for Close, when ctx is nil, and StopAll.
+// Both dispose in the same order; Close calls Close rather than
+// CloseContext, waits for ScopeWaitGroup goroutines as long as configured,
+// and reports failures as a *DisposalError.
+func (p *provider) shutdown(ctx context.Context) (result error) {
+	if !p.disposed.CompareAndSwap(0, 1) {
+		var done <-chan struct{}
+		if ctx != nil {
+			done = ctx.Done()
+		}
+		select {
+		case <-p.closeDone:
+			return p.closeErr
+		case <-done:
+			return ctx.Err()
+		}
+	}
+	defer func() {
+		p.closeErr = result
+		close(p.closeDone)
+	}()
+
+	st := newStopper(p, ctx)
+	scopes, err := p.takeOpenScopes()
+	if err != nil {
+		st.fail(scopeType, err)
+	}
+	// The root scope is deliberately not nil-ed: concurrent Get calls read
+	// it without synchronization, and once closed it rejects resolution
+	// with ErrScopeDisposed.
+	if p.rootScope != nil {
+		scopes = append(scopes, p.rootScope)
+	}
+
+	st.stopScopes(scopes)
+	// Pooled scopes retain instances that may depend on singletons.
+	st.stop(st.entries(p.drainScopePool(), nil))
+
+	// Retire refreshing instances before the singletons they depend on.
+	for d, state := range p.refreshing {
+		if err := state.close(); err != nil {
+			st.fail(d.Type, fmt.Errorf("refreshing instance: %w", err))
+		}
+	}
+
+	st.stop(st.entries(p.takeDisposables(), nil))
+	p.clearSingletons()
+	return st.err()
+}
+
+// StopAll stops the scope and its child scopes; see godi.StopAll.
+func (s *scope) StopAll(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	st := newStopper(s.rootProvider, ctx)
+	if closed := st.stopScopes(s.descendants()); !slices.Contains(closed, s) {
+		// Another Close or StopAll got there first.
+		select {
+		case <-s.closeDone:
+			return s.closeErr
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return st.err()
+}
+
+// descendants returns s and all its open descendant scopes.
+func (s *scope) descendants() []*scope {
+	scopes := []*scope{s}
+	for i := 0; i < len(scopes); i++ {
+		scopes[i].childrenMu.Lock()
+		for child := range scopes[i].children {
+			scopes = append(scopes, child)
+		}
+		scopes[i].childrenMu.Unlock()
+	}
+	return scopes
+}
+
+// stopper carries out StopAll, and Close when ctx is nil.
+type stopper struct {
+	p        *provider
+	ctx      context.Context
+	levels   map[*descriptor]int
+	failures map[reflect.Type][]error
+	errs     []error // the failures in the order they happened, for Close
+}
+
+// stopEntry is one disposable to stop.
+type stopEntry struct {
+	trackedDisposable
+	scope *scope // nil for singletons
+	level int    // longest dependency chain below the service
+	depth int    // nesting depth of the scope
+	index int    // creation order within the scope
+}
+
+func newStopper(p *provider, ctx context.Context) *stopper {
+	return &stopper{p: p, ctx: ctx, levels: dependencyLevels(p), failures: make(map[reflect.Type][]error)}
+}
+
+// stopScopes closes scopes together: their contexts are cancelled, their
+// goroutines awaited, and then their services stopped in dependency order.
+// Scopes already being closed are left to their Close call; the others are
+// returned.
+func (st *stopper) stopScopes(scopes []*scope) []*scope {
+	var closing []*scope
+	for _, s := range scopes {
+		if s.disposed.CompareAndSwap(0, 1) {
+			if s.cancel != nil {
+				s.cancel()
+			}
+			closing = append(closing, s)
+		}
+	}
+
+	var entries []stopEntry
+	for _, s := range closing {
+		if err := s.waitGroup.wait(st.p.clock, stopWaitTimeout(st.ctx, st.p.scopeWaitTimeout)); err != nil {
+			st.fail(scopeWaitGroupType, fmt.Errorf("scope %s: %w", s.id, err))
+		}
+		entries = append(entries, st.entries(s.takeDisposables(), s)...)
+	}
+
+	scopeErrs := st.stop(entries)
+	for _, s := range closing {
+		s.childrenMu.Lock()
+		s.children = nil
+		s.childrenMu.Unlock()
+		s.unlink()
+
+		if errs := scopeErrs[s]; len(errs) > 0 {
+			s.closeErr = &DisposalError{Context: "scope", Errors: errs}
+		}
+		close(s.closeDone)
+	}
+	return closing
+}
+
+// entries wraps the disposables of one scope, or the singletons when s is
+// nil, for stop.
+func (st *stopper) entries(disposables []trackedDisposable, s *scope) []stopEntry {
+	depth := 0
+	if s != nil {
+		for parent := s.parentScope; parent != nil; parent = parent.parentScope {
+			depth++
+		}
+	}
+
+	entries := make([]stopEntry, 0, len(disposables))
+	for i, d := range disposables {
+		if d.Disposable == nil {
+			continue
+		}
+		level := math.MaxInt // unknown services are stopped first
+		if d.descriptor != nil {
+			level = st.levels[d.descriptor]
+		}
+		entries = append(entries, stopEntry{trackedDisposable: d, scope: s, level: level, depth: depth, index: i})
+	}
+	return entries
+}
+
+// stop closes entries, consumers before their dependencies. Services at
+// the same level are stopped children scopes first, newest first. It
+// returns the errors per scope.
+func (st *stopper) stop(entries []stopEntry) map[*scope][]error {
+	slices.SortStableFunc(entries, func(a, b stopEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.level, a.level),
+			cmp.Compare(b.depth, a.depth),
+			cmp.Compare(b.index, a.index),
+		)
+	})
+
+	errs := make(map[*scope][]error)
+	for _, e := range entries {
+		var err error
+		if withContext, ok := e.Disposable.(DisposableWithContext); ok && st.ctx != nil {
+			err = safeCloseContext(st.ctx, withContext)
+		} else {
+			err = safeClose(e.Disposable)
+		}
+		if err == nil {
+			continue
+		}
+
+		serviceType := reflect.TypeOf(e.Disposable)
+		if e.descriptor != nil {
+			serviceType = e.descriptor.Type
+		}
+		st.failures[serviceType] = append(st.failures[serviceType], err)
+		st.errs = append(st.errs, fmt.Errorf("%s: %w", formatType(serviceType), err))
+		errs[e.scope] = append(errs[e.scope], err)
+	}
+	return errs
+}
+
+func (st *stopper) fail(serviceType reflect.Type, err error) {
+	st.failures[serviceType] = append(st.failures[serviceType], err)
+	st.errs = append(st.errs, err)
+}
+
+func (st *stopper) err() error {
+	switch {
+	case len(st.errs) == 0:
+		return nil
+	case st.ctx == nil:
+		return &DisposalError{Context: "provider", Errors: st.errs}
+	default:
+		return &StopError{Failures: st.failures}
+	}
+}
+
+// safeCloseContext is safeClose for DisposableWithContext.
+func safeCloseContext(ctx context.Context, d DisposableWithContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during CloseContext: %v", r)
+		}
+	}()
+	return d.CloseContext(ctx)
+}
+
+// stopWaitTimeout bounds a ScopeWaitGroup wait by the configured timeout
+// and ctx's deadline, if there is a ctx.
+func stopWaitTimeout(ctx context.Context, configured time.Duration) time.Duration {
+	if ctx == nil {
+		return configured
+	}
+	if ctx.Err() != nil {
+		return time.Nanosecond
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return configured
+	}
+	remaining := max(time.Until(deadline), time.Nanosecond)
+	if configured > 0 && configured < remaining {
+		return configured
+	}
+	return remaining
+}
+
+// dependencyLevels returns the length of the longest dependency chain
+// below each of the provider's registrations: 0 for services without
+// registered dependencies, and more than each of its dependencies' for the
+// rest.
+func dependencyLevels(p *provider) map[*descriptor]int {
+	levels := make(map[*descriptor]int, len(p.descriptors))
+	visiting := make(map[*descriptor]bool)
+
+	var level func(d *descriptor) int
+	level = func(d *descriptor) int {
+		if l, ok := levels[d]; ok {
+			return l
+		}
+		if visiting[d] {
+			return 0 // cycles are rejected at build time
+		}
+		visiting[d] = true
+
+		l := 0
+		for _, dep := range d.Dependencies {
+			var deps []*descriptor
+			if dep.Group != "" {
+				deps = p.findGroupDescriptors(dep.Type, dep.Group)
+			} else if found := p.findDescriptor(dep.Type, dep.Key); found != nil {
+				deps = []*descriptor{found}
+			}
+			for _, found := range deps {
+				l = max(l, level(found)+1)
+			}
+		}
+
+		delete(visiting, d)
+		levels[d] = l
+		return l
+	}
+
+	for _, d := range p.descriptors {
+		if d != nil {
+			level(d)
+		}
+	}
+	return levels
+}
@@ -0,0 +1,236 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stopLog struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (l *stopLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = append(l.stopped, name)
+}
+
+func (l *stopLog) names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.stopped...)
+}
+
+type stopDB struct {
+	log *stopLog
+	err error
+}
+
+func (d *stopDB) Close() error {
+	d.log.add("db")
+	return d.err
+}
+
+type stopRepo struct {
+	log   *stopLog
+	scope string
+}
+
+func (r *stopRepo) Close() error {
+	r.log.add("repo " + r.scope)
+	return nil
+}
+
+type stopServer struct {
+	log *stopLog
+	ctx context.Context
+}
+
+func (s *stopServer) Close() error {
+	s.log.add("server Close")
+	return nil
+}
+
+func (s *stopServer) CloseContext(ctx context.Context) error {
+	s.ctx = ctx
+	s.log.add("server")
+	return nil
+}
+
+func TestStopAll(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, log *stopLog, dbErr error) Provider {
+		t.Helper()
+		return BuildProvider(t,
+			AddSingleton(func() *stopLog { return log }),
+			AddSingleton(func(log *stopLog) *stopDB { return &stopDB{log: log, err: dbErr} }),
+			AddScoped(func(log *stopLog, _ *stopDB, s Scope) *stopRepo { return &stopRepo{log: log, scope: s.ID()} }),
+			AddScoped(func(log *stopLog, _ *stopRepo) *stopServer { return &stopServer{log: log} }),
+		)
+	}
+
+	t.Run("stops_consumers_before_dependencies_across_scopes", func(t *testing.T) {
+		t.Parallel()
+
+		log := &stopLog{}
+		provider := newProvider(t, log, nil)
+
+		first, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+		second, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		// The first scope only has a repository; the second scope's server
+		// is created after it but must still be stopped first.
+		_, err = Resolve[*stopRepo](first)
+		require.NoError(t, err)
+		_, err = Resolve[*stopServer](second)
+		require.NoError(t, err)
+
+		require.NoError(t, StopAll(context.Background(), provider))
+
+		stopped := log.names()
+		require.Len(t, stopped, 4)
+		assert.Equal(t, "server", stopped[0])
+		assert.ElementsMatch(t, []string{"repo " + first.ID(), "repo " + second.ID()}, stopped[1:3])
+		assert.Equal(t, "db", stopped[3])
+
+		_, err = Resolve[*stopRepo](first)
+		assert.ErrorIs(t, err, ErrScopeDisposed)
+		assert.NoError(t, provider.Close())
+	})
+
+	t.Run("close_stops_in_the_same_order", func(t *testing.T) {
+		t.Parallel()
+
+		log := &stopLog{}
+		provider := newProvider(t, log, nil)
+
+		first, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+		second, err := provider.CreateScope(context.Background())
+		require.NoError(t, err)
+		_, err = Resolve[*stopRepo](first)
+		require.NoError(t, err)
+		_, err = Resolve[*stopServer](second)
+		require.NoError(t, err)
+
+		require.NoError(t, provider.Close())
+
+		stopped := log.names()
+		require.Len(t, stopped, 4)
+		assert.Equal(t, "server Close", stopped[0], "Close calls Close, not CloseContext")
+		assert.ElementsMatch(t, []string{"repo " + first.ID(), "repo " + second.ID()}, stopped[1:3])
+		assert.Equal(t, "db", stopped[3])
+	})
+
+	t.Run("passes_context_to_disposables_with_context", func(t *testing.T) {
+		t.Parallel()
+
+		log := &stopLog{}
+		provider := newProvider(t, log, nil)
+		scope := NewTestScope(t, provider)
+		server, err := Resolve[*stopServer](scope)
+		require.NoError(t, err)
+
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "shutdown")
+		require.NoError(t, StopAll(ctx, provider))
+
+		require.NotNil(t, server.ctx)
+		assert.Equal(t, "shutdown", server.ctx.Value(ctxKey{}))
+		assert.NotContains(t, log.names(), "server Close")
+	})
+
+	t.Run("returns_failures_keyed_by_service_type", func(t *testing.T) {
+		t.Parallel()
+
+		closeErr := errors.New("connection reset")
+		provider := newProvider(t, &stopLog{}, closeErr)
+		_, err := Resolve[*stopDB](provider)
+		require.NoError(t, err)
+
+		err = StopAll(context.Background(), provider)
+		require.ErrorIs(t, err, closeErr)
+
+		stopErr, ok := errors.AsType[*StopError](err)
+		require.True(t, ok)
+		assert.Equal(t, []error{closeErr}, stopErr.Failures[reflect.TypeFor[*stopDB]()])
+		assert.Contains(t, err.Error(), "*stopDB: connection reset")
+
+		assert.Same(t, err, provider.Close(), "Close must report the StopAll result")
+	})
+
+	t.Run("bounds_scope_wait_group_by_context", func(t *testing.T) {
+		t.Parallel()
+
+		provider := BuildProvider(t)
+		scope := NewTestScope(t, provider)
+		wg, err := Resolve[*ScopeWaitGroup](scope)
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		defer close(release)
+		wg.Go(func() { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err = StopAll(ctx, provider)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		stopErr, ok := errors.AsType[*StopError](err)
+		require.True(t, ok)
+		assert.Len(t, stopErr.Failures[reflect.TypeFor[*ScopeWaitGroup]()], 1)
+	})
+
+	t.Run("stops_a_single_scope", func(t *testing.T) {
+		t.Parallel()
+
+		log := &stopLog{}
+		provider := newProvider(t, log, nil)
+		parent := NewTestScope(t, provider)
+		child, err := parent.CreateScope(context.Background())
+		require.NoError(t, err)
+
+		_, err = Resolve[*stopRepo](child)
+		require.NoError(t, err)
+		_, err = Resolve[*stopServer](parent)
+		require.NoError(t, err)
+
+		require.NoError(t, StopAll(context.Background(), parent))
+		assert.Equal(t, []string{"server", "repo " + child.ID(), "repo " + parent.ID()}, log.names())
+		assert.NoError(t, child.Close())
+
+		_, err = Resolve[*stopDB](provider)
+		assert.NoError(t, err, "singletons are left to the provider")
+	})
+
+	t.Run("sealed_views", func(t *testing.T) {
+		t.Parallel()
+
+		provider := BuildProvider(t)
+		sealed, err := Seal(provider)
+		require.NoError(t, err)
+		assert.ErrorIs(t, StopAll(context.Background(), sealed), ErrProviderSealed)
+
+		scope, err := sealed.CreateScope(context.Background())
+		require.NoError(t, err)
+		assert.NoError(t, StopAll(context.Background(), scope))
+	})
+
+	t.Run("nil_provider", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, StopAll(context.Background(), nil), ErrProviderNil)
+	})
+}
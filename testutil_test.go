@@ -251,6 +251,15 @@ func BuildScope(t *testing.T, opts ...ModuleOption) Scope {
 	return s
 }
 
+// NewTestScope creates a scope from p that is closed when the test ends.
+func NewTestScope(t *testing.T, p Provider) Scope {
+	t.Helper()
+	s, err := p.CreateScope(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
 // BuildCollection creates a collection with the given module options.
 func BuildCollection(t *testing.T, opts ...ModuleOption) Collection {
 	t.Helper()
@@ -285,11 +294,6 @@ func RequireResolveKeyed[T any](t *testing.T, p Provider, key any) T {
 	return v
 }
 
-// TypeOf returns the reflect.Type for a type parameter.
-func TypeOf[T any]() reflect.Type {
-	return reflect.TypeFor[T]()
-}
-
 // PtrTypeOf returns the reflect.Type for a pointer to the type parameter.
 func PtrTypeOf[T any]() reflect.Type {
 	return reflect.TypeFor[*T]()
@@ -0,0 +1,262 @@
+package godi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TraceJSON is a trace captured by CaptureTrace, encoded as JSON.
+// It has three parts:
+//
+//   - "provider", "start" and "durationNs" describe the capture itself,
+//     with "error" set when the captured function failed.
+//   - "resolutions" lists every resolution of a registered service, in the
+//     order they finished, so dependencies come before the services that
+//     needed them. Each names the service's type, key and group, the
+//     scope that resolved it, its path from the service originally
+//     requested, whether it came from a cache, its start offset and
+//     duration in nanoseconds, and its error, if any.
+//   - "scopes" is the scope tree: the scopes open when the capture ended
+//     and those that resolved services during it, each with its parent,
+//     name, creation time, number of cached instances and whether it has
+//     been closed.
+//
+// The format is meant for people reading bug reports and may gain fields
+// between releases.
+type TraceJSON []byte
+
+// MarshalJSON returns t itself, so a trace can be embedded in a larger JSON
+// document as is.
+func (t TraceJSON) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+	return t, nil
+}
+
+type traceDocument struct {
+	Provider    string            `json:"provider"`
+	Start       time.Time         `json:"start"`
+	DurationNS  int64             `json:"durationNs"`
+	Error       string            `json:"error,omitempty"`
+	Resolutions []traceResolution `json:"resolutions"`
+	Scopes      []traceScope      `json:"scopes"`
+}
+
+type traceResolution struct {
+	Type       string   `json:"type"`
+	Key        string   `json:"key,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Lifetime   string   `json:"lifetime,omitempty"`
+	Scope      string   `json:"scope"`
+	Path       []string `json:"path,omitempty"`
+	Cached     bool     `json:"cached"`
+	StartNS    int64    `json:"startNs"`
+	DurationNS int64    `json:"durationNs"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type traceScope struct {
+	ID        string    `json:"id"`
+	Parent    string    `json:"parent,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Created   time.Time `json:"created"`
+	Instances int       `json:"instances"`
+	Closed    bool      `json:"closed,omitempty"`
+}
+
+// traceRecorder collects the resolutions of one CaptureTrace call. Its
+// fields are guarded by provider.tracersMu.
+type traceRecorder struct {
+	start       time.Time
+	resolutions []traceResolution
+	scopes      map[string]*scope
+}
+
+// CaptureTrace calls fn and returns a trace of what the container p
+// belongs to did meanwhile: every resolution of a registered service, with
+// its duration and whether it was cached, and the scope tree. Attach it to
+// bug reports so the team owning the container can see its exact behavior
+// without reproducing the problem. Resolutions made by other goroutines during fn
+// are captured too.
+//
+// The trace is returned even when fn fails, with fn's error, since failures
+// are usually what needs explaining. If ctx is done before fn is called,
+// CaptureTrace returns ctx's error without calling fn.
+//
+// Example:
+//
+//	trace, err := godi.CaptureTrace(ctx, provider, func() error {
+//	    _, err := godi.Resolve[*OrderService](scope)
+//	    return err
+//	})
+//	os.WriteFile("godi-trace.json", trace, 0o644)
+func CaptureTrace(ctx context.Context, p Provider, fn func() error) (TraceJSON, error) {
+	root, err := providerOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return root.captureTrace(ctx, fn)
+}
+
+func (p *provider) captureTrace(ctx context.Context, fn func() error) (TraceJSON, error) {
+	if fn == nil {
+		return nil, &ValidationError{Cause: fmt.Errorf("CaptureTrace needs a function to trace")}
+	}
+	if p.disposed.Load() != 0 {
+		return nil, ErrProviderDisposed
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	recorder := &traceRecorder{start: time.Now(), scopes: make(map[string]*scope)}
+	p.tracersMu.Lock()
+	p.tracers = append(p.tracers, recorder)
+	p.tracing.Add(1)
+	p.tracersMu.Unlock()
+
+	err := fn()
+	duration := time.Since(recorder.start)
+
+	p.tracersMu.Lock()
+	p.tracers = slices.DeleteFunc(p.tracers, func(r *traceRecorder) bool { return r == recorder })
+	p.tracing.Add(-1)
+	p.tracersMu.Unlock()
+
+	doc := traceDocument{
+		Provider:    p.id,
+		Start:       recorder.start,
+		DurationNS:  int64(duration),
+		Resolutions: recorder.resolutions,
+		Scopes:      p.traceScopes(recorder.scopes),
+	}
+	if err != nil {
+		doc.Error = err.Error()
+	}
+	if doc.Resolutions == nil {
+		doc.Resolutions = []traceResolution{}
+	}
+	trace, marshalErr := json.Marshal(doc)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	return trace, err
+}
+
+// trace reports a finished resolution to the CaptureTrace calls in
+// progress.
+func (p *provider) trace(s *scope, event *ResolutionEvent, lifetime Lifetime, start time.Time) {
+	resolution := traceResolution{
+		Type:       event.ServiceType.String(),
+		Group:      event.Group,
+		Lifetime:   lifetime.String(),
+		Scope:      s.id,
+		Cached:     event.Cached,
+		DurationNS: int64(event.Duration),
+	}
+	if event.ServiceKey != nil {
+		resolution.Key = fmt.Sprint(event.ServiceKey)
+	}
+	if len(event.Path) > 1 {
+		resolution.Path = make([]string, len(event.Path))
+		for i, frame := range event.Path {
+			resolution.Path[i] = frame.ServiceType.String()
+		}
+	}
+	if event.Err != nil {
+		resolution.Error = event.Err.Error()
+	}
+
+	p.tracersMu.Lock()
+	defer p.tracersMu.Unlock()
+	for _, recorder := range p.tracers {
+		recorded := resolution
+		recorded.StartNS = int64(start.Sub(recorder.start))
+		recorder.resolutions = append(recorder.resolutions, recorded)
+		recorder.scopes[s.id] = s
+	}
+}
+
+// traceScopes returns the open scopes of the provider together with seen,
+// the scopes that resolved services during a capture, parents first.
+func (p *provider) traceScopes(seen map[string]*scope) []traceScope {
+	var scopes []traceScope
+	listed := make(map[*scope]bool)
+	var add func(s *scope)
+	add = func(s *scope) {
+		if listed[s] {
+			return
+		}
+		if s.parentScope != nil && s.parentScope != p.rootScope {
+			add(s.parentScope)
+		}
+		listed[s] = true
+
+		info := s.info()
+		s.instancesMu.RLock()
+		instances := len(s.instances)
+		s.instancesMu.RUnlock()
+		scopes = append(scopes, traceScope{
+			ID:        info.ID,
+			Parent:    info.ParentID,
+			Name:      info.Name,
+			Created:   info.Created,
+			Instances: instances,
+			Closed:    s.isDisposed(),
+		})
+
+		s.childrenMu.Lock()
+		children := make([]*scope, 0, len(s.children))
+		for child := range s.children {
+			children = append(children, child)
+		}
+		s.childrenMu.Unlock()
+		sortScopes(children)
+		for _, child := range children {
+			add(child)
+		}
+	}
+
+	add(p.rootScope)
+
+	p.scopesMu.Lock()
+	open := make([]*scope, 0, len(p.scopes))
+	for s := range p.scopes {
+		open = append(open, s)
+	}
+	p.scopesMu.Unlock()
+	sortScopes(open)
+	for _, s := range open {
+		add(s)
+	}
+
+	closed := make([]*scope, 0, len(seen))
+	for _, s := range seen {
+		closed = append(closed, s)
+	}
+	sortScopes(closed)
+	for _, s := range closed {
+		add(s)
+	}
+	return scopes
+}
+
+// sortScopes orders scopes by creation time, then ID.
+func sortScopes(scopes []*scope) {
+	slices.SortFunc(scopes, func(a, b *scope) int {
+		if c := a.created.Compare(b.created); c != 0 {
+			return c
+		}
+		if a.id < b.id {
+			return -1
+		}
+		if a.id > b.id {
+			return 1
+		}
+		return 0
+	})
+}
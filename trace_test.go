@@ -0,0 +1,191 @@
+package godi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodedTrace is the subset of TraceJSON the tests check.
+type decodedTrace struct {
+	Provider    string `json:"provider"`
+	Error       string `json:"error"`
+	Resolutions []struct {
+		Type     string   `json:"type"`
+		Key      string   `json:"key"`
+		Lifetime string   `json:"lifetime"`
+		Scope    string   `json:"scope"`
+		Path     []string `json:"path"`
+		Cached   bool     `json:"cached"`
+		Error    string   `json:"error"`
+	} `json:"resolutions"`
+	Scopes []struct {
+		ID     string `json:"id"`
+		Parent string `json:"parent"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"scopes"`
+}
+
+func decodeTrace(t *testing.T, trace TraceJSON) decodedTrace {
+	t.Helper()
+	var decoded decodedTrace
+	require.NoError(t, json.Unmarshal(trace, &decoded))
+	return decoded
+}
+
+func TestCaptureTrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records_resolutions_and_scopes", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t,
+			AddSingleton(NewTService),
+			AddScoped(NewTDependency, Name("primary")),
+			AddScoped(func(p struct {
+				In
+				Svc *TService
+				Dep *TDependency `name:"primary"`
+			}) *TServiceWithDeps {
+				return &TServiceWithDeps{Svc: p.Svc, Dep: p.Dep}
+			}),
+		)
+		parent := NewTestScope(t, p)
+
+		var child Scope
+		trace, err := CaptureTrace(context.Background(), p, func() error {
+			var err error
+			child, err = CreateScope(context.Background(), parent, WithScopeName("request"))
+			if err != nil {
+				return err
+			}
+			if _, err := Resolve[*TServiceWithDeps](child); err != nil {
+				return err
+			}
+			_, err = Resolve[*TServiceWithDeps](child)
+			return err
+		})
+		require.NoError(t, err)
+		decoded := decodeTrace(t, trace)
+		assert.Equal(t, p.ID(), decoded.Provider)
+
+		require.Len(t, decoded.Resolutions, 4)
+		svc, dep, first, second := decoded.Resolutions[0], decoded.Resolutions[1], decoded.Resolutions[2], decoded.Resolutions[3]
+		assert.Equal(t, "*godi.TService", svc.Type)
+		assert.True(t, svc.Cached)
+		assert.Equal(t, "Singleton", svc.Lifetime)
+		assert.Equal(t, []string{"*godi.TServiceWithDeps", "*godi.TService"}, svc.Path)
+		assert.Equal(t, "*godi.TDependency", dep.Type)
+		assert.Equal(t, "primary", dep.Key)
+		assert.False(t, dep.Cached)
+		assert.Equal(t, "*godi.TServiceWithDeps", first.Type)
+		assert.Equal(t, child.ID(), first.Scope)
+		assert.Empty(t, first.Path)
+		assert.False(t, first.Cached)
+		assert.True(t, second.Cached)
+
+		var ids []string
+		for _, s := range decoded.Scopes {
+			ids = append(ids, s.ID)
+			if s.ID == child.ID() {
+				assert.Equal(t, parent.ID(), s.Parent)
+				assert.Equal(t, "request", s.Name)
+			}
+		}
+		assert.Contains(t, ids, parent.ID())
+		assert.Contains(t, ids, child.ID())
+	})
+
+	t.Run("returns_the_trace_of_a_failure", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		p := BuildProvider(t, AddScoped(func() (*TDependency, error) { return nil, boom }))
+		s := NewTestScope(t, p)
+
+		trace, err := CaptureTrace(context.Background(), p, func() error {
+			_, err := Resolve[*TDependency](s)
+			return err
+		})
+		require.ErrorIs(t, err, boom)
+		decoded := decodeTrace(t, trace)
+		assert.Contains(t, decoded.Error, "boom")
+		require.Len(t, decoded.Resolutions, 1)
+		assert.Contains(t, decoded.Resolutions[0].Error, "boom")
+	})
+
+	t.Run("lists_closed_scopes_that_resolved_services", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDependency))
+
+		var id string
+		trace, err := CaptureTrace(context.Background(), p, func() error {
+			s, err := p.CreateScope(context.Background())
+			if err != nil {
+				return err
+			}
+			id = s.ID()
+			if _, err := Resolve[*TDependency](s); err != nil {
+				return err
+			}
+			return s.Close()
+		})
+		require.NoError(t, err)
+
+		decoded := decodeTrace(t, trace)
+		require.NotEmpty(t, decoded.Scopes)
+		last := decoded.Scopes[len(decoded.Scopes)-1]
+		assert.Equal(t, id, last.ID)
+		assert.True(t, last.Closed)
+	})
+
+	t.Run("only_records_during_the_capture", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddTransient(NewTTransient))
+
+		trace, err := CaptureTrace(context.Background(), p, func() error { return nil })
+		require.NoError(t, err)
+		RequireResolve[*TTransient](t, p)
+
+		assert.Empty(t, decodeTrace(t, trace).Resolutions)
+	})
+
+	t.Run("embeds_in_other_documents", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+		trace, err := CaptureTrace(context.Background(), p, func() error { return nil })
+		require.NoError(t, err)
+
+		report, err := json.Marshal(map[string]any{"trace": trace})
+		require.NoError(t, err)
+		var decoded struct{ Trace decodedTrace }
+		require.NoError(t, json.Unmarshal(report, &decoded))
+		assert.Equal(t, p.ID(), decoded.Trace.Provider)
+	})
+
+	t.Run("cancelled_context", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		_, err := CaptureTrace(ctx, p, func() error { called = true; return nil })
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+	})
+
+	t.Run("sealed_and_nil_providers", func(t *testing.T) {
+		t.Parallel()
+		sealed, err := Seal(BuildProvider(t))
+		require.NoError(t, err)
+
+		_, err = CaptureTrace(context.Background(), sealed, func() error { return nil })
+		assert.ErrorIs(t, err, ErrProviderSealed)
+		_, err = CaptureTrace(context.Background(), nil, func() error { return nil })
+		assert.ErrorIs(t, err, ErrProviderNil)
+	})
+}
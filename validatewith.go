@@ -0,0 +1,115 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/junioryono/godi/v5/internal/reflection"
+)
+
+// ValidateWith is an AddOption that checks every instance of type T the
+// registration constructs, before any decorator runs and before it is
+// cached. An instance a check rejects fails the resolution with a
+// ResolutionError whose Cause is an *InstanceCheckError, and is closed if
+// it is Disposable, so a misconfigured client or a pool whose ping failed
+// is never handed out. The next resolution constructs a new instance.
+//
+// Example:
+//
+//	services.AddSingleton(NewPool, godi.ValidateWith(func(p *Pool) error {
+//	    return p.Ping(context.Background())
+//	}))
+//
+// The option may be given several times; the checks run in order. For
+// constructors producing several services, each check applies to the values
+// of its type.
+func ValidateWith[T any](check func(instance T) error) AddOption {
+	o := validateWithOption{serviceType: reflect.TypeFor[T]()}
+	if check != nil {
+		o.check = func(instance any) error {
+			return check(instance.(T))
+		}
+	}
+	return o
+}
+
+type validateWithOption struct {
+	serviceType reflect.Type
+	check       func(instance any) error // instance is a T
+}
+
+func (o validateWithOption) String() string {
+	return fmt.Sprintf("ValidateWith[%s]", formatType(o.serviceType))
+}
+
+func (o validateWithOption) applyAddOption(opts *addOptions) {
+	opts.validators = append(opts.validators, o)
+}
+
+// validateValidateWith checks that d produces a value of the type a
+// ValidateWith option checks.
+func validateValidateWith(d *descriptor, info *reflection.ConstructorInfo, opt validateWithOption) error {
+	if opt.check == nil {
+		return &ValidationError{ServiceType: d.Type, Cause: fmt.Errorf("%s: check function cannot be nil", opt)}
+	}
+
+	// The returns of a result object are its fields.
+	var produced []reflect.Type
+	if d.IsInstance {
+		produced = append(produced, d.Type)
+	} else {
+		for _, ret := range info.Returns {
+			if !ret.IsError {
+				produced = append(produced, ret.Type)
+			}
+		}
+	}
+	for _, t := range produced {
+		// A constructor returning an interface may produce a T at run time.
+		if t.AssignableTo(opt.serviceType) || (t.Kind() == reflect.Interface && opt.serviceType.Implements(t)) {
+			return nil
+		}
+	}
+	return &ValidationError{
+		ServiceType: d.Type,
+		Cause:       fmt.Errorf("%s: registration produces no value of type %s", opt, formatType(opt.serviceType)),
+	}
+}
+
+// checkInstance runs the ValidateWith checks of descriptor that apply to
+// instance. A rejected instance is disposed, since it will never be
+// cached.
+func (s *scope) checkInstance(r *resolution, requested instanceKey, descriptor *descriptor, instance any) error {
+	instanceType := reflect.TypeOf(instance)
+	for _, opt := range descriptor.validators {
+		if !instanceType.AssignableTo(opt.serviceType) {
+			continue
+		}
+		if err := runCheck(opt, instance); err != nil {
+			if d, ok := disposableFor(descriptor, instance); ok {
+				closeOrphan(d)
+			}
+			return &ResolutionError{
+				ServiceType: requested.Type,
+				ServiceKey:  requested.Key,
+				Cause: &InstanceCheckError{
+					ServiceType: instanceType,
+					Check:       opt.serviceType,
+					Cause:       err,
+				},
+				Path: append(r.path(), newResolutionFrame(requested, descriptor)),
+			}
+		}
+	}
+	return nil
+}
+
+// runCheck calls opt's check, returning a panic as an error.
+func runCheck(opt validateWithOption, instance any) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("check panicked: %v", p)
+		}
+	}()
+	return opt.check(instance)
+}
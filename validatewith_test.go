@@ -0,0 +1,132 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireNamed(name string) func(*TDependency) error {
+	return func(d *TDependency) error {
+		if d.Name != name {
+			return errors.New("unexpected name " + d.Name)
+		}
+		return nil
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts_valid_instances", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddSingleton(NewTDependency, ValidateWith(requireNamed("dep"))))
+		assert.Equal(t, "dep", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("rejected_instances_are_not_cached", func(t *testing.T) {
+		t.Parallel()
+		var created []*TDisposable
+		healthy := false
+		p := BuildProvider(t, AddScoped(func() *TDisposable {
+			d := NewTDisposable()
+			created = append(created, d)
+			return d
+		}, ValidateWith(func(*TDisposable) error {
+			if !healthy {
+				return errors.New("ping failed")
+			}
+			return nil
+		})))
+		s := NewTestScope(t, p)
+
+		_, err := Resolve[*TDisposable](s)
+		checkErr, ok := errors.AsType[*InstanceCheckError](err)
+		require.True(t, ok, "expected InstanceCheckError, got %v", err)
+		assert.Equal(t, PtrTypeOf[TDisposable](), checkErr.ServiceType)
+		assert.EqualError(t, checkErr.Cause, "ping failed")
+		resErr, ok := errors.AsType[*ResolutionError](err)
+		require.True(t, ok)
+		require.Len(t, resErr.Path, 1)
+		require.Len(t, created, 1)
+		assert.True(t, created[0].IsClosed(), "a rejected instance is disposed")
+
+		healthy = true
+		d := RequireResolve[*TDisposable](t, s)
+		require.Len(t, created, 2)
+		assert.Same(t, created[1], d)
+		assert.Same(t, d, RequireResolve[*TDisposable](t, s))
+	})
+
+	t.Run("singletons_fail_the_build", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(NewTDependency, ValidateWith(requireNamed("other")))
+		_, err := c.Build()
+		require.ErrorAs(t, err, new(*InstanceCheckError))
+	})
+
+	t.Run("checks_run_before_decorators", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, NewModule("m",
+			AddSingleton(NewTDependency, ValidateWith(requireNamed("dep"))),
+			ModuleDecorate(suffixDependency("+decorated")),
+		))
+		assert.Equal(t, "dep+decorated", RequireResolve[*TDependency](t, p).Name)
+	})
+
+	t.Run("applies_to_values_of_its_type", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() (*TService, *TDependency) {
+			return NewTService(), &TDependency{Name: "bad"}
+		}, ValidateWith(requireNamed("dep")))
+		_, err := c.Build()
+		checkErr, ok := errors.AsType[*InstanceCheckError](err)
+		require.True(t, ok, "expected InstanceCheckError, got %v", err)
+		assert.Equal(t, PtrTypeOf[TDependency](), checkErr.ServiceType)
+	})
+
+	t.Run("checks_concrete_types_behind_interfaces", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() TInterface { return NewTServiceWithID("bad")() }, ValidateWith(func(s *TService) error {
+			return errors.New("rejected " + s.ID)
+		}))
+		_, err := c.Build()
+		require.ErrorContains(t, err, "rejected bad")
+	})
+
+	t.Run("check_panics_are_errors", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddScoped(NewTDependency, ValidateWith(func(*TDependency) error { panic("boom") }))
+		p, err := c.Build()
+		require.NoError(t, err)
+		_, err = Resolve[*TDependency](NewTestScope(t, p))
+		require.ErrorContains(t, err, "check panicked: boom")
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name string
+			opt  AddOption
+			want string
+		}{
+			{"nil", ValidateWith[*TDependency](nil), "check function cannot be nil"},
+			{"other_type", ValidateWith(func(*TService) error { return nil }), "produces no value of type *TService"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				c := NewCollection()
+				c.AddSingleton(NewTDependency, tt.opt)
+				require.Error(t, c.Err())
+				assert.Contains(t, c.Err().Error(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package godi
+
+import (
+	"errors"
+	"reflect"
+)
+
+// GraphValidator enforces an application-specific rule on the registrations
+// of a provider being built, e.g. "repositories must be scoped" or "handlers
+// may not depend on repositories directly". Validators run through
+// ProviderOptions.Validators after godi's own validation; every error they
+// return fails the build.
+type GraphValidator interface {
+	Validate(graph GraphView) []error
+}
+
+// GraphValidatorFunc adapts a function to a GraphValidator.
+type GraphValidatorFunc func(graph GraphView) []error
+
+// Validate calls f(graph).
+func (f GraphValidatorFunc) Validate(graph GraphView) []error {
+	return f(graph)
+}
+
+// GraphView is a read-only view of the dependency graph handed to a
+// GraphValidator. Services are identified by the ServiceInfo values returned
+// from Services.
+type GraphView interface {
+	// Services returns every registration in registration order, like
+	// Collection.ToSlice.
+	Services() []ServiceInfo
+
+	// Dependencies returns the parameters the constructor of service
+	// declares, including those taken through In structs. Built-in types
+	// such as context.Context are included.
+	Dependencies(service ServiceInfo) []DependencyInfo
+
+	// Providers returns the registrations that satisfy dep: the keyed or
+	// unkeyed service it names, or every member of its group. It is empty
+	// for built-in types and missing optional dependencies.
+	Providers(dep DependencyInfo) []ServiceInfo
+
+	// Source describes where service was registered, for error messages.
+	Source(service ServiceInfo) string
+
+	// Descriptors returns the public description of every registration, in
+	// the same order as Services.
+	Descriptors() []Descriptor
+}
+
+// GraphOf returns the dependency graph of the provider p was built as, or
+// that the scope p belongs to, so tests and tooling can inspect wiring
+// without constructing services. A sealed view reports the registrations
+// it exposes, and a composed provider those of each of its providers, in
+// order.
+func GraphOf(p Provider) (GraphView, error) {
+	graph, err := graphOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+func graphOf(p Provider) (*graphView, error) {
+	switch v := p.(type) {
+	case *sealedProvider:
+		return v.graph(), nil
+	case *sealedScope:
+		return v.graph(), nil
+	case *composedProvider:
+		return v.graph()
+	case *composedScope:
+		return v.graph()
+	}
+	root, err := providerOf(p)
+	if err != nil {
+		return nil, err
+	}
+	return newGraphView(root.descriptors, root.services, root.groups), nil
+}
+
+// DependencyInfo describes one dependency of a constructor.
+type DependencyInfo struct {
+	// ServiceType is the requested type; for groups, the element type.
+	ServiceType reflect.Type
+	// Key is the name requested with a `name` tag, or nil.
+	Key any
+	// Group is the group requested with a `group` tag, or "".
+	Group string
+	// Optional reports whether the dependency may be missing.
+	Optional bool
+}
+
+// graphView implements GraphView over a provider's registration snapshot.
+type graphView struct {
+	all         []*descriptor
+	services    []ServiceInfo
+	descriptors map[ServiceInfo]*descriptor
+	byType      map[TypeKey]*descriptor
+	groups      map[GroupKey][]*descriptor
+}
+
+func newGraphView(all []*descriptor, services map[TypeKey]*descriptor, groups map[GroupKey][]*descriptor) *graphView {
+	v := &graphView{
+		all:         all,
+		services:    make([]ServiceInfo, 0, len(all)),
+		descriptors: make(map[ServiceInfo]*descriptor, len(all)),
+		byType:      services,
+		groups:      groups,
+	}
+	for _, d := range all {
+		if d == nil {
+			continue
+		}
+		info := d.serviceInfo()
+		v.services = append(v.services, info)
+		v.descriptors[info] = d
+	}
+	return v
+}
+
+func (v *graphView) Services() []ServiceInfo {
+	return append([]ServiceInfo(nil), v.services...)
+}
+
+func (v *graphView) Dependencies(service ServiceInfo) []DependencyInfo {
+	d := v.descriptors[service]
+	if d == nil {
+		return nil
+	}
+	deps := make([]DependencyInfo, 0, len(d.Dependencies))
+	for _, dep := range d.Dependencies {
+		if dep == nil {
+			continue
+		}
+		deps = append(deps, DependencyInfo{
+			ServiceType: dep.Type,
+			Key:         dep.Key,
+			Group:       dep.Group,
+			Optional:    dep.Optional,
+		})
+	}
+	return deps
+}
+
+func (v *graphView) Providers(dep DependencyInfo) []ServiceInfo {
+	if dep.Group != "" {
+		members := v.groups[GroupKey{Type: dep.ServiceType, Group: dep.Group}]
+		infos := make([]ServiceInfo, 0, len(members))
+		for _, member := range members {
+			infos = append(infos, member.serviceInfo())
+		}
+		return infos
+	}
+	if d := v.byType[TypeKey{Type: dep.ServiceType, Key: dep.Key}]; d != nil {
+		return []ServiceInfo{d.serviceInfo()}
+	}
+	return nil
+}
+
+func (v *graphView) Source(service ServiceInfo) string {
+	if d := v.descriptors[service]; d != nil {
+		return d.source()
+	}
+	return ""
+}
+
+func (v *graphView) Descriptors() []Descriptor {
+	descriptors := make([]Descriptor, 0, len(v.services))
+	for _, d := range v.all {
+		if d != nil {
+			descriptors = append(descriptors, d.public())
+		}
+	}
+	return descriptors
+}
+
+// runGraphValidators runs every validator against view and joins the errors
+// they report.
+func runGraphValidators(validators []GraphValidator, view GraphView) error {
+	var errs []error
+	for _, validator := range validators {
+		if validator == nil {
+			continue
+		}
+		errs = append(errs, validator.Validate(view)...)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,237 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	validatorRepository struct{}
+	validatorService    struct{ repo *validatorRepository }
+	validatorHandler    struct{}
+)
+
+func newValidatorRepository() *validatorRepository { return &validatorRepository{} }
+
+func newValidatorService(repo *validatorRepository) *validatorService {
+	return &validatorService{repo: repo}
+}
+
+func TestGraphValidators(t *testing.T) {
+	t.Parallel()
+
+	buildWith := func(t *testing.T, validators []GraphValidator, modules ...ModuleOption) (Provider, error) {
+		t.Helper()
+		c := NewCollection()
+		c.AddModules(modules...)
+		p, err := c.BuildWithOptions(&ProviderOptions{Validators: validators})
+		if p != nil {
+			t.Cleanup(func() { _ = p.Close() })
+		}
+		return p, err
+	}
+
+	errNotScoped := errors.New("repositories must be scoped")
+	repositoriesScoped := GraphValidatorFunc(func(g GraphView) []error {
+		var errs []error
+		for _, svc := range g.Services() {
+			if strings.Contains(svc.ServiceType.String(), "Repository") && svc.Lifetime != Scoped {
+				errs = append(errs, fmt.Errorf("%v (%s): %w", svc.ServiceType, g.Source(svc), errNotScoped))
+			}
+		}
+		return errs
+	})
+
+	t.Run("valid_graph_builds", func(t *testing.T) {
+		t.Parallel()
+		_, err := buildWith(t, []GraphValidator{repositoriesScoped, nil},
+			AddScoped(newValidatorRepository),
+			AddScoped(newValidatorService),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("violations_fail_build", func(t *testing.T) {
+		t.Parallel()
+		_, err := buildWith(t, []GraphValidator{repositoriesScoped},
+			AddSingleton(newValidatorRepository),
+			AddScoped(newValidatorService),
+		)
+		require.ErrorIs(t, err, errNotScoped)
+
+		buildErr, ok := errors.AsType[*BuildError](err)
+		require.True(t, ok)
+		assert.Equal(t, "validation", buildErr.Phase)
+		assert.Contains(t, err.Error(), "validator_test.go", "source points at the constructor")
+	})
+
+	t.Run("errors_of_all_validators_are_reported", func(t *testing.T) {
+		t.Parallel()
+		errFirst, errSecond := errors.New("first"), errors.New("second")
+		_, err := buildWith(t, []GraphValidator{
+			GraphValidatorFunc(func(GraphView) []error { return []error{errFirst} }),
+			GraphValidatorFunc(func(GraphView) []error { return []error{errSecond} }),
+		})
+		require.ErrorIs(t, err, errFirst)
+		require.ErrorIs(t, err, errSecond)
+	})
+
+	t.Run("layering_through_providers", func(t *testing.T) {
+		t.Parallel()
+		handlerType := PtrTypeOf[validatorHandler]()
+		repoType := PtrTypeOf[validatorRepository]()
+		noDirectRepository := GraphValidatorFunc(func(g GraphView) []error {
+			var errs []error
+			for _, svc := range g.Services() {
+				if svc.ServiceType != handlerType {
+					continue
+				}
+				for _, dep := range g.Dependencies(svc) {
+					for _, target := range g.Providers(dep) {
+						if target.ServiceType == repoType {
+							errs = append(errs, fmt.Errorf("handler depends on repository %v", target.Key))
+						}
+					}
+				}
+			}
+			return errs
+		})
+
+		_, err := buildWith(t, []GraphValidator{noDirectRepository},
+			AddScoped(newValidatorRepository),
+			AddScoped(newValidatorService),
+			AddScoped(func(*validatorService) *validatorHandler { return &validatorHandler{} }),
+		)
+		require.NoError(t, err, "depending on a repository through a service is allowed")
+
+		_, err = buildWith(t, []GraphValidator{noDirectRepository},
+			AddScoped(newValidatorRepository, Name("orders")),
+			AddScoped(func(struct {
+				In
+				Repo *validatorRepository `name:"orders"`
+			}) *validatorHandler {
+				return &validatorHandler{}
+			}),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "handler depends on repository orders")
+	})
+
+	t.Run("dependencies_and_groups", func(t *testing.T) {
+		t.Parallel()
+		var seen []DependencyInfo
+		var members, missing []ServiceInfo
+		inspect := GraphValidatorFunc(func(g GraphView) []error {
+			for _, svc := range g.Services() {
+				if svc.ServiceType != PtrTypeOf[validatorHandler]() {
+					continue
+				}
+				seen = g.Dependencies(svc)
+				for _, dep := range seen {
+					if dep.Group != "" {
+						members = g.Providers(dep)
+					}
+					if dep.Optional {
+						missing = append(missing, g.Providers(dep)...)
+					}
+				}
+			}
+			return nil
+		})
+
+		_, err := buildWith(t, []GraphValidator{inspect},
+			AddSingleton(NewTService, Group("services")),
+			AddSingleton(NewTService, Group("services")),
+			AddScoped(func(struct {
+				In
+				All []*TService       `group:"services"`
+				Dep *TDependency      `optional:"true"`
+				Res *validatorService `optional:"true"`
+			}) *validatorHandler {
+				return &validatorHandler{}
+			}),
+		)
+		require.NoError(t, err)
+		require.Len(t, seen, 3)
+		assert.Equal(t, "services", seen[0].Group)
+		assert.True(t, seen[1].Optional)
+		assert.Len(t, members, 2)
+		assert.Empty(t, missing, "missing optional dependencies have no providers")
+	})
+}
+
+func TestGraphOf(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollection()
+	c.AddModules(
+		AddScoped(newValidatorRepository),
+		AddScoped(newValidatorService),
+	)
+
+	t.Run("collection_graph_is_a_snapshot", func(t *testing.T) {
+		t.Parallel()
+		graph := NewCollection().Graph()
+		assert.Empty(t, graph.Services())
+
+		c := NewCollection()
+		c.AddScoped(newValidatorService)
+		graph = c.Graph()
+		c.AddScoped(newValidatorRepository)
+		require.Len(t, graph.Services(), 1)
+		deps := graph.Dependencies(graph.Services()[0])
+		require.Len(t, deps, 1)
+		assert.Empty(t, graph.Providers(deps[0]), "unvalidated graphs may have missing dependencies")
+	})
+
+	t.Run("provider_and_scope", func(t *testing.T) {
+		t.Parallel()
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		for _, source := range []Provider{p, NewTestScope(t, p)} {
+			graph, err := GraphOf(source)
+			require.NoError(t, err)
+			require.Len(t, graph.Services(), 2)
+			svc := graph.Services()[1]
+			assert.Equal(t, PtrTypeOf[validatorService](), svc.ServiceType)
+			deps := graph.Dependencies(svc)
+			require.Len(t, deps, 1)
+			assert.Equal(t, []ServiceInfo{graph.Services()[0]}, graph.Providers(deps[0]))
+		}
+	})
+
+	t.Run("sealed_and_composed_providers", func(t *testing.T) {
+		t.Parallel()
+		p, err := c.Build()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+		sealed, err := Seal(p, AllowServices(PtrTypeOf[validatorService]()))
+		require.NoError(t, err)
+
+		graph, err := GraphOf(sealed)
+		require.NoError(t, err)
+		require.Len(t, graph.Services(), 1)
+		assert.Equal(t, PtrTypeOf[validatorService](), graph.Services()[0].ServiceType)
+
+		app, err := Compose(BuildProvider(t, AddSingleton(NewTDependency)), sealed)
+		require.NoError(t, err)
+		graph, err = GraphOf(app)
+		require.NoError(t, err)
+		require.Len(t, graph.Services(), 2)
+		assert.Equal(t, PtrTypeOf[TDependency](), graph.Services()[0].ServiceType)
+		assert.Equal(t, PtrTypeOf[validatorService](), graph.Services()[1].ServiceType)
+	})
+
+	t.Run("invalid_provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := GraphOf(nil)
+		require.ErrorIs(t, err, ErrProviderNil)
+	})
+}
@@ -0,0 +1,99 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ScopeWaitGroup tracks goroutines started by services of one scope.
+// Constructors receive the wait group of the scope resolving them by taking
+// *ScopeWaitGroup as a parameter; it cannot be registered.
+//
+// Closing the scope cancels its context, closes its child scopes, then waits
+// for the tracked goroutines to finish before disposing any service, so
+// background work never observes its dependencies already closed. The wait
+// is bounded by ProviderOptions.ScopeWaitTimeout.
+//
+// Singletons and the transients they depend on are built in the provider's
+// root scope, so their goroutines are awaited by Provider.Close.
+//
+// Example:
+//
+//	func NewAuditWriter(wg *godi.ScopeWaitGroup, ctx context.Context, db *sql.DB) *AuditWriter {
+//	    w := &AuditWriter{db: db, entries: make(chan Entry, 16)}
+//	    wg.Go(func() { w.flushUntilDone(ctx) })
+//	    return w
+//	}
+type ScopeWaitGroup struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // closed when count drops back to zero; nil while idle
+}
+
+// Add adds delta, which may be negative, to the number of tracked
+// goroutines. It panics if the counter becomes negative.
+func (wg *ScopeWaitGroup) Add(delta int) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	wg.count += delta
+	switch {
+	case wg.count < 0:
+		panic("godi: negative ScopeWaitGroup counter")
+	case wg.count > 0 && wg.idle == nil:
+		wg.idle = make(chan struct{})
+	case wg.count == 0 && wg.idle != nil:
+		close(wg.idle)
+		wg.idle = nil
+	}
+}
+
+// Done decrements the number of tracked goroutines by one.
+func (wg *ScopeWaitGroup) Done() {
+	wg.Add(-1)
+}
+
+// Go calls f in a new goroutine tracked by the wait group.
+func (wg *ScopeWaitGroup) Go(f func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f()
+	}()
+}
+
+// wait blocks until no goroutines are tracked or, when timeout is positive,
+// until it expires on clock. It returns an error reporting the goroutines
+// still running on timeout.
+func (wg *ScopeWaitGroup) wait(clock Clock, timeout time.Duration) error {
+	wg.mu.Lock()
+	idle := wg.idle
+	wg.mu.Unlock()
+
+	if idle == nil {
+		return nil
+	}
+	if timeout <= 0 {
+		<-idle
+		return nil
+	}
+
+	expired := make(chan struct{})
+	timer := clock.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return nil
+	case <-expired:
+		wg.mu.Lock()
+		running := wg.count
+		wg.mu.Unlock()
+		if running == 0 {
+			return nil
+		}
+		return fmt.Errorf("%d goroutine(s) tracked by ScopeWaitGroup still running after %v: %w",
+			running, timeout, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,122 @@
+package godi
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitWorker starts a goroutine that keeps using its dependency until the
+// scope context is cancelled.
+type waitWorker struct {
+	dep          *TDisposable
+	usedAfterEnd atomic.Bool
+}
+
+func newWaitWorker(wg *ScopeWaitGroup, ctx context.Context, dep *TDisposable) *waitWorker {
+	w := &waitWorker{dep: dep}
+	wg.Go(func() {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // flush work after cancellation
+		if w.dep.IsClosed() {
+			w.usedAfterEnd.Store(true)
+		}
+	})
+	return w
+}
+
+func TestScopeWaitGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("close_waits_before_disposing", func(t *testing.T) {
+		t.Parallel()
+		p := BuildProvider(t, AddScoped(NewTDisposable), AddScoped(newWaitWorker))
+
+		s := NewTestScope(t, p)
+		w := RequireResolveFrom[*waitWorker](t, s)
+		require.NoError(t, s.Close())
+
+		assert.False(t, w.usedAfterEnd.Load(), "dependency was disposed while the goroutine still ran")
+		assert.True(t, w.dep.IsClosed())
+	})
+
+	t.Run("each_scope_has_its_own_group", func(t *testing.T) {
+		t.Parallel()
+		type holder struct{ wg *ScopeWaitGroup }
+		p := BuildProvider(t, AddScoped(func(wg *ScopeWaitGroup) *holder { return &holder{wg: wg} }))
+
+		first := NewTestScope(t, p)
+		second := NewTestScope(t, p)
+		a := RequireResolveFrom[*holder](t, first).wg
+		b := RequireResolveFrom[*holder](t, second).wg
+		assert.NotSame(t, a, b)
+
+		a.Add(1)
+		require.NoError(t, second.Close(), "a scope does not wait for another scope's goroutines")
+		a.Done()
+	})
+
+	t.Run("timeout_reports_running_goroutines", func(t *testing.T) {
+		t.Parallel()
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		c := NewCollection()
+		c.AddScoped(func(wg *ScopeWaitGroup) *TDisposable {
+			wg.Go(func() { <-release })
+			return NewTDisposable()
+		})
+		p, err := c.BuildWithOptions(&ProviderOptions{ScopeWaitTimeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = p.Close() })
+
+		s := NewTestScope(t, p)
+		d := RequireResolveFrom[*TDisposable](t, s)
+
+		err = s.Close()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "1 goroutine(s) tracked by ScopeWaitGroup still running after 10ms")
+		assert.True(t, d.IsClosed(), "services are still disposed after the timeout")
+	})
+
+	t.Run("provider_close_waits_for_singleton_goroutines", func(t *testing.T) {
+		t.Parallel()
+		var finished atomic.Bool
+		c := NewCollection()
+		c.AddSingleton(func(wg *ScopeWaitGroup) *TService {
+			wg.Go(func() {
+				time.Sleep(10 * time.Millisecond)
+				finished.Store(true)
+			})
+			return &TService{}
+		})
+		p, err := c.Build()
+		require.NoError(t, err)
+
+		require.NoError(t, p.Close())
+		assert.True(t, finished.Load())
+	})
+
+	t.Run("negative_counter_panics", func(t *testing.T) {
+		t.Parallel()
+		var wg ScopeWaitGroup
+		assert.PanicsWithValue(t, "godi: negative ScopeWaitGroup counter", wg.Done)
+	})
+
+	t.Run("reserved_and_validated", func(t *testing.T) {
+		t.Parallel()
+		c := NewCollection()
+		c.AddSingleton(func() *ScopeWaitGroup { return &ScopeWaitGroup{} })
+		_, err := c.Build()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reserved")
+
+		_, err = NewCollection().BuildWithOptions(&ProviderOptions{ScopeWaitTimeout: -time.Second})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timeout cannot be negative")
+	})
+}